@@ -1,18 +1,22 @@
 package cmd
 
 import (
+	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"strings"
 
+	"github.com/krutsko/istio-mcp-server/pkg/istio"
 	"github.com/krutsko/istio-mcp-server/pkg/mcp"
 	"github.com/krutsko/istio-mcp-server/pkg/version"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"golang.org/x/net/context"
+	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
 	"k8s.io/klog/v2/textlogger"
 )
@@ -42,11 +46,18 @@ and Envoy proxy configurations.
   # start a SSE server on port 8443 with a public HTTPS host of example.com
   istio-mcp-server --sse-port 8443 --sse-base-url https://example.com:8443
 
+  # start with options read from a YAML config file
+  istio-mcp-server --config ~/.config/istio-mcp-server.yaml
+
   # start with custom kubeconfig and read-only mode
   istio-mcp-server --kubeconfig ~/.kube/config --read-only
 
   # start HTTP server on port 8080`,
 	Run: func(cmd *cobra.Command, args []string) {
+		if err := loadConfigFile(); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
 		initLogging()
 		profile := mcp.ProfileFromString(viper.GetString("profile"))
 		if profile == nil {
@@ -54,6 +65,14 @@ and Envoy proxy configurations.
 			os.Exit(1)
 		}
 
+		if viper.GetBool("dump-tools") {
+			if err := dumpTools(os.Stdout, profile); err != nil {
+				fmt.Printf("Failed to dump tools: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+
 		klog.V(1).Info("Starting istio-mcp-server")
 		klog.V(1).Infof(" - Profile: %s", profile.GetName())
 
@@ -61,10 +80,7 @@ and Envoy proxy configurations.
 			fmt.Println(version.Version)
 			return
 		}
-		mcpServer, err := mcp.NewServer(mcp.Configuration{
-			Profile:    profile,
-			Kubeconfig: viper.GetString("kubeconfig"),
-		})
+		mcpServer, err := mcp.NewServer(resolveConfiguration(profile))
 		if err != nil {
 			fmt.Printf("Failed to initialize MCP server: %v\n", err)
 			os.Exit(1)
@@ -106,6 +122,39 @@ func Execute() {
 	}
 }
 
+// loadConfigFile reads the YAML file given with --config, whose keys are flag names. Flags set on the command line
+// take precedence over the file.
+func loadConfigFile() error {
+	path := viper.GetString("config")
+	if path == "" {
+		return nil
+	}
+	viper.SetConfigFile(path)
+	if err := viper.ReadInConfig(); err != nil {
+		return fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// resolveConfiguration builds the server configuration from the flags and config file
+func resolveConfiguration(profile mcp.Profile) mcp.Configuration {
+	return mcp.Configuration{
+		Profile:             profile,
+		Kubeconfig:          viper.GetString("kubeconfig"),
+		ProxyConfigCacheTTL: viper.GetDuration("proxy-config-cache-ttl"),
+		AnalyzeCacheTTL:     viper.GetDuration("analyze-cache-ttl"),
+		PrometheusURL:       viper.GetString("prometheus-url"),
+		ExportRoot:          viper.GetString("export-root"),
+		ServerName:          viper.GetString("server-name"),
+		ServerVersion:       viper.GetString("server-version"),
+		ToolTimeout:         viper.GetDuration("tool-timeout"),
+		KubeQPS:             float32(viper.GetFloat64("kube-qps")),
+		KubeBurst:           viper.GetInt("kube-burst"),
+		LogRequests:         viper.GetBool("log-requests"),
+		LogRequestsLevel:    viper.GetInt("log-requests-level"),
+	}
+}
+
 // initLogging initializes the logging configuration
 func initLogging() {
 	flagSet := flag.NewFlagSet("istio-mcp-server", flag.ContinueOnError)
@@ -123,16 +172,39 @@ func initLogging() {
 // Exposed for testing purposes.
 func flagInit() {
 	rootCmd.Flags().BoolP("version", "v", false, "Print version information and quit")
+	rootCmd.Flags().String("config", "", "Path to a YAML file setting any of these options by flag name; command line flags take precedence")
 	rootCmd.Flags().IntP("log-level", "", 0, "Set the log level (from 0 to 9)")
+	rootCmd.Flags().Bool("log-requests", false, "Log the name, arguments (with sensitive values redacted), duration and error of every tool call")
+	rootCmd.Flags().Int("log-requests-level", 0, "Log level of the tool call entries enabled by --log-requests")
+	rootCmd.Flags().Bool("dump-tools", false, "Print the name, description and input schema of every tool of the profile as JSON and quit")
 	rootCmd.Flags().IntP("sse-port", "", 0, "Start a SSE server on the specified port")
 	rootCmd.Flags().IntP("http-port", "", 0, "Start a streamable HTTP server on the specified port")
 	rootCmd.Flags().StringP("sse-base-url", "", "", "SSE public base URL to use when sending the endpoint message (e.g. https://example.com)")
 	rootCmd.Flags().StringP("kubeconfig", "", "", "Path to the kubeconfig file to use for authentication")
 	rootCmd.Flags().String("profile", "full", "MCP profile to use (one of: "+strings.Join(mcp.ProfileNames, ", ")+")")
+	rootCmd.Flags().Float32("kube-qps", rest.DefaultQPS, "Maximum sustained queries per second to the Kubernetes API server")
+	rootCmd.Flags().Int("kube-burst", rest.DefaultBurst, "Maximum burst of queries to the Kubernetes API server")
+	rootCmd.Flags().Duration("proxy-config-cache-ttl", istio.DefaultProxyConfigCacheTTL, "How long proxy configuration of a pod is reused between tool calls (0 disables caching)")
+	rootCmd.Flags().Duration("analyze-cache-ttl", istio.DefaultAnalyzeCacheTTL, "How long istioctl analyze results of a namespace are reused between tool calls (0 disables caching)")
+	rootCmd.Flags().String("server-name", version.BinaryName, "Server name advertised to MCP clients")
+	rootCmd.Flags().String("server-version", version.Version, "Server version advertised to MCP clients")
+	rootCmd.Flags().Duration("tool-timeout", mcp.DefaultToolTimeout, "Maximum duration of a single tool call before it fails with a timeout error (0 disables the limit)")
+	rootCmd.Flags().String("prometheus-url", "", "Base URL of the Prometheus server scraping Istio metrics, enables metrics-backed tools (e.g. http://prometheus.istio-system:9090)")
+	rootCmd.Flags().String("export-root", "", "Directory under which export-as-kustomize may write files; the tool is only available when set")
 
 	_ = viper.BindPFlags(rootCmd.Flags())
 }
 
+// dumpTools writes the definitions of the tools of a profile to w as a JSON array
+func dumpTools(w io.Writer, profile mcp.Profile) error {
+	data, err := json.MarshalIndent(mcp.ToolDefinitions(profile), "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
 func init() {
 	flagInit()
 }
@@ -2,12 +2,14 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/krutsko/istio-mcp-server/pkg/mcp"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -74,6 +76,18 @@ func TestFlagInit(t *testing.T) {
 			"sse-base-url",
 			"kubeconfig",
 			"profile",
+			"proxy-config-cache-ttl",
+			"analyze-cache-ttl",
+			"prometheus-url",
+			"server-name",
+			"server-version",
+			"tool-timeout",
+			"dump-tools",
+			"config",
+			"log-requests",
+			"log-requests-level",
+			"kube-qps",
+			"kube-burst",
 		}
 
 		for _, flagName := range expectedFlags {
@@ -94,6 +108,20 @@ func TestFlagInit(t *testing.T) {
 		}
 	})
 
+	t.Run("proxy config cache ttl flag has correct default", func(t *testing.T) {
+		flag := testCmd.Flags().Lookup("proxy-config-cache-ttl")
+		if flag.DefValue != "10s" {
+			t.Fatalf("Expected proxy-config-cache-ttl flag default '10s', got '%s'", flag.DefValue)
+		}
+	})
+
+	t.Run("analyze cache ttl flag has correct default", func(t *testing.T) {
+		flag := testCmd.Flags().Lookup("analyze-cache-ttl")
+		if flag.DefValue != "30s" {
+			t.Fatalf("Expected analyze-cache-ttl flag default '30s', got '%s'", flag.DefValue)
+		}
+	})
+
 	t.Run("profile flag has correct default", func(t *testing.T) {
 		flag := testCmd.Flags().Lookup("profile")
 		if flag.DefValue != "full" {
@@ -213,6 +241,106 @@ users:
 	})
 }
 
+// TestDumpTools tests that the tool definitions are printed as JSON with their input schemas
+func TestDumpTools(t *testing.T) {
+	var buf bytes.Buffer
+	if err := dumpTools(&buf, mcp.ProfileFromString("full")); err != nil {
+		t.Fatalf("dumpTools failed: %v", err)
+	}
+
+	var tools []struct {
+		Name        string          `json:"name"`
+		Description string          `json:"description"`
+		InputSchema json.RawMessage `json:"inputSchema"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &tools); err != nil {
+		t.Fatalf("Dump is not valid JSON: %v", err)
+	}
+
+	names := make(map[string]bool)
+	for _, tool := range tools {
+		names[tool.Name] = true
+		if tool.Description == "" {
+			t.Errorf("Tool %s has no description", tool.Name)
+		}
+		var schema struct {
+			Type       string                     `json:"type"`
+			Properties map[string]json.RawMessage `json:"properties"`
+		}
+		if err := json.Unmarshal(tool.InputSchema, &schema); err != nil {
+			t.Fatalf("Tool %s has an invalid input schema: %v", tool.Name, err)
+		}
+		if schema.Type != "object" {
+			t.Errorf("Tool %s has input schema type %q, expected 'object'", tool.Name, schema.Type)
+		}
+	}
+	for _, name := range []string{"get-virtual-services", "get-proxy-clusters", "batch", "export-as-kustomize"} {
+		if !names[name] {
+			t.Errorf("Expected tool %s in dump", name)
+		}
+	}
+	for _, tool := range tools {
+		if tool.Name == "export-as-kustomize" && !strings.Contains(tool.Description, "--export-root") {
+			t.Errorf("Expected the description of export-as-kustomize to note that it requires --export-root, got %q", tool.Description)
+		}
+	}
+}
+
+// TestLoadConfigFile tests that options are read from a YAML config file and that command line flags override them
+func TestLoadConfigFile(t *testing.T) {
+	testCmd := &cobra.Command{Use: "test"}
+	originalRootCmd := rootCmd
+	rootCmd = testCmd
+	defer func() {
+		rootCmd = originalRootCmd
+		viper.Reset()
+		_ = viper.BindPFlags(rootCmd.Flags())
+	}()
+	viper.Reset()
+	flagInit()
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	config := `prometheus-url: http://prometheus.istio-system:9090
+tool-timeout: 2m
+proxy-config-cache-ttl: 0s
+server-name: file-server
+`
+	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	if err := testCmd.Flags().Set("config", configPath); err != nil {
+		t.Fatalf("Failed to set config flag: %v", err)
+	}
+	if err := testCmd.Flags().Set("server-name", "cli-server"); err != nil {
+		t.Fatalf("Failed to set server-name flag: %v", err)
+	}
+
+	if err := loadConfigFile(); err != nil {
+		t.Fatalf("loadConfigFile failed: %v", err)
+	}
+	resolved := resolveConfiguration(mcp.ProfileFromString("full"))
+	if resolved.PrometheusURL != "http://prometheus.istio-system:9090" {
+		t.Errorf("Expected prometheus URL from the config file, got %q", resolved.PrometheusURL)
+	}
+	if resolved.ToolTimeout != 2*time.Minute {
+		t.Errorf("Expected tool timeout 2m from the config file, got %v", resolved.ToolTimeout)
+	}
+	if resolved.ProxyConfigCacheTTL != 0 {
+		t.Errorf("Expected proxy config cache TTL 0 from the config file, got %v", resolved.ProxyConfigCacheTTL)
+	}
+	if resolved.ServerName != "cli-server" {
+		t.Errorf("Expected the command line flag to override the config file, got server name %q", resolved.ServerName)
+	}
+	if resolved.AnalyzeCacheTTL != 30*time.Second {
+		t.Errorf("Expected the default analyze cache TTL for an option missing from the file, got %v", resolved.AnalyzeCacheTTL)
+	}
+
+	viper.Set("config", filepath.Join(t.TempDir(), "missing.yaml"))
+	if err := loadConfigFile(); err == nil {
+		t.Error("Expected an error for a missing config file")
+	}
+}
+
 func TestInvalidProfile(t *testing.T) {
 	// This test would require running the actual command with invalid profile
 	// For now, we test the profile validation logic indirectly
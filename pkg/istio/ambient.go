@@ -0,0 +1,167 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// dataplaneModeLabel enrolls a namespace or pod in the ambient mesh when set to dataplaneModeAmbient;
+	// a pod can opt out of an ambient namespace by setting it to dataplaneModeNone
+	dataplaneModeLabel   = "istio.io/dataplane-mode"
+	dataplaneModeAmbient = "ambient"
+	dataplaneModeNone    = "none"
+
+	// waypointManagedSelector selects the waypoint deployments istiod generates for Gateways of class istio-waypoint
+	waypointManagedSelector = "gateway.istio.io/managed=istio.io-mesh-controller"
+	// gatewayNameLabel holds the name of the Gateway a generated deployment belongs to
+	gatewayNameLabel = "gateway.networking.k8s.io/gateway-name"
+	// waypointForLabel selects the traffic a waypoint handles: service (default), workload, all or none
+	waypointForLabel = "istio.io/waypoint-for"
+	// useWaypointLabel, on a namespace or service, routes its traffic through the named waypoint;
+	// useWaypointNamespaceLabel names the waypoint's namespace when it lives elsewhere
+	useWaypointLabel          = "istio.io/use-waypoint"
+	useWaypointNamespaceLabel = "istio.io/use-waypoint-namespace"
+)
+
+// Mesh modes of a pod, as reported by podMeshMode
+const (
+	meshModeNone    = ""
+	meshModeSidecar = "sidecar"
+	meshModeAmbient = "ambient"
+)
+
+// isAmbientEnrolled reports whether ztunnel captures the pod's traffic: the pod has no sidecar and either it
+// or its namespace carries the ambient dataplane-mode label, without the pod opting out
+func isAmbientEnrolled(pod v1.Pod, namespaceLabels map[string]string) bool {
+	if hasIstioSidecar(pod) {
+		return false
+	}
+	switch pod.Labels[dataplaneModeLabel] {
+	case dataplaneModeAmbient:
+		return true
+	case dataplaneModeNone:
+		return false
+	}
+	return namespaceLabels[dataplaneModeLabel] == dataplaneModeAmbient
+}
+
+// podMeshMode returns how the pod is enrolled in the mesh: meshModeSidecar, meshModeAmbient or meshModeNone
+func podMeshMode(pod v1.Pod, namespaceLabels map[string]string) string {
+	switch {
+	case hasIstioSidecar(pod):
+		return meshModeSidecar
+	case isAmbientEnrolled(pod, namespaceLabels):
+		return meshModeAmbient
+	}
+	return meshModeNone
+}
+
+// namespaceLabels returns the labels of a namespace, or nil when it cannot be read; ambient enrollment is
+// then detected from pod labels only
+func (i *Istio) namespaceLabels(ctx context.Context, namespace string) map[string]string {
+	ns, err := i.kubeClient.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+	if err != nil {
+		klog.V(1).Infof("Failed to get namespace %s, detecting ambient enrollment from pod labels only: %v", namespace, err)
+		return nil
+	}
+	return ns.Labels
+}
+
+// allNamespaceLabels returns the labels of every namespace by name, or nil when namespaces cannot be listed
+func (i *Istio) allNamespaceLabels(ctx context.Context) map[string]map[string]string {
+	namespaces, err := i.kubeClient.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		klog.V(1).Infof("Failed to list namespaces, detecting ambient enrollment from pod labels only: %v", err)
+		return nil
+	}
+	labelsByNamespace := make(map[string]map[string]string, len(namespaces.Items))
+	for _, ns := range namespaces.Items {
+		labelsByNamespace[ns.Name] = ns.Labels
+	}
+	return labelsByNamespace
+}
+
+// GetWaypointProxies lists the waypoint proxies of a namespace, the ambient mode equivalent of sidecars for
+// L7 policy, with the namespaces and services whose traffic they handle
+func (i *Istio) GetWaypointProxies(ctx context.Context, namespace string) (string, error) {
+	deployments, err := i.kubeClient.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{LabelSelector: waypointManagedSelector})
+	if err != nil {
+		return "", fmt.Errorf("failed to list waypoint deployments: %w", explainForbidden(err, "list", "deployments", namespace))
+	}
+	if len(deployments.Items) == 0 {
+		return fmt.Sprintf("No waypoint proxies found in namespace '%s'\n", namespace), nil
+	}
+
+	services, err := i.kubeClient.CoreV1().Services("").List(ctx, metav1.ListOptions{LabelSelector: useWaypointLabel})
+	if err != nil {
+		return "", fmt.Errorf("failed to list services using waypoints: %w", explainForbidden(err, "list", "services", ""))
+	}
+	namespaceLabels := i.allNamespaceLabels(ctx)
+
+	// usesWaypoint reports whether labels of an object in objectNamespace select the named waypoint of namespace
+	usesWaypoint := func(objectLabels map[string]string, objectNamespace, name string) bool {
+		if objectLabels[useWaypointLabel] != name {
+			return false
+		}
+		waypointNamespace := objectLabels[useWaypointNamespaceLabel]
+		if waypointNamespace == "" {
+			waypointNamespace = objectNamespace
+		}
+		return waypointNamespace == namespace
+	}
+
+	result := fmt.Sprintf("Found %d waypoint proxies in namespace '%s':\n", len(deployments.Items), namespace)
+	for _, deployment := range deployments.Items {
+		name := deployment.Labels[gatewayNameLabel]
+		if name == "" {
+			name = deployment.Name
+		}
+		trafficType := deployment.Labels[waypointForLabel]
+		if trafficType == "" {
+			trafficType = "service"
+		}
+		replicas := int32(1)
+		if deployment.Spec.Replicas != nil {
+			replicas = *deployment.Spec.Replicas
+		}
+
+		result += fmt.Sprintf("\n- %s (Deployment: %s, Ready: %d/%d, traffic: %s)\n", name, deployment.Name, deployment.Status.ReadyReplicas, replicas, trafficType)
+		if deployment.Status.ReadyReplicas == 0 {
+			result += "  [WARNING] No ready replicas; traffic routed through this waypoint will fail\n"
+		}
+
+		var servedNamespaces []string
+		for ns, nsLabels := range namespaceLabels {
+			if usesWaypoint(nsLabels, ns, name) {
+				servedNamespaces = append(servedNamespaces, ns)
+			}
+		}
+		sort.Strings(servedNamespaces)
+		var servedServices []string
+		for _, service := range services.Items {
+			if usesWaypoint(service.Labels, service.Namespace, name) {
+				servedServices = append(servedServices, service.Namespace+"/"+service.Name)
+			}
+		}
+		sort.Strings(servedServices)
+
+		if len(servedNamespaces) == 0 && len(servedServices) == 0 {
+			result += fmt.Sprintf("  Serves: nothing; no namespace or service is labeled %s=%s\n", useWaypointLabel, name)
+			continue
+		}
+		if len(servedNamespaces) > 0 {
+			result += fmt.Sprintf("  Serves namespaces: %s\n", strings.Join(servedNamespaces, ", "))
+		}
+		if len(servedServices) > 0 {
+			result += fmt.Sprintf("  Serves services: %s\n", strings.Join(servedServices, ", "))
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,176 @@
+package istio
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+// TestDiscoverNamespacesWithAmbient tests that ambient-enrolled workloads are counted separately from sidecars
+func TestDiscoverNamespacesWithAmbient(t *testing.T) {
+	server := newMockAPIServer(map[string]string{
+		"/api/v1/namespaces": `{
+			"apiVersion": "v1",
+			"kind": "NamespaceList",
+			"items": [
+				{"metadata": {"name": "ambient-apps", "labels": {"istio.io/dataplane-mode": "ambient"}}},
+				{"metadata": {"name": "sidecar-apps", "labels": {"istio-injection": "enabled"}}},
+				{"metadata": {"name": "plain"}}
+			]
+		}`,
+		"/api/v1/pods": `{
+			"apiVersion": "v1",
+			"kind": "PodList",
+			"items": [
+				{"metadata": {"name": "a-1", "namespace": "ambient-apps"}, "spec": {"containers": [{"name": "app"}]}, "status": {"phase": "Running"}},
+				{"metadata": {"name": "a-2", "namespace": "ambient-apps"}, "spec": {"containers": [{"name": "app"}]}, "status": {"phase": "Running"}},
+				{"metadata": {"name": "a-3", "namespace": "ambient-apps", "labels": {"istio.io/dataplane-mode": "none"}}, "spec": {"containers": [{"name": "app"}]}, "status": {"phase": "Running"}},
+				{"metadata": {"name": "s-1", "namespace": "sidecar-apps"}, "spec": {"containers": [{"name": "app"}, {"name": "istio-proxy"}]}, "status": {"phase": "Running"}},
+				{"metadata": {"name": "p-1", "namespace": "plain", "labels": {"istio.io/dataplane-mode": "ambient"}}, "spec": {"containers": [{"name": "app"}]}, "status": {"phase": "Running"}},
+				{"metadata": {"name": "p-2", "namespace": "plain"}, "spec": {"containers": [{"name": "app"}]}, "status": {"phase": "Running"}}
+			]
+		}`,
+	})
+	defer server.Close()
+	istio := newTestIstio(t, server.URL)
+
+	result, err := istio.DiscoverNamespacesWithSidecars(context.Background())
+	if err != nil {
+		t.Fatalf("DiscoverNamespacesWithSidecars failed: %v", err)
+	}
+	assertContains(t, result,
+		"Found 3 namespaces with Istio workloads",
+		"   1 | ambient-apps |             0 |             2 |",
+		"   2 | plain     |             0 |             1 |",
+		"   3 | sidecar-apps |             1 |             0 |",
+		"3 workloads are enrolled in ambient mode",
+	)
+}
+
+// TestDiscoverNamespacesWithSidecarsJSON tests that the JSON discovery output is an array ordered by workload count
+func TestDiscoverNamespacesWithSidecarsJSON(t *testing.T) {
+	server := newMockAPIServer(map[string]string{
+		"/api/v1/namespaces": `{"apiVersion": "v1", "kind": "NamespaceList", "items": []}`,
+		"/api/v1/pods": `{
+			"apiVersion": "v1",
+			"kind": "PodList",
+			"items": [
+				{"metadata": {"name": "r-1", "namespace": "reviews"}, "spec": {"containers": [{"name": "app"}, {"name": "istio-proxy"}]}, "status": {"phase": "Running"}},
+				{"metadata": {"name": "b-1", "namespace": "bookinfo"}, "spec": {"containers": [{"name": "app"}, {"name": "istio-proxy"}]}, "status": {"phase": "Running"}},
+				{"metadata": {"name": "b-2", "namespace": "bookinfo"}, "spec": {"containers": [{"name": "app"}, {"name": "istio-proxy"}]}, "status": {"phase": "Running"}},
+				{"metadata": {"name": "b-3", "namespace": "bookinfo"}, "spec": {"containers": [{"name": "app"}, {"name": "istio-proxy"}]}, "status": {"phase": "Running"}},
+				{"metadata": {"name": "p-1", "namespace": "plain"}, "spec": {"containers": [{"name": "app"}]}, "status": {"phase": "Running"}}
+			]
+		}`,
+	})
+	defer server.Close()
+	istio := newTestIstio(t, server.URL)
+
+	result, err := istio.DiscoverNamespacesWithSidecarsJSON(context.Background())
+	if err != nil {
+		t.Fatalf("DiscoverNamespacesWithSidecarsJSON failed: %v", err)
+	}
+	var namespaces []struct {
+		Namespace    string `json:"namespace"`
+		SidecarCount int    `json:"sidecarCount"`
+		Rank         int    `json:"rank"`
+	}
+	if err := json.Unmarshal([]byte(result), &namespaces); err != nil {
+		t.Fatalf("Output is not a JSON array: %v\n%s", err, result)
+	}
+	if len(namespaces) != 2 {
+		t.Fatalf("Expected 2 namespaces, got %d: %s", len(namespaces), result)
+	}
+	for idx, ns := range namespaces {
+		if ns.Rank != idx+1 {
+			t.Errorf("Expected namespace %s to have rank %d, got %d", ns.Namespace, idx+1, ns.Rank)
+		}
+		if idx > 0 && ns.SidecarCount > namespaces[idx-1].SidecarCount {
+			t.Errorf("Namespaces are not ordered by sidecar count descending: %s", result)
+		}
+	}
+	if namespaces[0].Namespace != "bookinfo" || namespaces[0].SidecarCount != 3 {
+		t.Errorf("Expected bookinfo with 3 sidecars first, got %+v", namespaces[0])
+	}
+}
+
+// TestGetPodsByServiceAmbient tests that pods in an ambient namespace are reported as mesh-enrolled
+func TestGetPodsByServiceAmbient(t *testing.T) {
+	server := newMockAPIServer(map[string]string{
+		"/api/v1/namespaces/bookinfo": `{
+			"apiVersion": "v1",
+			"kind": "Namespace",
+			"metadata": {"name": "bookinfo", "labels": {"istio.io/dataplane-mode": "ambient"}}
+		}`,
+		"/api/v1/namespaces/bookinfo/services/reviews": `{
+			"apiVersion": "v1",
+			"kind": "Service",
+			"metadata": {"name": "reviews", "namespace": "bookinfo"},
+			"spec": {"selector": {"app": "reviews"}}
+		}`,
+		"/api/v1/namespaces/bookinfo/pods": `{
+			"apiVersion": "v1",
+			"kind": "PodList",
+			"items": [
+				{"metadata": {"name": "reviews-v1-abc", "labels": {"app": "reviews"}}, "spec": {"containers": [{"name": "reviews"}]}, "status": {"phase": "Running"}}
+			]
+		}`,
+	})
+	defer server.Close()
+	istio := newTestIstio(t, server.URL)
+
+	result, err := istio.GetPodsByService(context.Background(), "bookinfo", "reviews")
+	if err != nil {
+		t.Fatalf("GetPodsByService failed: %v", err)
+	}
+	assertContains(t, result, "reviews-v1-abc", "Istio mesh: AMBIENT")
+	assertNotContains(t, result, "Istio mesh: NOT ENABLED")
+}
+
+// TestGetWaypointProxies tests that waypoint deployments are identified with the namespaces and services they serve
+func TestGetWaypointProxies(t *testing.T) {
+	server := newMockAPIServer(map[string]string{
+		"/apis/apps/v1/namespaces/bookinfo/deployments": `{
+			"apiVersion": "apps/v1",
+			"kind": "DeploymentList",
+			"items": [{
+				"metadata": {"name": "waypoint", "namespace": "bookinfo", "labels": {
+					"gateway.istio.io/managed": "istio.io-mesh-controller",
+					"gateway.networking.k8s.io/gateway-name": "waypoint",
+					"istio.io/waypoint-for": "service"
+				}},
+				"spec": {"replicas": 1},
+				"status": {"readyReplicas": 1}
+			}]
+		}`,
+		"/api/v1/namespaces": `{
+			"apiVersion": "v1",
+			"kind": "NamespaceList",
+			"items": [
+				{"metadata": {"name": "bookinfo", "labels": {"istio.io/dataplane-mode": "ambient", "istio.io/use-waypoint": "waypoint"}}},
+				{"metadata": {"name": "other", "labels": {"istio.io/use-waypoint": "waypoint"}}}
+			]
+		}`,
+		"/api/v1/services": `{
+			"apiVersion": "v1",
+			"kind": "ServiceList",
+			"items": [
+				{"metadata": {"name": "ratings", "namespace": "shared", "labels": {"istio.io/use-waypoint": "waypoint", "istio.io/use-waypoint-namespace": "bookinfo"}}}
+			]
+		}`,
+	})
+	defer server.Close()
+	istio := newTestIstio(t, server.URL)
+
+	result, err := istio.GetWaypointProxies(context.Background(), "bookinfo")
+	if err != nil {
+		t.Fatalf("GetWaypointProxies failed: %v", err)
+	}
+	assertContains(t, result,
+		"Found 1 waypoint proxies in namespace 'bookinfo'",
+		"- waypoint (Deployment: waypoint, Ready: 1/1, traffic: service)",
+		"Serves namespaces: bookinfo\n",
+		"Serves services: shared/ratings",
+	)
+	assertNotContains(t, result, "other", "[WARNING]")
+}
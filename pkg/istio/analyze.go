@@ -0,0 +1,87 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DefaultAnalyzeCacheTTL is how long istioctl analyze results are reused before the analysis is run again
+const DefaultAnalyzeCacheTTL = 30 * time.Second
+
+// analyzeMessage matches an istioctl analyze message, e.g. 'Warning [IST0102] (Namespace default) ...'
+var analyzeMessage = regexp.MustCompile(`(?m)^(Error|Warning|Info) \[IST\d+\]`)
+
+// analyzeSeverities ranks the severities of istioctl analyze messages
+var analyzeSeverities = map[string]int{"info": 0, "warning": 1, "error": 2}
+
+// SetAnalyzeCacheTTL sets how long istioctl analyze results of a namespace are reused. A zero TTL disables caching.
+func (p *ProxyConfigClient) SetAnalyzeCacheTTL(ttl time.Duration) {
+	p.analyzeCache = newProxyConfigCache(ttl)
+}
+
+// Analyze runs istioctl analyze on a namespace (all namespaces when empty) and returns the messages at or
+// above severity ('info', 'warning' or 'error'; all messages when empty). Results are cached unfiltered,
+// so every severity is served from the same run; refresh bypasses the cache.
+func (p *ProxyConfigClient) Analyze(ctx context.Context, namespace, severity string, refresh bool) (string, error) {
+	threshold := 0
+	if severity != "" {
+		var ok bool
+		if threshold, ok = analyzeSeverities[strings.ToLower(severity)]; !ok {
+			return "", fmt.Errorf("invalid severity '%s', valid values are: info, warning, error", severity)
+		}
+	}
+
+	key := proxyConfigCacheKey{namespace: namespace, command: "analyze"}
+	output, cached := p.analyzeCache.get(key)
+	if refresh || !cached {
+		args := []string{"analyze", "--output-threshold", "Info"}
+		if namespace == "" {
+			args = append(args, "--all-namespaces")
+		} else {
+			args = append(args, "--namespace", namespace)
+		}
+		raw, err := p.runWithTimeout(ctx, args...)
+		// istioctl analyze exits with an error status when it finds errors, which is a successful analysis
+		if err != nil && !analyzeMessage.Match(raw) {
+			return "", fmt.Errorf("istioctl command failed: %w, output: %s", err, string(raw))
+		}
+		output, cached = string(raw), false
+		p.analyzeCache.put(key, output)
+	}
+
+	scope := fmt.Sprintf("namespace '%s'", namespace)
+	if namespace == "" {
+		scope = "all namespaces"
+	}
+	result := fmt.Sprintf("istioctl analyze results for %s", scope)
+	if severity != "" {
+		result += fmt.Sprintf(" (severity %s and above)", strings.ToLower(severity))
+	}
+	result += ":\n\n"
+
+	counts := make(map[string]int)
+	shown := 0
+	for _, line := range strings.Split(output, "\n") {
+		match := analyzeMessage.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		level := strings.ToLower(match[1])
+		counts[level]++
+		if analyzeSeverities[level] >= threshold {
+			result += line + "\n"
+			shown++
+		}
+	}
+	if shown == 0 {
+		result += "[OK] No validation issues found\n"
+	}
+	result += fmt.Sprintf("\n[RESULT] %d errors, %d warnings, %d info messages\n", counts["error"], counts["warning"], counts["info"])
+	if cached {
+		result += "(cached result; set refresh to run the analysis again)\n"
+	}
+	return result, nil
+}
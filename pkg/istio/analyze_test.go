@@ -0,0 +1,89 @@
+package istio
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// TestAnalyze tests filtering and caching of istioctl analyze results
+func TestAnalyze(t *testing.T) {
+	ctx := context.Background()
+	const analysis = `Error [IST0101] (VirtualService default/reviews) Referenced host not found: "reviews-v3"
+Warning [IST0102] (Namespace default) The namespace is not enabled for Istio injection. Run 'kubectl label namespace default istio-injection=enabled' to enable it, or 'kubectl label namespace default istio-injection=disabled' to explicitly mark it as not needing injection.
+Info [IST0118] (Service default/legacy) Port name tcp (port: 8080, targetPort: 8080) doesn't follow the naming convention of Istio port.
+Error: Analyzers found issues when analyzing namespace: default.
+See https://istio.io/v1.25/docs/reference/config/analysis for more information about causes and resolutions.
+`
+
+	newClient := func() (*ProxyConfigClient, *int) {
+		client := NewProxyConfigClient("")
+		calls := 0
+		client.execCommand = func(ctx context.Context, args ...string) ([]byte, error) {
+			calls++
+			// istioctl analyze exits with status 79 when it reports errors
+			return []byte(analysis), errors.New("exit status 79")
+		}
+		return client, &calls
+	}
+
+	t.Run("filters by severity", func(t *testing.T) {
+		client, _ := newClient()
+		result, err := client.Analyze(ctx, "default", "warning", false)
+		if err != nil {
+			t.Fatalf("Failed to analyze: %v", err)
+		}
+		assertContains(t, result, "(severity warning and above)", "Error [IST0101]", "Warning [IST0102]", "[RESULT] 1 errors, 1 warnings, 1 info messages")
+		assertNotContains(t, result, "Info [IST0118]", "cached result")
+	})
+
+	t.Run("cached within TTL for every severity", func(t *testing.T) {
+		client, calls := newClient()
+		if _, err := client.Analyze(ctx, "default", "", false); err != nil {
+			t.Fatalf("Failed to analyze: %v", err)
+		}
+		result, err := client.Analyze(ctx, "default", "error", false)
+		if err != nil {
+			t.Fatalf("Failed to analyze: %v", err)
+		}
+		if *calls != 1 {
+			t.Errorf("Expected istioctl analyze to run once, got %d", *calls)
+		}
+		assertContains(t, result, "Error [IST0101]", "cached result")
+		assertNotContains(t, result, "Warning [IST0102]")
+	})
+
+	t.Run("refresh bypasses the cache", func(t *testing.T) {
+		client, calls := newClient()
+		for _, refresh := range []bool{false, true} {
+			if _, err := client.Analyze(ctx, "default", "", refresh); err != nil {
+				t.Fatalf("Failed to analyze: %v", err)
+			}
+		}
+		if *calls != 2 {
+			t.Errorf("Expected istioctl analyze to run twice, got %d", *calls)
+		}
+	})
+
+	t.Run("namespaces are cached separately", func(t *testing.T) {
+		client, calls := newClient()
+		for _, namespace := range []string{"default", "bookinfo"} {
+			if _, err := client.Analyze(ctx, namespace, "", false); err != nil {
+				t.Fatalf("Failed to analyze: %v", err)
+			}
+		}
+		if *calls != 2 {
+			t.Errorf("Expected istioctl analyze to run per namespace, got %d", *calls)
+		}
+	})
+
+	t.Run("reports istioctl failures", func(t *testing.T) {
+		client := NewProxyConfigClient("")
+		client.execCommand = func(ctx context.Context, args ...string) ([]byte, error) {
+			return []byte("Error: failed to get Kubernetes client"), errors.New("exit status 1")
+		}
+		if _, err := client.Analyze(ctx, "default", "", false); err == nil {
+			t.Fatal("Expected error when istioctl fails")
+		}
+	})
+}
@@ -0,0 +1,114 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	networkingapi "istio.io/api/networking/v1alpha3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// VerifyCanaryWeight checks that the VirtualService for a host sends the intended percentage of requests to a
+// subset: every HTTP route of the mesh VirtualService routing to the host is checked, a route with a single
+// destination sends it all requests. It also checks the DestinationRule applying to the host for clients in the
+// namespace defines the subset, since requests routed to an undefined subset fail with 503.
+func (i *Istio) VerifyCanaryWeight(ctx context.Context, namespace, host, subset string, expectedWeight int) (string, error) {
+	if expectedWeight < 0 || expectedWeight > 100 {
+		return "", fmt.Errorf("expected weight must be between 0 and 100, got %d", expectedWeight)
+	}
+	target, err := i.resolveHost(ctx, host, namespace)
+	if err != nil {
+		return "", err
+	}
+	mesh, err := i.getMeshConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+	vsList, err := i.istioClient.NetworkingV1alpha3().VirtualServices("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list virtual services: %w", i.explainAPIError(ctx, err, "list", "virtualservices", ""))
+	}
+	drList, err := i.istioClient.NetworkingV1alpha3().DestinationRules("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list destination rules: %w", explainForbidden(err, "list", "destinationrules", ""))
+	}
+
+	result := fmt.Sprintf("Canary weight of subset '%s' of %s (intended %d%%):\n\n", subset, target, expectedWeight)
+	discrepancies := 0
+
+	virtualServices := meshVirtualServicesForHost(vsList.Items, namespace, target, mesh)
+	if len(virtualServices) == 0 {
+		discrepancies++
+		result += fmt.Sprintf("[FAIL] No VirtualService defines the host; requests are spread across all endpoints and subset '%s' gets no dedicated share\n", subset)
+	} else {
+		vs := virtualServices[0]
+		result += fmt.Sprintf("VirtualService '%s/%s':\n", vs.Namespace, vs.Name)
+		checked := 0
+		for idx, route := range vs.Spec.GetHttp() {
+			routesToHost := slices.ContainsFunc(route.GetRoute(), func(rd *networkingapi.HTTPRouteDestination) bool {
+				return qualifiedHost(rd.GetDestination().GetHost(), vs.Namespace) == target
+			})
+			if !routesToHost {
+				continue
+			}
+			checked++
+			weight := int32(0)
+			var parts []string
+			for _, rd := range route.GetRoute() {
+				destinationWeight := rd.GetWeight()
+				// A single destination receives all requests whatever its weight
+				if len(route.GetRoute()) == 1 {
+					destinationWeight = 100
+				}
+				if rd.GetDestination().GetSubset() == subset && qualifiedHost(rd.GetDestination().GetHost(), vs.Namespace) == target {
+					weight += destinationWeight
+				}
+				parts = append(parts, fmt.Sprintf("%s=%d", describeDestination(rd.GetDestination()), destinationWeight))
+			}
+			if int(weight) == expectedWeight {
+				result += fmt.Sprintf("  [OK] Route %s: subset '%s' receives %d%% (%s)\n", httpRouteName(route, idx), subset, weight, strings.Join(parts, ", "))
+			} else {
+				discrepancies++
+				result += fmt.Sprintf("  [FAIL] Route %s: subset '%s' receives %d%%, intended %d%% (%s)\n", httpRouteName(route, idx), subset, weight, expectedWeight, strings.Join(parts, ", "))
+			}
+		}
+		if checked == 0 {
+			discrepancies++
+			result += "  [FAIL] No HTTP route of the VirtualService routes to the host\n"
+		}
+		if len(virtualServices) > 1 {
+			result += fmt.Sprintf("  [WARNING] %d VirtualServices define this host; only the first is considered\n", len(virtualServices))
+		}
+	}
+
+	// The DestinationRule with the highest precedence for clients in the namespace defines the subsets
+	rule := effectiveDestinationRule(drList.Items, target, namespace, mesh)
+	result += "\n"
+	var defined *networkingapi.Subset
+	if rule != nil {
+		for _, s := range rule.Spec.GetSubsets() {
+			if s.GetName() == subset {
+				defined = s
+			}
+		}
+	}
+	switch {
+	case rule == nil:
+		discrepancies++
+		result += fmt.Sprintf("[FAIL] No DestinationRule for the host defines subset '%s'; requests routed to it fail with 503\n", subset)
+	case defined == nil:
+		discrepancies++
+		result += fmt.Sprintf("[FAIL] DestinationRule '%s/%s' does not define subset '%s'; requests routed to it fail with 503\n", rule.Namespace, rule.Name, subset)
+	default:
+		result += fmt.Sprintf("[OK] DestinationRule '%s/%s' defines subset '%s' (labels %v)\n", rule.Namespace, rule.Name, subset, defined.GetLabels())
+	}
+
+	if discrepancies == 0 {
+		result += fmt.Sprintf("\n[RESULT] Subset '%s' receives the intended %d%% of requests\n", subset, expectedWeight)
+	} else {
+		result += fmt.Sprintf("\n[RESULT] %d discrepancies with the intended rollout\n", discrepancies)
+	}
+	return result, nil
+}
@@ -0,0 +1,53 @@
+package istio
+
+import (
+	"context"
+	"testing"
+)
+
+// TestVerifyCanaryWeight tests that the weight routed to a canary subset is compared with the intended percentage
+func TestVerifyCanaryWeight(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{
+		"/apis/networking.istio.io/v1alpha3/virtualservices": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "VirtualServiceList",
+			"items": [
+				{"metadata": {"name": "reviews", "namespace": "default"}, "spec": {
+					"hosts": ["reviews"],
+					"http": [{"route": [
+						{"destination": {"host": "reviews", "subset": "v1"}, "weight": 80},
+						{"destination": {"host": "reviews", "subset": "v2"}, "weight": 20}
+					]}]
+				}}
+			]
+		}`,
+		"/apis/networking.istio.io/v1alpha3/destinationrules": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "DestinationRuleList",
+			"items": [
+				{"metadata": {"name": "reviews", "namespace": "default"}, "spec": {
+					"host": "reviews",
+					"subsets": [{"name": "v1", "labels": {"version": "v1"}}, {"name": "v2", "labels": {"version": "v2"}}]
+				}}
+			]
+		}`,
+	})
+	defer mockServer.Close()
+	istio := newTestIstio(t, mockServer.URL)
+
+	result, err := istio.VerifyCanaryWeight(context.Background(), "default", "reviews", "v2", 10)
+	if err != nil {
+		t.Fatalf("VerifyCanaryWeight failed: %v", err)
+	}
+	assertContains(t, result,
+		"[FAIL] Route #1: subset 'v2' receives 20%, intended 10% (reviews (subset v1)=80, reviews (subset v2)=20)",
+		"[OK] DestinationRule 'default/reviews' defines subset 'v2'",
+		"[RESULT] 1 discrepancies with the intended rollout",
+	)
+
+	result, err = istio.VerifyCanaryWeight(context.Background(), "default", "reviews", "v2", 20)
+	if err != nil {
+		t.Fatalf("VerifyCanaryWeight failed: %v", err)
+	}
+	assertContains(t, result, "[RESULT] Subset 'v2' receives the intended 20% of requests")
+}
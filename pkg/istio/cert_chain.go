@@ -0,0 +1,142 @@
+package istio
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// certExpiryWarning is how close to expiry a workload certificate is flagged; Istio rotates them well before
+const certExpiryWarning = 2 * time.Hour
+
+// secretsConfigDump is the subset of the JSON output of istioctl proxy-config secret used to decode certificates.
+// Private keys are never read.
+type secretsConfigDump struct {
+	DynamicActiveSecrets []struct {
+		Name   string `json:"name"`
+		Secret struct {
+			TLSCertificate struct {
+				CertificateChain struct {
+					InlineBytes string `json:"inlineBytes"`
+				} `json:"certificateChain"`
+			} `json:"tlsCertificate"`
+			ValidationContext struct {
+				TrustedCA struct {
+					InlineBytes string `json:"inlineBytes"`
+				} `json:"trustedCa"`
+			} `json:"validationContext"`
+		} `json:"secret"`
+	} `json:"dynamicActiveSecrets"`
+}
+
+// parseCertificates decodes the base64-encoded PEM bundle of an Envoy secret into its certificates
+func parseCertificates(inlineBytes string) ([]*x509.Certificate, error) {
+	data, err := base64.StdEncoding.DecodeString(inlineBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode certificate bytes: %w", err)
+	}
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse certificate: %w", err)
+		}
+		certs = append(certs, cert)
+	}
+	return certs, nil
+}
+
+// describeCertificate renders the identity, issuer and validity of a certificate
+func describeCertificate(cert *x509.Certificate) string {
+	var sans []string
+	for _, uri := range cert.URIs {
+		sans = append(sans, uri.String())
+	}
+	sans = append(sans, cert.DNSNames...)
+	description := fmt.Sprintf("  Subject: %s\n", cert.Subject)
+	if len(sans) > 0 {
+		description += fmt.Sprintf("  SAN: %s\n", strings.Join(sans, ", "))
+	}
+	description += fmt.Sprintf("  Issuer: %s\n", cert.Issuer)
+	description += fmt.Sprintf("  Serial: %s\n", cert.SerialNumber.Text(16))
+	description += fmt.Sprintf("  Valid from: %s\n", cert.NotBefore.UTC().Format(time.RFC3339))
+	description += fmt.Sprintf("  Valid until: %s\n", cert.NotAfter.UTC().Format(time.RFC3339))
+	return description
+}
+
+// GetWorkloadCertChain decodes the workload certificate chain a pod's proxy presents in mTLS connections from
+// istioctl proxy-config secret: the SPIFFE SAN, issuer, validity and chain depth, and the root CA it trusts.
+// Private key material is never read or returned.
+func (p *ProxyConfigClient) GetWorkloadCertChain(ctx context.Context, namespace, podName string) (string, error) {
+	output, err := p.GetSecret(ctx, namespace, podName)
+	if err != nil {
+		return "", err
+	}
+	var dump secretsConfigDump
+	if err := json.Unmarshal([]byte(output), &dump); err != nil {
+		return "", fmt.Errorf("failed to parse secrets of pod %s: %w", podName, err)
+	}
+
+	now := time.Now()
+	result := fmt.Sprintf("Workload certificate chain of pod '%s' in namespace '%s':\n\n", podName, namespace)
+	found := false
+	for _, secret := range dump.DynamicActiveSecrets {
+		if chain := secret.Secret.TLSCertificate.CertificateChain.InlineBytes; chain != "" {
+			certs, err := parseCertificates(chain)
+			if err != nil {
+				return "", fmt.Errorf("failed to decode secret %s of pod %s: %w", secret.Name, podName, err)
+			}
+			if len(certs) == 0 {
+				continue
+			}
+			found = true
+			leaf := certs[0]
+			result += fmt.Sprintf("Secret '%s' (chain depth: %d)\n", secret.Name, len(certs))
+			result += "Workload certificate:\n" + describeCertificate(leaf)
+			for idx, cert := range certs[1:] {
+				result += fmt.Sprintf("Intermediate %d:\n  Subject: %s\n  Issuer: %s\n  Valid until: %s\n", idx+1, cert.Subject, cert.Issuer, cert.NotAfter.UTC().Format(time.RFC3339))
+			}
+			switch remaining := leaf.NotAfter.Sub(now); {
+			case now.Before(leaf.NotBefore):
+				result += "[ERROR] The certificate is not valid yet; check the clocks of the node and istiod\n"
+			case remaining <= 0:
+				result += fmt.Sprintf("[ERROR] The certificate expired %s ago; the proxy failed to rotate it\n", (-remaining).Round(time.Second))
+			case remaining < certExpiryWarning:
+				result += fmt.Sprintf("[WARNING] The certificate expires in %s\n", remaining.Round(time.Second))
+			default:
+				result += fmt.Sprintf("[OK] The certificate is valid for another %s\n", remaining.Round(time.Minute))
+			}
+			if len(leaf.URIs) == 0 {
+				result += "[WARNING] The certificate has no SPIFFE URI SAN, so peers cannot derive the workload identity\n"
+			}
+			result += "\n"
+		}
+		if trusted := secret.Secret.ValidationContext.TrustedCA.InlineBytes; trusted != "" {
+			roots, err := parseCertificates(trusted)
+			if err != nil {
+				return "", fmt.Errorf("failed to decode secret %s of pod %s: %w", secret.Name, podName, err)
+			}
+			for _, root := range roots {
+				result += fmt.Sprintf("Trusted root CA (secret '%s'):\n  Subject: %s\n  Valid until: %s\n\n", secret.Name, root.Subject, root.NotAfter.UTC().Format(time.RFC3339))
+			}
+		}
+	}
+
+	if !found {
+		result += "[WARNING] The proxy has no workload certificate; it may still be starting or not use mTLS\n"
+	}
+	return result, nil
+}
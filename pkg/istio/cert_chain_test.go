@@ -0,0 +1,92 @@
+package istio
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// TestGetWorkloadCertChain tests decoding the SPIFFE SAN and validity of a proxy's workload certificate
+func TestGetWorkloadCertChain(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	notBefore := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{Organization: []string{"cluster.local"}},
+		NotBefore:             notBefore,
+		NotAfter:              notBefore.AddDate(10, 0, 0),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create CA certificate: %v", err)
+	}
+	spiffe, _ := url.Parse("spiffe://cluster.local/ns/bookinfo/sa/bookinfo-reviews")
+	leafTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		URIs:         []*url.URL{spiffe},
+		NotBefore:    notBefore,
+		NotAfter:     notBefore.Add(24 * time.Hour),
+	}
+	leafDER, err := x509.CreateCertificate(rand.Reader, leafTemplate, caTemplate, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create workload certificate: %v", err)
+	}
+	encode := func(ders ...[]byte) string {
+		var bundle []byte
+		for _, der := range ders {
+			bundle = append(bundle, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})...)
+		}
+		return base64.StdEncoding.EncodeToString(bundle)
+	}
+
+	client := NewProxyConfigClient("")
+	stubIstioctl(client, fmt.Sprintf(`{
+		"dynamicActiveSecrets": [
+			{
+				"name": "default",
+				"secret": {
+					"name": "default",
+					"tlsCertificate": {
+						"certificateChain": {"inlineBytes": %q},
+						"privateKey": {"inlineString": "[redacted]"}
+					}
+				}
+			},
+			{
+				"name": "ROOTCA",
+				"secret": {"name": "ROOTCA", "validationContext": {"trustedCa": {"inlineBytes": %q}}}
+			}
+		]
+	}`, encode(leafDER, caDER), encode(caDER)))
+
+	result, err := client.GetWorkloadCertChain(context.Background(), "bookinfo", "reviews-v1-abc")
+	if err != nil {
+		t.Fatalf("Failed to get workload cert chain: %v", err)
+	}
+	assertContains(t, result,
+		"Secret 'default' (chain depth: 2)",
+		"SAN: spiffe://cluster.local/ns/bookinfo/sa/bookinfo-reviews",
+		"Issuer: O=cluster.local",
+		"Valid from: 2026-01-01T00:00:00Z",
+		"Valid until: 2026-01-02T00:00:00Z",
+		"[ERROR] The certificate expired",
+		"Trusted root CA (secret 'ROOTCA')",
+	)
+	assertNotContains(t, result, "redacted", "privateKey")
+}
@@ -0,0 +1,190 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// circuitBreakerGauges are the Envoy gauges that are 1 while a circuit breaker of a cluster is open
+var circuitBreakerGauges = []string{"cx_open", "cx_pool_open", "rq_open", "rq_pending_open", "rq_retry_open"}
+
+// clusterBreakerState is the live circuit breaker and outlier detection state of an Envoy cluster
+type clusterBreakerState struct {
+	open            []string
+	thresholds      map[string]string
+	ejectionsActive int
+	ejectionsTotal  int
+	hosts           int
+	ejectedHosts    []string
+}
+
+// parseAdminClusters reads the text output of Envoy's /clusters admin endpoint, with lines like
+// 'cluster::default_priority::max_connections::1024' and 'cluster::10.0.0.5:9080::health_flags::/failed_outlier_check'
+func parseAdminClusters(output string, states map[string]*clusterBreakerState) {
+	for _, line := range strings.Split(output, "\n") {
+		parts := strings.Split(strings.TrimSpace(line), "::")
+		if len(parts) != 4 {
+			continue
+		}
+		cluster, scope, field, value := parts[0], parts[1], parts[2], parts[3]
+		state := clusterState(states, cluster)
+		switch {
+		case scope == "default_priority" && strings.HasPrefix(field, "max_"):
+			state.thresholds[field] = value
+		case strings.Contains(scope, ":") && field == "cx_active":
+			// every host reports cx_active exactly once
+			state.hosts++
+		case strings.Contains(scope, ":") && field == "health_flags" && strings.Contains(value, "failed_outlier_check"):
+			state.ejectedHosts = append(state.ejectedHosts, scope)
+		}
+	}
+}
+
+// parseClusterStats reads the circuit breaker gauges and outlier detection counters from Envoy's /stats output
+func parseClusterStats(output string, states map[string]*clusterBreakerState) {
+	for _, line := range strings.Split(output, "\n") {
+		name, value, found := strings.Cut(strings.TrimSpace(line), ": ")
+		if !found || !strings.HasPrefix(name, "cluster.") {
+			continue
+		}
+		name = strings.TrimPrefix(name, "cluster.")
+		count, err := strconv.Atoi(value)
+		if err != nil {
+			continue
+		}
+		if cluster, stat, found := strings.Cut(name, ".circuit_breakers.default."); found {
+			state := clusterState(states, cluster)
+			for _, gauge := range circuitBreakerGauges {
+				if stat == gauge && count > 0 {
+					state.open = append(state.open, gauge)
+				}
+			}
+			continue
+		}
+		if cluster, stat, found := strings.Cut(name, ".outlier_detection."); found {
+			state := clusterState(states, cluster)
+			switch stat {
+			case "ejections_active":
+				state.ejectionsActive = count
+			case "ejections_enforced_total":
+				state.ejectionsTotal = count
+			}
+		}
+	}
+}
+
+// clusterState returns the state of a cluster, creating it on first use
+func clusterState(states map[string]*clusterBreakerState, cluster string) *clusterBreakerState {
+	state, ok := states[cluster]
+	if !ok {
+		state = &clusterBreakerState{thresholds: make(map[string]string)}
+		states[cluster] = state
+	}
+	return state
+}
+
+// getClusterBreakerStates reads the live circuit breaker and outlier detection state of every cluster of a proxy
+// from its /clusters and /stats admin output, returning the states and the sorted cluster names
+func (p *ProxyConfigClient) getClusterBreakerStates(ctx context.Context, proxy string) (map[string]*clusterBreakerState, []string, error) {
+	clusters, err := p.execIstioctl(ctx, "experimental", "envoy-stats", proxy, "--type", "clusters")
+	if err != nil {
+		return nil, nil, err
+	}
+	stats, err := p.execIstioctl(ctx, "experimental", "envoy-stats", proxy, "--type", "server")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	states := make(map[string]*clusterBreakerState)
+	parseAdminClusters(clusters, states)
+	parseClusterStats(stats, states)
+
+	names := make([]string, 0, len(states))
+	for name := range states {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return states, names, nil
+}
+
+// ejectionSummary describes the hosts of a cluster that outlier detection has ejected
+func ejectionSummary(name string, state *clusterBreakerState) string {
+	summary := fmt.Sprintf("[EJECTED] %s: %d of %d hosts ejected by outlier detection (%d ejections in total)", name, max(state.ejectionsActive, len(state.ejectedHosts)), state.hosts, state.ejectionsTotal)
+	if len(state.ejectedHosts) > 0 {
+		summary += fmt.Sprintf(": %s", strings.Join(state.ejectedHosts, ", "))
+	}
+	return summary + "\n"
+}
+
+// GetCircuitBreakerState reports which circuit breakers of a pod's Envoy clusters are currently open and which
+// hosts outlier detection has ejected, from the live /stats and /clusters admin output rather than static config
+func (p *ProxyConfigClient) GetCircuitBreakerState(ctx context.Context, namespace, podName string) (string, error) {
+	proxy := fmt.Sprintf("%s.%s", podName, namespace)
+	states, names, err := p.getClusterBreakerStates(ctx, proxy)
+	if err != nil {
+		return "", err
+	}
+
+	result := fmt.Sprintf("Circuit breaker state of proxy '%s':\n\n", proxy)
+	tripped, ejecting := 0, 0
+	for _, name := range names {
+		state := states[name]
+		if len(state.open) > 0 {
+			tripped++
+			result += fmt.Sprintf("[OPEN] %s: %s", name, strings.Join(state.open, ", "))
+			var limits []string
+			for _, field := range []string{"max_connections", "max_pending_requests", "max_requests", "max_retries"} {
+				if value, ok := state.thresholds[field]; ok {
+					limits = append(limits, fmt.Sprintf("%s=%s", field, value))
+				}
+			}
+			if len(limits) > 0 {
+				result += fmt.Sprintf(" (limits: %s)", strings.Join(limits, ", "))
+			}
+			result += "\n"
+		}
+		if state.ejectionsActive > 0 || len(state.ejectedHosts) > 0 {
+			ejecting++
+			result += ejectionSummary(name, state)
+		} else if state.ejectionsTotal > 0 {
+			result += fmt.Sprintf("[WARNING] %s: no hosts ejected now, but %d ejections happened since the proxy started\n", name, state.ejectionsTotal)
+		}
+	}
+
+	if tripped == 0 && ejecting == 0 {
+		result += fmt.Sprintf("[OK] No circuit breaker is open and no host is ejected across %d clusters\n", len(names))
+	} else {
+		result += fmt.Sprintf("\n[RESULT] %d of %d clusters have an open circuit breaker, %d are ejecting hosts\n", tripped, len(names), ejecting)
+	}
+	return result, nil
+}
+
+// GetEjectedClusters lists only the clusters of a pod's proxy where outlier detection is currently ejecting
+// hosts (nonzero ejections_active or hosts flagged failed_outlier_check), pinpointing failing upstream services
+func (p *ProxyConfigClient) GetEjectedClusters(ctx context.Context, namespace, podName string) (string, error) {
+	proxy := fmt.Sprintf("%s.%s", podName, namespace)
+	states, names, err := p.getClusterBreakerStates(ctx, proxy)
+	if err != nil {
+		return "", err
+	}
+
+	result := fmt.Sprintf("Clusters of proxy '%s' with hosts ejected by outlier detection:\n\n", proxy)
+	ejecting := 0
+	for _, name := range names {
+		state := states[name]
+		if state.ejectionsActive > 0 || len(state.ejectedHosts) > 0 {
+			ejecting++
+			result += ejectionSummary(name, state)
+		}
+	}
+
+	if ejecting == 0 {
+		result += fmt.Sprintf("[OK] No host is ejected by outlier detection across %d clusters\n", len(names))
+	} else {
+		result += fmt.Sprintf("\n[RESULT] %d of %d clusters are ejecting hosts\n", ejecting, len(names))
+	}
+	return result, nil
+}
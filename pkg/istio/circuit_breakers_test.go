@@ -0,0 +1,78 @@
+package istio
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+// TestGetCircuitBreakerState tests reporting of open circuit breakers and ejected hosts from Envoy admin output
+func TestGetCircuitBreakerState(t *testing.T) {
+	client := NewProxyConfigClient("")
+	client.execCommand = func(ctx context.Context, args ...string) ([]byte, error) {
+		if slices.Contains(args, "clusters") {
+			return []byte(`outbound|9080||reviews.default.svc.cluster.local::observability_name::outbound|9080||reviews.default.svc.cluster.local
+outbound|9080||reviews.default.svc.cluster.local::default_priority::max_connections::1
+outbound|9080||reviews.default.svc.cluster.local::default_priority::max_pending_requests::1
+outbound|9080||reviews.default.svc.cluster.local::10.244.0.12:9080::cx_active::1
+outbound|9080||reviews.default.svc.cluster.local::10.244.0.12:9080::health_flags::healthy
+outbound|9080||reviews.default.svc.cluster.local::10.244.0.13:9080::cx_active::0
+outbound|9080||reviews.default.svc.cluster.local::10.244.0.13:9080::health_flags::/failed_outlier_check
+outbound|9080||ratings.default.svc.cluster.local::default_priority::max_connections::4294967295
+outbound|9080||ratings.default.svc.cluster.local::10.244.0.20:9080::cx_active::2
+outbound|9080||ratings.default.svc.cluster.local::10.244.0.20:9080::health_flags::healthy
+`), nil
+		}
+		return []byte(`cluster.outbound|9080||reviews.default.svc.cluster.local.circuit_breakers.default.cx_open: 1
+cluster.outbound|9080||reviews.default.svc.cluster.local.circuit_breakers.default.rq_pending_open: 1
+cluster.outbound|9080||reviews.default.svc.cluster.local.circuit_breakers.default.rq_open: 0
+cluster.outbound|9080||reviews.default.svc.cluster.local.outlier_detection.ejections_active: 1
+cluster.outbound|9080||reviews.default.svc.cluster.local.outlier_detection.ejections_enforced_total: 3
+cluster.outbound|9080||ratings.default.svc.cluster.local.circuit_breakers.default.cx_open: 0
+cluster.outbound|9080||ratings.default.svc.cluster.local.outlier_detection.ejections_active: 0
+`), nil
+	}
+
+	result, err := client.GetCircuitBreakerState(context.Background(), "default", "productpage-v1")
+	if err != nil {
+		t.Fatalf("Failed to get circuit breaker state: %v", err)
+	}
+	assertContains(t, result,
+		"[OPEN] outbound|9080||reviews.default.svc.cluster.local: cx_open, rq_pending_open (limits: max_connections=1, max_pending_requests=1)",
+		"[EJECTED] outbound|9080||reviews.default.svc.cluster.local: 1 of 2 hosts ejected by outlier detection (3 ejections in total): 10.244.0.13:9080",
+		"[RESULT] 1 of 2 clusters have an open circuit breaker, 1 are ejecting hosts",
+	)
+	assertNotContains(t, result, "rq_open", "ratings.default.svc.cluster.local:")
+}
+
+// TestGetEjectedClusters tests that only clusters with active outlier ejections are listed
+func TestGetEjectedClusters(t *testing.T) {
+	client := NewProxyConfigClient("")
+	client.execCommand = func(ctx context.Context, args ...string) ([]byte, error) {
+		if slices.Contains(args, "clusters") {
+			return []byte(`outbound|9080||reviews.default.svc.cluster.local::10.244.0.12:9080::cx_active::1
+outbound|9080||reviews.default.svc.cluster.local::10.244.0.12:9080::health_flags::healthy
+outbound|9080||reviews.default.svc.cluster.local::10.244.0.13:9080::cx_active::0
+outbound|9080||reviews.default.svc.cluster.local::10.244.0.13:9080::health_flags::healthy
+outbound|9080||ratings.default.svc.cluster.local::10.244.0.20:9080::cx_active::2
+outbound|9080||ratings.default.svc.cluster.local::10.244.0.20:9080::health_flags::healthy
+`), nil
+		}
+		return []byte(`cluster.outbound|9080||reviews.default.svc.cluster.local.circuit_breakers.default.cx_open: 1
+cluster.outbound|9080||reviews.default.svc.cluster.local.outlier_detection.ejections_active: 1
+cluster.outbound|9080||reviews.default.svc.cluster.local.outlier_detection.ejections_enforced_total: 2
+cluster.outbound|9080||ratings.default.svc.cluster.local.outlier_detection.ejections_active: 0
+cluster.outbound|9080||ratings.default.svc.cluster.local.outlier_detection.ejections_enforced_total: 5
+`), nil
+	}
+
+	result, err := client.GetEjectedClusters(context.Background(), "default", "productpage-v1")
+	if err != nil {
+		t.Fatalf("Failed to get ejected clusters: %v", err)
+	}
+	assertContains(t, result,
+		"[EJECTED] outbound|9080||reviews.default.svc.cluster.local: 1 of 2 hosts ejected by outlier detection (2 ejections in total)",
+		"[RESULT] 1 of 2 clusters are ejecting hosts",
+	)
+	assertNotContains(t, result, "ratings.default.svc.cluster.local", "cx_open")
+}
@@ -0,0 +1,77 @@
+package istio
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newMockAPIServer creates a mock Kubernetes API server that serves the given JSON
+// responses keyed by request path (query parameters are ignored). Unknown paths
+// return a 404 Status object, like the real API server does for missing resources.
+func newMockAPIServer(responses map[string]string) *httptest.Server {
+	return httptest.NewServer(mockAPIHandler(responses))
+}
+
+// mockAPIHandler serves the responses of newMockAPIServer, for tests that wrap it to inspect requests
+func mockAPIHandler(responses map[string]string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if response, ok := responses[r.URL.Path]; ok {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(response))
+			return
+		}
+		switch r.URL.Path {
+		case "/api":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"kind":"APIVersions","versions":["v1"]}`))
+		case "/apis":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"kind":"APIGroupList","groups":[]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"NotFound","code":404,"message":"the server could not find the requested resource"}`))
+		}
+	})
+	return mux
+}
+
+// newTestIstio creates an Istio client backed by a kubeconfig pointing at the given server
+func newTestIstio(t *testing.T, serverURL string) *Istio {
+	t.Helper()
+	kubeconfigPath := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(kubeconfigPath, []byte(createTestKubeconfigForVS(serverURL)), 0644); err != nil {
+		t.Fatalf("Failed to write kubeconfig: %v", err)
+	}
+	istio, err := NewIstio(kubeconfigPath)
+	if err != nil {
+		t.Fatalf("Failed to create Istio client: %v", err)
+	}
+	t.Cleanup(istio.Close)
+	return istio
+}
+
+// assertContains fails the test if result doesn't contain every expected pattern
+func assertContains(t *testing.T, result string, patterns ...string) {
+	t.Helper()
+	for _, pattern := range patterns {
+		if !strings.Contains(result, pattern) {
+			t.Errorf("Expected result to contain '%s', got: %s", pattern, result)
+		}
+	}
+}
+
+// assertNotContains fails the test if result contains any of the given patterns
+func assertNotContains(t *testing.T, result string, patterns ...string) {
+	t.Helper()
+	for _, pattern := range patterns {
+		if strings.Contains(result, pattern) {
+			t.Errorf("Expected result not to contain '%s', got: %s", pattern, result)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package istio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// bootstrapProxyConfig holds the part of an istioctl bootstrap dump with the effective ProxyConfig of the proxy
+type bootstrapProxyConfig struct {
+	Bootstrap struct {
+		Node struct {
+			Metadata struct {
+				ProxyConfig struct {
+					Concurrency *int `json:"concurrency"`
+				} `json:"PROXY_CONFIG"`
+			} `json:"metadata"`
+		} `json:"node"`
+	} `json:"bootstrap"`
+}
+
+// istioProxyContainer returns the istio-proxy container of a pod, either a regular or a native sidecar container
+func istioProxyContainer(pod *v1.Pod) *v1.Container {
+	for idx := range pod.Spec.Containers {
+		if pod.Spec.Containers[idx].Name == "istio-proxy" {
+			return &pod.Spec.Containers[idx]
+		}
+	}
+	for idx := range pod.Spec.InitContainers {
+		if pod.Spec.InitContainers[idx].Name == "istio-proxy" {
+			return &pod.Spec.InitContainers[idx]
+		}
+	}
+	return nil
+}
+
+// containerArgConcurrency returns the --concurrency argument passed to the proxy container, if any
+func containerArgConcurrency(container *v1.Container) (int, bool) {
+	for idx, arg := range container.Args {
+		if arg == "--concurrency" && idx+1 < len(container.Args) {
+			if n, err := strconv.Atoi(container.Args[idx+1]); err == nil {
+				return n, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// resourceOrUnset formats a resource quantity, or "not set" when the resource list doesn't define it
+func resourceOrUnset(resources v1.ResourceList, name v1.ResourceName) string {
+	if quantity, ok := resources[name]; ok {
+		return quantity.String()
+	}
+	return "not set"
+}
+
+// GetProxyConcurrency reports the number of Envoy worker threads of a pod's proxy together with the proxy's
+// CPU and memory resources, warning when the worker threads can't all be scheduled within the CPU limit
+func (i *Istio) GetProxyConcurrency(ctx context.Context, namespace, podName string) (string, error) {
+	pod, err := i.kubeClient.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get pod %s: %w", podName, explainForbidden(err, "get", "pods", namespace))
+	}
+	container := istioProxyContainer(pod)
+	if container == nil {
+		return "", fmt.Errorf("pod %s in namespace %s has no istio-proxy container", podName, namespace)
+	}
+
+	bootstrap, err := i.ProxyConfig.GetBootstrap(ctx, namespace, podName)
+	if err != nil {
+		return "", fmt.Errorf("failed to get bootstrap of pod %s: %w", podName, err)
+	}
+	var config bootstrapProxyConfig
+	if err := json.Unmarshal([]byte(bootstrap), &config); err != nil {
+		return "", fmt.Errorf("failed to parse bootstrap of pod %s: %w", podName, err)
+	}
+
+	concurrency, source := 0, ""
+	if c := config.Bootstrap.Node.Metadata.ProxyConfig.Concurrency; c != nil {
+		concurrency, source = *c, "proxy config"
+	} else if c, ok := containerArgConcurrency(container); ok {
+		concurrency, source = c, "--concurrency argument"
+	}
+
+	result := fmt.Sprintf("Proxy concurrency for pod '%s' in namespace '%s':\n\n", podName, namespace)
+	switch {
+	case source == "":
+		result += "Concurrency: not set (worker threads are sized from the CPU resources, or all node cores without a CPU limit)\n"
+	case concurrency == 0:
+		result += fmt.Sprintf("Concurrency: 0 from %s (one worker thread per node core)\n", source)
+	default:
+		result += fmt.Sprintf("Concurrency: %d worker threads (from %s)\n", concurrency, source)
+	}
+	result += fmt.Sprintf("CPU request: %s, limit: %s\n",
+		resourceOrUnset(container.Resources.Requests, v1.ResourceCPU), resourceOrUnset(container.Resources.Limits, v1.ResourceCPU))
+	result += fmt.Sprintf("Memory request: %s, limit: %s\n",
+		resourceOrUnset(container.Resources.Requests, v1.ResourceMemory), resourceOrUnset(container.Resources.Limits, v1.ResourceMemory))
+	result += "\n"
+
+	cpuLimit, hasCPULimit := container.Resources.Limits[v1.ResourceCPU]
+	switch {
+	case source != "" && concurrency == 0:
+		result += "[WARNING] Concurrency 0 starts a worker thread per node core regardless of the CPU limit; set proxy.istio.io/config concurrency on large nodes\n"
+	case source == "" && !hasCPULimit:
+		result += "[WARNING] Neither concurrency nor a CPU limit is set, so Envoy starts a worker thread per node core; set proxy.istio.io/config concurrency on large nodes\n"
+	case hasCPULimit && concurrency > int(math.Ceil(float64(cpuLimit.MilliValue())/1000)):
+		result += fmt.Sprintf("[WARNING] Concurrency %d exceeds the CPU limit of %s, so worker threads compete for CPU and are throttled; lower concurrency or raise the limit\n",
+			concurrency, cpuLimit.String())
+	default:
+		result += "[OK] Concurrency fits the proxy's CPU resources\n"
+	}
+	return result, nil
+}
@@ -0,0 +1,67 @@
+package istio
+
+import (
+	"context"
+	"testing"
+)
+
+// TestGetProxyConcurrency tests reporting of the worker threads of a proxy against its CPU limit
+func TestGetProxyConcurrency(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{
+		"/api/v1/namespaces/bookinfo/pods/reviews-v1-abc": `{
+			"apiVersion": "v1",
+			"kind": "Pod",
+			"metadata": {"name": "reviews-v1-abc", "namespace": "bookinfo"},
+			"spec": {"containers": [
+				{"name": "reviews"},
+				{"name": "istio-proxy", "args": ["proxy", "sidecar", "--concurrency", "2"], "resources": {
+					"requests": {"cpu": "100m", "memory": "128Mi"},
+					"limits": {"cpu": "500m", "memory": "1Gi"}
+				}}
+			]}
+		}`,
+		"/api/v1/namespaces/bookinfo/pods/ratings-v1-def": `{
+			"apiVersion": "v1",
+			"kind": "Pod",
+			"metadata": {"name": "ratings-v1-def", "namespace": "bookinfo"},
+			"spec": {"containers": [{"name": "ratings"}]}
+		}`,
+	})
+	defer mockServer.Close()
+
+	istio := newTestIstio(t, mockServer.URL)
+	ctx := context.Background()
+
+	t.Run("concurrency above the CPU limit is flagged", func(t *testing.T) {
+		stubIstioctl(istio.ProxyConfig, `{"bootstrap": {"node": {"id": "sidecar~10.0.0.1~reviews-v1-abc.bookinfo~bookinfo.svc.cluster.local", "metadata": {"PROXY_CONFIG": {"concurrency": 4, "discoveryAddress": "istiod.istio-system.svc:15012"}}}}}`)
+		istio.ProxyConfig.SetCacheTTL(0)
+
+		result, err := istio.GetProxyConcurrency(ctx, "bookinfo", "reviews-v1-abc")
+		if err != nil {
+			t.Fatalf("GetProxyConcurrency failed: %v", err)
+		}
+		assertContains(t, result,
+			"Concurrency: 4 worker threads (from proxy config)",
+			"CPU request: 100m, limit: 500m",
+			"Memory request: 128Mi, limit: 1Gi",
+			"[WARNING] Concurrency 4 exceeds the CPU limit of 500m",
+		)
+	})
+
+	t.Run("container argument is used when the bootstrap has no concurrency", func(t *testing.T) {
+		stubIstioctl(istio.ProxyConfig, `{"bootstrap": {"node": {"metadata": {"PROXY_CONFIG": {}}}}}`)
+		istio.ProxyConfig.SetCacheTTL(0)
+
+		result, err := istio.GetProxyConcurrency(ctx, "bookinfo", "reviews-v1-abc")
+		if err != nil {
+			t.Fatalf("GetProxyConcurrency failed: %v", err)
+		}
+		assertContains(t, result, "Concurrency: 2 worker threads (from --concurrency argument)", "[WARNING] Concurrency 2 exceeds the CPU limit of 500m")
+	})
+
+	t.Run("pod without a proxy is an error", func(t *testing.T) {
+		if _, err := istio.GetProxyConcurrency(ctx, "bookinfo", "ratings-v1-def"); err == nil {
+			t.Fatal("Expected an error for a pod without istio-proxy")
+		}
+	})
+}
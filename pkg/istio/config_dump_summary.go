@@ -0,0 +1,104 @@
+package istio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	// ConfigDumpSummaryThreshold is the size in bytes above which a config dump is summarized unless the full dump is requested
+	ConfigDumpSummaryThreshold = 256 * 1024
+	// configDumpSummaryNames is the number of resource names listed per section of a config dump summary
+	configDumpSummaryNames = 20
+)
+
+// GetConfigDumpLimited retrieves the configuration dump from a pod's Envoy proxy, returning a summary of it
+// instead when it is larger than ConfigDumpSummaryThreshold, so that a dump of several megabytes is only
+// returned when explicitly requested through GetConfigDump
+func (p *ProxyConfigClient) GetConfigDumpLimited(ctx context.Context, namespace, podName string) (string, error) {
+	dump, err := p.GetConfigDump(ctx, namespace, podName)
+	if err != nil {
+		return "", err
+	}
+	if len(dump) <= ConfigDumpSummaryThreshold {
+		return dump, nil
+	}
+	summary, err := summarizeConfigDump(dump)
+	if err != nil {
+		return "", err
+	}
+	result := fmt.Sprintf("The config dump of pod '%s' in namespace '%s' is %d bytes, more than the %d bytes returned by default; showing a summary.\n", podName, namespace, len(dump), ConfigDumpSummaryThreshold)
+	result += "Set full=true for the complete dump, or path to extract a part of it (e.g. 'configs.dynamic_listeners').\n\n"
+	return result + summary, nil
+}
+
+// summarizeConfigDump lists, for each section of an Envoy config dump, the number of resources of each list with their names
+func summarizeConfigDump(dump string) (string, error) {
+	var data struct {
+		Configs []map[string]interface{} `json:"configs"`
+	}
+	if err := json.Unmarshal([]byte(dump), &data); err != nil {
+		return "", fmt.Errorf("failed to parse config dump: %w", err)
+	}
+
+	var result string
+	for _, config := range data.Configs {
+		configType, _ := config["@type"].(string)
+		result += configType[strings.LastIndex(configType, ".")+1:] + ":\n"
+
+		if bootstrap, ok := config["bootstrap"].(map[string]interface{}); ok {
+			if node, ok := bootstrap["node"].(map[string]interface{}); ok {
+				result += fmt.Sprintf("  node: %v\n", node["id"])
+			}
+		}
+
+		keys := make([]string, 0, len(config))
+		for key := range config {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			items, ok := config[key].([]interface{})
+			if !ok {
+				continue
+			}
+			result += fmt.Sprintf("  %s: %d\n", key, len(items))
+			var names []string
+			for _, item := range items {
+				if name := configDumpItemName(item); name != "" {
+					names = append(names, name)
+				}
+			}
+			if len(names) > configDumpSummaryNames {
+				names = append(names[:configDumpSummaryNames], fmt.Sprintf("... and %d more", len(names)-configDumpSummaryNames))
+			}
+			if len(names) > 0 {
+				result += fmt.Sprintf("    %s\n", strings.Join(names, ", "))
+			}
+		}
+	}
+	return result, nil
+}
+
+// configDumpItemName returns the name of a config dump list item, either its own "name" field or the
+// "name" of the resource it wraps (e.g. dynamic_active_clusters[].cluster.name)
+func configDumpItemName(item interface{}) string {
+	fields, ok := item.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	if name, ok := fields["name"].(string); ok {
+		return name
+	}
+	for _, value := range fields {
+		if nested, ok := value.(map[string]interface{}); ok {
+			if name, ok := nested["name"].(string); ok {
+				return name
+			}
+		}
+	}
+	return ""
+}
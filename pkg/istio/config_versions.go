@@ -0,0 +1,136 @@
+package istio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// uuidLength is the length of the random UUID istiod appends to the push version to form an xDS nonce
+const uuidLength = 36
+
+// proxySyncStatus is a row of istioctl proxy-status -o json: the xDS nonces istiod sent to a proxy and the
+// ones the proxy acknowledged
+type proxySyncStatus struct {
+	Proxy         string `json:"proxy"`
+	ClusterSent   string `json:"cluster_sent"`
+	ClusterAcked  string `json:"cluster_acked"`
+	ListenerSent  string `json:"listener_sent"`
+	ListenerAcked string `json:"listener_acked"`
+	RouteSent     string `json:"route_sent"`
+	RouteAcked    string `json:"route_acked"`
+	EndpointSent  string `json:"endpoint_sent"`
+	EndpointAcked string `json:"endpoint_acked"`
+}
+
+// nonceVersion returns the push version of an xDS nonce, which istiod builds as the push version
+// ('<RFC3339 time>/<counter>') followed by a random UUID
+func nonceVersion(nonce string) string {
+	if len(nonce) <= uuidLength {
+		return nonce
+	}
+	suffix := nonce[len(nonce)-uuidLength:]
+	for _, idx := range []int{8, 13, 18, 23} {
+		if suffix[idx] != '-' {
+			return nonce
+		}
+	}
+	return nonce[:len(nonce)-uuidLength]
+}
+
+// parsePushVersion splits an istiod push version ('<RFC3339 time>/<counter>') into its time and counter
+func parsePushVersion(version string) (time.Time, int, bool) {
+	idx := strings.LastIndex(version, "/")
+	if idx < 0 {
+		return time.Time{}, 0, false
+	}
+	pushed, err := time.Parse(time.RFC3339, version[:idx])
+	if err != nil {
+		return time.Time{}, 0, false
+	}
+	counter, err := strconv.Atoi(version[idx+1:])
+	return pushed, counter, err == nil
+}
+
+// pushVersionLess orders push versions by their time, then their counter; versions in another format are
+// compared as strings
+func pushVersionLess(a, b string) bool {
+	timeA, counterA, okA := parsePushVersion(a)
+	timeB, counterB, okB := parsePushVersion(b)
+	switch {
+	case !okA || !okB:
+		return a < b
+	case !timeA.Equal(timeB):
+		return timeA.Before(timeB)
+	default:
+		return counterA < counterB
+	}
+}
+
+// GetConfigVersionDistribution buckets the proxies connected to istiod by the configuration version they last
+// acknowledged, taken from the cluster (CDS) nonce of proxy-status, or the listener (LDS) nonce when there is none.
+// During a rollout the counts show how far a push has propagated; proxies left on an old version are stuck.
+func (i *Istio) GetConfigVersionDistribution(ctx context.Context) (string, error) {
+	output, err := i.ProxyConfig.GetProxyStatusJSON(ctx, "", "")
+	if err != nil {
+		return "", fmt.Errorf("failed to get proxy status: %w", err)
+	}
+	var statuses []proxySyncStatus
+	if err := json.Unmarshal([]byte(output), &statuses); err != nil {
+		return "", fmt.Errorf("failed to parse proxy status: %w", err)
+	}
+	if len(statuses) == 0 {
+		return "No proxies are connected to istiod\n", nil
+	}
+
+	proxiesByVersion := make(map[string][]string)
+	var pending []string
+	for _, status := range statuses {
+		acked := status.ClusterAcked
+		if acked == "" {
+			acked = status.ListenerAcked
+		}
+		version := "(none acknowledged)"
+		if acked != "" {
+			version = nonceVersion(acked)
+		}
+		proxiesByVersion[version] = append(proxiesByVersion[version], status.Proxy)
+		if status.ClusterSent != status.ClusterAcked || status.ListenerSent != status.ListenerAcked {
+			pending = append(pending, status.Proxy)
+		}
+	}
+	versions := make([]string, 0, len(proxiesByVersion))
+	for version := range proxiesByVersion {
+		versions = append(versions, version)
+	}
+	// Newest first
+	sort.Slice(versions, func(a, b int) bool { return pushVersionLess(versions[b], versions[a]) })
+
+	result := fmt.Sprintf("Config version distribution of %d proxies:\n\n", len(statuses))
+	behind := 0
+	for idx, version := range versions {
+		proxies := proxiesByVersion[version]
+		sort.Strings(proxies)
+		if idx == 0 {
+			result += fmt.Sprintf("- %s (latest): %d proxies\n", version, len(proxies))
+			continue
+		}
+		behind += len(proxies)
+		result += fmt.Sprintf("- %s: %d proxies (%s)\n", version, len(proxies), strings.Join(proxies, ", "))
+	}
+	if len(pending) > 0 {
+		sort.Strings(pending)
+		result += fmt.Sprintf("\n[WARNING] %d proxies have not acknowledged the last push sent to them: %s\n", len(pending), strings.Join(pending, ", "))
+	}
+
+	if behind == 0 {
+		result += fmt.Sprintf("\n[OK] All %d proxies are on the latest config version\n", len(statuses))
+	} else {
+		result += fmt.Sprintf("\n[RESULT] %d of %d proxies are not on the latest config version; if they stay behind, check get-proxy-status and get-istiod-logs-for-proxy for rejected pushes\n", behind, len(statuses))
+	}
+	return result, nil
+}
@@ -0,0 +1,32 @@
+package istio
+
+import (
+	"context"
+	"testing"
+)
+
+// TestGetConfigVersionDistribution tests bucketing of proxies by the configuration version they acknowledged
+func TestGetConfigVersionDistribution(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{})
+	defer mockServer.Close()
+
+	istio := newTestIstio(t, mockServer.URL)
+	stubIstioctl(istio.ProxyConfig, `[
+		{"proxy": "productpage-v1-abc.bookinfo", "cluster_sent": "2026-10-16T10:05:00Z/13c7a1e0b2-4d3f-4a8e-9b1c-2f6d8e0a1b3c", "cluster_acked": "2026-10-16T10:05:00Z/13c7a1e0b2-4d3f-4a8e-9b1c-2f6d8e0a1b3c"},
+		{"proxy": "ratings-v1-def.bookinfo", "cluster_sent": "2026-10-16T10:05:00Z/13f1e2d3c4-b5a6-4978-8695-a4b3c2d1e0f9", "cluster_acked": "2026-10-16T10:05:00Z/13f1e2d3c4-b5a6-4978-8695-a4b3c2d1e0f9"},
+		{"proxy": "details-v1-ghi.bookinfo", "cluster_sent": "2026-10-16T10:05:00Z/130a1b2c3d-4e5f-4061-8273-849506a7b8c9", "cluster_acked": "2026-10-16T10:05:00Z/130a1b2c3d-4e5f-4061-8273-849506a7b8c9"},
+		{"proxy": "reviews-v1-jkl.bookinfo", "cluster_sent": "2026-10-16T10:05:00Z/139f8e7d6c-5b4a-4392-8170-6f5e4d3c2b1a", "cluster_acked": "2026-10-16T09:58:00Z/94c3b2a1f-0e9d-48c7-b6a5-948372615049"}
+	]`)
+
+	result, err := istio.GetConfigVersionDistribution(context.Background())
+	if err != nil {
+		t.Fatalf("GetConfigVersionDistribution failed: %v", err)
+	}
+	assertContains(t, result,
+		"Config version distribution of 4 proxies:",
+		"- 2026-10-16T10:05:00Z/13 (latest): 3 proxies",
+		"- 2026-10-16T09:58:00Z/9: 1 proxies (reviews-v1-jkl.bookinfo)",
+		"[WARNING] 1 proxies have not acknowledged the last push sent to them: reviews-v1-jkl.bookinfo",
+		"[RESULT] 1 of 4 proxies are not on the latest config version",
+	)
+}
@@ -0,0 +1,82 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	networkingapi "istio.io/api/networking/v1alpha3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// describeStringMatch formats a string match as its exact value, or 'prefix:' or 'regex:' followed by the pattern
+func describeStringMatch(match *networkingapi.StringMatch) string {
+	switch {
+	case match.GetPrefix() != "":
+		return "prefix:" + match.GetPrefix()
+	case match.GetRegex() != "":
+		return "regex:" + match.GetRegex()
+	}
+	return match.GetExact()
+}
+
+// listOrNone joins values, or returns "(none)" when there are none
+func listOrNone(values []string) string {
+	if len(values) == 0 {
+		return "(none)"
+	}
+	return strings.Join(values, ", ")
+}
+
+// GetCorsConfig lists the HTTP routes of the VirtualServices in a namespace that set a CORS policy, with the
+// allowed origins, methods and headers, and flags policies browsers will reject
+func (i *Istio) GetCorsConfig(ctx context.Context, namespace string) (string, error) {
+	vsList, err := i.istioClient.NetworkingV1alpha3().VirtualServices(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list virtual services: %w", explainForbidden(err, "list", "virtualservices", namespace))
+	}
+
+	result := fmt.Sprintf("CORS policies in Virtual Services of namespace '%s':\n\n", namespace)
+	routes := 0
+	for _, vs := range vsList.Items {
+		for idx, route := range vs.Spec.GetHttp() {
+			cors := route.GetCorsPolicy()
+			if cors == nil {
+				continue
+			}
+			routes++
+
+			var origins []string
+			for _, origin := range cors.GetAllowOrigins() {
+				origins = append(origins, describeStringMatch(origin))
+			}
+			// allowOrigin is deprecated in favor of allowOrigins but still honored
+			origins = append(origins, cors.GetAllowOrigin()...)
+			credentials := cors.GetAllowCredentials().GetValue()
+
+			result += fmt.Sprintf("%s route %s (hosts: %s)\n", vs.Name, httpRouteName(route, idx), strings.Join(vs.Spec.GetHosts(), ", "))
+			result += fmt.Sprintf("  Allowed origins: %s\n", listOrNone(origins))
+			result += fmt.Sprintf("  Allowed methods: %s\n", listOrNone(cors.GetAllowMethods()))
+			result += fmt.Sprintf("  Allowed headers: %s\n", listOrNone(cors.GetAllowHeaders()))
+			result += fmt.Sprintf("  Exposed headers: %s\n", listOrNone(cors.GetExposeHeaders()))
+			if cors.GetMaxAge() != nil {
+				result += fmt.Sprintf("  Max age: %s\n", cors.GetMaxAge().AsDuration())
+			}
+			result += fmt.Sprintf("  Allow credentials: %t\n", credentials)
+
+			if len(origins) == 0 {
+				result += "  [WARNING] No allowed origins: cross-origin requests from browsers are rejected\n"
+			}
+			if credentials && (slices.Contains(origins, "*") || slices.Contains(origins, "regex:.*")) {
+				result += "  [WARNING] Credentials are allowed for any origin: browsers reject credentialed responses with a wildcard origin\n"
+			}
+		}
+	}
+
+	if routes == 0 {
+		return result + "No Virtual Service route sets a CORS policy\n", nil
+	}
+	result += fmt.Sprintf("\n[RESULT] %d routes set a CORS policy\n", routes)
+	return result, nil
+}
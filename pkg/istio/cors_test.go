@@ -0,0 +1,59 @@
+package istio
+
+import (
+	"context"
+	"testing"
+)
+
+// TestGetCorsConfig tests reporting of the CORS policies of the routes of VirtualServices
+func TestGetCorsConfig(t *testing.T) {
+	server := newMockAPIServer(map[string]string{
+		"/apis/networking.istio.io/v1alpha3/namespaces/frontend/virtualservices": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "VirtualServiceList",
+			"items": [
+				{
+					"metadata": {"name": "api", "namespace": "frontend"},
+					"spec": {"hosts": ["api.example.com"], "http": [
+						{
+							"name": "cors",
+							"corsPolicy": {
+								"allowOrigins": [{"exact": "https://app.example.com"}, {"regex": "https://.*\\.example\\.org"}],
+								"allowMethods": ["GET", "POST"],
+								"allowHeaders": ["Authorization"],
+								"maxAge": "24h",
+								"allowCredentials": true
+							},
+							"route": [{"destination": {"host": "api"}}]
+						},
+						{
+							"corsPolicy": {"allowOrigins": [{"exact": "*"}], "allowCredentials": true},
+							"route": [{"destination": {"host": "api"}}]
+						},
+						{"route": [{"destination": {"host": "api"}}]}
+					]}
+				}
+			]
+		}`,
+	})
+	defer server.Close()
+	istio := newTestIstio(t, server.URL)
+
+	result, err := istio.GetCorsConfig(context.Background(), "frontend")
+	if err != nil {
+		t.Fatalf("GetCorsConfig failed: %v", err)
+	}
+	assertContains(t, result,
+		"api route 'cors' (hosts: api.example.com)",
+		"Allowed origins: https://app.example.com, regex:https://.*\\.example\\.org",
+		"Allowed methods: GET, POST",
+		"Allowed headers: Authorization",
+		"Exposed headers: (none)",
+		"Max age: 24h0m0s",
+		"Allow credentials: true",
+		"api route #2",
+		"[WARNING] Credentials are allowed for any origin",
+		"[RESULT] 2 routes set a CORS policy",
+	)
+	assertNotContains(t, result, "route #3")
+}
@@ -0,0 +1,100 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	networkingapi "istio.io/api/networking/v1alpha3"
+	networkingv1alpha3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// effectiveExportTo returns the exportTo of a resource, falling back to the mesh-wide default and then to all
+// namespaces when the resource sets none
+func effectiveExportTo(exportTo, defaultExportTo []string) []string {
+	switch {
+	case len(exportTo) > 0:
+		return exportTo
+	case len(defaultExportTo) > 0:
+		return defaultExportTo
+	default:
+		return []string{"*"}
+	}
+}
+
+// AnalyzeCrossNamespaceRouting checks that the destinations of the VirtualServices in a namespace that are governed
+// by DestinationRules of other namespaces can see those rules. A DestinationRule not exported to the namespace is
+// ignored there: routes to its subsets fail with 503 and its traffic policy silently doesn't apply.
+func (i *Istio) AnalyzeCrossNamespaceRouting(ctx context.Context, namespace string) (string, error) {
+	mesh, err := i.getMeshConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+	vsList, err := i.istioClient.NetworkingV1alpha3().VirtualServices(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list virtual services: %w", i.explainAPIError(ctx, err, "list", "virtualservices", namespace))
+	}
+	drList, err := i.istioClient.NetworkingV1alpha3().DestinationRules("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list destination rules: %w", explainForbidden(err, "list", "destinationrules", ""))
+	}
+
+	result := fmt.Sprintf("Cross-namespace routing of the VirtualServices in namespace '%s':\n\n", namespace)
+	crossing, issues := 0, 0
+	for _, vs := range vsList.Items {
+		seen := make(map[string]bool)
+		for _, destination := range virtualServiceDestinations(&vs.Spec) {
+			host := qualifiedHost(destination.GetHost(), vs.Namespace)
+			subset := destination.GetSubset()
+			key := host + "|" + subset
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			var visible, hidden []*networkingv1alpha3.DestinationRule
+			for _, dr := range drList.Items {
+				if !hostMatches(qualifiedHost(dr.Spec.GetHost(), dr.Namespace), host) {
+					continue
+				}
+				if exportedTo(effectiveExportTo(dr.Spec.GetExportTo(), mesh.DefaultDestinationRuleExportTo), dr.Namespace, vs.Namespace) {
+					visible = append(visible, dr)
+				} else {
+					hidden = append(hidden, dr)
+				}
+			}
+			if !slices.ContainsFunc(append(slices.Clone(visible), hidden...), func(dr *networkingv1alpha3.DestinationRule) bool { return dr.Namespace != vs.Namespace }) {
+				continue
+			}
+			crossing++
+
+			definesSubset := func(dr *networkingv1alpha3.DestinationRule) bool {
+				return slices.ContainsFunc(dr.Spec.GetSubsets(), func(s *networkingapi.Subset) bool { return s.GetName() == subset })
+			}
+			switch {
+			case subset != "" && !slices.ContainsFunc(visible, definesSubset) && slices.ContainsFunc(hidden, definesSubset):
+				dr := hidden[slices.IndexFunc(hidden, definesSubset)]
+				issues++
+				result += fmt.Sprintf("[ERROR] VirtualService '%s' routes to subset '%s' of '%s', defined by DestinationRule %s/%s, which is not exported to namespace '%s' (exportTo: %s); requests fail with 503\n",
+					vs.Name, subset, host, dr.Namespace, dr.Name, vs.Namespace, strings.Join(effectiveExportTo(dr.Spec.GetExportTo(), mesh.DefaultDestinationRuleExportTo), ", "))
+			case len(visible) == 0:
+				issues++
+				dr := hidden[0]
+				result += fmt.Sprintf("[WARNING] VirtualService '%s' routes to '%s', governed by DestinationRule %s/%s, which is not exported to namespace '%s' (exportTo: %s); its traffic policy is silently not applied\n",
+					vs.Name, host, dr.Namespace, dr.Name, vs.Namespace, strings.Join(effectiveExportTo(dr.Spec.GetExportTo(), mesh.DefaultDestinationRuleExportTo), ", "))
+			}
+		}
+	}
+
+	switch {
+	case crossing == 0:
+		result += "[OK] No VirtualService routes to a host governed by a DestinationRule of another namespace\n"
+	case issues == 0:
+		result += fmt.Sprintf("[OK] All %d cross-namespace destinations can see their DestinationRules\n", crossing)
+	default:
+		result += fmt.Sprintf("\n[RESULT] %d of %d cross-namespace destinations cannot see their DestinationRules\n", issues, crossing)
+	}
+	return result, nil
+}
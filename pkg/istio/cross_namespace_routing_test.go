@@ -0,0 +1,45 @@
+package istio
+
+import (
+	"context"
+	"testing"
+)
+
+// TestAnalyzeCrossNamespaceRouting tests flagging a route to a subset whose DestinationRule is not exported to the
+// namespace of the VirtualService
+func TestAnalyzeCrossNamespaceRouting(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{
+		"/apis/networking.istio.io/v1alpha3/namespaces/frontend/virtualservices": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "VirtualServiceList",
+			"items": [{"metadata": {"name": "reviews-canary", "namespace": "frontend"}, "spec": {
+				"hosts": ["reviews.backend.svc.cluster.local"],
+				"http": [{"route": [
+					{"destination": {"host": "reviews.backend.svc.cluster.local", "subset": "v2"}, "weight": 10},
+					{"destination": {"host": "reviews.backend.svc.cluster.local", "subset": "v1"}, "weight": 90}
+				]}]
+			}}]
+		}`,
+		"/apis/networking.istio.io/v1alpha3/destinationrules": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "DestinationRuleList",
+			"items": [{"metadata": {"name": "reviews", "namespace": "backend"}, "spec": {
+				"host": "reviews",
+				"exportTo": ["."],
+				"subsets": [{"name": "v1", "labels": {"version": "v1"}}, {"name": "v2", "labels": {"version": "v2"}}]
+			}}]
+		}`,
+	})
+	defer mockServer.Close()
+	istio := newTestIstio(t, mockServer.URL)
+
+	result, err := istio.AnalyzeCrossNamespaceRouting(context.Background(), "frontend")
+	if err != nil {
+		t.Fatalf("AnalyzeCrossNamespaceRouting failed: %v", err)
+	}
+	assertContains(t, result,
+		"[ERROR] VirtualService 'reviews-canary' routes to subset 'v2' of 'reviews.backend.svc.cluster.local', defined by DestinationRule backend/reviews, which is not exported to namespace 'frontend' (exportTo: .); requests fail with 503",
+		"[ERROR] VirtualService 'reviews-canary' routes to subset 'v1'",
+		"[RESULT] 2 of 2 cross-namespace destinations cannot see their DestinationRules",
+	)
+}
@@ -0,0 +1,72 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+
+	securityv1beta1api "istio.io/api/security/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetCustomAuthzPolicies lists the AuthorizationPolicies with the CUSTOM action in a namespace with the external
+// authorization provider each one delegates to, and checks the provider is defined as an envoyExtAuthzHttp or
+// envoyExtAuthzGrpc extension provider in the mesh config. Istio rejects every request matched by a CUSTOM policy
+// whose provider is undefined.
+func (i *Istio) GetCustomAuthzPolicies(ctx context.Context, namespace string) (string, error) {
+	mesh, err := i.getMeshConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+	policies, err := i.listAuthorizationPolicies(ctx, namespace, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list authorization policies: %w", i.explainAPIError(ctx, err, "list", "authorizationpolicies", namespace))
+	}
+
+	result := fmt.Sprintf("CUSTOM authorization policies in namespace '%s':\n\n", namespace)
+	custom, dangling := 0, 0
+	for _, policy := range policies {
+		if policy.Spec.GetAction() != securityv1beta1api.AuthorizationPolicy_CUSTOM {
+			continue
+		}
+		custom++
+		name := policy.Spec.GetProvider().GetName()
+		result += fmt.Sprintf("- %s (%d rules) -> provider '%s'\n", policy.Name, len(policy.Spec.GetRules()), name)
+
+		defined, authorizer := false, ""
+		for _, provider := range mesh.ExtensionProviders {
+			if provider.Name != name {
+				continue
+			}
+			defined = true
+			switch {
+			case provider.EnvoyExtAuthzHTTP != nil:
+				authorizer = fmt.Sprintf("HTTP ext_authz at %s:%d", provider.EnvoyExtAuthzHTTP.Service, provider.EnvoyExtAuthzHTTP.Port)
+			case provider.EnvoyExtAuthzGrpc != nil:
+				authorizer = fmt.Sprintf("gRPC ext_authz at %s:%d", provider.EnvoyExtAuthzGrpc.Service, provider.EnvoyExtAuthzGrpc.Port)
+			}
+		}
+		switch {
+		case name == "":
+			dangling++
+			result += "  [ERROR] The policy names no provider; matching requests are rejected\n"
+		case !defined:
+			dangling++
+			result += fmt.Sprintf("  [WARNING] Provider '%s' is not defined in meshConfig.extensionProviders; requests matching the policy are rejected\n", name)
+		case authorizer == "":
+			dangling++
+			result += fmt.Sprintf("  [WARNING] Provider '%s' is not an envoyExtAuthzHttp or envoyExtAuthzGrpc provider; requests matching the policy are rejected\n", name)
+		default:
+			result += fmt.Sprintf("  [OK] Provider defined: %s\n", authorizer)
+		}
+	}
+
+	switch {
+	case custom == 0:
+		result += "No AuthorizationPolicy with the CUSTOM action found\n"
+	case dangling == 0:
+		result += fmt.Sprintf("\n[OK] All %d CUSTOM policies reference a defined external authorization provider\n", custom)
+	default:
+		result += fmt.Sprintf("\n[RESULT] %d of %d CUSTOM policies reference an undefined or unusable provider\n", dangling, custom)
+	}
+	return result, nil
+}
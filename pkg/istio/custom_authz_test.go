@@ -0,0 +1,42 @@
+package istio
+
+import (
+	"context"
+	"testing"
+)
+
+// TestGetCustomAuthzPolicies tests that CUSTOM AuthorizationPolicies are matched with the extension providers of the mesh config
+func TestGetCustomAuthzPolicies(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{
+		"/api/v1/namespaces/istio-system/configmaps/istio": `{
+			"apiVersion": "v1",
+			"kind": "ConfigMap",
+			"metadata": {"name": "istio", "namespace": "istio-system"},
+			"data": {"mesh": "extensionProviders:\n- name: opa\n  envoyExtAuthzGrpc:\n    service: opa.opa-system.svc.cluster.local\n    port: 9191\n"}
+		}`,
+		"/apis/security.istio.io/v1beta1/namespaces/bookinfo/authorizationpolicies": `{
+			"apiVersion": "security.istio.io/v1beta1",
+			"kind": "AuthorizationPolicyList",
+			"items": [
+				{"metadata": {"name": "opa-check", "namespace": "bookinfo"}, "spec": {"action": "CUSTOM", "provider": {"name": "opa"}, "rules": [{"to": [{"operation": {"paths": ["/api/*"]}}]}]}},
+				{"metadata": {"name": "oauth2", "namespace": "bookinfo"}, "spec": {"action": "CUSTOM", "provider": {"name": "oauth2-proxy"}, "rules": [{}]}},
+				{"metadata": {"name": "allow-all", "namespace": "bookinfo"}, "spec": {"action": "ALLOW", "rules": [{}]}}
+			]
+		}`,
+	})
+	defer mockServer.Close()
+	istio := newTestIstio(t, mockServer.URL)
+
+	result, err := istio.GetCustomAuthzPolicies(context.Background(), "bookinfo")
+	if err != nil {
+		t.Fatalf("GetCustomAuthzPolicies failed: %v", err)
+	}
+	assertContains(t, result,
+		"- opa-check (1 rules) -> provider 'opa'",
+		"[OK] Provider defined: gRPC ext_authz at opa.opa-system.svc.cluster.local:9191",
+		"- oauth2 (1 rules) -> provider 'oauth2-proxy'",
+		"[WARNING] Provider 'oauth2-proxy' is not defined in meshConfig.extensionProviders",
+		"[RESULT] 1 of 2 CUSTOM policies reference an undefined or unusable provider",
+	)
+	assertNotContains(t, result, "allow-all")
+}
@@ -0,0 +1,115 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	networkingv1alpha3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// delegateTargets returns the VirtualServices, as 'namespace/name', that the HTTP routes of a VirtualService
+// delegate to; a delegate without a namespace is in the namespace of the VirtualService
+func delegateTargets(vs *networkingv1alpha3.VirtualService) []string {
+	var targets []string
+	for _, route := range vs.Spec.GetHttp() {
+		delegate := route.GetDelegate()
+		if delegate == nil {
+			continue
+		}
+		namespace := delegate.GetNamespace()
+		if namespace == "" {
+			namespace = vs.Namespace
+		}
+		if target := namespace + "/" + delegate.GetName(); !slices.Contains(targets, target) {
+			targets = append(targets, target)
+		}
+	}
+	return targets
+}
+
+// ValidateDelegates resolves the delegate of every HTTP route of the VirtualServices in a namespace to an existing
+// VirtualService and flags missing targets, delegate cycles and nested delegation, which Istio doesn't support, as
+// well as delegates that set hosts or aren't exported to the delegating namespace, explicitly or through the mesh
+// default. The routes of a broken delegate are dropped.
+func (i *Istio) ValidateDelegates(ctx context.Context, namespace string) (string, error) {
+	mesh, err := i.getMeshConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+	vsList, err := i.istioClient.NetworkingV1alpha3().VirtualServices("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list virtual services: %w", i.explainAPIError(ctx, err, "list", "virtualservices", ""))
+	}
+	byName := make(map[string]*networkingv1alpha3.VirtualService, len(vsList.Items))
+	for _, vs := range vsList.Items {
+		byName[vs.Namespace+"/"+vs.Name] = vs
+	}
+
+	result := fmt.Sprintf("VirtualService delegates in namespace '%s':\n\n", namespace)
+	delegations, broken := 0, 0
+	for _, vs := range vsList.Items {
+		if vs.Namespace != namespace {
+			continue
+		}
+		root := vs.Namespace + "/" + vs.Name
+		for _, target := range delegateTargets(vs) {
+			delegations++
+			delegate, ok := byName[target]
+			if !ok {
+				broken++
+				result += fmt.Sprintf("[ERROR] VirtualService '%s' delegates to '%s', which does not exist; the routes of the delegating route are dropped\n", vs.Name, target)
+				continue
+			}
+
+			var issues []string
+			if len(delegate.Spec.GetHosts()) > 0 {
+				issues = append(issues, fmt.Sprintf("[WARNING] Delegate '%s' sets hosts (%s); a delegate must not set hosts, otherwise it is treated as a root VirtualService", target, strings.Join(delegate.Spec.GetHosts(), ", ")))
+			}
+			if exportTo := effectiveExportTo(delegate.Spec.GetExportTo(), mesh.DefaultVirtualServiceExportTo); !exportedTo(exportTo, delegate.Namespace, vs.Namespace) {
+				issues = append(issues, fmt.Sprintf("[ERROR] Delegate '%s' is not exported to namespace '%s' (exportTo: %s)", target, vs.Namespace, strings.Join(exportTo, ", ")))
+			}
+			// Follow the chain to detect cycles; Istio supports a single level of delegation
+			chain := []string{root, target}
+			for next := delegateTargets(delegate); len(next) > 0; {
+				hop := next[0]
+				if slices.Contains(chain, hop) {
+					issues = append(issues, fmt.Sprintf("[ERROR] Delegate cycle: %s -> %s", strings.Join(chain, " -> "), hop))
+					break
+				}
+				chain = append(chain, hop)
+				nested, ok := byName[hop]
+				if !ok {
+					issues = append(issues, fmt.Sprintf("[WARNING] Nested delegation %s is not supported, and '%s' does not exist", strings.Join(chain, " -> "), hop))
+					break
+				}
+				next = delegateTargets(nested)
+				if len(next) == 0 {
+					issues = append(issues, fmt.Sprintf("[WARNING] Nested delegation %s is not supported; only one level of delegation is applied", strings.Join(chain, " -> ")))
+				}
+			}
+
+			if len(issues) == 0 {
+				result += fmt.Sprintf("[OK] VirtualService '%s' delegates to '%s' (%d HTTP routes)\n", vs.Name, target, len(delegate.Spec.GetHttp()))
+				continue
+			}
+			broken++
+			result += fmt.Sprintf("VirtualService '%s' delegates to '%s':\n", vs.Name, target)
+			for _, issue := range issues {
+				result += "  " + issue + "\n"
+			}
+		}
+	}
+
+	switch {
+	case delegations == 0:
+		result += "No VirtualService in the namespace delegates routes\n"
+	case broken == 0:
+		result += fmt.Sprintf("\n[OK] All %d delegate references resolve\n", delegations)
+	default:
+		result += fmt.Sprintf("\n[RESULT] %d of %d delegate references are broken\n", broken, delegations)
+	}
+	return result, nil
+}
@@ -0,0 +1,78 @@
+package istio
+
+import (
+	"context"
+	"testing"
+)
+
+// TestValidateDelegates tests resolution of route delegates and detection of missing, cyclic and nested delegates
+func TestValidateDelegates(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{
+		"/apis/networking.istio.io/v1alpha3/virtualservices": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "VirtualServiceList",
+			"items": [
+				{"metadata": {"name": "frontend", "namespace": "web"}, "spec": {
+					"hosts": ["shop.example.com"],
+					"gateways": ["istio-system/public"],
+					"http": [
+						{"match": [{"uri": {"prefix": "/cart"}}], "delegate": {"name": "cart", "namespace": "cart"}},
+						{"match": [{"uri": {"prefix": "/search"}}], "delegate": {"name": "search"}}
+					]
+				}},
+				{"metadata": {"name": "cart", "namespace": "cart"}, "spec": {
+					"http": [{"route": [{"destination": {"host": "cart.cart.svc.cluster.local"}}]}]
+				}}
+			]
+		}`,
+	})
+	defer mockServer.Close()
+	istio := newTestIstio(t, mockServer.URL)
+
+	result, err := istio.ValidateDelegates(context.Background(), "web")
+	if err != nil {
+		t.Fatalf("ValidateDelegates failed: %v", err)
+	}
+	assertContains(t, result,
+		"[OK] VirtualService 'frontend' delegates to 'cart/cart' (1 HTTP routes)",
+		"[ERROR] VirtualService 'frontend' delegates to 'web/search', which does not exist",
+		"[RESULT] 1 of 2 delegate references are broken",
+	)
+}
+
+// TestValidateDelegatesDefaultExportTo tests that a delegate without exportTo follows the mesh-wide default
+func TestValidateDelegatesDefaultExportTo(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{
+		"/api/v1/namespaces/istio-system/configmaps/istio": `{
+			"apiVersion": "v1",
+			"kind": "ConfigMap",
+			"metadata": {"name": "istio", "namespace": "istio-system"},
+			"data": {"mesh": "defaultVirtualServiceExportTo:\n- .\n"}
+		}`,
+		"/apis/networking.istio.io/v1alpha3/virtualservices": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "VirtualServiceList",
+			"items": [
+				{"metadata": {"name": "frontend", "namespace": "web"}, "spec": {
+					"hosts": ["shop.example.com"],
+					"gateways": ["istio-system/public"],
+					"http": [{"match": [{"uri": {"prefix": "/cart"}}], "delegate": {"name": "cart", "namespace": "cart"}}]
+				}},
+				{"metadata": {"name": "cart", "namespace": "cart"}, "spec": {
+					"http": [{"route": [{"destination": {"host": "cart.cart.svc.cluster.local"}}]}]
+				}}
+			]
+		}`,
+	})
+	defer mockServer.Close()
+	istio := newTestIstio(t, mockServer.URL)
+
+	result, err := istio.ValidateDelegates(context.Background(), "web")
+	if err != nil {
+		t.Fatalf("ValidateDelegates failed: %v", err)
+	}
+	assertContains(t, result,
+		"[ERROR] Delegate 'cart/cart' is not exported to namespace 'web' (exportTo: .)",
+		"[RESULT] 1 of 1 delegate references are broken",
+	)
+}
@@ -0,0 +1,148 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+
+	networkingapi "istio.io/api/networking/v1alpha3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// deprecatedUsage is a deprecated field or value set in a resource, with the replacement Istio recommends
+type deprecatedUsage struct {
+	location    string
+	field       string
+	replacement string
+}
+
+// deprecatedTrafficPolicyUsage collects the deprecated load balancer and outlier detection settings of a traffic
+// policy, or of one of its port-level settings
+func deprecatedTrafficPolicyUsage(scope string, lb *networkingapi.LoadBalancerSettings, outlier *networkingapi.OutlierDetection) []deprecatedUsage {
+	var usages []deprecatedUsage
+	if lb.GetSimple() == networkingapi.LoadBalancerSettings_LEAST_CONN {
+		usages = append(usages, deprecatedUsage{scope, "loadBalancer.simple: LEAST_CONN", "loadBalancer.simple: LEAST_REQUEST"})
+	}
+	if lb.GetWarmupDurationSecs() != nil {
+		usages = append(usages, deprecatedUsage{scope, "loadBalancer.warmupDurationSecs", "loadBalancer.warmup.duration"})
+	}
+	if lb.GetConsistentHash().GetMinimumRingSize() != 0 {
+		usages = append(usages, deprecatedUsage{scope, "loadBalancer.consistentHash.minimumRingSize", "loadBalancer.consistentHash.ringHash.minimumRingSize"})
+	}
+	if outlier.GetConsecutiveErrors() != 0 {
+		usages = append(usages, deprecatedUsage{scope, "outlierDetection.consecutiveErrors", "outlierDetection.consecutive5xxErrors or consecutiveGatewayErrors"})
+	}
+	return usages
+}
+
+// destinationRuleDeprecatedUsage collects the deprecated settings of the traffic policy of a DestinationRule,
+// including port-level settings and subset-level overrides
+func destinationRuleDeprecatedUsage(spec *networkingapi.DestinationRule) []deprecatedUsage {
+	var usages []deprecatedUsage
+	collect := func(scope string, policy *networkingapi.TrafficPolicy) {
+		if policy == nil {
+			return
+		}
+		usages = append(usages, deprecatedTrafficPolicyUsage(scope, policy.GetLoadBalancer(), policy.GetOutlierDetection())...)
+		for _, portPolicy := range policy.GetPortLevelSettings() {
+			scope := fmt.Sprintf("%s, port %d", scope, portPolicy.GetPort().GetNumber())
+			usages = append(usages, deprecatedTrafficPolicyUsage(scope, portPolicy.GetLoadBalancer(), portPolicy.GetOutlierDetection())...)
+		}
+	}
+	collect("traffic policy", spec.GetTrafficPolicy())
+	for _, subset := range spec.GetSubsets() {
+		collect(fmt.Sprintf("subset '%s'", subset.GetName()), subset.GetTrafficPolicy())
+	}
+	return usages
+}
+
+// virtualServiceDeprecatedUsage collects the deprecated fields of the HTTP routes of a VirtualService
+func virtualServiceDeprecatedUsage(spec *networkingapi.VirtualService) []deprecatedUsage {
+	var usages []deprecatedUsage
+	for idx, route := range spec.GetHttp() {
+		location := "route " + httpRouteName(route, idx)
+		if route.GetMirrorPercent() != nil {
+			usages = append(usages, deprecatedUsage{location, "mirrorPercent", "mirrorPercentage"})
+		}
+		if len(route.GetCorsPolicy().GetAllowOrigin()) > 0 {
+			usages = append(usages, deprecatedUsage{location, "corsPolicy.allowOrigin", "corsPolicy.allowOrigins"})
+		}
+		if route.GetFault().GetDelay().GetPercent() != 0 {
+			usages = append(usages, deprecatedUsage{location, "fault.delay.percent", "fault.delay.percentage"})
+		}
+	}
+	return usages
+}
+
+// FindDeprecatedUsage scans the VirtualServices, DestinationRules, Gateways and Sidecars of a namespace for fields
+// and values the Istio API marks as deprecated and reports each one with its replacement. Deprecated fields are
+// still honored but may be removed in a later release, so they should be migrated before upgrading Istio.
+func (i *Istio) FindDeprecatedUsage(ctx context.Context, namespace string) (string, error) {
+	vsList, err := i.istioClient.NetworkingV1alpha3().VirtualServices(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list virtual services: %w", i.explainAPIError(ctx, err, "list", "virtualservices", namespace))
+	}
+	drList, err := i.istioClient.NetworkingV1alpha3().DestinationRules(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list destination rules: %w", explainForbidden(err, "list", "destinationrules", namespace))
+	}
+	gwList, err := i.istioClient.NetworkingV1alpha3().Gateways(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list gateways: %w", explainForbidden(err, "list", "gateways", namespace))
+	}
+	sidecarList, err := i.istioClient.NetworkingV1alpha3().Sidecars(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list sidecars: %w", explainForbidden(err, "list", "sidecars", namespace))
+	}
+
+	result := fmt.Sprintf("Deprecated API usage in namespace '%s':\n\n", namespace)
+	found, affected := 0, 0
+	report := func(kind, name string, usages []deprecatedUsage) {
+		if len(usages) == 0 {
+			return
+		}
+		affected++
+		found += len(usages)
+		result += fmt.Sprintf("%s '%s':\n", kind, name)
+		for _, usage := range usages {
+			result += fmt.Sprintf("  [WARNING] %s: %s is deprecated; use %s\n", usage.location, usage.field, usage.replacement)
+		}
+	}
+
+	for _, vs := range vsList.Items {
+		report("VirtualService", vs.Name, virtualServiceDeprecatedUsage(&vs.Spec))
+	}
+	for _, dr := range drList.Items {
+		report("DestinationRule", dr.Name, destinationRuleDeprecatedUsage(&dr.Spec))
+	}
+	for _, gw := range gwList.Items {
+		var usages []deprecatedUsage
+		for idx, server := range gw.Spec.GetServers() {
+			if server.GetPort().GetTargetPort() != 0 {
+				usages = append(usages, deprecatedUsage{fmt.Sprintf("server #%d", idx+1), "port.targetPort", "nothing; the field has no effect on Gateways"})
+			}
+		}
+		report("Gateway", gw.Name, usages)
+	}
+	for _, sidecar := range sidecarList.Items {
+		var usages []deprecatedUsage
+		for idx, ingress := range sidecar.Spec.GetIngress() {
+			if ingress.GetPort().GetTargetPort() != 0 {
+				usages = append(usages, deprecatedUsage{fmt.Sprintf("ingress #%d", idx+1), "port.targetPort", "nothing; the field has no effect"})
+			}
+		}
+		for idx, egress := range sidecar.Spec.GetEgress() {
+			if egress.GetPort().GetTargetPort() != 0 {
+				usages = append(usages, deprecatedUsage{fmt.Sprintf("egress #%d", idx+1), "port.targetPort", "nothing; the field has no effect"})
+			}
+		}
+		report("Sidecar", sidecar.Name, usages)
+	}
+
+	total := len(vsList.Items) + len(drList.Items) + len(gwList.Items) + len(sidecarList.Items)
+	if found == 0 {
+		result += fmt.Sprintf("[OK] No deprecated fields found in %d resources\n", total)
+	} else {
+		result += fmt.Sprintf("\n[RESULT] %d deprecated usages found in %d of %d resources; migrate them before upgrading Istio\n", found, affected, total)
+	}
+	return result, nil
+}
@@ -0,0 +1,51 @@
+package istio
+
+import (
+	"context"
+	"testing"
+)
+
+// TestFindDeprecatedUsage tests detection of deprecated fields and values in the Istio resources of a namespace
+func TestFindDeprecatedUsage(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{
+		"/apis/networking.istio.io/v1alpha3/namespaces/default/virtualservices": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "VirtualServiceList",
+			"items": [
+				{"metadata": {"name": "reviews", "namespace": "default"}, "spec": {
+					"hosts": ["reviews"],
+					"http": [{"mirror": {"host": "reviews-shadow"}, "mirrorPercent": 10, "route": [{"destination": {"host": "reviews"}}]}]
+				}}
+			]
+		}`,
+		"/apis/networking.istio.io/v1alpha3/namespaces/default/destinationrules": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "DestinationRuleList",
+			"items": [
+				{"metadata": {"name": "ratings", "namespace": "default"}, "spec": {
+					"host": "ratings",
+					"subsets": [{"name": "v1", "labels": {"version": "v1"}, "trafficPolicy": {"loadBalancer": {"simple": "LEAST_CONN"}}}]
+				}},
+				{"metadata": {"name": "details", "namespace": "default"}, "spec": {
+					"host": "details",
+					"trafficPolicy": {"loadBalancer": {"simple": "LEAST_REQUEST"}}
+				}}
+			]
+		}`,
+		"/apis/networking.istio.io/v1alpha3/namespaces/default/gateways": `{"apiVersion": "networking.istio.io/v1alpha3", "kind": "GatewayList", "items": []}`,
+		"/apis/networking.istio.io/v1alpha3/namespaces/default/sidecars": `{"apiVersion": "networking.istio.io/v1alpha3", "kind": "SidecarList", "items": []}`,
+	})
+	defer mockServer.Close()
+	istio := newTestIstio(t, mockServer.URL)
+
+	result, err := istio.FindDeprecatedUsage(context.Background(), "default")
+	if err != nil {
+		t.Fatalf("FindDeprecatedUsage failed: %v", err)
+	}
+	assertContains(t, result,
+		"VirtualService 'reviews':\n  [WARNING] route #1: mirrorPercent is deprecated; use mirrorPercentage",
+		"DestinationRule 'ratings':\n  [WARNING] subset 'v1': loadBalancer.simple: LEAST_CONN is deprecated; use loadBalancer.simple: LEAST_REQUEST",
+		"[RESULT] 2 deprecated usages found in 2 of 3 resources",
+	)
+	assertNotContains(t, result, "DestinationRule 'details'")
+}
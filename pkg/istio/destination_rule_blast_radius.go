@@ -0,0 +1,128 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetDestinationRuleBlastRadius reports what a change to a DestinationRule affects: the services its host
+// selects, the VirtualServices routing to its subsets, and the mesh workloads whose proxies have clusters for
+// those services and can see the rule through its exportTo
+func (i *Istio) GetDestinationRuleBlastRadius(ctx context.Context, namespace, name string) (string, error) {
+	dr, err := i.istioClient.NetworkingV1alpha3().DestinationRules(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get destination rule %s: %w", name, i.explainAPIError(ctx, err, "get", "destinationrules", namespace))
+	}
+	host := qualifiedHost(dr.Spec.Host, dr.Namespace)
+	mesh, err := i.getMeshConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+	exportTo := effectiveExportTo(dr.Spec.ExportTo, mesh.DefaultDestinationRuleExportTo)
+
+	services, err := i.kubeClient.CoreV1().Services("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list services: %w", explainForbidden(err, "list", "services", ""))
+	}
+	var hosts []string
+	for _, svc := range services.Items {
+		if svcHost := qualifiedHost(svc.Name, svc.Namespace); hostMatches(host, svcHost) {
+			hosts = append(hosts, svcHost)
+		}
+	}
+	// Hosts outside the cluster, e.g. those of ServiceEntries, are governed as written
+	if len(hosts) == 0 && !strings.HasPrefix(host, "*") {
+		hosts = append(hosts, host)
+	}
+	slices.Sort(hosts)
+
+	vsList, err := i.istioClient.NetworkingV1alpha3().VirtualServices("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list virtual services: %w", explainForbidden(err, "list", "virtualservices", ""))
+	}
+	defined := make(map[string]bool, len(dr.Spec.Subsets))
+	for _, subset := range dr.Spec.Subsets {
+		defined[subset.Name] = true
+	}
+	var references []string
+	for _, vs := range vsList.Items {
+		var subsets []string
+		for _, destination := range virtualServiceDestinations(&vs.Spec) {
+			subset := destination.GetSubset()
+			if subset == "" || slices.Contains(subsets, subset) || !hostMatches(host, qualifiedHost(destination.GetHost(), vs.Namespace)) {
+				continue
+			}
+			subsets = append(subsets, subset)
+		}
+		for _, subset := range subsets {
+			reference := fmt.Sprintf("%s/%s -> subset '%s'", vs.Namespace, vs.Name, subset)
+			if !defined[subset] {
+				reference += " [WARNING] not defined by this rule"
+			}
+			references = append(references, reference)
+		}
+	}
+
+	pods, err := i.kubeClient.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list pods: %w", explainForbidden(err, "list", "pods", ""))
+	}
+	var exported []v1.Pod
+	for _, pod := range meshWorkloadPods(pods.Items, nil) {
+		if exportedTo(exportTo, dr.Namespace, pod.Namespace) {
+			exported = append(exported, pod)
+		}
+	}
+	var callers, unreadable []string
+	scanned, skipped := i.scanProxyClusters(ctx, exported)
+	for _, proxy := range scanned {
+		if proxy.err != nil {
+			unreadable = append(unreadable, proxy.pod.Namespace+"/"+proxy.pod.Name)
+			continue
+		}
+		var reached []string
+		for _, governed := range hosts {
+			if _, ok := proxy.hosts[governed]; ok {
+				reached = append(reached, governed)
+			}
+		}
+		if len(reached) == 0 {
+			continue
+		}
+		workload := proxy.pod.Labels["app"]
+		if workload == "" {
+			workload = proxy.pod.Name
+		}
+		callers = append(callers, fmt.Sprintf("%s (namespace %s, pod %s) -> %s", workload, proxy.pod.Namespace, proxy.pod.Name, strings.Join(reached, ", ")))
+	}
+
+	result := fmt.Sprintf("Blast radius of DestinationRule '%s/%s' (host: %s):\n\n", dr.Namespace, dr.Name, dr.Spec.Host)
+	result += fmt.Sprintf("Governed hosts (%d):\n", len(hosts))
+	for _, governed := range hosts {
+		result += fmt.Sprintf("- %s\n", governed)
+	}
+	result += fmt.Sprintf("\nVirtualServices routing to its subsets (%d):\n", len(references))
+	for _, reference := range references {
+		result += fmt.Sprintf("- %s\n", reference)
+	}
+	result += fmt.Sprintf("\nWorkloads sending traffic to the governed hosts (%d):\n", len(callers))
+	for _, caller := range callers {
+		result += fmt.Sprintf("- %s\n", caller)
+	}
+	if len(unreadable) > 0 {
+		result += fmt.Sprintf("\n[WARNING] Could not read the clusters of %s\n", strings.Join(unreadable, ", "))
+	}
+	if skipped > 0 {
+		result += fmt.Sprintf("\n[WARNING] Partial result: only the proxies of the first %d mesh workloads were read, %d more were skipped\n", maxScannedProxies, skipped)
+	}
+	if !slices.Equal(exportTo, []string{"*"}) {
+		result += fmt.Sprintf("\nOnly workloads in namespaces the rule is exported to are listed (exportTo: %v)\n", exportTo)
+	}
+	result += "\nNote: a DestinationRule with higher precedence for the same host (e.g. in a client's own namespace) shadows this one for those clients\n"
+	return result, nil
+}
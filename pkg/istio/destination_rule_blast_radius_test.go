@@ -0,0 +1,105 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"testing"
+)
+
+// TestGetDestinationRuleBlastRadius tests reporting of the hosts, VirtualServices and workloads affected by a DestinationRule
+func TestGetDestinationRuleBlastRadius(t *testing.T) {
+	fixtures := map[string]string{
+		"/apis/networking.istio.io/v1alpha3/namespaces/bookinfo/destinationrules/reviews": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "DestinationRule",
+			"metadata": {"name": "reviews", "namespace": "bookinfo"},
+			"spec": {"host": "reviews", "subsets": [{"name": "v1", "labels": {"version": "v1"}}, {"name": "v2", "labels": {"version": "v2"}}]}
+		}`,
+		"/api/v1/services": `{
+			"apiVersion": "v1",
+			"kind": "ServiceList",
+			"items": [
+				{"metadata": {"name": "reviews", "namespace": "bookinfo"}},
+				{"metadata": {"name": "ratings", "namespace": "bookinfo"}}
+			]
+		}`,
+		"/apis/networking.istio.io/v1alpha3/virtualservices": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "VirtualServiceList",
+			"items": [
+				{"metadata": {"name": "reviews", "namespace": "bookinfo"}, "spec": {"hosts": ["reviews"], "http": [{"route": [
+					{"destination": {"host": "reviews", "subset": "v1"}, "weight": 90},
+					{"destination": {"host": "reviews", "subset": "v3"}, "weight": 10}
+				]}]}},
+				{"metadata": {"name": "ratings", "namespace": "bookinfo"}, "spec": {"hosts": ["ratings"], "http": [{"route": [{"destination": {"host": "ratings", "subset": "v1"}}]}]}}
+			]
+		}`,
+		"/api/v1/pods": `{
+			"apiVersion": "v1",
+			"kind": "PodList",
+			"items": [
+				{"metadata": {"name": "productpage-v1-abc", "namespace": "bookinfo", "labels": {"app": "productpage"}}, "spec": {"containers": [{"name": "productpage"}, {"name": "istio-proxy"}]}, "status": {"phase": "Running"}},
+				{"metadata": {"name": "ratings-v1-def", "namespace": "bookinfo", "labels": {"app": "ratings"}}, "spec": {"containers": [{"name": "ratings"}, {"name": "istio-proxy"}]}, "status": {"phase": "Running"}},
+				{"metadata": {"name": "gateway-ghi", "namespace": "ingress", "labels": {"app": "gateway"}}, "spec": {"containers": [{"name": "istio-proxy"}]}, "status": {"phase": "Running"}}
+			]
+		}`,
+	}
+	clusters := map[string]string{
+		"productpage-v1-abc.bookinfo": `[{"name": "outbound|9080||reviews.bookinfo.svc.cluster.local"}, {"name": "outbound|9080|v1|reviews.bookinfo.svc.cluster.local"}]`,
+		"ratings-v1-def.bookinfo":     `[{"name": "outbound|27017||mongodb.bookinfo.svc.cluster.local"}]`,
+		"gateway-ghi.ingress":         `[{"name": "outbound|9080||reviews.bookinfo.svc.cluster.local"}]`,
+	}
+	newIstio := func(t *testing.T, fixtures map[string]string) *Istio {
+		mockServer := newMockAPIServer(fixtures)
+		t.Cleanup(mockServer.Close)
+		istio := newTestIstio(t, mockServer.URL)
+		istio.ProxyConfig.execCommand = func(ctx context.Context, args ...string) ([]byte, error) {
+			pod := args[slices.Index(args, "cluster")+1]
+			if output, ok := clusters[pod]; ok {
+				return []byte(output), nil
+			}
+			return nil, fmt.Errorf("unexpected pod %s", pod)
+		}
+		return istio
+	}
+
+	t.Run("reports affected hosts, routes and workloads", func(t *testing.T) {
+		result, err := newIstio(t, fixtures).GetDestinationRuleBlastRadius(context.Background(), "bookinfo", "reviews")
+		if err != nil {
+			t.Fatalf("GetDestinationRuleBlastRadius failed: %v", err)
+		}
+		assertContains(t, result,
+			"Blast radius of DestinationRule 'bookinfo/reviews' (host: reviews)",
+			"Governed hosts (1):\n- reviews.bookinfo.svc.cluster.local",
+			"VirtualServices routing to its subsets (2):",
+			"- bookinfo/reviews -> subset 'v1'\n",
+			"- bookinfo/reviews -> subset 'v3' [WARNING] not defined by this rule",
+			"Workloads sending traffic to the governed hosts (2):",
+			"- productpage (namespace bookinfo, pod productpage-v1-abc) -> reviews.bookinfo.svc.cluster.local",
+			"- gateway (namespace ingress, pod gateway-ghi) -> reviews.bookinfo.svc.cluster.local",
+		)
+		assertNotContains(t, result, "bookinfo/ratings", "ratings (namespace", "Could not read", "exportTo")
+	})
+
+	t.Run("follows the mesh default exportTo", func(t *testing.T) {
+		scoped := maps.Clone(fixtures)
+		scoped["/api/v1/namespaces/istio-system/configmaps/istio"] = `{
+			"apiVersion": "v1",
+			"kind": "ConfigMap",
+			"metadata": {"name": "istio", "namespace": "istio-system"},
+			"data": {"mesh": "defaultDestinationRuleExportTo:\n- .\n"}
+		}`
+		result, err := newIstio(t, scoped).GetDestinationRuleBlastRadius(context.Background(), "bookinfo", "reviews")
+		if err != nil {
+			t.Fatalf("GetDestinationRuleBlastRadius failed: %v", err)
+		}
+		assertContains(t, result,
+			"Workloads sending traffic to the governed hosts (1):",
+			"- productpage (namespace bookinfo, pod productpage-v1-abc)",
+			"(exportTo: [.])",
+		)
+		assertNotContains(t, result, "gateway (namespace ingress")
+	})
+}
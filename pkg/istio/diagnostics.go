@@ -0,0 +1,76 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Diagnose runs a battery of self-checks covering everything the server depends on:
+// Kubernetes API connectivity, the istioctl binary, the Istio CRDs, and the target namespace
+func (i *Istio) Diagnose(ctx context.Context, namespace string) (string, error) {
+	result := "Istio MCP Server Diagnostics:\n\n"
+	failures := 0
+
+	check := func(ok bool, okMessage, failMessage string) {
+		if ok {
+			result += "[OK] " + okMessage + "\n"
+		} else {
+			result += "[FAIL] " + failMessage + "\n"
+			failures++
+		}
+	}
+
+	// Check 1: Kubernetes API connectivity
+	serverVersion, err := i.kubeClient.Discovery().ServerVersion()
+	if err != nil {
+		check(false, "", fmt.Sprintf("Kubernetes API: not reachable at %s: %v", i.config.Host, err))
+	} else {
+		check(true, fmt.Sprintf("Kubernetes API: reachable at %s (version %s)", i.config.Host, serverVersion.GitVersion), "")
+	}
+
+	// Check 2: istioctl availability
+	istioctlVersion, err := i.ProxyConfig.GetIstioctlVersion(ctx)
+	if err != nil {
+		check(false, "", fmt.Sprintf("istioctl: not available (proxy configuration tools will fail): %v", err))
+	} else {
+		check(true, fmt.Sprintf("istioctl: available (version %s)", istioctlVersion), "")
+	}
+
+	// Check 3: Istio CRDs
+	groups, err := i.kubeClient.Discovery().ServerGroups()
+	if err != nil {
+		check(false, "", fmt.Sprintf("Istio CRDs: unable to query API groups: %v", err))
+	} else {
+		var istioGroups []string
+		for _, group := range groups.Groups {
+			if strings.HasSuffix(group.Name, ".istio.io") {
+				istioGroups = append(istioGroups, group.Name)
+			}
+		}
+		found := false
+		for _, group := range istioGroups {
+			if group == "networking.istio.io" {
+				found = true
+			}
+		}
+		check(found,
+			fmt.Sprintf("Istio CRDs: installed (%s)", strings.Join(istioGroups, ", ")),
+			"Istio CRDs: networking.istio.io API group not found - is Istio installed in this cluster?")
+	}
+
+	// Check 4: target namespace
+	_, err = i.kubeClient.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+	check(err == nil,
+		fmt.Sprintf("Namespace: '%s' exists", namespace),
+		fmt.Sprintf("Namespace: '%s' not accessible: %v", namespace, err))
+
+	if failures == 0 {
+		result += "\n[RESULT] All checks passed\n"
+	} else {
+		result += fmt.Sprintf("\n[RESULT] %d checks failed\n", failures)
+	}
+	return result, nil
+}
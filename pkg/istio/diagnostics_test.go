@@ -0,0 +1,49 @@
+package istio
+
+import (
+	"context"
+	"testing"
+)
+
+// TestDiagnose tests the self-check report against a healthy mock environment
+func TestDiagnose(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{
+		"/version": `{"major": "1", "minor": "30", "gitVersion": "v1.30.2"}`,
+		"/apis": `{
+			"kind": "APIGroupList",
+			"groups": [
+				{"name": "networking.istio.io", "versions": [{"groupVersion": "networking.istio.io/v1alpha3", "version": "v1alpha3"}]},
+				{"name": "security.istio.io", "versions": [{"groupVersion": "security.istio.io/v1beta1", "version": "v1beta1"}]}
+			]
+		}`,
+		"/api/v1/namespaces/bookinfo": `{"apiVersion": "v1", "kind": "Namespace", "metadata": {"name": "bookinfo"}}`,
+	})
+	defer mockServer.Close()
+
+	istio := newTestIstio(t, mockServer.URL)
+	stubIstioctl(istio.ProxyConfig, "1.25.1\n")
+	ctx := context.Background()
+
+	t.Run("healthy environment passes every check", func(t *testing.T) {
+		result, err := istio.Diagnose(ctx, "bookinfo")
+		if err != nil {
+			t.Fatalf("Failed to run diagnostics: %v", err)
+		}
+		assertContains(t, result,
+			"[OK] Kubernetes API: reachable at "+mockServer.URL+" (version v1.30.2)",
+			"[OK] istioctl: available (version 1.25.1)",
+			"[OK] Istio CRDs: installed (networking.istio.io, security.istio.io)",
+			"[OK] Namespace: 'bookinfo' exists",
+			"[RESULT] All checks passed",
+		)
+		assertNotContains(t, result, "[FAIL]")
+	})
+
+	t.Run("missing namespace fails its check", func(t *testing.T) {
+		result, err := istio.Diagnose(ctx, "missing")
+		if err != nil {
+			t.Fatalf("Failed to run diagnostics: %v", err)
+		}
+		assertContains(t, result, "[FAIL] Namespace: 'missing' not accessible", "[RESULT] 1 checks failed")
+	})
+}
@@ -0,0 +1,147 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	securityv1beta1api "istio.io/api/security/v1beta1"
+	securityv1beta1 "istio.io/client-go/pkg/apis/security/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// authzPolicyScope tells whether an AuthorizationPolicy applies to a workload and how broadly: to the whole
+// mesh, the whole namespace or selected workloads
+func authzPolicyScope(policy *securityv1beta1.AuthorizationPolicy, rootNamespace string, workloadLabels labels.Set) (string, bool) {
+	selector := policy.Spec.GetSelector().GetMatchLabels()
+	if len(selector) > 0 {
+		return "workload", labels.SelectorFromSet(selector).Matches(workloadLabels)
+	}
+	if policy.Namespace == rootNamespace {
+		return "mesh-wide", true
+	}
+	return "namespace-wide", true
+}
+
+// matchesAllRequests reports whether a policy has a rule without from, to and when, which matches every request
+func matchesAllRequests(policy *securityv1beta1.AuthorizationPolicy) bool {
+	for _, rule := range policy.Spec.GetRules() {
+		if len(rule.GetFrom()) == 0 && len(rule.GetTo()) == 0 && len(rule.GetWhen()) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// GetEffectiveAuthzForWorkload gathers the AuthorizationPolicies of the mesh root namespace and the namespace
+// that apply to a workload with the given labels (e.g. 'app=reviews,version=v1') and explains their combined
+// effect: CUSTOM policies are evaluated first, then any matching DENY rule rejects the request, then a request
+// must match an ALLOW rule if any ALLOW policy applies
+func (i *Istio) GetEffectiveAuthzForWorkload(ctx context.Context, namespace, selector string) (string, error) {
+	workloadLabels, err := labels.ConvertSelectorToLabelsMap(selector)
+	if err != nil {
+		return "", fmt.Errorf("invalid workload selector '%s': %w", selector, err)
+	}
+	mesh, err := i.getMeshConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	namespaces := []string{namespace}
+	if mesh.RootNamespace != namespace {
+		namespaces = append([]string{mesh.RootNamespace}, namespaces...)
+	}
+	byAction := map[securityv1beta1api.AuthorizationPolicy_Action][]string{}
+	var allowAll, denyAll, allowNone []string
+	var skipped []string
+	for _, ns := range namespaces {
+		policies, err := i.listAuthorizationPolicies(ctx, ns, metav1.ListOptions{})
+		if err != nil {
+			return "", fmt.Errorf("failed to list authorization policies: %w", explainForbidden(err, "list", "authorizationpolicies", ns))
+		}
+		for _, policy := range policies {
+			name := policy.Namespace + "/" + policy.Name
+			// Policies attached to gateways or services through targetRefs don't select workloads by label
+			if policy.Spec.GetTargetRef() != nil || len(policy.Spec.GetTargetRefs()) > 0 {
+				skipped = append(skipped, name)
+				continue
+			}
+			scope, applies := authzPolicyScope(policy, mesh.RootNamespace, labels.Set(workloadLabels))
+			if !applies {
+				continue
+			}
+			action := policy.Spec.GetAction()
+			description := fmt.Sprintf("%s (%s, %d rules", name, scope, len(policy.Spec.GetRules()))
+			if action == securityv1beta1api.AuthorizationPolicy_CUSTOM {
+				description += ", provider " + policy.Spec.GetProvider().GetName()
+			}
+			byAction[action] = append(byAction[action], description+")")
+
+			switch {
+			case action == securityv1beta1api.AuthorizationPolicy_ALLOW && len(policy.Spec.GetRules()) == 0:
+				allowNone = append(allowNone, name)
+			case action == securityv1beta1api.AuthorizationPolicy_ALLOW && matchesAllRequests(policy):
+				allowAll = append(allowAll, name)
+			case action == securityv1beta1api.AuthorizationPolicy_DENY && matchesAllRequests(policy):
+				denyAll = append(denyAll, name)
+			}
+		}
+	}
+
+	result := fmt.Sprintf("Authorization policies affecting workload '%s' in namespace '%s':\n\n", selector, namespace)
+	if len(byAction) == 0 {
+		result += "[RESULT] No authorization policy applies: all requests are allowed\n"
+		if len(skipped) > 0 {
+			result += fmt.Sprintf("\nNot evaluated (attached to gateways or services through targetRefs): %s\n", strings.Join(skipped, ", "))
+		}
+		return result, nil
+	}
+
+	for _, action := range []securityv1beta1api.AuthorizationPolicy_Action{
+		securityv1beta1api.AuthorizationPolicy_CUSTOM,
+		securityv1beta1api.AuthorizationPolicy_DENY,
+		securityv1beta1api.AuthorizationPolicy_ALLOW,
+		securityv1beta1api.AuthorizationPolicy_AUDIT,
+	} {
+		if len(byAction[action]) == 0 {
+			continue
+		}
+		result += fmt.Sprintf("%s:\n", action)
+		for _, description := range byAction[action] {
+			result += fmt.Sprintf("  - %s\n", description)
+		}
+	}
+
+	result += "\nCombined effect, in evaluation order:\n"
+	step := 1
+	if len(byAction[securityv1beta1api.AuthorizationPolicy_CUSTOM]) > 0 {
+		result += fmt.Sprintf("%d. CUSTOM: requests matching the rules of CUSTOM policies are delegated to their external authorizer; if it denies, the request is rejected\n", step)
+		step++
+	}
+	if len(byAction[securityv1beta1api.AuthorizationPolicy_DENY]) > 0 {
+		result += fmt.Sprintf("%d. DENY wins: a request matching any rule of a DENY policy is rejected, even if an ALLOW policy matches it\n", step)
+		if len(denyAll) > 0 {
+			result += fmt.Sprintf("   [WARNING] %s has a rule without from, to or when and denies all requests\n", strings.Join(denyAll, ", "))
+		}
+		step++
+	}
+	if len(byAction[securityv1beta1api.AuthorizationPolicy_ALLOW]) > 0 {
+		result += fmt.Sprintf("%d. ALLOW required: since ALLOW policies apply, a request is only allowed if it matches a rule of one of them; all other requests are rejected\n", step)
+		if len(allowAll) > 0 {
+			result += fmt.Sprintf("   %s has a rule without from, to or when and allows all requests not denied above\n", strings.Join(allowAll, ", "))
+		}
+		if len(allowNone) > 0 {
+			result += fmt.Sprintf("   [WARNING] %s has no rules and matches no request\n", strings.Join(allowNone, ", "))
+		}
+	} else {
+		result += fmt.Sprintf("%d. No ALLOW policy applies: requests not rejected above are allowed\n", step)
+	}
+	if len(byAction[securityv1beta1api.AuthorizationPolicy_AUDIT]) > 0 {
+		result += "AUDIT policies only log matching requests and don't affect the decision\n"
+	}
+	if len(skipped) > 0 {
+		result += fmt.Sprintf("\nNot evaluated (attached to gateways or services through targetRefs): %s\n", strings.Join(skipped, ", "))
+	}
+	return result, nil
+}
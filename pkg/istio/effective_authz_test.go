@@ -0,0 +1,49 @@
+package istio
+
+import (
+	"context"
+	"testing"
+)
+
+// TestGetEffectiveAuthzForWorkload tests resolution of the AuthorizationPolicies applying to a workload
+func TestGetEffectiveAuthzForWorkload(t *testing.T) {
+	server := newMockAPIServer(map[string]string{
+		"/apis/security.istio.io/v1beta1/namespaces/istio-system/authorizationpolicies": `{
+			"apiVersion": "security.istio.io/v1beta1",
+			"kind": "AuthorizationPolicyList",
+			"items": []
+		}`,
+		"/apis/security.istio.io/v1beta1/namespaces/bookinfo/authorizationpolicies": `{
+			"apiVersion": "security.istio.io/v1beta1",
+			"kind": "AuthorizationPolicyList",
+			"items": [
+				{
+					"metadata": {"name": "allow-bookinfo", "namespace": "bookinfo"},
+					"spec": {"action": "ALLOW", "rules": [{"from": [{"source": {"namespaces": ["bookinfo"]}}]}]}
+				},
+				{
+					"metadata": {"name": "deny-delete", "namespace": "bookinfo"},
+					"spec": {"selector": {"matchLabels": {"app": "reviews"}}, "action": "DENY", "rules": [{"to": [{"operation": {"methods": ["DELETE"]}}]}]}
+				},
+				{
+					"metadata": {"name": "deny-ratings", "namespace": "bookinfo"},
+					"spec": {"selector": {"matchLabels": {"app": "ratings"}}, "action": "DENY", "rules": [{}]}
+				}
+			]
+		}`,
+	})
+	defer server.Close()
+	istio := newTestIstio(t, server.URL)
+
+	result, err := istio.GetEffectiveAuthzForWorkload(context.Background(), "bookinfo", "app=reviews,version=v1")
+	if err != nil {
+		t.Fatalf("GetEffectiveAuthzForWorkload failed: %v", err)
+	}
+	assertContains(t, result,
+		"DENY:\n  - bookinfo/deny-delete (workload, 1 rules)",
+		"ALLOW:\n  - bookinfo/allow-bookinfo (namespace-wide, 1 rules)",
+		"1. DENY wins: a request matching any rule of a DENY policy is rejected, even if an ALLOW policy matches it",
+		"2. ALLOW required",
+	)
+	assertNotContains(t, result, "deny-ratings", "denies all requests")
+}
@@ -0,0 +1,223 @@
+package istio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	networkingapi "istio.io/api/networking/v1alpha3"
+	networkingv1alpha3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// exportedTo reports whether a resource in namespace with the given exportTo list is visible in the target namespace
+func exportedTo(exportTo []string, namespace, target string) bool {
+	if len(exportTo) == 0 {
+		return true
+	}
+	for _, ns := range exportTo {
+		switch ns {
+		case "*":
+			return true
+		case ".":
+			if namespace == target {
+				return true
+			}
+		case target:
+			return true
+		}
+	}
+	return false
+}
+
+// destinationRulePrecedence ranks where Istio looks up DestinationRules for a client: its own namespace,
+// then the namespace of the service, then the mesh root namespace, then anywhere else
+func destinationRulePrecedence(namespace, clientNamespace, serviceNamespace, rootNamespace string) int {
+	switch namespace {
+	case clientNamespace:
+		return 0
+	case serviceNamespace:
+		return 1
+	case rootNamespace:
+		return 2
+	}
+	return 3
+}
+
+// sortByDestinationRulePrecedence orders the DestinationRules applying to a host from the highest precedence for
+// clients in clientNamespace to the lowest
+func sortByDestinationRulePrecedence(rules []*networkingv1alpha3.DestinationRule, clientNamespace, serviceNamespace, rootNamespace string) {
+	sort.SliceStable(rules, func(a, b int) bool {
+		pa := destinationRulePrecedence(rules[a].Namespace, clientNamespace, serviceNamespace, rootNamespace)
+		pb := destinationRulePrecedence(rules[b].Namespace, clientNamespace, serviceNamespace, rootNamespace)
+		if pa != pb {
+			return pa < pb
+		}
+		// Exact hosts are more specific than wildcards
+		return !strings.Contains(rules[a].Spec.Host, "*") && strings.Contains(rules[b].Spec.Host, "*")
+	})
+}
+
+// visibleDestinationRules returns the DestinationRules matching host that are exported to clientNamespace, from the
+// highest precedence to the lowest, and the matching ones that are not. A rule without exportTo falls back to the
+// mesh-wide defaultDestinationRuleExportTo.
+func visibleDestinationRules(drs []*networkingv1alpha3.DestinationRule, host, clientNamespace string, mesh *meshConfig) (visible, hidden []*networkingv1alpha3.DestinationRule) {
+	for _, dr := range drs {
+		if !hostMatches(qualifiedHost(dr.Spec.GetHost(), dr.Namespace), host) {
+			continue
+		}
+		if exportedTo(effectiveExportTo(dr.Spec.GetExportTo(), mesh.DefaultDestinationRuleExportTo), dr.Namespace, clientNamespace) {
+			visible = append(visible, dr)
+		} else {
+			hidden = append(hidden, dr)
+		}
+	}
+	sortByDestinationRulePrecedence(visible, clientNamespace, hostNamespace(host), mesh.RootNamespace)
+	return visible, hidden
+}
+
+// appliedDestinationRules splits the visible DestinationRules of a host, ordered by precedence, into those Istio
+// merges, which share the namespace and host of the first one, and those they shadow. Istio never combines rules
+// from different namespaces.
+func appliedDestinationRules(visible []*networkingv1alpha3.DestinationRule) (applied, shadowed []*networkingv1alpha3.DestinationRule) {
+	for _, dr := range visible {
+		if len(applied) == 0 || (dr.Namespace == applied[0].Namespace && dr.Spec.GetHost() == applied[0].Spec.GetHost()) {
+			applied = append(applied, dr)
+		} else {
+			shadowed = append(shadowed, dr)
+		}
+	}
+	return applied, shadowed
+}
+
+// effectiveDestinationRule returns the DestinationRule Istio applies to host for clients in clientNamespace, or nil
+// when none does: the visible rule with the highest precedence, merged with the other rules for the same host in
+// its namespace, whose subsets are added and whose traffic policy is used only when it sets none
+func effectiveDestinationRule(drs []*networkingv1alpha3.DestinationRule, host, clientNamespace string, mesh *meshConfig) *networkingv1alpha3.DestinationRule {
+	visible, _ := visibleDestinationRules(drs, host, clientNamespace, mesh)
+	if len(visible) == 0 {
+		return nil
+	}
+	applied, _ := appliedDestinationRules(visible)
+	if len(applied) == 1 {
+		return applied[0]
+	}
+	merged := applied[0].DeepCopy()
+	for _, dr := range applied[1:] {
+		if merged.Spec.TrafficPolicy == nil {
+			merged.Spec.TrafficPolicy = dr.Spec.GetTrafficPolicy()
+		}
+		for _, subset := range dr.Spec.GetSubsets() {
+			if !slices.ContainsFunc(merged.Spec.Subsets, func(s *networkingapi.Subset) bool { return s.GetName() == subset.GetName() }) {
+				merged.Spec.Subsets = append(merged.Spec.Subsets, subset)
+			}
+		}
+	}
+	return merged
+}
+
+// GetAllDestinationRulesAffecting finds the DestinationRules in all namespaces that apply to a host for clients
+// in clientNamespace, honoring exportTo and the mesh-wide default, and reports the effective configuration. The
+// rule with the highest precedence wins; other rules for the same host in its namespace are merged into it (the
+// first traffic policy set is kept and subsets are combined), and the rules of other namespaces are shadowed. An
+// empty clientNamespace uses the namespace of the host.
+func (i *Istio) GetAllDestinationRulesAffecting(ctx context.Context, host, clientNamespace string) (string, error) {
+	if clientNamespace == "" {
+		resolved, err := i.resolveHost(ctx, host, "default")
+		if err != nil {
+			return "", err
+		}
+		clientNamespace = hostNamespace(resolved)
+		if clientNamespace == "" {
+			clientNamespace = "default"
+		}
+	}
+	target, err := i.resolveHost(ctx, host, clientNamespace)
+	if err != nil {
+		return "", err
+	}
+
+	mesh, err := i.getMeshConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+	drList, err := i.istioClient.NetworkingV1alpha3().DestinationRules("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list destination rules: %w", explainForbidden(err, "list", "destinationrules", ""))
+	}
+
+	result := fmt.Sprintf("Destination Rules affecting host '%s' for clients in namespace '%s':\n\n", target, clientNamespace)
+
+	visible, hidden := visibleDestinationRules(drList.Items, target, clientNamespace, mesh)
+	for _, dr := range hidden {
+		result += fmt.Sprintf("[SKIPPED] %s/%s: not exported to namespace '%s' (exportTo: %v)\n", dr.Namespace, dr.Name, clientNamespace,
+			effectiveExportTo(dr.Spec.GetExportTo(), mesh.DefaultDestinationRuleExportTo))
+	}
+	if len(visible) == 0 {
+		result += "No Destination Rules apply; Istio uses the default traffic policy for this host\n"
+		return result, nil
+	}
+
+	applied, shadowed := appliedDestinationRules(visible)
+	winner := applied[0].Namespace + "/" + applied[0].Name
+	var trafficPolicy interface{}
+	policySource := ""
+	var subsets []interface{}
+	subsetNames := make(map[string]bool)
+	for idx, dr := range visible {
+		name := dr.Namespace + "/" + dr.Name
+		result += fmt.Sprintf("%d. %s (host: %s)", idx+1, name, dr.Spec.Host)
+		if slices.Contains(shadowed, dr) {
+			result += fmt.Sprintf(" [SHADOWED] by %s\n", winner)
+			continue
+		}
+		result += " [APPLIED]\n"
+
+		spec, err := json.Marshal(&dr.Spec)
+		if err != nil {
+			return "", fmt.Errorf("failed to decode destination rule %s: %w", name, err)
+		}
+		var decoded struct {
+			TrafficPolicy map[string]interface{}   `json:"trafficPolicy"`
+			Subsets       []map[string]interface{} `json:"subsets"`
+		}
+		if err := json.Unmarshal(spec, &decoded); err != nil {
+			return "", fmt.Errorf("failed to decode destination rule %s: %w", name, err)
+		}
+		if trafficPolicy == nil && len(decoded.TrafficPolicy) > 0 {
+			trafficPolicy = decoded.TrafficPolicy
+			policySource = name
+		}
+		for _, subset := range decoded.Subsets {
+			subsetName, _ := subset["name"].(string)
+			if !subsetNames[subsetName] {
+				subsetNames[subsetName] = true
+				subsets = append(subsets, subset)
+			}
+		}
+	}
+
+	effective := map[string]interface{}{}
+	if trafficPolicy != nil {
+		effective["trafficPolicy"] = trafficPolicy
+	}
+	if len(subsets) > 0 {
+		effective["subsets"] = subsets
+	}
+	rendered, err := yaml.Marshal(effective)
+	if err != nil {
+		return "", fmt.Errorf("failed to format effective destination rule: %w", err)
+	}
+	result += "\nEffective configuration:\n" + string(rendered)
+	if policySource != "" {
+		result += fmt.Sprintf("\nTraffic policy from: %s\n", policySource)
+	}
+	if len(shadowed) > 0 {
+		result += fmt.Sprintf("\n[WARNING] %d Destination Rules are shadowed by %s; Istio does not merge rules across namespaces, so none of their settings apply to these clients\n", len(shadowed), winner)
+	}
+	return result, nil
+}
@@ -0,0 +1,186 @@
+package istio
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	networkingapi "istio.io/api/networking/v1alpha3"
+	networkingv1alpha3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TestGetAllDestinationRulesAffecting tests that the highest precedence namespace wins and shadows the others
+func TestGetAllDestinationRulesAffecting(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{
+		"/api/v1/namespaces/bookinfo": `{"apiVersion": "v1", "kind": "Namespace", "metadata": {"name": "bookinfo"}}`,
+		"/apis/networking.istio.io/v1alpha3/destinationrules": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "DestinationRuleList",
+			"items": [
+				{
+					"metadata": {"name": "reviews", "namespace": "bookinfo"},
+					"spec": {
+						"host": "reviews",
+						"trafficPolicy": {"tls": {"mode": "ISTIO_MUTUAL"}, "connectionPool": {"tcp": {"maxConnections": 100}}},
+						"subsets": [{"name": "v1", "labels": {"version": "v1"}}, {"name": "v2", "labels": {"version": "v2"}}]
+					}
+				},
+				{
+					"metadata": {"name": "reviews-client", "namespace": "frontend"},
+					"spec": {
+						"host": "reviews.bookinfo.svc.cluster.local",
+						"exportTo": ["."],
+						"trafficPolicy": {"connectionPool": {"tcp": {"maxConnections": 10}}}
+					}
+				},
+				{
+					"metadata": {"name": "reviews-private", "namespace": "batch"},
+					"spec": {
+						"host": "reviews.bookinfo.svc.cluster.local",
+						"exportTo": ["."],
+						"trafficPolicy": {"loadBalancer": {"simple": "RANDOM"}}
+					}
+				},
+				{
+					"metadata": {"name": "reviews-canary", "namespace": "bookinfo"},
+					"spec": {
+						"host": "reviews",
+						"trafficPolicy": {"loadBalancer": {"simple": "LEAST_REQUEST"}},
+						"subsets": [{"name": "v3", "labels": {"version": "v3"}}]
+					}
+				},
+				{
+					"metadata": {"name": "ratings", "namespace": "bookinfo"},
+					"spec": {"host": "ratings"}
+				}
+			]
+		}`,
+	})
+	defer mockServer.Close()
+
+	istio := newTestIstio(t, mockServer.URL)
+	ctx := context.Background()
+
+	t.Run("client namespace rule takes precedence", func(t *testing.T) {
+		result, err := istio.GetAllDestinationRulesAffecting(ctx, "reviews.bookinfo.svc.cluster.local", "frontend")
+		if err != nil {
+			t.Fatalf("Failed to get destination rules: %v", err)
+		}
+		assertContains(t, result,
+			"1. frontend/reviews-client (host: reviews.bookinfo.svc.cluster.local) [APPLIED]",
+			"2. bookinfo/reviews (host: reviews) [SHADOWED] by frontend/reviews-client",
+			"3. bookinfo/reviews-canary (host: reviews) [SHADOWED] by frontend/reviews-client",
+			"[SKIPPED] batch/reviews-private: not exported to namespace 'frontend'",
+			"maxConnections: 10",
+			"Traffic policy from: frontend/reviews-client",
+			"[WARNING] 2 Destination Rules are shadowed by frontend/reviews-client",
+		)
+		assertNotContains(t, result, "maxConnections: 100", "ISTIO_MUTUAL", "name: v2", "ratings", "RANDOM")
+	})
+
+	t.Run("defaults to the service namespace", func(t *testing.T) {
+		result, err := istio.GetAllDestinationRulesAffecting(ctx, "reviews.bookinfo", "")
+		if err != nil {
+			t.Fatalf("Failed to get destination rules: %v", err)
+		}
+		assertContains(t, result,
+			"for clients in namespace 'bookinfo'",
+			"1. bookinfo/reviews (host: reviews) [APPLIED]",
+			"2. bookinfo/reviews-canary (host: reviews) [APPLIED]",
+			"maxConnections: 100",
+			"name: v2",
+			"name: v3",
+			"Traffic policy from: bookinfo/reviews",
+		)
+		assertNotContains(t, result, "1. frontend/reviews-client", "LEAST_REQUEST", "shadowed")
+	})
+}
+
+// TestGetAllDestinationRulesAffectingDefaultExportTo tests that rules without exportTo follow the mesh-wide default
+func TestGetAllDestinationRulesAffectingDefaultExportTo(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{
+		"/api/v1/namespaces/istio-system/configmaps/istio": `{
+			"apiVersion": "v1",
+			"kind": "ConfigMap",
+			"metadata": {"name": "istio", "namespace": "istio-system"},
+			"data": {"mesh": "defaultDestinationRuleExportTo:\n- .\n"}
+		}`,
+		"/apis/networking.istio.io/v1alpha3/destinationrules": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "DestinationRuleList",
+			"items": [
+				{
+					"metadata": {"name": "reviews", "namespace": "bookinfo"},
+					"spec": {"host": "reviews", "trafficPolicy": {"tls": {"mode": "ISTIO_MUTUAL"}}}
+				}
+			]
+		}`,
+	})
+	defer mockServer.Close()
+
+	istio := newTestIstio(t, mockServer.URL)
+	result, err := istio.GetAllDestinationRulesAffecting(context.Background(), "reviews.bookinfo.svc.cluster.local", "frontend")
+	if err != nil {
+		t.Fatalf("Failed to get destination rules: %v", err)
+	}
+	assertContains(t, result,
+		"[SKIPPED] bookinfo/reviews: not exported to namespace 'frontend' (exportTo: [.])",
+		"No Destination Rules apply",
+	)
+}
+
+// TestEffectiveDestinationRule tests the selection of the DestinationRule Istio applies for a client namespace
+func TestEffectiveDestinationRule(t *testing.T) {
+	rule := func(namespace, name, host string, exportTo []string, policy *networkingapi.TrafficPolicy, subsets ...string) *networkingv1alpha3.DestinationRule {
+		dr := &networkingv1alpha3.DestinationRule{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+		dr.Spec.Host, dr.Spec.ExportTo, dr.Spec.TrafficPolicy = host, exportTo, policy
+		for _, subset := range subsets {
+			dr.Spec.Subsets = append(dr.Spec.Subsets, &networkingapi.Subset{Name: subset})
+		}
+		return dr
+	}
+	random := &networkingapi.TrafficPolicy{LoadBalancer: &networkingapi.LoadBalancerSettings{
+		LbPolicy: &networkingapi.LoadBalancerSettings_Simple{Simple: networkingapi.LoadBalancerSettings_RANDOM}}}
+	drs := []*networkingv1alpha3.DestinationRule{
+		rule("istio-system", "mesh-default", "*.svc.cluster.local", nil, random),
+		rule("bookinfo", "reviews", "reviews", nil, nil, "v1"),
+		rule("bookinfo", "reviews-canary", "reviews", nil, random, "v1", "v2"),
+		rule("frontend", "reviews-private", "reviews.bookinfo.svc.cluster.local", []string{"."}, nil, "v3"),
+	}
+	host := "reviews.bookinfo.svc.cluster.local"
+
+	t.Run("merges the rules for the host in the winning namespace", func(t *testing.T) {
+		dr := effectiveDestinationRule(drs, host, "bookinfo", defaultMeshConfig())
+		if dr == nil || dr.Name != "reviews" {
+			t.Fatalf("Expected bookinfo/reviews, got %v", dr)
+		}
+		var subsets []string
+		for _, subset := range dr.Spec.GetSubsets() {
+			subsets = append(subsets, subset.GetName())
+		}
+		if strings.Join(subsets, ",") != "v1,v2" || dr.Spec.GetTrafficPolicy() != random {
+			t.Errorf("Expected subsets v1,v2 and the canary traffic policy, got %v and %v", subsets, dr.Spec.GetTrafficPolicy())
+		}
+		if len(drs[1].Spec.GetSubsets()) != 1 {
+			t.Error("Expected the listed rule to be left unchanged")
+		}
+	})
+
+	t.Run("client namespace takes precedence", func(t *testing.T) {
+		if dr := effectiveDestinationRule(drs, host, "frontend", defaultMeshConfig()); dr == nil || dr.Name != "reviews-private" {
+			t.Errorf("Expected frontend/reviews-private, got %v", dr)
+		}
+	})
+
+	t.Run("honors the mesh-wide default exportTo", func(t *testing.T) {
+		mesh := defaultMeshConfig()
+		mesh.DefaultDestinationRuleExportTo = []string{"."}
+		if dr := effectiveDestinationRule(drs, host, "ratings", mesh); dr != nil {
+			t.Errorf("Expected no rule visible from namespace ratings, got %s/%s", dr.Namespace, dr.Name)
+		}
+		if dr := effectiveDestinationRule(drs, host, "ratings", defaultMeshConfig()); dr == nil || dr.Name != "reviews" {
+			t.Errorf("Expected bookinfo/reviews without a default exportTo, got %v", dr)
+		}
+	})
+}
@@ -0,0 +1,93 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+
+	networkingapi "istio.io/api/networking/v1alpha3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// defaultRetryOn are the conditions Istio retries HTTP requests on when neither the route nor the mesh config
+// sets a retry policy
+const defaultRetryOn = "connect-failure,refused-stream,unavailable,cancelled,retriable-status-codes"
+
+// describeRetries renders the attempts, per-try timeout and retry conditions of a retry policy
+func describeRetries(retries *networkingapi.HTTPRetry) string {
+	if retries.GetAttempts() == 0 {
+		return "disabled (0 attempts)"
+	}
+	description := fmt.Sprintf("%d attempts", retries.GetAttempts())
+	if retries.GetPerTryTimeout() != nil {
+		description += fmt.Sprintf(", perTryTimeout %s", retries.GetPerTryTimeout().AsDuration())
+	} else {
+		description += ", no perTryTimeout"
+	}
+	if retries.GetRetryOn() != "" {
+		description += fmt.Sprintf(", retryOn %s", retries.GetRetryOn())
+	}
+	return description
+}
+
+// GetEffectiveTimeout resolves the HTTP route a request from namespace to host and path matches and reports the
+// timeout and retries the sidecar applies to it, from the route when it sets them, otherwise the defaults: Istio
+// disables Envoy's 15s route timeout, and retries follow the defaultHttpRetryPolicy of the mesh config or Istio's
+// built-in policy
+func (i *Istio) GetEffectiveTimeout(ctx context.Context, namespace, host, path string) (string, error) {
+	if path == "" {
+		path = "/"
+	}
+	target, err := i.resolveHost(ctx, host, namespace)
+	if err != nil {
+		return "", err
+	}
+	mesh, err := i.getMeshConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+	vsList, err := i.istioClient.NetworkingV1alpha3().VirtualServices("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list virtual services: %w", explainForbidden(err, "list", "virtualservices", ""))
+	}
+
+	result := fmt.Sprintf("Effective timeout for %s%s from namespace '%s':\n\n", target, path, namespace)
+	var route *networkingapi.HTTPRoute
+	virtualServices := meshVirtualServicesForHost(vsList.Items, namespace, target, mesh)
+	if len(virtualServices) == 0 {
+		result += "No VirtualService defines this host; the default route applies\n"
+	} else {
+		vs := virtualServices[0]
+		var idx int
+		var skipped []string
+		route, idx, skipped = matchHTTPRoute(&vs.Spec, simulatedRequest{authority: host, path: path, method: "GET"})
+		if route == nil {
+			result += fmt.Sprintf("VirtualService '%s/%s': no route matches the path\n", vs.Namespace, vs.Name)
+			for _, reason := range skipped {
+				result += fmt.Sprintf("  [SKIP] %s (a plain GET without headers is assumed)\n", reason)
+			}
+			result += "\n[RESULT] The request gets a 404; no timeout or retries apply\n"
+			return result, nil
+		}
+		result += fmt.Sprintf("VirtualService '%s/%s', route %s\n", vs.Namespace, vs.Name, httpRouteName(route, idx))
+		for _, reason := range skipped {
+			result += fmt.Sprintf("  [SKIP] %s (a plain GET without headers is assumed)\n", reason)
+		}
+	}
+	result += "\n"
+
+	if route.GetTimeout() != nil {
+		result += fmt.Sprintf("Timeout: %s (set by the route)\n", route.GetTimeout().AsDuration())
+	} else {
+		result += "Timeout: none (default); Istio disables Envoy's 15s route timeout, so requests wait for the upstream indefinitely\n"
+	}
+
+	switch {
+	case route.GetRetries() != nil:
+		result += fmt.Sprintf("Retries: %s (set by the route)\n", describeRetries(route.GetRetries()))
+	case mesh.DefaultHTTPRetryPolicy != nil:
+		result += fmt.Sprintf("Retries: %s (default, from meshConfig.defaultHttpRetryPolicy)\n", describeRetries(mesh.DefaultHTTPRetryPolicy))
+	default:
+		result += fmt.Sprintf("Retries: 2 attempts, no perTryTimeout, retryOn %s (Istio default)\n", defaultRetryOn)
+	}
+	return result, nil
+}
@@ -0,0 +1,49 @@
+package istio
+
+import (
+	"context"
+	"testing"
+)
+
+// TestGetEffectiveTimeout tests resolution of the timeout and retries applying to requests to a host
+func TestGetEffectiveTimeout(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{
+		"/apis/networking.istio.io/v1alpha3/virtualservices": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "VirtualServiceList",
+			"items": [
+				{"metadata": {"name": "reviews", "namespace": "default"}, "spec": {
+					"hosts": ["reviews"],
+					"http": [
+						{"name": "api", "match": [{"uri": {"prefix": "/api"}}], "timeout": "5s",
+							"retries": {"attempts": 3, "perTryTimeout": "1s", "retryOn": "5xx"},
+							"route": [{"destination": {"host": "reviews"}}]},
+						{"route": [{"destination": {"host": "reviews"}}]}
+					]
+				}}
+			]
+		}`,
+	})
+	defer mockServer.Close()
+	istio := newTestIstio(t, mockServer.URL)
+
+	result, err := istio.GetEffectiveTimeout(context.Background(), "default", "reviews", "/api/v1")
+	if err != nil {
+		t.Fatalf("GetEffectiveTimeout failed: %v", err)
+	}
+	assertContains(t, result,
+		"VirtualService 'default/reviews', route 'api'",
+		"Timeout: 5s (set by the route)",
+		"Retries: 3 attempts, perTryTimeout 1s, retryOn 5xx (set by the route)",
+	)
+
+	result, err = istio.GetEffectiveTimeout(context.Background(), "default", "reviews", "/health")
+	if err != nil {
+		t.Fatalf("GetEffectiveTimeout failed: %v", err)
+	}
+	assertContains(t, result,
+		"route #2",
+		"Timeout: none (default)",
+		"(Istio default)",
+	)
+}
@@ -0,0 +1,233 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	networkingapi "istio.io/api/networking/v1alpha3"
+	networkingv1alpha3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// gatewayRef resolves a Gateway reference of a VirtualService in namespace, 'name' or 'namespace/name', to
+// 'namespace/name'; the reserved 'mesh' gateway is returned as is
+func gatewayRef(ref, namespace string) string {
+	if ref == "mesh" || strings.Contains(ref, "/") {
+		return ref
+	}
+	return namespace + "/" + ref
+}
+
+// virtualServiceRoute is an HTTP, TLS or TCP route of a VirtualService reduced to the gateways it applies to and
+// its destinations
+type virtualServiceRoute struct {
+	// gateways are resolved with gatewayRef; empty means the gateways of the VirtualService
+	gateways     []string
+	destinations []*networkingapi.Destination
+}
+
+// newVirtualServiceRoute reduces the match conditions and destinations of an HTTP, TLS or TCP route of a
+// VirtualService in namespace to a virtualServiceRoute
+func newVirtualServiceRoute[M interface{ GetGateways() []string }, D interface {
+	GetDestination() *networkingapi.Destination
+}](namespace string, matches []M, routeDestinations []D) virtualServiceRoute {
+	var route virtualServiceRoute
+	for _, rd := range routeDestinations {
+		route.destinations = append(route.destinations, rd.GetDestination())
+	}
+	for _, match := range matches {
+		if len(match.GetGateways()) == 0 {
+			// A match condition without gateways applies to all of them
+			route.gateways = nil
+			break
+		}
+		for _, gateway := range match.GetGateways() {
+			route.gateways = append(route.gateways, gatewayRef(gateway, namespace))
+		}
+	}
+	return route
+}
+
+// virtualServiceRoutes collects the routes of a VirtualService with the gateways their match conditions restrict them to
+func virtualServiceRoutes(vs *networkingv1alpha3.VirtualService) []virtualServiceRoute {
+	var routes []virtualServiceRoute
+	for _, route := range vs.Spec.GetHttp() {
+		routes = append(routes, newVirtualServiceRoute(vs.Namespace, route.GetMatch(), route.GetRoute()))
+	}
+	for _, route := range vs.Spec.GetTls() {
+		routes = append(routes, newVirtualServiceRoute(vs.Namespace, route.GetMatch(), route.GetRoute()))
+	}
+	for _, route := range vs.Spec.GetTcp() {
+		routes = append(routes, newVirtualServiceRoute(vs.Namespace, route.GetMatch(), route.GetRoute()))
+	}
+	return routes
+}
+
+// appliesTo reports whether a route applies to traffic through a gateway of its VirtualService
+func (r virtualServiceRoute) appliesTo(gateway string) bool {
+	return len(r.gateways) == 0 || slices.Contains(r.gateways, gateway)
+}
+
+// CheckEgressGatewayRouting validates the chain routing requests from namespace to an external host through an
+// egress gateway: the ServiceEntry declaring the host, the VirtualService sending mesh traffic to the egress
+// gateway and gateway traffic to the host, the Gateway admitting the host, and the DestinationRule for the egress
+// gateway service. Each missing piece is reported.
+func (i *Istio) CheckEgressGatewayRouting(ctx context.Context, namespace, host string) (string, error) {
+	result := fmt.Sprintf("Egress gateway routing for host '%s' from namespace '%s':\n\n", host, namespace)
+	mesh, err := i.getMeshConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+	failures := 0
+	fail := func(format string, args ...any) {
+		failures++
+		result += "[FAIL] " + fmt.Sprintf(format, args...) + "\n"
+	}
+
+	seList, err := i.istioClient.NetworkingV1alpha3().ServiceEntries("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list service entries: %w", i.explainAPIError(ctx, err, "list", "serviceentries", ""))
+	}
+	var serviceEntries []string
+	for _, se := range seList.Items {
+		if !exportedTo(effectiveExportTo(se.Spec.ExportTo, mesh.DefaultServiceExportTo), se.Namespace, namespace) {
+			continue
+		}
+		for _, seHost := range se.Spec.Hosts {
+			if hostMatches(seHost, host) {
+				serviceEntries = append(serviceEntries, fmt.Sprintf("%s/%s (ports %s)", se.Namespace, se.Name, strings.Trim(serviceEntryPorts(se), "[]")))
+				break
+			}
+		}
+	}
+	if len(serviceEntries) == 0 {
+		fail("No ServiceEntry visible in namespace '%s' declares host '%s'; the egress gateway has no cluster for it", namespace, host)
+	} else {
+		result += fmt.Sprintf("[OK] ServiceEntry: %s\n", strings.Join(serviceEntries, ", "))
+	}
+
+	vsList, err := i.istioClient.NetworkingV1alpha3().VirtualServices("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list virtual services: %w", explainForbidden(err, "list", "virtualservices", ""))
+	}
+	var vs *networkingv1alpha3.VirtualService
+	for _, candidate := range vsList.Items {
+		if !exportedTo(effectiveExportTo(candidate.Spec.ExportTo, mesh.DefaultVirtualServiceExportTo), candidate.Namespace, namespace) || len(candidate.Spec.Gateways) < 2 {
+			continue
+		}
+		if slices.ContainsFunc(candidate.Spec.Hosts, func(vsHost string) bool { return hostMatches(vsHost, host) }) {
+			vs = candidate
+			break
+		}
+	}
+	if vs == nil {
+		fail("No VirtualService for host '%s' is bound to both the mesh and an egress gateway; requests go directly to the external host, bypassing the gateway", host)
+		result += fmt.Sprintf("\n[RESULT] %d pieces of the egress chain are missing\n", failures)
+		return result, nil
+	}
+	result += fmt.Sprintf("[OK] VirtualService: %s/%s (gateways: %s)\n", vs.Namespace, vs.Name, strings.Join(vs.Spec.Gateways, ", "))
+
+	gwList, err := i.istioClient.NetworkingV1alpha3().Gateways("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list gateways: %w", explainForbidden(err, "list", "gateways", ""))
+	}
+	var gateway *networkingv1alpha3.Gateway
+	for _, ref := range vs.Spec.Gateways {
+		ref = gatewayRef(ref, vs.Namespace)
+		if ref == "mesh" {
+			continue
+		}
+		idx := slices.IndexFunc(gwList.Items, func(gw *networkingv1alpha3.Gateway) bool { return gw.Namespace+"/"+gw.Name == ref })
+		switch {
+		case idx < 0:
+			fail("Gateway '%s' referenced by the VirtualService does not exist", ref)
+		case !gatewayPermitsHost(gwList.Items[idx], host, vs.Namespace):
+			fail("Gateway '%s' has no server admitting host '%s' from namespace '%s'", ref, host, vs.Namespace)
+		default:
+			gateway = gwList.Items[idx]
+			result += fmt.Sprintf("[OK] Gateway: %s admits host '%s'\n", ref, host)
+		}
+		if gateway != nil {
+			break
+		}
+	}
+	if gateway == nil {
+		result += fmt.Sprintf("\n[RESULT] %d pieces of the egress chain are missing\n", failures)
+		return result, nil
+	}
+	gatewayName := gateway.Namespace + "/" + gateway.Name
+
+	// Mesh traffic must go to the egress gateway service, and traffic through the gateway on to the external host
+	var meshDestination *networkingapi.Destination
+	gatewayToHost := false
+	for _, route := range virtualServiceRoutes(vs) {
+		for _, destination := range route.destinations {
+			toHost := hostMatches(host, destination.GetHost())
+			if route.appliesTo("mesh") && !toHost && meshDestination == nil {
+				meshDestination = destination
+			}
+			if route.appliesTo(gatewayName) && toHost {
+				gatewayToHost = true
+			}
+		}
+	}
+	if !gatewayToHost {
+		fail("No route of the VirtualService for gateway '%s' sends requests on to '%s'", gatewayName, host)
+	} else {
+		result += fmt.Sprintf("[OK] Gateway route: %s -> %s\n", gatewayName, host)
+	}
+	if meshDestination == nil {
+		fail("No route of the VirtualService for the mesh sends requests to the egress gateway")
+		result += fmt.Sprintf("\n[RESULT] %d pieces of the egress chain are missing\n", failures)
+		return result, nil
+	}
+	gatewayHost := qualifiedHost(meshDestination.GetHost(), vs.Namespace)
+
+	services, err := i.kubeClient.CoreV1().Services("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list services: %w", explainForbidden(err, "list", "services", ""))
+	}
+	pods, err := i.kubeClient.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list pods: %w", explainForbidden(err, "list", "pods", ""))
+	}
+	var fronting []string
+	for _, service := range gatewayServices(gateway.Spec.Selector, services.Items, pods.Items) {
+		fronting = append(fronting, qualifiedHost(service.Name, service.Namespace))
+	}
+	if !slices.Contains(fronting, gatewayHost) {
+		fail("The mesh route sends requests to '%s', which is not a service of the workload Gateway '%s' selects (%s)", gatewayHost, gatewayName, strings.Join(fronting, ", "))
+	} else {
+		result += fmt.Sprintf("[OK] Mesh route: -> %s\n", gatewayHost)
+	}
+
+	drList, err := i.istioClient.NetworkingV1alpha3().DestinationRules("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list destination rules: %w", explainForbidden(err, "list", "destinationrules", ""))
+	}
+	var rule *networkingv1alpha3.DestinationRule
+	for _, dr := range drList.Items {
+		if hostMatches(qualifiedHost(dr.Spec.Host, dr.Namespace), gatewayHost) && exportedTo(effectiveExportTo(dr.Spec.ExportTo, mesh.DefaultDestinationRuleExportTo), dr.Namespace, namespace) {
+			rule = dr
+			break
+		}
+	}
+	subset := meshDestination.GetSubset()
+	switch {
+	case rule == nil:
+		fail("No DestinationRule for the egress gateway service '%s'; define one with the subset and TLS settings used to reach the gateway", gatewayHost)
+	case subset != "" && !slices.ContainsFunc(rule.Spec.Subsets, func(s *networkingapi.Subset) bool { return s.GetName() == subset }):
+		fail("DestinationRule '%s/%s' does not define subset '%s' the mesh route uses; requests fail with 503", rule.Namespace, rule.Name, subset)
+	default:
+		result += fmt.Sprintf("[OK] DestinationRule: %s/%s for %s\n", rule.Namespace, rule.Name, gatewayHost)
+	}
+
+	if failures == 0 {
+		result += fmt.Sprintf("\n[RESULT] Requests from namespace '%s' to '%s' leave the mesh through egress gateway '%s'\n", namespace, host, gatewayName)
+	} else {
+		result += fmt.Sprintf("\n[RESULT] %d pieces of the egress chain are missing\n", failures)
+	}
+	return result, nil
+}
@@ -0,0 +1,117 @@
+package istio
+
+import (
+	"context"
+	"testing"
+)
+
+// egressChain is the Istio configuration routing requests to edition.cnn.com through the egress gateway, keyed by API path
+func egressChain() map[string]string {
+	return map[string]string{
+		"/apis/networking.istio.io/v1alpha3/serviceentries": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "ServiceEntryList",
+			"items": [{"metadata": {"name": "cnn", "namespace": "default"}, "spec": {
+				"hosts": ["edition.cnn.com"], "resolution": "DNS",
+				"ports": [{"number": 80, "name": "http-port", "protocol": "HTTP"}, {"number": 443, "name": "https", "protocol": "HTTPS"}]
+			}}]
+		}`,
+		"/apis/networking.istio.io/v1alpha3/virtualservices": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "VirtualServiceList",
+			"items": [{"metadata": {"name": "direct-cnn-through-egress-gateway", "namespace": "default"}, "spec": {
+				"hosts": ["edition.cnn.com"],
+				"gateways": ["istio-egressgateway", "mesh"],
+				"http": [
+					{"match": [{"gateways": ["mesh"], "port": 80}], "route": [{"destination": {"host": "istio-egressgateway.istio-system.svc.cluster.local", "subset": "cnn", "port": {"number": 80}}}]},
+					{"match": [{"gateways": ["istio-egressgateway"], "port": 80}], "route": [{"destination": {"host": "edition.cnn.com", "port": {"number": 80}}}]}
+				]
+			}}]
+		}`,
+		"/apis/networking.istio.io/v1alpha3/gateways": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "GatewayList",
+			"items": [{"metadata": {"name": "istio-egressgateway", "namespace": "default"}, "spec": {
+				"selector": {"istio": "egressgateway"},
+				"servers": [{"port": {"number": 80, "name": "http", "protocol": "HTTP"}, "hosts": ["edition.cnn.com"]}]
+			}}]
+		}`,
+		"/api/v1/services": `{
+			"apiVersion": "v1",
+			"kind": "ServiceList",
+			"items": [{"metadata": {"name": "istio-egressgateway", "namespace": "istio-system"}, "spec": {"selector": {"app": "istio-egressgateway", "istio": "egressgateway"}}}]
+		}`,
+		"/api/v1/pods": `{"apiVersion": "v1", "kind": "PodList", "items": []}`,
+		"/apis/networking.istio.io/v1alpha3/destinationrules": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "DestinationRuleList",
+			"items": [{"metadata": {"name": "egressgateway-for-cnn", "namespace": "default"}, "spec": {
+				"host": "istio-egressgateway.istio-system.svc.cluster.local",
+				"subsets": [{"name": "cnn"}]
+			}}]
+		}`,
+	}
+}
+
+// TestCheckEgressGatewayRouting tests the checks of the resources routing external traffic through an egress gateway
+func TestCheckEgressGatewayRouting(t *testing.T) {
+	t.Run("complete chain", func(t *testing.T) {
+		mockServer := newMockAPIServer(egressChain())
+		defer mockServer.Close()
+		istio := newTestIstio(t, mockServer.URL)
+
+		result, err := istio.CheckEgressGatewayRouting(context.Background(), "default", "edition.cnn.com")
+		if err != nil {
+			t.Fatalf("CheckEgressGatewayRouting failed: %v", err)
+		}
+		assertContains(t, result,
+			"[OK] ServiceEntry: default/cnn (ports 443/HTTPS, 80/HTTP)",
+			"[OK] VirtualService: default/direct-cnn-through-egress-gateway",
+			"[OK] Gateway: default/istio-egressgateway admits host 'edition.cnn.com'",
+			"[OK] Gateway route: default/istio-egressgateway -> edition.cnn.com",
+			"[OK] Mesh route: -> istio-egressgateway.istio-system.svc.cluster.local",
+			"[OK] DestinationRule: default/egressgateway-for-cnn",
+			"[RESULT] Requests from namespace 'default' to 'edition.cnn.com' leave the mesh through egress gateway 'default/istio-egressgateway'",
+		)
+		assertNotContains(t, result, "[FAIL]")
+	})
+
+	t.Run("missing destination rule", func(t *testing.T) {
+		paths := egressChain()
+		paths["/apis/networking.istio.io/v1alpha3/destinationrules"] = `{"apiVersion": "networking.istio.io/v1alpha3", "kind": "DestinationRuleList", "items": []}`
+		mockServer := newMockAPIServer(paths)
+		defer mockServer.Close()
+		istio := newTestIstio(t, mockServer.URL)
+
+		result, err := istio.CheckEgressGatewayRouting(context.Background(), "default", "edition.cnn.com")
+		if err != nil {
+			t.Fatalf("CheckEgressGatewayRouting failed: %v", err)
+		}
+		assertContains(t, result,
+			"[FAIL] No DestinationRule for the egress gateway service 'istio-egressgateway.istio-system.svc.cluster.local'",
+			"[RESULT] 1 pieces of the egress chain are missing",
+		)
+	})
+
+	t.Run("service entry hidden by the mesh default exportTo", func(t *testing.T) {
+		paths := egressChain()
+		paths["/api/v1/namespaces/istio-system/configmaps/istio"] = `{
+			"apiVersion": "v1",
+			"kind": "ConfigMap",
+			"metadata": {"name": "istio", "namespace": "istio-system"},
+			"data": {"mesh": "defaultServiceExportTo:\n- .\n"}
+		}`
+		mockServer := newMockAPIServer(paths)
+		defer mockServer.Close()
+		istio := newTestIstio(t, mockServer.URL)
+
+		result, err := istio.CheckEgressGatewayRouting(context.Background(), "frontend", "edition.cnn.com")
+		if err != nil {
+			t.Fatalf("CheckEgressGatewayRouting failed: %v", err)
+		}
+		assertContains(t, result,
+			"[FAIL] No ServiceEntry visible in namespace 'frontend' declares host 'edition.cnn.com'",
+			"[OK] VirtualService: default/direct-cnn-through-egress-gateway",
+		)
+	})
+}
@@ -0,0 +1,129 @@
+package istio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// endpointConcentrationShare is the share of a cluster's traffic which, when received by at most half of its healthy
+// endpoints, is reported as weight skew
+const endpointConcentrationShare = 0.8
+
+// clusterEndpoints is a cluster in the JSON output of istioctl proxy-config endpoint
+type clusterEndpoints struct {
+	Name         string `json:"name"`
+	HostStatuses []struct {
+		Address struct {
+			SocketAddress struct {
+				Address   string `json:"address"`
+				PortValue int    `json:"portValue"`
+			} `json:"socketAddress"`
+		} `json:"address"`
+		HealthStatus struct {
+			EdsHealthStatus         string `json:"edsHealthStatus"`
+			FailedOutlierCheck      bool   `json:"failedOutlierCheck"`
+			FailedActiveHealthCheck bool   `json:"failedActiveHealthCheck"`
+		} `json:"healthStatus"`
+		Weight int `json:"weight"`
+	} `json:"hostStatuses"`
+}
+
+// endpointHealth classifies an endpoint as HEALTHY, UNHEALTHY or DRAINING from its EDS status and health checks
+func endpointHealth(edsStatus string, failedOutlierCheck, failedActiveHealthCheck bool) string {
+	switch {
+	case edsStatus == "DRAINING":
+		return "DRAINING"
+	case edsStatus == "UNHEALTHY" || edsStatus == "TIMEOUT" || failedOutlierCheck || failedActiveHealthCheck:
+		return "UNHEALTHY"
+	}
+	return "HEALTHY"
+}
+
+// concentratedEndpoints returns how many of the given endpoint weights receive endpointConcentrationShare of the traffic
+func concentratedEndpoints(weights []int) int {
+	total := 0
+	for _, weight := range weights {
+		total += weight
+	}
+	sorted := append([]int(nil), weights...)
+	sort.Sort(sort.Reverse(sort.IntSlice(sorted)))
+	received := 0
+	for idx, weight := range sorted {
+		received += weight
+		if float64(received) >= endpointConcentrationShare*float64(total) {
+			return idx + 1
+		}
+	}
+	return len(sorted)
+}
+
+// GetEndpointHealthSummary summarizes, for each cluster of a pod's proxy, its healthy, unhealthy and draining
+// endpoints and flags clusters whose load-balancing weights concentrate traffic on few endpoints
+func (p *ProxyConfigClient) GetEndpointHealthSummary(ctx context.Context, namespace, podName string) (string, error) {
+	output, err := p.GetEndpoints(ctx, namespace, podName)
+	if err != nil {
+		return "", err
+	}
+	var clusters []clusterEndpoints
+	if err := json.Unmarshal([]byte(output), &clusters); err != nil {
+		return "", fmt.Errorf("failed to parse endpoints of pod %s: %w", podName, err)
+	}
+	sort.Slice(clusters, func(a, b int) bool {
+		return clusters[a].Name < clusters[b].Name
+	})
+
+	result := fmt.Sprintf("Endpoint health of the clusters of pod '%s' in namespace '%s':\n\n", podName, namespace)
+	summarized, flagged := 0, 0
+	for _, cluster := range clusters {
+		if len(cluster.HostStatuses) == 0 {
+			continue
+		}
+		summarized++
+		counts := map[string]int{}
+		var healthyWeights []int
+		var endpoints []string
+		for _, host := range cluster.HostStatuses {
+			health := endpointHealth(host.HealthStatus.EdsHealthStatus, host.HealthStatus.FailedOutlierCheck, host.HealthStatus.FailedActiveHealthCheck)
+			counts[health]++
+			weight := host.Weight
+			if weight == 0 {
+				weight = 1
+			}
+			if health == "HEALTHY" {
+				healthyWeights = append(healthyWeights, weight)
+			}
+			endpoints = append(endpoints, fmt.Sprintf("    %s:%d %s (weight %d)", host.Address.SocketAddress.Address, host.Address.SocketAddress.PortValue, health, weight))
+		}
+
+		var warnings []string
+		if counts["UNHEALTHY"] > 0 || counts["DRAINING"] > 0 {
+			warnings = append(warnings, fmt.Sprintf("  [WARNING] Only %d of %d endpoints receive traffic", counts["HEALTHY"], len(cluster.HostStatuses)))
+		}
+		if n := len(healthyWeights); n >= 2 {
+			if top := concentratedEndpoints(healthyWeights); top <= n/2 {
+				warnings = append(warnings, fmt.Sprintf("  [WARNING] Weight skew: %d of %d healthy endpoints receive %.0f%%+ of the traffic", top, n, endpointConcentrationShare*100))
+			}
+		}
+
+		result += fmt.Sprintf("%s: %d endpoints (%d healthy, %d unhealthy, %d draining)\n", cluster.Name,
+			len(cluster.HostStatuses), counts["HEALTHY"], counts["UNHEALTHY"], counts["DRAINING"])
+		if len(warnings) > 0 {
+			flagged++
+			result += strings.Join(warnings, "\n") + "\n" + strings.Join(endpoints, "\n") + "\n"
+		}
+	}
+
+	if summarized == 0 {
+		result += "No cluster has endpoints\n"
+		return result, nil
+	}
+	if flagged == 0 {
+		result += fmt.Sprintf("\n[OK] All endpoints of the %d clusters are healthy and evenly weighted\n", summarized)
+	} else {
+		result += fmt.Sprintf("\n[RESULT] %d of %d clusters have unhealthy endpoints or uneven weights\n", flagged, summarized)
+	}
+	return result, nil
+}
@@ -0,0 +1,45 @@
+package istio
+
+import (
+	"context"
+	"testing"
+)
+
+// TestGetEndpointHealthSummary tests summarizing of the endpoint health and weights of each cluster of a proxy
+func TestGetEndpointHealthSummary(t *testing.T) {
+	client := NewProxyConfigClient("")
+	stubIstioctl(client, `[
+		{"name": "outbound|9080||reviews.bookinfo.svc.cluster.local", "hostStatuses": [
+			{"address": {"socketAddress": {"address": "10.0.0.1", "portValue": 9080}}, "healthStatus": {"edsHealthStatus": "HEALTHY"}, "weight": 1},
+			{"address": {"socketAddress": {"address": "10.0.0.2", "portValue": 9080}}, "healthStatus": {"edsHealthStatus": "HEALTHY", "failedOutlierCheck": true}, "weight": 1},
+			{"address": {"socketAddress": {"address": "10.0.0.3", "portValue": 9080}}, "healthStatus": {"edsHealthStatus": "DRAINING"}, "weight": 1},
+			{"address": {"socketAddress": {"address": "10.0.0.4", "portValue": 9080}}, "healthStatus": {"edsHealthStatus": "HEALTHY"}, "weight": 1}
+		]},
+		{"name": "outbound|9080||ratings.bookinfo.svc.cluster.local", "hostStatuses": [
+			{"address": {"socketAddress": {"address": "10.0.1.1", "portValue": 9080}}, "healthStatus": {"edsHealthStatus": "HEALTHY"}, "weight": 10},
+			{"address": {"socketAddress": {"address": "10.0.1.2", "portValue": 9080}}, "healthStatus": {"edsHealthStatus": "HEALTHY"}, "weight": 1},
+			{"address": {"socketAddress": {"address": "10.0.1.3", "portValue": 9080}}, "healthStatus": {"edsHealthStatus": "HEALTHY"}, "weight": 1}
+		]},
+		{"name": "outbound|9080||details.bookinfo.svc.cluster.local", "hostStatuses": [
+			{"address": {"socketAddress": {"address": "10.0.2.1", "portValue": 9080}}, "healthStatus": {"edsHealthStatus": "HEALTHY"}, "weight": 1},
+			{"address": {"socketAddress": {"address": "10.0.2.2", "portValue": 9080}}, "healthStatus": {"edsHealthStatus": "HEALTHY"}, "weight": 1}
+		]},
+		{"name": "BlackHoleCluster"}
+	]`)
+
+	result, err := client.GetEndpointHealthSummary(context.Background(), "bookinfo", "productpage-v1-abc")
+	if err != nil {
+		t.Fatalf("GetEndpointHealthSummary failed: %v", err)
+	}
+	assertContains(t, result,
+		"outbound|9080||reviews.bookinfo.svc.cluster.local: 4 endpoints (2 healthy, 1 unhealthy, 1 draining)",
+		"[WARNING] Only 2 of 4 endpoints receive traffic",
+		"10.0.0.2:9080 UNHEALTHY (weight 1)",
+		"10.0.0.3:9080 DRAINING (weight 1)",
+		"outbound|9080||ratings.bookinfo.svc.cluster.local: 3 endpoints (3 healthy, 0 unhealthy, 0 draining)",
+		"[WARNING] Weight skew: 1 of 3 healthy endpoints receive 80%+ of the traffic",
+		"outbound|9080||details.bookinfo.svc.cluster.local: 2 endpoints (2 healthy, 0 unhealthy, 0 draining)",
+		"[RESULT] 2 of 3 clusters have unhealthy endpoints or uneven weights",
+	)
+	assertNotContains(t, result, "BlackHoleCluster", "10.0.2.1")
+}
@@ -0,0 +1,100 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	networkingv1alpha3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// envoyFilterPatchTargets lists the distinct applyTo targets of an EnvoyFilter's patches, in order of appearance
+func envoyFilterPatchTargets(ef *networkingv1alpha3.EnvoyFilter) []string {
+	var targets []string
+	for _, patch := range ef.Spec.GetConfigPatches() {
+		target := patch.GetApplyTo().String()
+		if !slices.Contains(targets, target) {
+			targets = append(targets, target)
+		}
+	}
+	return targets
+}
+
+// GetEnvoyFiltersForWorkload lists the EnvoyFilters of the mesh root namespace and the pod's namespace whose
+// workload selector matches the pod, in the order Istio applies them: root namespace filters first, then by
+// priority (lower first) and creation time
+func (i *Istio) GetEnvoyFiltersForWorkload(ctx context.Context, namespace, podName string) (string, error) {
+	pod, err := i.kubeClient.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get pod %s: %w", podName, explainForbidden(err, "get", "pods", namespace))
+	}
+	mesh, err := i.getMeshConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	namespaces := []string{namespace}
+	if mesh.RootNamespace != namespace {
+		namespaces = append([]string{mesh.RootNamespace}, namespaces...)
+	}
+	var applied []*networkingv1alpha3.EnvoyFilter
+	var skipped []string
+	for _, ns := range namespaces {
+		list, err := i.istioClient.NetworkingV1alpha3().EnvoyFilters(ns).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return "", fmt.Errorf("failed to list envoy filters: %w", explainForbidden(err, "list", "envoyfilters", ns))
+		}
+		var matching []*networkingv1alpha3.EnvoyFilter
+		for _, ef := range list.Items {
+			// Filters attached to gateways or services through targetRefs don't select workloads by label
+			if len(ef.Spec.GetTargetRefs()) > 0 {
+				skipped = append(skipped, ef.Namespace+"/"+ef.Name)
+				continue
+			}
+			selector := ef.Spec.GetWorkloadSelector().GetLabels()
+			if len(selector) > 0 && !labels.SelectorFromSet(selector).Matches(labels.Set(pod.Labels)) {
+				continue
+			}
+			matching = append(matching, ef)
+		}
+		sort.SliceStable(matching, func(a, b int) bool {
+			if matching[a].Spec.GetPriority() != matching[b].Spec.GetPriority() {
+				return matching[a].Spec.GetPriority() < matching[b].Spec.GetPriority()
+			}
+			if !matching[a].CreationTimestamp.Equal(&matching[b].CreationTimestamp) {
+				return matching[a].CreationTimestamp.Before(&matching[b].CreationTimestamp)
+			}
+			return matching[a].Name < matching[b].Name
+		})
+		applied = append(applied, matching...)
+	}
+
+	result := fmt.Sprintf("EnvoyFilters applied to pod '%s' in namespace '%s':\n\n", podName, namespace)
+	if len(applied) == 0 {
+		result += "No EnvoyFilter applies to this pod\n"
+	}
+	for idx, ef := range applied {
+		scope := "namespace-wide"
+		switch {
+		case len(ef.Spec.GetWorkloadSelector().GetLabels()) > 0:
+			scope = fmt.Sprintf("selector %s", labels.SelectorFromSet(ef.Spec.GetWorkloadSelector().GetLabels()))
+		case ef.Namespace == mesh.RootNamespace:
+			scope = "mesh-wide"
+		}
+		result += fmt.Sprintf("%d. %s/%s (%s, priority %d)\n", idx+1, ef.Namespace, ef.Name, scope, ef.Spec.GetPriority())
+		if targets := envoyFilterPatchTargets(ef); len(targets) > 0 {
+			result += fmt.Sprintf("   Patches: %d (%s)\n", len(ef.Spec.GetConfigPatches()), strings.Join(targets, ", "))
+		}
+	}
+	if len(skipped) > 0 {
+		result += fmt.Sprintf("\nNot evaluated (attached to gateways or services through targetRefs): %s\n", strings.Join(skipped, ", "))
+	}
+	if len(applied) > 0 {
+		result += fmt.Sprintf("\n[RESULT] %d EnvoyFilters patch this proxy, applied in the order listed\n", len(applied))
+	}
+	return result, nil
+}
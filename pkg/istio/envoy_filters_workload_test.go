@@ -0,0 +1,59 @@
+package istio
+
+import (
+	"context"
+	"testing"
+)
+
+// TestGetEnvoyFiltersForWorkload tests selection of the EnvoyFilters applying to a workload
+func TestGetEnvoyFiltersForWorkload(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{
+		"/api/v1/namespaces/bookinfo/pods/reviews-v1-abc": `{
+			"apiVersion": "v1",
+			"kind": "Pod",
+			"metadata": {"name": "reviews-v1-abc", "namespace": "bookinfo", "labels": {"app": "reviews", "version": "v1"}},
+			"spec": {"containers": [{"name": "reviews"}, {"name": "istio-proxy"}]}
+		}`,
+		"/apis/networking.istio.io/v1alpha3/namespaces/istio-system/envoyfilters": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "EnvoyFilterList",
+			"items": [
+				{"metadata": {"name": "mesh-lua", "namespace": "istio-system"}, "spec": {"configPatches": [{"applyTo": "HTTP_FILTER"}]}}
+			]
+		}`,
+		"/apis/networking.istio.io/v1alpha3/namespaces/bookinfo/envoyfilters": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "EnvoyFilterList",
+			"items": [
+				{"metadata": {"name": "reviews-buffer", "namespace": "bookinfo", "creationTimestamp": "2024-01-01T00:00:00Z"}, "spec": {
+					"workloadSelector": {"labels": {"app": "reviews"}},
+					"priority": -1,
+					"configPatches": [{"applyTo": "HTTP_FILTER"}, {"applyTo": "CLUSTER"}]
+				}},
+				{"metadata": {"name": "namespace-timeouts", "namespace": "bookinfo", "creationTimestamp": "2023-01-01T00:00:00Z"}, "spec": {
+					"configPatches": [{"applyTo": "NETWORK_FILTER"}]
+				}},
+				{"metadata": {"name": "ratings-only", "namespace": "bookinfo"}, "spec": {
+					"workloadSelector": {"labels": {"app": "ratings"}},
+					"configPatches": [{"applyTo": "LISTENER"}]
+				}}
+			]
+		}`,
+	})
+	defer mockServer.Close()
+
+	istio := newTestIstio(t, mockServer.URL)
+
+	result, err := istio.GetEnvoyFiltersForWorkload(context.Background(), "bookinfo", "reviews-v1-abc")
+	if err != nil {
+		t.Fatalf("GetEnvoyFiltersForWorkload failed: %v", err)
+	}
+	assertContains(t, result,
+		"1. istio-system/mesh-lua (mesh-wide, priority 0)",
+		"2. bookinfo/reviews-buffer (selector app=reviews, priority -1)",
+		"   Patches: 2 (HTTP_FILTER, CLUSTER)",
+		"3. bookinfo/namespace-timeouts (namespace-wide, priority 0)",
+		"[RESULT] 3 EnvoyFilters patch this proxy",
+	)
+	assertNotContains(t, result, "ratings-only")
+}
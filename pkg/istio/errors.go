@@ -0,0 +1,102 @@
+package istio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// forbiddenError explains an RBAC denial returned by the Kubernetes API
+type forbiddenError struct {
+	verb      string
+	resource  string
+	namespace string
+	err       error
+}
+
+func (e *forbiddenError) Error() string {
+	scope := fmt.Sprintf("in namespace '%s'", e.namespace)
+	if e.namespace == "" {
+		scope = "across all namespaces"
+	}
+	return fmt.Sprintf("cannot %s %s %s: the configured identity lacks RBAC permission; grant '%s' on '%s' with a Role or ClusterRole bound to it",
+		e.verb, e.resource, scope, e.verb, e.resource)
+}
+
+func (e *forbiddenError) Unwrap() error {
+	return e.err
+}
+
+// explainForbidden turns a Forbidden error from a List/Get call into actionable guidance naming the
+// missing permission. Other errors are returned unchanged.
+func explainForbidden(err error, verb, resource, namespace string) error {
+	if !apierrors.IsForbidden(err) {
+		return err
+	}
+	var status apierrors.APIStatus
+	if errors.As(err, &status) {
+		if details := status.Status().Details; details != nil && details.Group != "" {
+			resource = resource + "." + details.Group
+		}
+	}
+	return &forbiddenError{verb: verb, resource: resource, namespace: namespace, err: err}
+}
+
+// crdNotInstalledError explains that a request for an Istio resource failed because the CRDs of its API group are
+// missing from the cluster, which the API server reports as a confusing 404
+type crdNotInstalledError struct {
+	group string
+	err   error
+}
+
+func (e *crdNotInstalledError) Error() string {
+	return fmt.Sprintf("Istio %s CRDs are not installed in this cluster: install Istio (e.g. 'istioctl install' or the istio/base Helm chart), or check that the kubeconfig points at the cluster running Istio", e.group)
+}
+
+func (e *crdNotInstalledError) Unwrap() error {
+	return e.err
+}
+
+// istioResourceGroup returns the API group of a supported Istio resource by its plural name, or "" for other resources
+func istioResourceGroup(plural string) string {
+	for _, rk := range resourceKinds {
+		if rk.plural == plural {
+			return rk.gvk.Group
+		}
+	}
+	return ""
+}
+
+// apiGroupServed reports whether the API server serves a group. Discovery failures count as served, so that the
+// original error is reported rather than a guess.
+func (i *Istio) apiGroupServed(ctx context.Context, group string) bool {
+	// The discovery client's ServerGroups takes no context, so the group list is requested directly to honor ctx
+	var groups metav1.APIGroupList
+	if err := i.kubeClient.Discovery().RESTClient().Get().AbsPath("/apis").Do(ctx).Into(&groups); err != nil {
+		klog.V(1).Infof("Failed to discover API groups: %v", err)
+		return true
+	}
+	for _, served := range groups.Groups {
+		if served.Name == group {
+			return true
+		}
+	}
+	return false
+}
+
+// explainAPIError explains a failed call for an Istio resource: RBAC denials like explainForbidden, and NotFound
+// errors caused by the CRDs of the resource's API group not being installed
+func (i *Istio) explainAPIError(ctx context.Context, err error, verb, resource, namespace string) error {
+	if !apierrors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+		return explainForbidden(err, verb, resource, namespace)
+	}
+	if group := istioResourceGroup(resource); group != "" && !i.apiGroupServed(ctx, group) {
+		return &crdNotInstalledError{group: group, err: err}
+	}
+	return err
+}
@@ -0,0 +1,118 @@
+package istio
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// TestForbiddenErrors tests that RBAC denials are reported with the missing permission
+func TestForbiddenErrors(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{
+			"kind": "Status",
+			"apiVersion": "v1",
+			"status": "Failure",
+			"reason": "Forbidden",
+			"code": 403,
+			"message": "virtualservices.networking.istio.io is forbidden: User \"system:serviceaccount:tools:mcp\" cannot list resource \"virtualservices\" in API group \"networking.istio.io\" in the namespace \"payments\"",
+			"details": {"group": "networking.istio.io", "kind": "virtualservices"}
+		}`))
+	}))
+	defer mockServer.Close()
+
+	istio := newTestIstio(t, mockServer.URL)
+
+	_, err := istio.GetVirtualServices(context.Background(), "payments")
+	if err == nil {
+		t.Fatal("Expected error for forbidden request")
+	}
+	assertContains(t, err.Error(),
+		"failed to list virtual services",
+		"cannot list virtualservices.networking.istio.io in namespace 'payments': the configured identity lacks RBAC permission",
+	)
+	if !apierrors.IsForbidden(err) {
+		t.Errorf("Expected the original Forbidden error to be preserved, got: %v", err)
+	}
+}
+
+// TestExplainForbidden tests that non-RBAC errors pass through unchanged
+func TestExplainForbidden(t *testing.T) {
+	original := errors.New("connection refused")
+	if err := explainForbidden(original, "list", "pods", "default"); err != original {
+		t.Errorf("Expected non-Forbidden error to be returned unchanged, got: %v", err)
+	}
+
+	err := explainForbidden(apierrors.NewForbidden(schema.GroupResource{Resource: "pods"}, "", errors.New("denied")), "list", "pods", "")
+	assertContains(t, err.Error(), "cannot list pods across all namespaces")
+}
+
+// TestCRDsNotInstalled tests that listing Istio resources in a cluster without the Istio API groups reports the
+// missing CRDs rather than a bare 404
+func TestCRDsNotInstalled(t *testing.T) {
+	t.Run("API group missing from discovery", func(t *testing.T) {
+		mockServer := newMockAPIServer(map[string]string{
+			"/apis": `{"kind": "APIGroupList", "apiVersion": "v1", "groups": [
+				{"name": "apps", "versions": [{"groupVersion": "apps/v1", "version": "v1"}], "preferredVersion": {"groupVersion": "apps/v1", "version": "v1"}}
+			]}`,
+		})
+		defer mockServer.Close()
+		istio := newTestIstio(t, mockServer.URL)
+
+		_, err := istio.GetVirtualServices(context.Background(), "default")
+		if err == nil {
+			t.Fatal("Expected an error when the CRDs are not installed")
+		}
+		assertContains(t, err.Error(), "Istio networking.istio.io CRDs are not installed in this cluster")
+		if !apierrors.IsNotFound(err) {
+			t.Errorf("Expected the original NotFound error to be preserved, got: %v", err)
+		}
+
+		_, err = istio.GetTelemetries(context.Background(), "default")
+		if err == nil {
+			t.Fatal("Expected an error when the CRDs are not installed")
+		}
+		assertContains(t, err.Error(), "Istio telemetry.istio.io CRDs are not installed in this cluster")
+	})
+
+	t.Run("API group served", func(t *testing.T) {
+		mockServer := newMockAPIServer(map[string]string{
+			"/apis": `{"kind": "APIGroupList", "apiVersion": "v1", "groups": [
+				{"name": "networking.istio.io", "versions": [{"groupVersion": "networking.istio.io/v1alpha3", "version": "v1alpha3"}], "preferredVersion": {"groupVersion": "networking.istio.io/v1alpha3", "version": "v1alpha3"}}
+			]}`,
+		})
+		defer mockServer.Close()
+		istio := newTestIstio(t, mockServer.URL)
+
+		_, err := istio.GetResource(context.Background(), "VirtualService", "default", "missing")
+		if err == nil {
+			t.Fatal("Expected an error for a missing resource")
+		}
+		assertNotContains(t, err.Error(), "CRDs are not installed")
+	})
+
+	t.Run("discovery honors the context", func(t *testing.T) {
+		mockServer := newMockAPIServer(map[string]string{
+			"/apis": `{"kind": "APIGroupList", "apiVersion": "v1", "groups": []}`,
+		})
+		defer mockServer.Close()
+		istio := newTestIstio(t, mockServer.URL)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		// A discovery request cut short by the context counts as served, so the original error is reported
+		if !istio.apiGroupServed(ctx, "networking.istio.io") {
+			t.Error("Expected a cancelled discovery request to count the group as served")
+		}
+		if istio.apiGroupServed(context.Background(), "networking.istio.io") {
+			t.Error("Expected the group to be reported as not served")
+		}
+	})
+}
@@ -0,0 +1,98 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	networkingv1alpha3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// describeExportTo explains an exportTo list in words
+func describeExportTo(exportTo []string, namespace string) string {
+	var parts []string
+	for _, ns := range exportTo {
+		switch ns {
+		case "*":
+			parts = append(parts, "all namespaces (*)")
+		case ".":
+			parts = append(parts, fmt.Sprintf("its own namespace '%s' (.)", namespace))
+		case "~":
+			parts = append(parts, "no namespace (~)")
+		default:
+			parts = append(parts, fmt.Sprintf("namespace '%s'", ns))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// GetResourceExportScope reports the effective exportTo of a VirtualService, DestinationRule or ServiceEntry,
+// falling back to the mesh-wide default when the resource sets none, and lists the namespaces that can see it
+func (i *Istio) GetResourceExportScope(ctx context.Context, kind, namespace, name string) (string, error) {
+	obj, err := i.getResource(ctx, kind, namespace, name)
+	if err != nil {
+		return "", err
+	}
+	mesh, err := i.getMeshConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var exportTo, defaultExportTo []string
+	switch resource := obj.(type) {
+	case *networkingv1alpha3.VirtualService:
+		exportTo, defaultExportTo = resource.Spec.GetExportTo(), mesh.DefaultVirtualServiceExportTo
+	case *networkingv1alpha3.DestinationRule:
+		exportTo, defaultExportTo = resource.Spec.GetExportTo(), mesh.DefaultDestinationRuleExportTo
+	case *networkingv1alpha3.ServiceEntry:
+		exportTo, defaultExportTo = resource.Spec.GetExportTo(), mesh.DefaultServiceExportTo
+	default:
+		return "", fmt.Errorf("%s resources have no exportTo: only VirtualService, DestinationRule and ServiceEntry are exported across namespaces", obj.GetObjectKind().GroupVersionKind().Kind)
+	}
+	kind = obj.GetObjectKind().GroupVersionKind().Kind
+
+	result := fmt.Sprintf("Export scope of %s '%s' in namespace '%s':\n\n", kind, name, namespace)
+	switch {
+	case len(exportTo) > 0:
+		result += fmt.Sprintf("exportTo: %s\n", strings.Join(exportTo, ", "))
+	case len(defaultExportTo) > 0:
+		exportTo = defaultExportTo
+		result += fmt.Sprintf("exportTo: not set, using the mesh-wide default %s\n", strings.Join(exportTo, ", "))
+	default:
+		exportTo = []string{"*"}
+		result += "exportTo: not set and no mesh-wide default, so exported to all namespaces\n"
+	}
+	result += fmt.Sprintf("Effective scope: %s\n\n", describeExportTo(exportTo, namespace))
+
+	namespaces, err := i.kubeClient.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list namespaces: %w", explainForbidden(err, "list", "namespaces", ""))
+	}
+	var visible, existing []string
+	for _, ns := range namespaces.Items {
+		existing = append(existing, ns.Name)
+		if exportedTo(exportTo, namespace, ns.Name) {
+			visible = append(visible, ns.Name)
+		}
+	}
+	slices.Sort(visible)
+	if len(visible) == len(existing) {
+		result += fmt.Sprintf("Visible in all %d namespaces\n", len(existing))
+	} else {
+		result += fmt.Sprintf("Visible in %d of %d namespaces: %s\n", len(visible), len(existing), strings.Join(visible, ", "))
+	}
+
+	for _, ns := range exportTo {
+		if ns != "*" && ns != "." && ns != "~" && !slices.Contains(existing, ns) {
+			result += fmt.Sprintf("[WARNING] exportTo names namespace '%s', which doesn't exist\n", ns)
+		}
+	}
+	if len(visible) == 0 {
+		result += "[WARNING] The resource is visible in no namespace, so it has no effect\n"
+	} else if !slices.Contains(visible, namespace) {
+		result += fmt.Sprintf("[WARNING] The resource is not visible in its own namespace '%s', so workloads there ignore it\n", namespace)
+	}
+	return result, nil
+}
@@ -0,0 +1,59 @@
+package istio
+
+import (
+	"context"
+	"testing"
+)
+
+// TestGetResourceExportScope tests reporting of the namespaces a resource is visible in through exportTo
+func TestGetResourceExportScope(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{
+		"/apis/networking.istio.io/v1alpha3/namespaces/bookinfo/destinationrules/reviews": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "DestinationRule",
+			"metadata": {"name": "reviews", "namespace": "bookinfo"},
+			"spec": {"host": "reviews", "exportTo": ["frontend"]}
+		}`,
+		"/apis/networking.istio.io/v1alpha3/namespaces/bookinfo/serviceentries/payments": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "ServiceEntry",
+			"metadata": {"name": "payments", "namespace": "bookinfo"},
+			"spec": {"hosts": ["api.payments.com"]}
+		}`,
+		"/api/v1/namespaces": `{
+			"apiVersion": "v1",
+			"kind": "NamespaceList",
+			"items": [
+				{"metadata": {"name": "bookinfo"}},
+				{"metadata": {"name": "frontend"}},
+				{"metadata": {"name": "istio-system"}}
+			]
+		}`,
+	})
+	defer mockServer.Close()
+
+	istio := newTestIstio(t, mockServer.URL)
+	ctx := context.Background()
+
+	t.Run("destination rule exported to a single namespace", func(t *testing.T) {
+		result, err := istio.GetResourceExportScope(ctx, "DestinationRule", "bookinfo", "reviews")
+		if err != nil {
+			t.Fatalf("GetResourceExportScope failed: %v", err)
+		}
+		assertContains(t, result,
+			"exportTo: frontend",
+			"Effective scope: namespace 'frontend'",
+			"Visible in 1 of 3 namespaces: frontend",
+			"[WARNING] The resource is not visible in its own namespace 'bookinfo'",
+		)
+	})
+
+	t.Run("unset exportTo is visible everywhere", func(t *testing.T) {
+		result, err := istio.GetResourceExportScope(ctx, "serviceentry", "bookinfo", "payments")
+		if err != nil {
+			t.Fatalf("GetResourceExportScope failed: %v", err)
+		}
+		assertContains(t, result, "exportTo: not set and no mesh-wide default", "Visible in all 3 namespaces")
+		assertNotContains(t, result, "[WARNING]")
+	})
+}
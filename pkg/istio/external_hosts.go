@@ -0,0 +1,118 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	networkingapi "istio.io/api/networking/v1alpha3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// externalHost is a host outside the cluster with the resources declaring or routing to it
+type externalHost struct {
+	serviceEntries  []string
+	virtualServices []string
+}
+
+// ListExternalHosts aggregates the external dependencies of the mesh across all namespaces: the hosts of the
+// ServiceEntries located outside the mesh, and the hosts VirtualServices route to that are not Kubernetes services.
+// Each host is listed once with the resources referencing it; hosts only VirtualServices reference have no
+// ServiceEntry, so their traffic depends on the outbound traffic policy.
+func (i *Istio) ListExternalHosts(ctx context.Context) (string, error) {
+	mesh, err := i.getMeshConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+	seList, err := i.istioClient.NetworkingV1alpha3().ServiceEntries("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list service entries: %w", i.explainAPIError(ctx, err, "list", "serviceentries", ""))
+	}
+	vsList, err := i.istioClient.NetworkingV1alpha3().VirtualServices("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list virtual services: %w", explainForbidden(err, "list", "virtualservices", ""))
+	}
+	namespaceList, err := i.kubeClient.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list namespaces: %w", explainForbidden(err, "list", "namespaces", ""))
+	}
+	namespaces := make(map[string]bool, len(namespaceList.Items))
+	for _, ns := range namespaceList.Items {
+		namespaces[ns.Name] = true
+	}
+
+	hosts := make(map[string]*externalHost)
+	external := func(host string) *externalHost {
+		entry, ok := hosts[host]
+		if !ok {
+			entry = &externalHost{}
+			hosts[host] = entry
+		}
+		return entry
+	}
+	for _, se := range seList.Items {
+		if se.Spec.GetLocation() == networkingapi.ServiceEntry_MESH_INTERNAL {
+			continue
+		}
+		for _, host := range se.Spec.GetHosts() {
+			entry := external(host)
+			description := fmt.Sprintf("%s/%s (%s, %s)", se.Namespace, se.Name, strings.Trim(serviceEntryPorts(se), "[]"), se.Spec.GetResolution())
+			if !slices.Contains(entry.serviceEntries, description) {
+				entry.serviceEntries = append(entry.serviceEntries, description)
+			}
+		}
+	}
+	for _, vs := range vsList.Items {
+		for _, destination := range virtualServiceDestinations(&vs.Spec) {
+			host := qualifiedHost(destination.GetHost(), vs.Namespace)
+			// '<service>.<namespace>' names a service of another namespace, any other dotted host is external
+			parts := strings.Split(host, ".")
+			if strings.HasSuffix(host, serviceDomainSuffix) || len(parts) == 2 && namespaces[parts[1]] {
+				continue
+			}
+			entry := external(host)
+			if name := vs.Namespace + "/" + vs.Name; !slices.Contains(entry.virtualServices, name) {
+				entry.virtualServices = append(entry.virtualServices, name)
+			}
+		}
+	}
+
+	names := make([]string, 0, len(hosts))
+	for host := range hosts {
+		names = append(names, host)
+	}
+	slices.Sort(names)
+
+	result := fmt.Sprintf("External hosts referenced across the mesh (%d):\n\n", len(names))
+	undeclared := 0
+	for _, host := range names {
+		entry := hosts[host]
+		result += fmt.Sprintf("- %s\n", host)
+		if len(entry.serviceEntries) > 0 {
+			result += fmt.Sprintf("  ServiceEntries: %s\n", strings.Join(entry.serviceEntries, ", "))
+		}
+		if len(entry.virtualServices) > 0 {
+			result += fmt.Sprintf("  Routed to by VirtualServices: %s\n", strings.Join(entry.virtualServices, ", "))
+		}
+		// A wildcard ServiceEntry may declare the host, e.g. '*.googleapis.com'
+		declared := len(entry.serviceEntries) > 0 || slices.ContainsFunc(names, func(pattern string) bool {
+			return pattern != host && len(hosts[pattern].serviceEntries) > 0 && hostMatches(pattern, host)
+		})
+		if !declared {
+			undeclared++
+			if mesh.OutboundTrafficPolicy.Mode == "REGISTRY_ONLY" {
+				result += "  [WARNING] No ServiceEntry declares the host; with outboundTrafficPolicy REGISTRY_ONLY requests to it are blocked\n"
+			} else {
+				result += "  [WARNING] No ServiceEntry declares the host; requests to it pass through without mesh routing or telemetry\n"
+			}
+		}
+	}
+
+	if len(names) == 0 {
+		result += "No ServiceEntry or VirtualService references a host outside the cluster\n"
+	} else if undeclared > 0 {
+		result += fmt.Sprintf("\n[RESULT] %d of %d external hosts are not declared by a ServiceEntry\n", undeclared, len(names))
+	}
+	return result, nil
+}
@@ -0,0 +1,74 @@
+package istio
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// TestListExternalHosts tests aggregation of external hosts from ServiceEntries and VirtualService destinations
+func TestListExternalHosts(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{
+		"/api/v1/namespaces": `{
+			"apiVersion": "v1",
+			"kind": "NamespaceList",
+			"items": [{"metadata": {"name": "bookinfo"}}, {"metadata": {"name": "checkout"}}, {"metadata": {"name": "billing"}}]
+		}`,
+		"/apis/networking.istio.io/v1alpha3/serviceentries": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "ServiceEntryList",
+			"items": [
+				{"metadata": {"name": "payments", "namespace": "checkout"}, "spec": {
+					"hosts": ["api.stripe.com"], "location": "MESH_EXTERNAL", "resolution": "DNS",
+					"ports": [{"number": 443, "name": "https", "protocol": "TLS"}]
+				}},
+				{"metadata": {"name": "stripe", "namespace": "billing"}, "spec": {
+					"hosts": ["api.stripe.com", "files.stripe.com"], "location": "MESH_EXTERNAL", "resolution": "DNS",
+					"ports": [{"number": 443, "name": "https", "protocol": "TLS"}]
+				}},
+				{"metadata": {"name": "legacy-vm", "namespace": "billing"}, "spec": {
+					"hosts": ["legacy.billing.internal"], "location": "MESH_INTERNAL", "resolution": "STATIC"
+				}}
+			]
+		}`,
+		"/apis/networking.istio.io/v1alpha3/virtualservices": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "VirtualServiceList",
+			"items": [
+				{"metadata": {"name": "stripe-timeout", "namespace": "checkout"}, "spec": {
+					"hosts": ["api.stripe.com"],
+					"http": [{"timeout": "5s", "route": [{"destination": {"host": "api.stripe.com"}}]}]
+				}},
+				{"metadata": {"name": "reviews", "namespace": "bookinfo"}, "spec": {
+					"hosts": ["reviews"],
+					"http": [{"route": [{"destination": {"host": "reviews"}}]}]
+				}},
+				{"metadata": {"name": "orders", "namespace": "checkout"}, "spec": {
+					"hosts": ["orders"],
+					"http": [
+						{"match": [{"uri": {"prefix": "/anything"}}], "route": [{"destination": {"host": "httpbin.org"}}]},
+						{"route": [{"destination": {"host": "ledger.billing"}}]}
+					]
+				}}
+			]
+		}`,
+	})
+	defer mockServer.Close()
+	istio := newTestIstio(t, mockServer.URL)
+
+	result, err := istio.ListExternalHosts(context.Background())
+	if err != nil {
+		t.Fatalf("ListExternalHosts failed: %v", err)
+	}
+	assertContains(t, result,
+		"External hosts referenced across the mesh (3):",
+		"- httpbin.org\n  Routed to by VirtualServices: checkout/orders\n  [WARNING] No ServiceEntry declares the host",
+		"[RESULT] 1 of 3 external hosts are not declared by a ServiceEntry",
+		"- api.stripe.com\n  ServiceEntries: checkout/payments (443/TLS, DNS), billing/stripe (443/TLS, DNS)\n  Routed to by VirtualServices: checkout/stripe-timeout",
+		"- files.stripe.com\n  ServiceEntries: billing/stripe (443/TLS, DNS)",
+	)
+	if count := strings.Count(result, "- api.stripe.com"); count != 1 {
+		t.Errorf("Expected api.stripe.com to be listed once, got %d times:\n%s", count, result)
+	}
+	assertNotContains(t, result, "legacy.billing.internal", "reviews", "ledger", "svc.cluster.local")
+}
@@ -0,0 +1,74 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	networkingapi "istio.io/api/networking/v1alpha3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// faultPercentage describes the share of requests a fault applies to; without a percentage a fault applies to all requests
+func faultPercentage(percentage *networkingapi.Percent) string {
+	if percentage == nil {
+		return "all requests"
+	}
+	return fmt.Sprintf("%g%% of requests", percentage.GetValue())
+}
+
+// describeAbort describes the error returned by an abort fault
+func describeAbort(abort *networkingapi.HTTPFaultInjection_Abort) string {
+	switch {
+	case abort.GetGrpcStatus() != "":
+		return "gRPC status " + abort.GetGrpcStatus()
+	case abort.GetHttp2Error() != "":
+		return "HTTP/2 error " + abort.GetHttp2Error()
+	}
+	return fmt.Sprintf("HTTP %d", abort.GetHttpStatus())
+}
+
+// GetFaultInjectionConfig lists the HTTP routes of the VirtualServices in a namespace that inject delays or aborts,
+// with the share of requests affected, to tell whether fault injection left over from chaos testing is active
+func (i *Istio) GetFaultInjectionConfig(ctx context.Context, namespace string) (string, error) {
+	vsList, err := i.istioClient.NetworkingV1alpha3().VirtualServices(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list virtual services: %w", explainForbidden(err, "list", "virtualservices", namespace))
+	}
+
+	result := fmt.Sprintf("Fault injection in Virtual Services of namespace '%s':\n\n", namespace)
+	routes := 0
+	virtualServices := 0
+	for _, vs := range vsList.Items {
+		found := false
+		for idx, route := range vs.Spec.GetHttp() {
+			fault := route.GetFault()
+			if fault.GetDelay() == nil && fault.GetAbort() == nil {
+				continue
+			}
+			found = true
+			routes++
+			result += fmt.Sprintf("[WARNING] %s route %s (hosts: %s)\n", vs.Name, httpRouteName(route, idx), strings.Join(vs.Spec.GetHosts(), ", "))
+			if len(route.GetMatch()) == 0 {
+				result += "  Applies to: all requests of the route\n"
+			} else {
+				result += fmt.Sprintf("  Applies to: requests matching %d match conditions of the route\n", len(route.GetMatch()))
+			}
+			if delay := fault.GetDelay(); delay != nil {
+				result += fmt.Sprintf("  Delay: %s for %s\n", delay.GetFixedDelay().AsDuration(), faultPercentage(delay.GetPercentage()))
+			}
+			if abort := fault.GetAbort(); abort != nil {
+				result += fmt.Sprintf("  Abort: %s for %s\n", describeAbort(abort), faultPercentage(abort.GetPercentage()))
+			}
+		}
+		if found {
+			virtualServices++
+		}
+	}
+
+	if routes == 0 {
+		return result + "[OK] No Virtual Service injects faults\n", nil
+	}
+	result += fmt.Sprintf("\n[RESULT] Fault injection is active on %d routes of %d Virtual Services\n", routes, virtualServices)
+	return result, nil
+}
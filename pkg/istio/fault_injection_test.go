@@ -0,0 +1,49 @@
+package istio
+
+import (
+	"context"
+	"testing"
+)
+
+// TestGetFaultInjectionConfig tests reporting of the delays and aborts injected by VirtualService routes
+func TestGetFaultInjectionConfig(t *testing.T) {
+	server := newMockAPIServer(map[string]string{
+		"/apis/networking.istio.io/v1alpha3/namespaces/bookinfo/virtualservices": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "VirtualServiceList",
+			"items": [
+				{
+					"metadata": {"name": "ratings", "namespace": "bookinfo"},
+					"spec": {"hosts": ["ratings"], "http": [
+						{"name": "chaos", "fault": {"abort": {"httpStatus": 503, "percentage": {"value": 10}}}, "route": [{"destination": {"host": "ratings"}}]}
+					]}
+				},
+				{
+					"metadata": {"name": "reviews", "namespace": "bookinfo"},
+					"spec": {"hosts": ["reviews"], "http": [
+						{"match": [{"headers": {"end-user": {"exact": "jason"}}}], "fault": {"delay": {"fixedDelay": "7s"}}, "route": [{"destination": {"host": "reviews"}}]},
+						{"route": [{"destination": {"host": "reviews"}}]}
+					]}
+				},
+				{
+					"metadata": {"name": "details", "namespace": "bookinfo"},
+					"spec": {"hosts": ["details"], "http": [{"route": [{"destination": {"host": "details"}}]}]}
+				}
+			]
+		}`,
+	})
+	defer server.Close()
+	istio := newTestIstio(t, server.URL)
+
+	result, err := istio.GetFaultInjectionConfig(context.Background(), "bookinfo")
+	if err != nil {
+		t.Fatalf("GetFaultInjectionConfig failed: %v", err)
+	}
+	assertContains(t, result,
+		"[WARNING] ratings route 'chaos' (hosts: ratings)\n  Applies to: all requests of the route\n  Abort: HTTP 503 for 10% of requests",
+		"[WARNING] reviews route #1 (hosts: reviews)",
+		"Delay: 7s for all requests",
+		"[RESULT] Fault injection is active on 2 routes of 2 Virtual Services",
+	)
+	assertNotContains(t, result, "details", "route #2")
+}
@@ -0,0 +1,44 @@
+package istio
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+// crdSelectableFields are the fields the API server can select custom resources by, as Istio CRDs
+// declare no additional selectable fields
+var crdSelectableFields = []string{"metadata.name", "metadata.namespace"}
+
+// serviceSelectableFields are the fields the API server can select Services by
+var serviceSelectableFields = []string{"metadata.name", "metadata.namespace", "spec.clusterIP", "spec.type"}
+
+// WithFieldSelector restricts resource listings to the resources matching a Kubernetes field selector
+// (e.g. "metadata.name=reviews")
+func WithFieldSelector(selector string) GetOption {
+	return func(o *getOptions) {
+		o.fieldSelector = selector
+	}
+}
+
+// listOptions returns the options listing resources that can be selected by the given fields. Unsupported
+// fields are rejected here, because the API server answers them with an error that doesn't name the supported fields.
+func (o getOptions) listOptions(resource string, selectable []string) (metav1.ListOptions, error) {
+	if o.fieldSelector == "" {
+		return metav1.ListOptions{}, nil
+	}
+	selector, err := fields.ParseSelector(o.fieldSelector)
+	if err != nil {
+		return metav1.ListOptions{}, fmt.Errorf("invalid field selector '%s': %w", o.fieldSelector, err)
+	}
+	for _, requirement := range selector.Requirements() {
+		if !slices.Contains(selectable, requirement.Field) {
+			return metav1.ListOptions{}, fmt.Errorf("field selector '%s' is not supported for %s: supported fields are %s",
+				requirement.Field, resource, strings.Join(selectable, ", "))
+		}
+	}
+	return metav1.ListOptions{FieldSelector: selector.String()}, nil
+}
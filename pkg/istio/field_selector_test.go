@@ -0,0 +1,57 @@
+package istio
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// TestFieldSelector tests that a supported field selector is sent to the API server and an unsupported one
+// is rejected before any request is made
+func TestFieldSelector(t *testing.T) {
+	var lastSelector atomic.Value
+	var requests atomic.Int32
+	handler := mockAPIHandler(map[string]string{
+		"/apis/networking.istio.io/v1alpha3/namespaces/bookinfo/virtualservices": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "VirtualServiceList",
+			"items": [{"metadata": {"name": "reviews", "namespace": "bookinfo"}, "spec": {"hosts": ["reviews"]}}]
+		}`,
+	})
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/apis/networking.istio.io/v1alpha3/namespaces/bookinfo/virtualservices" {
+			requests.Add(1)
+			lastSelector.Store(r.URL.Query().Get("fieldSelector"))
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	defer mockServer.Close()
+
+	istio := newTestIstio(t, mockServer.URL)
+
+	result, err := istio.GetVirtualServices(context.Background(), "bookinfo", WithFieldSelector("metadata.name=reviews"))
+	if err != nil {
+		t.Fatalf("GetVirtualServices failed: %v", err)
+	}
+	assertContains(t, result, "- reviews")
+	if selector := lastSelector.Load(); selector != "metadata.name=reviews" {
+		t.Errorf("Expected field selector 'metadata.name=reviews' to be forwarded, got '%v'", selector)
+	}
+
+	_, err = istio.GetVirtualServices(context.Background(), "bookinfo", WithFieldSelector("spec.hosts=reviews"))
+	if err == nil {
+		t.Fatal("Expected an error for a field selector on an unsupported field")
+	}
+	if !strings.Contains(err.Error(), "field selector 'spec.hosts' is not supported for virtual services: supported fields are metadata.name, metadata.namespace") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if _, err := istio.GetVirtualServices(context.Background(), "bookinfo", WithFieldSelector("metadata.name")); err == nil {
+		t.Error("Expected an error for a malformed field selector")
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("Expected rejected field selectors not to reach the API server, got %d requests", n)
+	}
+}
@@ -0,0 +1,120 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	networkingv1alpha3api "istio.io/api/networking/v1alpha3"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// hasAnyKey reports whether a secret's data contains at least one of the given keys
+func hasAnyKey(secret *v1.Secret, keys ...string) bool {
+	for _, key := range keys {
+		if _, ok := secret.Data[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// gatewayWorkloadNamespaces returns the namespaces of the pods selected by a Gateway, where istiod looks up the
+// Secrets named by its credentialName
+func gatewayWorkloadNamespaces(selector map[string]string, pods []v1.Pod) []string {
+	if len(selector) == 0 {
+		return nil
+	}
+	matcher := labels.SelectorFromSet(selector)
+	seen := make(map[string]bool)
+	var namespaces []string
+	for _, pod := range pods {
+		if matcher.Matches(labels.Set(pod.Labels)) && !seen[pod.Namespace] {
+			seen[pod.Namespace] = true
+			namespaces = append(namespaces, pod.Namespace)
+		}
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+// checkGatewayCredential checks that a credentialName resolves to a Secret with a certificate and key in a gateway
+// workload namespace, and for MUTUAL servers that a CA certificate is available too
+func (i *Istio) checkGatewayCredential(ctx context.Context, namespace, name string, mode networkingv1alpha3api.ServerTLSSettings_TLSmode) (string, bool) {
+	secret, err := i.kubeClient.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return fmt.Sprintf("secret %s/%s not found; TLS handshakes on this server fail", namespace, name), false
+	}
+	if err != nil {
+		return fmt.Sprintf("failed to get secret %s/%s: %v", namespace, name, explainForbidden(err, "get", "secrets", namespace)), false
+	}
+	if !hasAnyKey(secret, v1.TLSCertKey, "cert") || !hasAnyKey(secret, v1.TLSPrivateKeyKey, "key") {
+		return fmt.Sprintf("secret %s/%s has no certificate and key (expected tls.crt/tls.key or cert/key)", namespace, name), false
+	}
+	if mode == networkingv1alpha3api.ServerTLSSettings_MUTUAL || mode == networkingv1alpha3api.ServerTLSSettings_OPTIONAL_MUTUAL {
+		if !hasAnyKey(secret, "ca.crt", "cacert") {
+			if _, err := i.kubeClient.CoreV1().Secrets(namespace).Get(ctx, name+"-cacert", metav1.GetOptions{}); err != nil {
+				return fmt.Sprintf("secret %s/%s has no CA certificate for %s TLS (expected ca.crt or cacert, or a %s-cacert secret)", namespace, name, mode, name), false
+			}
+		}
+	}
+	return fmt.Sprintf("secret %s/%s holds a certificate and key", namespace, name), true
+}
+
+// ValidateGatewayCredentials checks that the credentialName of every TLS server of the Gateways in a namespace
+// resolves to a TLS Secret in the namespace of the gateway workload, where istiod serves it over SDS
+func (i *Istio) ValidateGatewayCredentials(ctx context.Context, namespace string) (string, error) {
+	gateways, err := i.istioClient.NetworkingV1alpha3().Gateways(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list gateways: %w", explainForbidden(err, "list", "gateways", namespace))
+	}
+	pods, err := i.kubeClient.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list pods: %w", explainForbidden(err, "list", "pods", ""))
+	}
+
+	result := fmt.Sprintf("Gateway TLS credentials in namespace '%s':\n\n", namespace)
+	checked, failed := 0, 0
+	for _, gw := range gateways.Items {
+		var lines []string
+		workloadNamespaces := gatewayWorkloadNamespaces(gw.Spec.GetSelector(), pods.Items)
+		for _, server := range gw.Spec.GetServers() {
+			tls := server.GetTls()
+			if tls.GetCredentialName() == "" {
+				continue
+			}
+			if len(workloadNamespaces) == 0 {
+				workloadNamespaces = []string{gw.Namespace}
+				lines = append(lines, fmt.Sprintf("  [WARNING] No pod matches selector %v; looking up secrets in the Gateway's namespace", gw.Spec.GetSelector()))
+			}
+			description := fmt.Sprintf("port %d (hosts: %s)", server.GetPort().GetNumber(), strings.Join(server.GetHosts(), ", "))
+			for _, ns := range workloadNamespaces {
+				checked++
+				message, ok := i.checkGatewayCredential(ctx, ns, tls.GetCredentialName(), tls.GetMode())
+				if ok {
+					lines = append(lines, fmt.Sprintf("  [OK] Server %s: %s", description, message))
+				} else {
+					failed++
+					lines = append(lines, fmt.Sprintf("  [FAIL] Server %s: %s", description, message))
+				}
+			}
+		}
+		if len(lines) > 0 {
+			result += fmt.Sprintf("Gateway '%s':\n%s\n\n", gw.Name, strings.Join(lines, "\n"))
+		}
+	}
+
+	switch {
+	case checked == 0:
+		result += "No Gateway server references a TLS credential\n"
+	case failed == 0:
+		result += fmt.Sprintf("[RESULT] All %d credential references resolve to TLS secrets\n", checked)
+	default:
+		result += fmt.Sprintf("[RESULT] %d of %d credential references are broken\n", failed, checked)
+	}
+	return result, nil
+}
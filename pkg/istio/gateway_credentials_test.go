@@ -0,0 +1,63 @@
+package istio
+
+import (
+	"context"
+	"testing"
+)
+
+// TestValidateGatewayCredentials tests that the TLS secrets referenced by Gateway servers are found and valid
+func TestValidateGatewayCredentials(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{
+		"/apis/networking.istio.io/v1alpha3/namespaces/bookinfo/gateways": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "GatewayList",
+			"items": [
+				{"metadata": {"name": "bookinfo-gateway", "namespace": "bookinfo"}, "spec": {
+					"selector": {"istio": "ingressgateway"},
+					"servers": [
+						{"port": {"number": 80, "name": "http", "protocol": "HTTP"}, "hosts": ["bookinfo.example.com"]},
+						{"port": {"number": 443, "name": "https", "protocol": "HTTPS"}, "hosts": ["bookinfo.example.com"], "tls": {"mode": "SIMPLE", "credentialName": "bookinfo-cert"}},
+						{"port": {"number": 8443, "name": "https-admin", "protocol": "HTTPS"}, "hosts": ["admin.example.com"], "tls": {"mode": "SIMPLE", "credentialName": "admin-cert"}}
+					]
+				}}
+			]
+		}`,
+		"/api/v1/pods": `{
+			"apiVersion": "v1",
+			"kind": "PodList",
+			"items": [
+				{"metadata": {"name": "istio-ingressgateway-abc", "namespace": "istio-system", "labels": {"istio": "ingressgateway"}}}
+			]
+		}`,
+		"/api/v1/namespaces/istio-system/secrets/bookinfo-cert": `{
+			"apiVersion": "v1",
+			"kind": "Secret",
+			"metadata": {"name": "bookinfo-cert", "namespace": "istio-system"},
+			"type": "kubernetes.io/tls",
+			"data": {"tls.crt": "Y2VydA==", "tls.key": "a2V5"}
+		}`,
+		"/api/v1/namespaces/bookinfo/secrets/admin-cert": `{
+			"apiVersion": "v1",
+			"kind": "Secret",
+			"metadata": {"name": "admin-cert", "namespace": "bookinfo"},
+			"type": "kubernetes.io/tls",
+			"data": {"tls.crt": "Y2VydA==", "tls.key": "a2V5"}
+		}`,
+	})
+	defer mockServer.Close()
+
+	istio := newTestIstio(t, mockServer.URL)
+
+	result, err := istio.ValidateGatewayCredentials(context.Background(), "bookinfo")
+	if err != nil {
+		t.Fatalf("ValidateGatewayCredentials failed: %v", err)
+	}
+	assertContains(t, result,
+		"Gateway 'bookinfo-gateway':",
+		"[OK] Server port 443 (hosts: bookinfo.example.com): secret istio-system/bookinfo-cert holds a certificate and key",
+		// The secret exists, but in the Gateway's namespace rather than the gateway workload's
+		"[FAIL] Server port 8443 (hosts: admin.example.com): secret istio-system/admin-cert not found",
+		"[RESULT] 1 of 2 credential references are broken",
+	)
+	assertNotContains(t, result, "port 80 ")
+}
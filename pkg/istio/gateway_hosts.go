@@ -0,0 +1,144 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	networkingv1alpha3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// gatewayServerHost splits a Gateway server host like 'foo/*.example.com' into the namespaces allowed to bind
+// VirtualServices to it and the host pattern; './' selects the Gateway's own namespace and no prefix any namespace
+func gatewayServerHost(host, gatewayNamespace string) (namespace, pattern string) {
+	namespace, pattern, found := strings.Cut(host, "/")
+	if !found {
+		return "*", host
+	}
+	if namespace == "." {
+		namespace = gatewayNamespace
+	}
+	return namespace, pattern
+}
+
+// hostsIntersect reports whether two host patterns, each possibly a wildcard like '*.example.com', have a host in common
+func hostsIntersect(a, b string) bool {
+	return hostMatches(a, b) || hostMatches(b, a)
+}
+
+// gatewayPermitsHost reports whether any server of a Gateway lets a VirtualService in vsNamespace bind vsHost
+func gatewayPermitsHost(gw *networkingv1alpha3.Gateway, vsHost, vsNamespace string) bool {
+	for _, server := range gw.Spec.GetServers() {
+		for _, host := range server.GetHosts() {
+			namespace, pattern := gatewayServerHost(host, gw.Namespace)
+			if (namespace == "*" || namespace == vsNamespace) && hostsIntersect(pattern, vsHost) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// CheckGatewayHostMatch evaluates whether a VirtualService in vsNamespace may serve vsHost through a Gateway,
+// given the namespace scoping and host patterns of the Gateway's servers. The gateway is referenced as in a
+// VirtualService: 'name' for a Gateway in vsNamespace, or 'namespace/name'.
+func (i *Istio) CheckGatewayHostMatch(ctx context.Context, gateway, vsHost, vsNamespace string) (string, error) {
+	gatewayNamespace, gatewayName, found := strings.Cut(gateway, "/")
+	if !found {
+		gatewayNamespace, gatewayName = vsNamespace, gateway
+	}
+
+	gw, err := i.istioClient.NetworkingV1alpha3().Gateways(gatewayNamespace).Get(ctx, gatewayName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return fmt.Sprintf("[FAIL] Gateway '%s/%s' not found; a VirtualService referencing it is not bound to any gateway\n", gatewayNamespace, gatewayName), nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get gateway %s: %w", gatewayName, explainForbidden(err, "get", "gateways", gatewayNamespace))
+	}
+
+	result := fmt.Sprintf("Host rules of Gateway '%s/%s' for host '%s' of a VirtualService in namespace '%s':\n\n", gatewayNamespace, gatewayName, vsHost, vsNamespace)
+	var matchedPorts []string
+	for idx, server := range gw.Spec.GetServers() {
+		port := server.GetPort()
+		result += fmt.Sprintf("Server %d (port %d %s):\n", idx+1, port.GetNumber(), port.GetProtocol())
+		for _, host := range server.GetHosts() {
+			namespace, pattern := gatewayServerHost(host, gatewayNamespace)
+			switch {
+			case namespace != "*" && namespace != vsNamespace:
+				result += fmt.Sprintf("  [FAIL] '%s': only VirtualServices in namespace '%s' may bind\n", host, namespace)
+			case !hostsIntersect(pattern, vsHost):
+				result += fmt.Sprintf("  [FAIL] '%s': host '%s' does not match '%s'\n", host, vsHost, pattern)
+			default:
+				result += fmt.Sprintf("  [OK] '%s': matches\n", host)
+				matchedPorts = append(matchedPorts, fmt.Sprintf("%d", port.GetNumber()))
+			}
+		}
+	}
+
+	if len(matchedPorts) == 0 {
+		result += fmt.Sprintf("\n[RESULT] Host '%s' from namespace '%s' is not permitted by any server of the gateway; the VirtualService's routes are ignored on this gateway and requests return 404\n", vsHost, vsNamespace)
+	} else {
+		result += fmt.Sprintf("\n[RESULT] Host '%s' from namespace '%s' is permitted on ports: %s\n", vsHost, vsNamespace, strings.Join(matchedPorts, ", "))
+	}
+	return result, nil
+}
+
+// FindGatewayHostMismatches checks every binding of a VirtualService in a namespace to a Gateway and reports the
+// hosts no server of the Gateway permits, and references to Gateways that do not exist. Requests for such hosts
+// get a 404 from the gateway.
+func (i *Istio) FindGatewayHostMismatches(ctx context.Context, namespace string) (string, error) {
+	vsList, err := i.istioClient.NetworkingV1alpha3().VirtualServices(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list virtual services: %w", i.explainAPIError(ctx, err, "list", "virtualservices", namespace))
+	}
+	gwList, err := i.istioClient.NetworkingV1alpha3().Gateways("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list gateways: %w", i.explainAPIError(ctx, err, "list", "gateways", ""))
+	}
+	gateways := make(map[string]*networkingv1alpha3.Gateway, len(gwList.Items))
+	for _, gw := range gwList.Items {
+		gateways[gw.Namespace+"/"+gw.Name] = gw
+	}
+
+	result := fmt.Sprintf("Gateway host coverage of VirtualServices in namespace '%s':\n\n", namespace)
+	bindings, mismatches := 0, 0
+	for _, vs := range vsList.Items {
+		for _, gateway := range vs.Spec.Gateways {
+			if gateway == "mesh" {
+				continue
+			}
+			gatewayNamespace, gatewayName, found := strings.Cut(gateway, "/")
+			if !found {
+				gatewayNamespace, gatewayName = vs.Namespace, gateway
+			}
+			bindings++
+			gw, ok := gateways[gatewayNamespace+"/"+gatewayName]
+			if !ok {
+				mismatches++
+				result += fmt.Sprintf("[ERROR] VirtualService '%s' is bound to Gateway '%s/%s', which does not exist\n", vs.Name, gatewayNamespace, gatewayName)
+				continue
+			}
+			for _, host := range vs.Spec.Hosts {
+				if gatewayPermitsHost(gw, host, vs.Namespace) {
+					continue
+				}
+				mismatches++
+				result += fmt.Sprintf("[ERROR] VirtualService '%s' host '%s' is not covered by any server of Gateway '%s/%s'\n", vs.Name, host, gatewayNamespace, gatewayName)
+				var serverHosts []string
+				for _, server := range gw.Spec.GetServers() {
+					serverHosts = append(serverHosts, server.GetHosts()...)
+				}
+				result += fmt.Sprintf("  Gateway hosts: %s\n", strings.Join(serverHosts, ", "))
+			}
+		}
+	}
+
+	if mismatches == 0 {
+		result += fmt.Sprintf("[OK] All %d gateway bindings cover the VirtualService hosts\n", bindings)
+	} else {
+		result += fmt.Sprintf("\n[RESULT] %d host mismatches in %d gateway bindings; requests for these hosts get a 404 from the gateway\n", mismatches, bindings)
+	}
+	return result, nil
+}
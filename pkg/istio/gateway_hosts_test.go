@@ -0,0 +1,102 @@
+package istio
+
+import (
+	"context"
+	"testing"
+)
+
+// TestCheckGatewayHostMatch tests matching of the hosts of a VirtualService with the servers of its Gateway
+func TestCheckGatewayHostMatch(t *testing.T) {
+	server := newMockAPIServer(map[string]string{
+		"/apis/networking.istio.io/v1alpha3/namespaces/istio-system/gateways/ingress": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "Gateway",
+			"metadata": {"name": "ingress", "namespace": "istio-system"},
+			"spec": {"servers": [{"port": {"number": 80, "name": "http", "protocol": "HTTP"}, "hosts": ["./*"]}]}
+		}`,
+	})
+	defer server.Close()
+	istio := newTestIstio(t, server.URL)
+
+	t.Run("other namespace", func(t *testing.T) {
+		result, err := istio.CheckGatewayHostMatch(context.Background(), "istio-system/ingress", "bookinfo.example.com", "bookinfo")
+		if err != nil {
+			t.Fatalf("CheckGatewayHostMatch failed: %v", err)
+		}
+		assertContains(t, result,
+			"[FAIL] './*': only VirtualServices in namespace 'istio-system' may bind",
+			"[RESULT] Host 'bookinfo.example.com' from namespace 'bookinfo' is not permitted",
+		)
+	})
+
+	t.Run("gateway namespace", func(t *testing.T) {
+		result, err := istio.CheckGatewayHostMatch(context.Background(), "ingress", "bookinfo.example.com", "istio-system")
+		if err != nil {
+			t.Fatalf("CheckGatewayHostMatch failed: %v", err)
+		}
+		assertContains(t, result, "[OK] './*': matches", "is permitted on ports: 80")
+	})
+
+	t.Run("missing gateway", func(t *testing.T) {
+		result, err := istio.CheckGatewayHostMatch(context.Background(), "missing", "bookinfo.example.com", "bookinfo")
+		if err != nil {
+			t.Fatalf("CheckGatewayHostMatch failed: %v", err)
+		}
+		assertContains(t, result, "[FAIL] Gateway 'bookinfo/missing' not found")
+	})
+}
+
+// TestGatewayServerHost tests parsing of the namespace/host form of Gateway server hosts
+func TestGatewayServerHost(t *testing.T) {
+	tests := []struct {
+		host, namespace, pattern string
+	}{
+		{"*.example.com", "*", "*.example.com"},
+		{"./*", "istio-system", "*"},
+		{"bookinfo/reviews.example.com", "bookinfo", "reviews.example.com"},
+		{"*/*", "*", "*"},
+	}
+	for _, tt := range tests {
+		namespace, pattern := gatewayServerHost(tt.host, "istio-system")
+		if namespace != tt.namespace || pattern != tt.pattern {
+			t.Errorf("gatewayServerHost(%q) = %q, %q, expected %q, %q", tt.host, namespace, pattern, tt.namespace, tt.pattern)
+		}
+	}
+}
+
+// TestFindGatewayHostMismatches tests detection of VirtualServices whose hosts no server of their Gateways accepts
+func TestFindGatewayHostMismatches(t *testing.T) {
+	server := newMockAPIServer(map[string]string{
+		"/apis/networking.istio.io/v1alpha3/namespaces/bookinfo/virtualservices": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "VirtualServiceList",
+			"items": [
+				{"metadata": {"name": "bookinfo", "namespace": "bookinfo"}, "spec": {"hosts": ["bookinfo.example.com"], "gateways": ["istio-system/public"]}},
+				{"metadata": {"name": "admin", "namespace": "bookinfo"}, "spec": {"hosts": ["admin.internal.io"], "gateways": ["istio-system/public", "mesh"]}},
+				{"metadata": {"name": "legacy", "namespace": "bookinfo"}, "spec": {"hosts": ["legacy.example.com"], "gateways": ["legacy-gateway"]}},
+				{"metadata": {"name": "reviews", "namespace": "bookinfo"}, "spec": {"hosts": ["reviews"]}}
+			]
+		}`,
+		"/apis/networking.istio.io/v1alpha3/gateways": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "GatewayList",
+			"items": [
+				{"metadata": {"name": "public", "namespace": "istio-system"}, "spec": {"servers": [{"port": {"number": 443, "name": "https", "protocol": "HTTPS"}, "hosts": ["*.example.com"]}]}}
+			]
+		}`,
+	})
+	defer server.Close()
+	istio := newTestIstio(t, server.URL)
+
+	result, err := istio.FindGatewayHostMismatches(context.Background(), "bookinfo")
+	if err != nil {
+		t.Fatalf("FindGatewayHostMismatches failed: %v", err)
+	}
+	assertContains(t, result,
+		"[ERROR] VirtualService 'admin' host 'admin.internal.io' is not covered by any server of Gateway 'istio-system/public'",
+		"Gateway hosts: *.example.com",
+		"[ERROR] VirtualService 'legacy' is bound to Gateway 'bookinfo/legacy-gateway', which does not exist",
+		"[RESULT] 2 host mismatches in 3 gateway bindings",
+	)
+	assertNotContains(t, result, "'bookinfo' host", "'reviews'")
+}
@@ -0,0 +1,87 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// serviceDomainSuffix is the suffix of the fully qualified names of Kubernetes services in the default cluster domain
+const serviceDomainSuffix = ".svc.cluster.local"
+
+// resolveServiceName resolves a service name as users write it to the service's name and namespace. Short names
+// ('reviews') resolve in the given namespace, while 'reviews.bookinfo', 'reviews.bookinfo.svc' and the FQDN
+// 'reviews.bookinfo.svc.cluster.local' name the namespace explicitly. Other names are returned unchanged.
+func resolveServiceName(name, namespace string) (string, string) {
+	host := strings.TrimSuffix(name, ".")
+	host = strings.TrimSuffix(host, serviceDomainSuffix)
+	host = strings.TrimSuffix(host, ".svc")
+	parts := strings.Split(host, ".")
+	switch len(parts) {
+	case 1:
+		return parts[0], namespace
+	case 2:
+		return parts[0], parts[1]
+	}
+	return name, namespace
+}
+
+// qualifiedHost expands a short service host to its fully qualified name, resolving it relative to the namespace
+// of the referencing resource: 'reviews' and 'reviews.<namespace>' name a service of that namespace and
+// 'reviews.bookinfo.svc' a service of the named namespace. Any other dotted host, such as 'httpbin.org', is a
+// fully qualified name and is returned unchanged.
+func qualifiedHost(host, namespace string) string {
+	parts := strings.Split(host, ".")
+	switch {
+	case len(parts) == 1 && host != "*":
+		return fmt.Sprintf("%s.%s%s", host, namespace, serviceDomainSuffix)
+	case len(parts) == 2 && parts[1] == namespace:
+		return host + serviceDomainSuffix
+	case len(parts) == 3 && parts[2] == "svc":
+		return host + ".cluster.local"
+	}
+	return host
+}
+
+// resolveHost qualifies a host given as a tool argument. Besides the short names qualifiedHost expands, a host
+// of the form '<service>.<namespace>' names a service when the namespace exists; otherwise, like 'httpbin.org',
+// it is returned unchanged.
+func (i *Istio) resolveHost(ctx context.Context, host, namespace string) (string, error) {
+	parts := strings.Split(host, ".")
+	if len(parts) != 2 || parts[1] == namespace {
+		return qualifiedHost(host, namespace), nil
+	}
+	_, err := i.kubeClient.CoreV1().Namespaces().Get(ctx, parts[1], metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		return host, nil
+	case apierrors.IsForbidden(err):
+		// Without access to namespaces, trust the caller asking for a service
+		return host + serviceDomainSuffix, nil
+	case err != nil:
+		return "", fmt.Errorf("failed to get namespace %s: %w", parts[1], explainForbidden(err, "get", "namespaces", ""))
+	}
+	return host + serviceDomainSuffix, nil
+}
+
+// hostMatches reports whether a fully qualified host is selected by a host pattern, which may be a wildcard like '*.example.com'
+func hostMatches(pattern, host string) bool {
+	if pattern == "*" {
+		return true
+	}
+	if strings.HasPrefix(pattern, "*") {
+		return strings.HasSuffix(host, pattern[1:])
+	}
+	return pattern == host
+}
+
+// hostNamespace returns the namespace of a Kubernetes service FQDN (e.g. 'reviews.bookinfo.svc.cluster.local'), or "" for other hosts
+func hostNamespace(host string) string {
+	if parts := strings.Split(host, "."); len(parts) > 2 && parts[2] == "svc" {
+		return parts[1]
+	}
+	return ""
+}
@@ -0,0 +1,105 @@
+package istio
+
+import (
+	"context"
+	"testing"
+)
+
+// TestResolveServiceName tests that short, namespace-qualified and fully qualified service names resolve alike
+func TestResolveServiceName(t *testing.T) {
+	tests := []struct {
+		input, name, namespace string
+	}{
+		{"reviews", "reviews", "default"},
+		{"reviews.bookinfo", "reviews", "bookinfo"},
+		{"reviews.bookinfo.svc", "reviews", "bookinfo"},
+		{"reviews.bookinfo.svc.cluster.local", "reviews", "bookinfo"},
+		{"reviews.bookinfo.svc.cluster.local.", "reviews", "bookinfo"},
+		{"api.example.com", "api.example.com", "default"},
+	}
+	for _, test := range tests {
+		name, namespace := resolveServiceName(test.input, "default")
+		if name != test.name || namespace != test.namespace {
+			t.Errorf("resolveServiceName(%q) = %q, %q; expected %q, %q", test.input, name, namespace, test.name, test.namespace)
+		}
+	}
+	if host := qualifiedHost("reviews.bookinfo.svc", "default"); host != "reviews.bookinfo.svc.cluster.local" {
+		t.Errorf("qualifiedHost expanded 'reviews.bookinfo.svc' to %q", host)
+	}
+}
+
+// TestQualifiedHost tests that only service hosts are expanded and external hosts are left unchanged
+func TestQualifiedHost(t *testing.T) {
+	tests := []struct {
+		host, expected string
+	}{
+		{"reviews", "reviews.bookinfo.svc.cluster.local"},
+		{"reviews.bookinfo", "reviews.bookinfo.svc.cluster.local"},
+		{"reviews.prod.svc", "reviews.prod.svc.cluster.local"},
+		{"reviews.prod.svc.cluster.local", "reviews.prod.svc.cluster.local"},
+		{"httpbin.org", "httpbin.org"},
+		{"example.com", "example.com"},
+		{"api.example.com", "api.example.com"},
+		{"*.example.com", "*.example.com"},
+		{"*", "*"},
+	}
+	for _, test := range tests {
+		if host := qualifiedHost(test.host, "bookinfo"); host != test.expected {
+			t.Errorf("qualifiedHost(%q) = %q; expected %q", test.host, host, test.expected)
+		}
+	}
+
+	mockServer := newMockAPIServer(map[string]string{
+		"/api/v1/namespaces/prod": `{"apiVersion": "v1", "kind": "Namespace", "metadata": {"name": "prod"}}`,
+	})
+	defer mockServer.Close()
+	istio := newTestIstio(t, mockServer.URL)
+	for host, expected := range map[string]string{
+		"reviews.prod": "reviews.prod.svc.cluster.local",
+		"httpbin.org":  "httpbin.org",
+	} {
+		resolved, err := istio.resolveHost(context.Background(), host, "bookinfo")
+		if err != nil {
+			t.Fatalf("resolveHost(%q) failed: %v", host, err)
+		}
+		if resolved != expected {
+			t.Errorf("resolveHost(%q) = %q; expected %q", host, resolved, expected)
+		}
+	}
+}
+
+// TestGetPodsByServiceShortName tests that a short service name and its FQDN find the same pods
+func TestGetPodsByServiceShortName(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{
+		"/api/v1/namespaces/bookinfo/services/reviews": `{
+			"apiVersion": "v1",
+			"kind": "Service",
+			"metadata": {"name": "reviews", "namespace": "bookinfo"},
+			"spec": {"selector": {"app": "reviews"}}
+		}`,
+		"/api/v1/namespaces/bookinfo/pods": `{
+			"apiVersion": "v1",
+			"kind": "PodList",
+			"items": [{
+				"metadata": {"name": "reviews-v1-abc", "labels": {"app": "reviews"}},
+				"spec": {"containers": [{"name": "reviews"}, {"name": "istio-proxy"}]},
+				"status": {"phase": "Running"}
+			}]
+		}`,
+	})
+	defer mockServer.Close()
+	istio := newTestIstio(t, mockServer.URL)
+
+	short, err := istio.GetPodsByService(context.Background(), "bookinfo", "reviews")
+	if err != nil {
+		t.Fatalf("GetPodsByService with a short name failed: %v", err)
+	}
+	fqdn, err := istio.GetPodsByService(context.Background(), "default", "reviews.bookinfo.svc.cluster.local")
+	if err != nil {
+		t.Fatalf("GetPodsByService with an FQDN failed: %v", err)
+	}
+	if short != fqdn {
+		t.Errorf("Short name and FQDN resolved differently:\n%s\nvs\n%s", short, fqdn)
+	}
+	assertContains(t, fqdn, "reviews-v1-abc")
+}
@@ -0,0 +1,139 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// defaultTrustDomain is the SPIFFE trust domain used by Istio unless overridden in mesh config
+const defaultTrustDomain = "cluster.local"
+
+// spiffeID builds the SPIFFE identity Istio assigns to workloads running as the given ServiceAccount
+func spiffeID(trustDomain, namespace, serviceAccount string) string {
+	return fmt.Sprintf("spiffe://%s/ns/%s/sa/%s", trustDomain, namespace, serviceAccount)
+}
+
+// parseSpiffeID splits a SPIFFE identity into trust domain, namespace and ServiceAccount.
+// Both the URI form (spiffe://cluster.local/ns/foo/sa/bar) and the AuthorizationPolicy
+// principal form (cluster.local/ns/foo/sa/bar) are accepted.
+func parseSpiffeID(id string) (trustDomain, namespace, serviceAccount string, err error) {
+	parts := strings.Split(strings.TrimPrefix(id, "spiffe://"), "/")
+	if len(parts) != 5 || parts[1] != "ns" || parts[3] != "sa" || parts[0] == "" || parts[2] == "" || parts[4] == "" {
+		return "", "", "", fmt.Errorf("invalid SPIFFE identity '%s': expected format 'spiffe://<trust-domain>/ns/<namespace>/sa/<service-account>'", id)
+	}
+	return parts[0], parts[2], parts[4], nil
+}
+
+// podServiceAccount returns the ServiceAccount a pod runs as
+func podServiceAccount(pod v1.Pod) string {
+	if pod.Spec.ServiceAccountName != "" {
+		return pod.Spec.ServiceAccountName
+	}
+	return "default"
+}
+
+// GetWorkloadIdentity derives the SPIFFE identity a pod presents in mTLS connections from its ServiceAccount
+// and the trust domain of the mesh
+func (i *Istio) GetWorkloadIdentity(ctx context.Context, namespace, podName string) (string, error) {
+	pod, err := i.kubeClient.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get pod %s: %w", podName, explainForbidden(err, "get", "pods", namespace))
+	}
+	mesh, err := i.getMeshConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	serviceAccount := podServiceAccount(*pod)
+	id := spiffeID(mesh.TrustDomain, namespace, serviceAccount)
+
+	result := fmt.Sprintf("Workload identity for pod '%s' in namespace '%s':\n\n", podName, namespace)
+	result += fmt.Sprintf("Service Account: %s\n", serviceAccount)
+	result += fmt.Sprintf("SPIFFE ID: %s\n", id)
+	result += fmt.Sprintf("AuthorizationPolicy principal: %s\n", strings.TrimPrefix(id, "spiffe://"))
+	if !hasIstioSidecar(*pod) {
+		result += "\n[WARNING] Pod has no istio-proxy sidecar, so it does not present this identity over mTLS\n"
+	}
+	return result, nil
+}
+
+// FindWorkloadsByIdentity lists the pods running as the ServiceAccount encoded in a SPIFFE identity
+func (i *Istio) FindWorkloadsByIdentity(ctx context.Context, id string) (string, error) {
+	trustDomain, namespace, serviceAccount, err := parseSpiffeID(id)
+	if err != nil {
+		return "", err
+	}
+
+	pods, err := i.kubeClient.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list pods in namespace %s: %w", namespace, explainForbidden(err, "list", "pods", namespace))
+	}
+
+	var matches []v1.Pod
+	for _, pod := range pods.Items {
+		if podServiceAccount(pod) == serviceAccount {
+			matches = append(matches, pod)
+		}
+	}
+
+	result := fmt.Sprintf("Workloads with identity '%s':\n\n", spiffeID(trustDomain, namespace, serviceAccount))
+	if trustDomain != defaultTrustDomain {
+		result += fmt.Sprintf("Note: trust domain '%s' differs from the default '%s'; matching by namespace and service account only\n\n", trustDomain, defaultTrustDomain)
+	}
+	result += fmt.Sprintf("Found %d pods running as service account '%s' in namespace '%s':\n", len(matches), serviceAccount, namespace)
+	for _, pod := range matches {
+		mesh := "no sidecar"
+		if hasIstioSidecar(pod) {
+			mesh = "sidecar"
+		}
+		result += fmt.Sprintf("- %s (Status: %s, %s)\n", pod.Name, pod.Status.Phase, mesh)
+	}
+	return result, nil
+}
+
+// hasIstioSidecar reports whether the pod runs the istio-proxy sidecar,
+// either as a regular container or as a native sidecar init container
+func hasIstioSidecar(pod v1.Pod) bool {
+	for _, container := range pod.Spec.Containers {
+		if container.Name == "istio-proxy" {
+			return true
+		}
+	}
+	for _, container := range pod.Spec.InitContainers {
+		if container.Name == "istio-proxy" {
+			return true
+		}
+	}
+	return false
+}
+
+// GetMeshIdentityConfig reports the mesh settings SPIFFE identities and mesh-wide policies depend on: the root
+// namespace, whose PeerAuthentications and AuthorizationPolicies apply to the whole mesh, and the trust domain
+// (plus its aliases) forming the first segment of every workload identity and policy principal
+func (i *Istio) GetMeshIdentityConfig(ctx context.Context) (string, error) {
+	mesh, err := i.getMeshConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	result := "Mesh identity configuration:\n\n"
+	result += fmt.Sprintf("Root namespace: %s\n", mesh.RootNamespace)
+	result += fmt.Sprintf("  PeerAuthentications and AuthorizationPolicies in '%s' without a selector apply mesh-wide\n", mesh.RootNamespace)
+	result += fmt.Sprintf("Trust domain: %s\n", mesh.TrustDomain)
+	result += fmt.Sprintf("  Workload identities look like '%s'\n", spiffeID(mesh.TrustDomain, "<namespace>", "<service-account>"))
+	result += fmt.Sprintf("  AuthorizationPolicy principals look like '%s/ns/<namespace>/sa/<service-account>'\n", mesh.TrustDomain)
+	if len(mesh.TrustDomainAliases) > 0 {
+		result += fmt.Sprintf("Trust domain aliases: %s\n", strings.Join(mesh.TrustDomainAliases, ", "))
+		result += "  Principals using an alias are treated as the same identity as the trust domain\n"
+	} else {
+		result += "Trust domain aliases: none\n"
+	}
+	if mesh.TrustDomain != defaultTrustDomain {
+		result += fmt.Sprintf("\n[WARNING] The trust domain is not the default '%s'; principals written for '%s' don't match unless it is listed as an alias\n", defaultTrustDomain, defaultTrustDomain)
+	}
+	return result, nil
+}
@@ -0,0 +1,157 @@
+package istio
+
+import (
+	"context"
+	"testing"
+)
+
+// TestWorkloadIdentity tests mapping between pods and their SPIFFE identities in both directions
+func TestWorkloadIdentity(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{
+		"/api/v1/namespaces/bookinfo/pods/reviews-v1-abc": `{
+			"apiVersion": "v1",
+			"kind": "Pod",
+			"metadata": {"name": "reviews-v1-abc", "namespace": "bookinfo"},
+			"spec": {
+				"serviceAccountName": "bookinfo-reviews",
+				"containers": [{"name": "reviews"}, {"name": "istio-proxy"}]
+			}
+		}`,
+		"/api/v1/namespaces/bookinfo/pods": `{
+			"apiVersion": "v1",
+			"kind": "PodList",
+			"items": [
+				{
+					"metadata": {"name": "reviews-v1-abc", "namespace": "bookinfo"},
+					"spec": {"serviceAccountName": "bookinfo-reviews", "containers": [{"name": "reviews"}, {"name": "istio-proxy"}]},
+					"status": {"phase": "Running"}
+				},
+				{
+					"metadata": {"name": "reviews-v2-def", "namespace": "bookinfo"},
+					"spec": {"serviceAccountName": "bookinfo-reviews", "containers": [{"name": "reviews"}]},
+					"status": {"phase": "Running"}
+				},
+				{
+					"metadata": {"name": "ratings-v1-ghi", "namespace": "bookinfo"},
+					"spec": {"serviceAccountName": "bookinfo-ratings", "containers": [{"name": "ratings"}]},
+					"status": {"phase": "Running"}
+				}
+			]
+		}`,
+	})
+	defer mockServer.Close()
+
+	istio := newTestIstio(t, mockServer.URL)
+	ctx := context.Background()
+
+	t.Run("derives SPIFFE ID from pod service account", func(t *testing.T) {
+		result, err := istio.GetWorkloadIdentity(ctx, "bookinfo", "reviews-v1-abc")
+		if err != nil {
+			t.Fatalf("Failed to get workload identity: %v", err)
+		}
+		assertContains(t, result,
+			"Service Account: bookinfo-reviews",
+			"SPIFFE ID: spiffe://cluster.local/ns/bookinfo/sa/bookinfo-reviews",
+			"AuthorizationPolicy principal: cluster.local/ns/bookinfo/sa/bookinfo-reviews",
+		)
+	})
+
+	t.Run("finds workloads by SPIFFE ID", func(t *testing.T) {
+		result, err := istio.FindWorkloadsByIdentity(ctx, "spiffe://cluster.local/ns/bookinfo/sa/bookinfo-reviews")
+		if err != nil {
+			t.Fatalf("Failed to find workloads by identity: %v", err)
+		}
+		assertContains(t, result, "Found 2 pods", "reviews-v1-abc", "reviews-v2-def")
+		assertNotContains(t, result, "ratings-v1-ghi")
+	})
+
+	t.Run("accepts principal form", func(t *testing.T) {
+		result, err := istio.FindWorkloadsByIdentity(ctx, "cluster.local/ns/bookinfo/sa/bookinfo-ratings")
+		if err != nil {
+			t.Fatalf("Failed to find workloads by identity: %v", err)
+		}
+		assertContains(t, result, "Found 1 pods", "ratings-v1-ghi")
+	})
+
+	t.Run("rejects malformed identity", func(t *testing.T) {
+		if _, err := istio.FindWorkloadsByIdentity(ctx, "bookinfo/bookinfo-reviews"); err == nil {
+			t.Fatal("Expected error for malformed SPIFFE identity")
+		}
+	})
+}
+
+// TestGetMeshIdentityConfig tests reporting of a custom trust domain and its aliases from the mesh config
+func TestGetMeshIdentityConfig(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{
+		"/api/v1/namespaces/istio-system/configmaps/istio": `{
+			"apiVersion": "v1",
+			"kind": "ConfigMap",
+			"metadata": {"name": "istio", "namespace": "istio-system"},
+			"data": {"mesh": "rootNamespace: istio-config\ntrustDomain: prod.example.com\ntrustDomainAliases:\n- cluster.local\n- old.example.com\n"}
+		}`,
+	})
+	defer mockServer.Close()
+	istio := newTestIstio(t, mockServer.URL)
+
+	result, err := istio.GetMeshIdentityConfig(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to get mesh identity config: %v", err)
+	}
+	assertContains(t, result,
+		"Root namespace: istio-config",
+		"Trust domain: prod.example.com",
+		"spiffe://prod.example.com/ns/<namespace>/sa/<service-account>",
+		"Trust domain aliases: cluster.local, old.example.com",
+		"[WARNING] The trust domain is not the default 'cluster.local'",
+	)
+}
+
+// TestCustomTrustDomainIdentity tests that derived workload identities use the trust domain of the mesh config
+func TestCustomTrustDomainIdentity(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{
+		"/api/v1/namespaces/istio-system/configmaps/istio": `{
+			"apiVersion": "v1",
+			"kind": "ConfigMap",
+			"metadata": {"name": "istio", "namespace": "istio-system"},
+			"data": {"mesh": "trustDomain: prod.example.com\n"}
+		}`,
+		"/api/v1/namespaces/bookinfo/pods/reviews-v1-abc": `{
+			"apiVersion": "v1",
+			"kind": "Pod",
+			"metadata": {"name": "reviews-v1-abc", "namespace": "bookinfo"},
+			"spec": {"serviceAccountName": "bookinfo-reviews", "containers": [{"name": "reviews"}, {"name": "istio-proxy"}]}
+		}`,
+		"/apis/networking.istio.io/v1alpha3/namespaces/vm/workloadgroups/ledger": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "WorkloadGroup",
+			"metadata": {"name": "ledger", "namespace": "vm"},
+			"spec": {"metadata": {"labels": {"app": "ledger"}}, "template": {"serviceAccount": "ledger-vm"}}
+		}`,
+		"/api/v1/namespaces/vm/serviceaccounts/ledger-vm":                  `{"apiVersion": "v1", "kind": "ServiceAccount", "metadata": {"name": "ledger-vm", "namespace": "vm"}}`,
+		"/apis/networking.istio.io/v1alpha3/namespaces/vm/serviceentries":  `{"apiVersion": "networking.istio.io/v1alpha3", "kind": "ServiceEntryList", "items": []}`,
+		"/apis/networking.istio.io/v1alpha3/namespaces/vm/workloadentries": `{"apiVersion": "networking.istio.io/v1alpha3", "kind": "WorkloadEntryList", "items": []}`,
+	})
+	defer mockServer.Close()
+	istio := newTestIstio(t, mockServer.URL)
+	ctx := context.Background()
+
+	t.Run("workload identity", func(t *testing.T) {
+		result, err := istio.GetWorkloadIdentity(ctx, "bookinfo", "reviews-v1-abc")
+		if err != nil {
+			t.Fatalf("Failed to get workload identity: %v", err)
+		}
+		assertContains(t, result,
+			"SPIFFE ID: spiffe://prod.example.com/ns/bookinfo/sa/bookinfo-reviews",
+			"AuthorizationPolicy principal: prod.example.com/ns/bookinfo/sa/bookinfo-reviews",
+		)
+		assertNotContains(t, result, "cluster.local")
+	})
+
+	t.Run("mesh expansion service account", func(t *testing.T) {
+		result, err := istio.CheckMeshExpansionReadiness(ctx, "vm", "ledger")
+		if err != nil {
+			t.Fatalf("Failed to check readiness: %v", err)
+		}
+		assertContains(t, result, "[OK] ServiceAccount: 'ledger-vm' exists (identity spiffe://prod.example.com/ns/vm/sa/ledger-vm)")
+	})
+}
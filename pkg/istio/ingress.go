@@ -0,0 +1,226 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// isIngressGatewayService reports whether a Service fronts an Istio ingress gateway
+func isIngressGatewayService(service v1.Service) bool {
+	return service.Labels["istio"] == "ingressgateway" || strings.Contains(service.Name, "ingressgateway")
+}
+
+// loadBalancerAddresses returns the external IPs and hostnames assigned to a LoadBalancer Service
+func loadBalancerAddresses(service v1.Service) []string {
+	var addresses []string
+	for _, ingress := range service.Status.LoadBalancer.Ingress {
+		if ingress.IP != "" {
+			addresses = append(addresses, ingress.IP)
+		}
+		if ingress.Hostname != "" {
+			addresses = append(addresses, ingress.Hostname)
+		}
+	}
+	return addresses
+}
+
+// nodeAddress returns an address of a node that can be used to reach NodePorts, preferring external IPs
+func (i *Istio) nodeAddress(ctx context.Context) string {
+	nodes, err := i.kubeClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{Limit: 1})
+	if err != nil || len(nodes.Items) == 0 {
+		return ""
+	}
+	for _, addressType := range []v1.NodeAddressType{v1.NodeExternalIP, v1.NodeInternalIP} {
+		for _, address := range nodes.Items[0].Status.Addresses {
+			if address.Type == addressType {
+				return address.Address
+			}
+		}
+	}
+	return ""
+}
+
+// GetIngressGatewayAddress finds the ingress gateway Services in a namespace and reports the external
+// address and ports to reach the mesh from outside the cluster, with the NodePort fallback
+func (i *Istio) GetIngressGatewayAddress(ctx context.Context, namespace string) (string, error) {
+	services, err := i.kubeClient.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list services: %w", explainForbidden(err, "list", "services", namespace))
+	}
+
+	var gateways []v1.Service
+	for _, service := range services.Items {
+		if isIngressGatewayService(service) {
+			gateways = append(gateways, service)
+		}
+	}
+	if len(gateways) == 0 {
+		return fmt.Sprintf("No ingress gateway services found in namespace '%s'. Ingress gateways are usually installed in 'istio-system' or a dedicated gateway namespace.\n", namespace), nil
+	}
+
+	nodeIP := ""
+	result := fmt.Sprintf("Found %d ingress gateway services in namespace '%s':\n\n", len(gateways), namespace)
+	for _, gateway := range gateways {
+		result += fmt.Sprintf("- %s\n", gateway.Name)
+		result += fmt.Sprintf("  Type: %s\n", gateway.Spec.Type)
+
+		addresses := loadBalancerAddresses(gateway)
+		switch {
+		case len(addresses) > 0:
+			result += fmt.Sprintf("  External Address: %s\n", strings.Join(addresses, ", "))
+		case gateway.Spec.Type == v1.ServiceTypeLoadBalancer:
+			result += "  External Address: <pending>\n"
+			result += "  [WARNING] The load balancer has not been provisioned yet; use the NodePorts below until it is\n"
+		default:
+			result += "  External Address: <none>\n"
+		}
+
+		if len(gateway.Spec.Ports) > 0 {
+			result += "  Ports:\n"
+		}
+		for _, port := range gateway.Spec.Ports {
+			line := fmt.Sprintf("    - %s: %d/%s", port.Name, port.Port, port.Protocol)
+			if port.NodePort != 0 {
+				line += fmt.Sprintf(" (NodePort: %d)", port.NodePort)
+			}
+			result += line + "\n"
+		}
+
+		if len(addresses) == 0 && gateway.Spec.Type != v1.ServiceTypeClusterIP {
+			if nodeIP == "" {
+				nodeIP = i.nodeAddress(ctx)
+			}
+			host := nodeIP
+			if host == "" {
+				host = "<node-ip>"
+			}
+			for _, port := range gateway.Spec.Ports {
+				if port.NodePort != 0 {
+					result += fmt.Sprintf("  NodePort Fallback: %s:%d (%s)\n", host, port.NodePort, port.Name)
+				}
+			}
+		}
+		result += "\n"
+	}
+	return result, nil
+}
+
+// gatewayServices returns the Services fronting the workload a Gateway selects: those selecting one of its pods, or,
+// when no pod runs yet, those whose selector contains the Gateway's selector
+func gatewayServices(selector map[string]string, services []v1.Service, pods []v1.Pod) []v1.Service {
+	if len(selector) == 0 {
+		return nil
+	}
+	matcher := labels.SelectorFromSet(selector)
+	var selected []v1.Pod
+	for _, pod := range pods {
+		if matcher.Matches(labels.Set(pod.Labels)) {
+			selected = append(selected, pod)
+		}
+	}
+	var matches []v1.Service
+	for idx := range services {
+		service := &services[idx]
+		if len(service.Spec.Selector) == 0 {
+			continue
+		}
+		matched := matcher.Matches(labels.Set(service.Spec.Selector))
+		if len(selected) > 0 {
+			matched = len(servicePods(service, selected)) > 0
+		}
+		if matched {
+			matches = append(matches, *service)
+		}
+	}
+	return matches
+}
+
+// serviceAddresses returns the addresses clients use to reach a Service: its load balancer or external IPs, else its
+// cluster IP, which is only reachable from inside the cluster
+func serviceAddresses(service v1.Service) (addresses []string, external bool) {
+	addresses = append(loadBalancerAddresses(service), service.Spec.ExternalIPs...)
+	if len(addresses) > 0 {
+		return addresses, true
+	}
+	if service.Spec.Type == v1.ServiceTypeLoadBalancer {
+		return []string{"<pending>"}, false
+	}
+	if service.Spec.ClusterIP != "" && service.Spec.ClusterIP != "None" {
+		return []string{service.Spec.ClusterIP}, false
+	}
+	return nil, false
+}
+
+// GetGatewayEndpoints joins the Gateways of a namespace with the Services fronting the gateway workloads they
+// select, reporting for every server the address and port a client actually connects to
+func (i *Istio) GetGatewayEndpoints(ctx context.Context, namespace string) (string, error) {
+	gateways, err := i.istioClient.NetworkingV1alpha3().Gateways(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list gateways: %w", explainForbidden(err, "list", "gateways", namespace))
+	}
+	if len(gateways.Items) == 0 {
+		return fmt.Sprintf("No Gateways found in namespace '%s'\n", namespace), nil
+	}
+	services, err := i.kubeClient.CoreV1().Services("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list services: %w", explainForbidden(err, "list", "services", ""))
+	}
+	pods, err := i.kubeClient.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list pods: %w", explainForbidden(err, "list", "pods", ""))
+	}
+
+	result := fmt.Sprintf("Endpoints of the Gateways in namespace '%s':\n\n", namespace)
+	unreachable := 0
+	for _, gw := range gateways.Items {
+		selector := gw.Spec.GetSelector()
+		result += fmt.Sprintf("Gateway '%s' (selector %s):\n", gw.Name, labels.SelectorFromSet(selector))
+		frontends := gatewayServices(selector, services.Items, pods.Items)
+		if len(frontends) == 0 {
+			unreachable++
+			result += "  [WARNING] No Service fronts the workload selected by this Gateway, so it has no reachable address\n\n"
+			continue
+		}
+		for _, service := range frontends {
+			addresses, external := serviceAddresses(service)
+			scope := "external"
+			if !external {
+				scope = "cluster-internal"
+			}
+			result += fmt.Sprintf("  Service %s/%s (%s): %s (%s)\n", service.Namespace, service.Name, service.Spec.Type, strings.Join(addresses, ", "), scope)
+			for _, server := range gw.Spec.GetServers() {
+				port := server.GetPort()
+				hosts := strings.Join(server.GetHosts(), ", ")
+				var servicePort *v1.ServicePort
+				for idx := range service.Spec.Ports {
+					if uint32(service.Spec.Ports[idx].Port) == port.GetNumber() {
+						servicePort = &service.Spec.Ports[idx]
+					}
+				}
+				if servicePort == nil {
+					result += fmt.Sprintf("    - [WARNING] %s port %d (hosts: %s): the Service exposes no port %d\n", port.GetProtocol(), port.GetNumber(), hosts, port.GetNumber())
+					continue
+				}
+				line := fmt.Sprintf("    - %s port %d (hosts: %s)", port.GetProtocol(), port.GetNumber(), hosts)
+				if len(addresses) > 0 {
+					line += fmt.Sprintf(" -> %s:%d", addresses[0], servicePort.Port)
+				}
+				if servicePort.NodePort != 0 {
+					line += fmt.Sprintf(" (NodePort: %d)", servicePort.NodePort)
+				}
+				result += line + "\n"
+			}
+		}
+		result += "\n"
+	}
+
+	if unreachable > 0 {
+		result += fmt.Sprintf("[RESULT] %d of %d Gateways have no Service exposing them\n", unreachable, len(gateways.Items))
+	}
+	return result, nil
+}
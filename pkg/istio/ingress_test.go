@@ -0,0 +1,140 @@
+package istio
+
+import (
+	"context"
+	"testing"
+)
+
+// TestGetIngressGatewayAddress tests reporting of ingress gateway external addresses
+func TestGetIngressGatewayAddress(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{
+		"/api/v1/namespaces/istio-system/services": `{
+			"apiVersion": "v1",
+			"kind": "ServiceList",
+			"items": [
+				{
+					"metadata": {"name": "istio-ingressgateway", "namespace": "istio-system", "labels": {"istio": "ingressgateway"}},
+					"spec": {"type": "LoadBalancer", "ports": [
+						{"name": "http2", "port": 80, "protocol": "TCP", "nodePort": 31380},
+						{"name": "https", "port": 443, "protocol": "TCP", "nodePort": 31390}
+					]},
+					"status": {"loadBalancer": {"ingress": [{"ip": "34.120.1.10"}]}}
+				},
+				{
+					"metadata": {"name": "internal-ingressgateway", "namespace": "istio-system"},
+					"spec": {"type": "LoadBalancer", "ports": [{"name": "http2", "port": 80, "protocol": "TCP", "nodePort": 30080}]},
+					"status": {"loadBalancer": {}}
+				},
+				{
+					"metadata": {"name": "istiod", "namespace": "istio-system"},
+					"spec": {"type": "ClusterIP", "ports": [{"name": "grpc-xds", "port": 15010, "protocol": "TCP"}]}
+				}
+			]
+		}`,
+		"/api/v1/nodes": `{
+			"apiVersion": "v1",
+			"kind": "NodeList",
+			"items": [
+				{"metadata": {"name": "node-1"}, "status": {"addresses": [{"type": "InternalIP", "address": "10.0.0.5"}]}}
+			]
+		}`,
+		"/api/v1/namespaces/default/services": `{"apiVersion": "v1", "kind": "ServiceList", "items": []}`,
+	})
+	defer mockServer.Close()
+
+	istio := newTestIstio(t, mockServer.URL)
+	ctx := context.Background()
+
+	t.Run("assigned and pending load balancers", func(t *testing.T) {
+		result, err := istio.GetIngressGatewayAddress(ctx, "istio-system")
+		if err != nil {
+			t.Fatalf("Failed to get ingress gateway address: %v", err)
+		}
+		assertContains(t, result,
+			"Found 2 ingress gateway services",
+			"External Address: 34.120.1.10",
+			"- https: 443/TCP (NodePort: 31390)",
+			"- internal-ingressgateway",
+			"External Address: <pending>",
+			"NodePort Fallback: 10.0.0.5:30080 (http2)",
+		)
+		assertNotContains(t, result, "istiod", "10.0.0.5:31380")
+	})
+
+	t.Run("no gateways", func(t *testing.T) {
+		result, err := istio.GetIngressGatewayAddress(ctx, "default")
+		if err != nil {
+			t.Fatalf("Failed to get ingress gateway address: %v", err)
+		}
+		assertContains(t, result, "No ingress gateway services found in namespace 'default'")
+	})
+}
+
+// TestGetGatewayEndpoints tests joining a Gateway's selector to the LoadBalancer Service of the ingress gateway
+func TestGetGatewayEndpoints(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{
+		"/apis/networking.istio.io/v1alpha3/namespaces/bookinfo/gateways": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "GatewayList",
+			"items": [
+				{
+					"metadata": {"name": "bookinfo-gateway", "namespace": "bookinfo"},
+					"spec": {
+						"selector": {"istio": "ingressgateway"},
+						"servers": [
+							{"port": {"number": 443, "name": "https", "protocol": "HTTPS"}, "hosts": ["bookinfo.example.com"]},
+							{"port": {"number": 8443, "name": "admin", "protocol": "HTTPS"}, "hosts": ["admin.example.com"]}
+						]
+					}
+				},
+				{
+					"metadata": {"name": "orphan-gateway", "namespace": "bookinfo"},
+					"spec": {"selector": {"istio": "missing"}, "servers": [{"port": {"number": 80, "name": "http", "protocol": "HTTP"}, "hosts": ["*"]}]}
+				}
+			]
+		}`,
+		"/api/v1/services": `{
+			"apiVersion": "v1",
+			"kind": "ServiceList",
+			"items": [
+				{
+					"metadata": {"name": "istio-ingressgateway", "namespace": "istio-system"},
+					"spec": {
+						"type": "LoadBalancer",
+						"selector": {"app": "istio-ingressgateway", "istio": "ingressgateway"},
+						"ports": [{"name": "https", "port": 443, "targetPort": 8443, "nodePort": 31443}]
+					},
+					"status": {"loadBalancer": {"ingress": [{"ip": "34.120.10.20"}]}}
+				},
+				{
+					"metadata": {"name": "reviews", "namespace": "bookinfo"},
+					"spec": {"selector": {"app": "reviews"}, "ports": [{"port": 9080}]}
+				}
+			]
+		}`,
+		"/api/v1/pods": `{
+			"apiVersion": "v1",
+			"kind": "PodList",
+			"items": [{
+				"metadata": {"name": "istio-ingressgateway-7d9f", "namespace": "istio-system", "labels": {"app": "istio-ingressgateway", "istio": "ingressgateway"}},
+				"spec": {"containers": [{"name": "istio-proxy"}]}
+			}]
+		}`,
+	})
+	defer mockServer.Close()
+	istio := newTestIstio(t, mockServer.URL)
+
+	result, err := istio.GetGatewayEndpoints(context.Background(), "bookinfo")
+	if err != nil {
+		t.Fatalf("Failed to get gateway endpoints: %v", err)
+	}
+	assertContains(t, result,
+		"Gateway 'bookinfo-gateway' (selector istio=ingressgateway)",
+		"Service istio-system/istio-ingressgateway (LoadBalancer): 34.120.10.20 (external)",
+		"HTTPS port 443 (hosts: bookinfo.example.com) -> 34.120.10.20:443 (NodePort: 31443)",
+		"[WARNING] HTTPS port 8443 (hosts: admin.example.com): the Service exposes no port 8443",
+		"[WARNING] No Service fronts the workload selected by this Gateway",
+		"[RESULT] 1 of 2 Gateways have no Service exposing them",
+	)
+	assertNotContains(t, result, "reviews")
+}
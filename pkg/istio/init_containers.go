@@ -0,0 +1,79 @@
+package istio
+
+import (
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// istioInitContainers are the init containers added by sidecar injection: istio-init sets up traffic
+// redirection, or istio-validation checks it when the Istio CNI plugin does the setup instead
+var istioInitContainers = map[string]bool{"istio-init": true, "istio-validation": true}
+
+// containerStateSummary describes the state of a container like kubectl does, e.g. 'Waiting: CrashLoopBackOff'
+func containerStateSummary(status v1.ContainerStatus) string {
+	switch state := status.State; {
+	case state.Waiting != nil:
+		return "Waiting: " + state.Waiting.Reason
+	case state.Running != nil:
+		return "Running"
+	case state.Terminated != nil && state.Terminated.ExitCode == 0:
+		return "Completed"
+	case state.Terminated != nil:
+		return fmt.Sprintf("Terminated: %s (exit code %d)", state.Terminated.Reason, state.Terminated.ExitCode)
+	}
+	return "Unknown"
+}
+
+// initContainerFailure returns why an init container failed (e.g. 'CrashLoopBackOff' or 'Error'), or "" when it has
+// not. Only waiting reasons reporting a failure count: a container that is still being created or waits for an
+// earlier one (e.g. 'ContainerCreating' or 'PodInitializing') is starting, not failing.
+func initContainerFailure(status v1.ContainerStatus) string {
+	if waiting := status.State.Waiting; waiting != nil {
+		reason := waiting.Reason
+		if reason == "CrashLoopBackOff" || reason == "ImagePullBackOff" || strings.HasPrefix(reason, "Err") || strings.HasSuffix(reason, "Error") {
+			return reason
+		}
+	}
+	if terminated := status.State.Terminated; terminated != nil && terminated.ExitCode != 0 {
+		if terminated.Reason == "" {
+			return fmt.Sprintf("exit code %d", terminated.ExitCode)
+		}
+		return terminated.Reason
+	}
+	return ""
+}
+
+// podInitFailure returns why an init container blocks the pod from starting, or "" when none has failed
+func podInitFailure(pod v1.Pod) string {
+	for _, status := range pod.Status.InitContainerStatuses {
+		if reason := initContainerFailure(status); reason != "" {
+			return reason
+		}
+	}
+	return ""
+}
+
+// describeInitContainers lists the init containers of a pod with their state, explaining failures of the
+// containers added by sidecar injection
+func describeInitContainers(pod v1.Pod, indent string) string {
+	if len(pod.Status.InitContainerStatuses) == 0 {
+		return indent + "Init containers: none\n"
+	}
+	result := indent + "Init containers:\n"
+	for _, status := range pod.Status.InitContainerStatuses {
+		state := containerStateSummary(status)
+		result += fmt.Sprintf("%s  - %s: %s (restarts: %d)\n", indent, status.Name, state, status.RestartCount)
+		if initContainerFailure(status) == "" {
+			continue
+		}
+		if terminated := status.LastTerminationState.Terminated; terminated != nil && terminated.Message != "" {
+			result += fmt.Sprintf("%s    Last failure: %s\n", indent, terminated.Message)
+		}
+		if istioInitContainers[status.Name] {
+			result += fmt.Sprintf("%s    [ERROR] %s failed, so the pod cannot start: traffic redirection could not be set up. Check that the pod may use the NET_ADMIN and NET_RAW capabilities, or that the Istio CNI plugin is healthy on node '%s'\n", indent, status.Name, pod.Spec.NodeName)
+		}
+	}
+	return result
+}
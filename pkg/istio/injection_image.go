@@ -0,0 +1,122 @@
+package istio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// sidecarInjectorConfigMap is the ConfigMap holding the injection template and values of the default revision;
+	// other revisions use it suffixed with '-<revision>'
+	sidecarInjectorConfigMap = "istio-sidecar-injector"
+	// defaultRevision is the revision injecting namespaces labeled istio-injection=enabled
+	defaultRevision = "default"
+)
+
+// injectorValues is the subset of the Helm values in the sidecar injector ConfigMap that determine the proxy image
+type injectorValues struct {
+	Global struct {
+		Hub   string `json:"hub"`
+		Tag   any    `json:"tag"`
+		Proxy struct {
+			Image string `json:"image"`
+		} `json:"proxy"`
+		Variant string `json:"variant"`
+	} `json:"global"`
+}
+
+// proxyImage builds the proxy image reference the injector uses: '<hub>/<image>:<tag>[-<variant>]', or the image
+// as is when it is already a full reference
+func (v injectorValues) proxyImage() string {
+	image := v.Global.Proxy.Image
+	if image == "" {
+		image = "proxyv2"
+	}
+	if strings.Contains(image, "/") || strings.Contains(image, ":") {
+		return image
+	}
+	tag := fmt.Sprint(v.Global.Tag)
+	if v.Global.Tag == nil {
+		tag = "latest"
+	}
+	if v.Global.Variant != "" {
+		tag += "-" + v.Global.Variant
+	}
+	return fmt.Sprintf("%s/%s:%s", strings.TrimSuffix(v.Global.Hub, "/"), image, tag)
+}
+
+// resolveRevisionTag returns the revision a revision tag points to, from the istio-revision-tag-<tag>
+// MutatingWebhookConfiguration, or "" when no such tag exists
+func (i *Istio) resolveRevisionTag(ctx context.Context, tag string) (string, error) {
+	webhooks, err := i.kubeClient.AdmissionregistrationV1().MutatingWebhookConfigurations().List(ctx, metav1.ListOptions{LabelSelector: "istio.io/tag=" + tag})
+	if err != nil {
+		return "", fmt.Errorf("failed to list mutating webhook configurations: %w", explainForbidden(err, "list", "mutatingwebhookconfigurations", ""))
+	}
+	for _, webhook := range webhooks.Items {
+		if webhook.Labels["istio.io/tag"] == tag {
+			return webhook.Labels["istio.io/rev"], nil
+		}
+	}
+	return "", nil
+}
+
+// GetInjectionImage reports the proxy image new pods of a namespace get from sidecar injection: the revision
+// selected by the namespace labels, resolved through revision tags, and the image configured in the
+// istio-sidecar-injector ConfigMap of that revision
+func (i *Istio) GetInjectionImage(ctx context.Context, namespace string) (string, error) {
+	ns, err := i.kubeClient.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get namespace %s: %w", namespace, explainForbidden(err, "get", "namespaces", ""))
+	}
+
+	result := fmt.Sprintf("Sidecar injection image for namespace '%s':\n\n", namespace)
+	revision := defaultRevision
+	switch injection, rev := ns.Labels["istio-injection"], ns.Labels["istio.io/rev"]; {
+	case injection == "disabled":
+		result += "[WARNING] Injection is disabled by the label istio-injection=disabled; new pods get no sidecar\n"
+		return result, nil
+	case injection == "enabled":
+		result += "Injection: enabled by the label istio-injection=enabled (default revision)\n"
+	case rev != "":
+		revision = rev
+		result += fmt.Sprintf("Injection: enabled by the label istio.io/rev=%s\n", rev)
+	default:
+		result += "Injection: the namespace has no injection label; only pods labeled sidecar.istio.io/inject=true are injected, by the default revision\n"
+	}
+
+	tagged, err := i.resolveRevisionTag(ctx, revision)
+	if err != nil {
+		return "", err
+	}
+	if tagged != "" && tagged != revision {
+		result += fmt.Sprintf("Revision tag '%s' points to revision '%s'\n", revision, tagged)
+		revision = tagged
+	}
+
+	configMapName := sidecarInjectorConfigMap
+	if revision != defaultRevision {
+		configMapName += "-" + revision
+	}
+	cm, err := i.kubeClient.CoreV1().ConfigMaps(istioSystemNamespace).Get(ctx, configMapName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		result += fmt.Sprintf("\n[ERROR] ConfigMap %s/%s of revision '%s' not found; the revision is not installed, so injection fails\n", istioSystemNamespace, configMapName, revision)
+		return result, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get sidecar injector config: %w", explainForbidden(err, "get", "configmaps", istioSystemNamespace))
+	}
+	var values injectorValues
+	if err := json.Unmarshal([]byte(cm.Data["values"]), &values); err != nil {
+		return "", fmt.Errorf("failed to parse values of ConfigMap %s: %w", configMapName, err)
+	}
+
+	result += fmt.Sprintf("Revision: %s (ConfigMap %s/%s)\n", revision, istioSystemNamespace, configMapName)
+	result += fmt.Sprintf("Proxy image: %s\n", values.proxyImage())
+	result += "\nRunning pods keep the image they were injected with until they are restarted\n"
+	return result, nil
+}
@@ -0,0 +1,43 @@
+package istio
+
+import (
+	"context"
+	"testing"
+)
+
+// TestGetInjectionImage tests resolving a namespace's revision tag to the proxy image of the revision's injector
+func TestGetInjectionImage(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{
+		"/api/v1/namespaces/bookinfo": `{
+			"apiVersion": "v1",
+			"kind": "Namespace",
+			"metadata": {"name": "bookinfo", "labels": {"istio.io/rev": "prod-stable"}}
+		}`,
+		"/apis/admissionregistration.k8s.io/v1/mutatingwebhookconfigurations": `{
+			"apiVersion": "admissionregistration.k8s.io/v1",
+			"kind": "MutatingWebhookConfigurationList",
+			"items": [{
+				"metadata": {"name": "istio-revision-tag-prod-stable", "labels": {"istio.io/tag": "prod-stable", "istio.io/rev": "1-25-1"}}
+			}]
+		}`,
+		"/api/v1/namespaces/istio-system/configmaps/istio-sidecar-injector-1-25-1": `{
+			"apiVersion": "v1",
+			"kind": "ConfigMap",
+			"metadata": {"name": "istio-sidecar-injector-1-25-1", "namespace": "istio-system"},
+			"data": {"values": "{\"global\": {\"hub\": \"docker.io/istio\", \"tag\": \"1.25.1\", \"proxy\": {\"image\": \"proxyv2\"}, \"variant\": \"distroless\"}}"}
+		}`,
+	})
+	defer mockServer.Close()
+	istio := newTestIstio(t, mockServer.URL)
+
+	result, err := istio.GetInjectionImage(context.Background(), "bookinfo")
+	if err != nil {
+		t.Fatalf("Failed to get injection image: %v", err)
+	}
+	assertContains(t, result,
+		"Injection: enabled by the label istio.io/rev=prod-stable",
+		"Revision tag 'prod-stable' points to revision '1-25-1'",
+		"Revision: 1-25-1 (ConfigMap istio-system/istio-sidecar-injector-1-25-1)",
+		"Proxy image: docker.io/istio/proxyv2:1.25.1-distroless",
+	)
+}
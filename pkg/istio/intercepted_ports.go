@@ -0,0 +1,141 @@
+package istio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Pod annotations the sidecar injector turns into the iptables rules redirecting traffic to the proxy
+const (
+	includeInboundPortsAnnotation     = "traffic.sidecar.istio.io/includeInboundPorts"
+	excludeInboundPortsAnnotation     = "traffic.sidecar.istio.io/excludeInboundPorts"
+	includeOutboundPortsAnnotation    = "traffic.sidecar.istio.io/includeOutboundPorts"
+	excludeOutboundPortsAnnotation    = "traffic.sidecar.istio.io/excludeOutboundPorts"
+	includeOutboundIPRangesAnnotation = "traffic.sidecar.istio.io/includeOutboundIPRanges"
+	excludeOutboundIPRangesAnnotation = "traffic.sidecar.istio.io/excludeOutboundIPRanges"
+)
+
+// proxyPorts are the ports of the proxy itself (status, health and Prometheus), never redirected
+var proxyPorts = []string{"15020", "15021", "15090"}
+
+// bootstrapInterception holds the part of an istioctl bootstrap dump with the interception mode of the proxy
+type bootstrapInterception struct {
+	Bootstrap struct {
+		Node struct {
+			Metadata struct {
+				InterceptionMode string `json:"INTERCEPTION_MODE"`
+			} `json:"metadata"`
+		} `json:"node"`
+	} `json:"bootstrap"`
+}
+
+// annotationList splits a comma-separated interception annotation, returning def when the annotation is not set
+func annotationList(annotations map[string]string, name string, def []string) []string {
+	value, ok := annotations[name]
+	if !ok {
+		return def
+	}
+	var values []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			values = append(values, item)
+		}
+	}
+	return values
+}
+
+// GetInterceptedPorts reports which inbound and outbound traffic of a pod is redirected to its sidecar: the
+// inbound ports captured or bypassed according to the includeInboundPorts and excludeInboundPorts annotations,
+// and the outbound IP ranges and ports captured or excluded, with the interception mode from the proxy bootstrap.
+// Traffic that bypasses the sidecar gets no mTLS, authorization policy or telemetry.
+func (i *Istio) GetInterceptedPorts(ctx context.Context, namespace, podName string) (string, error) {
+	pod, err := i.kubeClient.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get pod %s: %w", podName, explainForbidden(err, "get", "pods", namespace))
+	}
+	if istioProxyContainer(pod) == nil {
+		return "", fmt.Errorf("pod %s in namespace %s has no istio-proxy container", podName, namespace)
+	}
+	annotations := pod.Annotations
+
+	result := fmt.Sprintf("Traffic interception for pod '%s' in namespace '%s':\n\n", podName, namespace)
+	bootstrap, err := i.ProxyConfig.GetBootstrap(ctx, namespace, podName)
+	var config bootstrapInterception
+	if err == nil {
+		err = json.Unmarshal([]byte(bootstrap), &config)
+	}
+	switch {
+	case err != nil:
+		result += fmt.Sprintf("[WARNING] Could not read the interception mode from the proxy bootstrap: %v\n", err)
+	case config.Bootstrap.Node.Metadata.InterceptionMode != "":
+		result += fmt.Sprintf("Interception mode: %s (from the proxy bootstrap)\n", config.Bootstrap.Node.Metadata.InterceptionMode)
+	}
+	redirection := "Istio CNI plugin"
+	if slices.ContainsFunc(pod.Spec.InitContainers, func(c v1.Container) bool { return c.Name == "istio-init" }) {
+		redirection = "istio-init container (iptables)"
+	}
+	result += fmt.Sprintf("Redirection set up by: %s\n", redirection)
+
+	// Inbound: the ports the containers of the pod listen on
+	includeInbound := annotationList(annotations, includeInboundPortsAnnotation, []string{"*"})
+	excludeInbound := annotationList(annotations, excludeInboundPortsAnnotation, nil)
+	result += fmt.Sprintf("\nInbound (includeInboundPorts: %s, excludeInboundPorts: %s):\n", listOrNone(includeInbound), listOrNone(excludeInbound))
+	bypassed := 0
+	for _, container := range pod.Spec.Containers {
+		if container.Name == "istio-proxy" {
+			continue
+		}
+		for _, port := range container.Ports {
+			number := strconv.Itoa(int(port.ContainerPort))
+			description := fmt.Sprintf("%s/%s", number, port.Protocol)
+			if port.Name != "" {
+				description += fmt.Sprintf(" (%s)", port.Name)
+			}
+			switch {
+			case slices.Contains(excludeInbound, number):
+				bypassed++
+				result += fmt.Sprintf("  [BYPASS] %s: excluded by excludeInboundPorts\n", description)
+			case !slices.Contains(includeInbound, "*") && !slices.Contains(includeInbound, number):
+				bypassed++
+				result += fmt.Sprintf("  [BYPASS] %s: not in includeInboundPorts\n", description)
+			default:
+				result += fmt.Sprintf("  [CAPTURED] %s\n", description)
+			}
+		}
+	}
+	result += fmt.Sprintf("  The proxy's own ports (%s) are never captured\n", strings.Join(proxyPorts, ", "))
+
+	// Outbound: all traffic to the included IP ranges, except excluded ports and IP ranges
+	includeRanges := annotationList(annotations, includeOutboundIPRangesAnnotation, []string{"*"})
+	excludeRanges := annotationList(annotations, excludeOutboundIPRangesAnnotation, nil)
+	excludePorts := annotationList(annotations, excludeOutboundPortsAnnotation, nil)
+	includePorts := annotationList(annotations, includeOutboundPortsAnnotation, nil)
+	result += "\nOutbound:\n"
+	result += fmt.Sprintf("  Captured IP ranges: %s\n", listOrNone(includeRanges))
+	if len(excludeRanges) > 0 {
+		result += fmt.Sprintf("  [BYPASS] Excluded IP ranges: %s\n", strings.Join(excludeRanges, ", "))
+	}
+	if len(excludePorts) > 0 {
+		result += fmt.Sprintf("  [BYPASS] Excluded ports: %s\n", strings.Join(excludePorts, ", "))
+	}
+	if len(includePorts) > 0 {
+		result += fmt.Sprintf("  Ports captured regardless of the IP ranges: %s\n", strings.Join(includePorts, ", "))
+	}
+	if len(includeRanges) == 0 && len(includePorts) == 0 {
+		result += "  [BYPASS] No outbound traffic is captured\n"
+	}
+
+	if bypassed > 0 || len(excludeRanges) > 0 || len(excludePorts) > 0 || !slices.Contains(includeRanges, "*") {
+		result += "\n[WARNING] Traffic that bypasses the sidecar gets no mTLS, authorization policy, routing or telemetry\n"
+	} else {
+		result += "\n[OK] All inbound and outbound traffic of the pod goes through the sidecar\n"
+	}
+	return result, nil
+}
@@ -0,0 +1,49 @@
+package istio
+
+import (
+	"context"
+	"testing"
+)
+
+// TestGetInterceptedPorts tests reporting of the inbound ports and outbound traffic redirected to a sidecar
+func TestGetInterceptedPorts(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{
+		"/api/v1/namespaces/bookinfo/pods/reviews-v1-abc": `{
+			"apiVersion": "v1",
+			"kind": "Pod",
+			"metadata": {"name": "reviews-v1-abc", "namespace": "bookinfo", "annotations": {
+				"traffic.sidecar.istio.io/includeInboundPorts": "*",
+				"traffic.sidecar.istio.io/excludeInboundPorts": "9090",
+				"traffic.sidecar.istio.io/excludeOutboundPorts": "5432, 6379"
+			}},
+			"spec": {
+				"initContainers": [{"name": "istio-init"}],
+				"containers": [
+					{"name": "reviews", "ports": [{"name": "http", "containerPort": 9080, "protocol": "TCP"}, {"name": "metrics", "containerPort": 9090, "protocol": "TCP"}]},
+					{"name": "istio-proxy", "ports": [{"name": "http-envoy-prom", "containerPort": 15090, "protocol": "TCP"}]}
+				]
+			}
+		}`,
+	})
+	defer mockServer.Close()
+
+	istio := newTestIstio(t, mockServer.URL)
+	stubIstioctl(istio.ProxyConfig, `{"bootstrap": {"node": {"metadata": {"INTERCEPTION_MODE": "REDIRECT"}}}}`)
+	istio.ProxyConfig.SetCacheTTL(0)
+
+	result, err := istio.GetInterceptedPorts(context.Background(), "bookinfo", "reviews-v1-abc")
+	if err != nil {
+		t.Fatalf("GetInterceptedPorts failed: %v", err)
+	}
+	assertContains(t, result,
+		"Interception mode: REDIRECT (from the proxy bootstrap)",
+		"Redirection set up by: istio-init container (iptables)",
+		"Inbound (includeInboundPorts: *, excludeInboundPorts: 9090):",
+		"[CAPTURED] 9080/TCP (http)",
+		"[BYPASS] 9090/TCP (metrics): excluded by excludeInboundPorts",
+		"Captured IP ranges: *",
+		"[BYPASS] Excluded ports: 5432, 6379",
+		"[WARNING] Traffic that bypasses the sidecar",
+	)
+	assertNotContains(t, result, "[CAPTURED] 9090", "15090/TCP")
+}
@@ -2,14 +2,23 @@ package istio
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"runtime"
+	"slices"
 	"sort"
 	"strings"
+	"sync"
 
 	"github.com/fsnotify/fsnotify"
+	"github.com/krutsko/istio-mcp-server/pkg/version"
+	networkingv1alpha3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
+	securityv1beta1 "istio.io/client-go/pkg/apis/security/v1beta1"
+	telemetryv1alpha1 "istio.io/client-go/pkg/apis/telemetry/v1alpha1"
 	"istio.io/client-go/pkg/clientset/versioned"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -30,11 +39,35 @@ type Istio struct {
 	clientCmdConfig      clientcmd.ClientConfig
 	CloseWatchKubeConfig CloseWatchKubeConfig
 	ProxyConfig          *ProxyConfigClient
+	// securityAPIOnce guards securityV1, which records whether security.istio.io/v1 is served
+	securityAPIOnce sync.Once
+	securityV1      bool
+	// prometheusURL is the base URL of the Prometheus server queried by metrics-backed tools, empty when not configured
+	prometheusURL string
+	// exportRoot is the directory exports may write under, empty when exporting is disabled
+	exportRoot string
+}
+
+// ClientOption tunes the configuration of the Kubernetes and Istio API clients
+type ClientOption func(*rest.Config)
+
+// WithRateLimit sets the sustained queries per second and the burst of requests the clients send to the API
+// server; client-go defaults to 5 and 10, which throttles scans of many namespaces on large clusters
+func WithRateLimit(qps float32, burst int) ClientOption {
+	return func(config *rest.Config) {
+		config.QPS = qps
+		config.Burst = burst
+	}
+}
+
+// userAgent identifies the server's requests in API server audit logs and metrics
+func userAgent() string {
+	return fmt.Sprintf("%s/%s (%s/%s)", version.BinaryName, version.Version, runtime.GOOS, runtime.GOARCH)
 }
 
 // NewIstio creates a new Istio client instance
-func NewIstio(kubeconfig string) (*Istio, error) {
-	config, clientCmdConfig, err := buildConfig(kubeconfig)
+func NewIstio(kubeconfig string, opts ...ClientOption) (*Istio, error) {
+	config, clientCmdConfig, err := buildConfig(kubeconfig, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build config: %w", err)
 	}
@@ -58,8 +91,9 @@ func NewIstio(kubeconfig string) (*Istio, error) {
 	}, nil
 }
 
-// buildConfig builds the Kubernetes configuration from kubeconfig path
-func buildConfig(kubeconfig string) (*rest.Config, clientcmd.ClientConfig, error) {
+// buildConfig builds the Kubernetes configuration from kubeconfig path, identified by the server's user agent and
+// tuned by the client options
+func buildConfig(kubeconfig string, opts ...ClientOption) (*rest.Config, clientcmd.ClientConfig, error) {
 	var clientCmdConfig clientcmd.ClientConfig
 	var config *rest.Config
 	var err error
@@ -89,161 +123,231 @@ func buildConfig(kubeconfig string) (*rest.Config, clientcmd.ClientConfig, error
 		}
 	}
 
+	config.UserAgent = userAgent()
+	for _, opt := range opts {
+		opt(config)
+	}
 	return config, clientCmdConfig, nil
 }
 
 // GetVirtualServices retrieves Virtual Services from the specified namespace
-func (i *Istio) GetVirtualServices(ctx context.Context, namespace string) (string, error) {
-	vsList, err := i.istioClient.NetworkingV1alpha3().VirtualServices(namespace).List(ctx, metav1.ListOptions{})
+func (i *Istio) GetVirtualServices(ctx context.Context, namespace string, opts ...GetOption) (string, error) {
+	o := newGetOptions(opts)
+	listOpts, err := o.listOptions("virtual services", crdSelectableFields)
+	if err != nil {
+		return "", err
+	}
+	vsList, err := i.istioClient.NetworkingV1alpha3().VirtualServices(namespace).List(ctx, listOpts)
 	if err != nil {
-		return "", fmt.Errorf("failed to list virtual services: %w", err)
+		return "", fmt.Errorf("failed to list virtual services: %w", i.explainAPIError(ctx, err, "list", "virtualservices", namespace))
 	}
 
 	result := fmt.Sprintf("Found %d Virtual Services in namespace '%s':\n", len(vsList.Items), namespace)
-	for _, vs := range vsList.Items {
-		result += fmt.Sprintf("- %s\n", vs.Name)
+	items, err := renderItems(vsList.Items, o, func(vs *networkingv1alpha3.VirtualService) string {
+		details := ""
 		if vs.Spec.Hosts != nil {
-			result += fmt.Sprintf("  Hosts: %v\n", vs.Spec.Hosts)
+			details += fmt.Sprintf("  Hosts: %v\n", vs.Spec.Hosts)
 		}
 		if vs.Spec.Gateways != nil {
-			result += fmt.Sprintf("  Gateways: %v\n", vs.Spec.Gateways)
+			details += fmt.Sprintf("  Gateways: %v\n", vs.Spec.Gateways)
 		}
 		if len(vs.Spec.Http) > 0 {
-			result += fmt.Sprintf("  HTTP Routes: %d\n", len(vs.Spec.Http))
+			details += fmt.Sprintf("  HTTP Routes: %d\n", len(vs.Spec.Http))
 		}
 		if len(vs.Spec.Tcp) > 0 {
-			result += fmt.Sprintf("  TCP Routes: %d\n", len(vs.Spec.Tcp))
+			details += fmt.Sprintf("  TCP Routes: %d\n", len(vs.Spec.Tcp))
 		}
 		if len(vs.Spec.Tls) > 0 {
-			result += fmt.Sprintf("  TLS Routes: %d\n", len(vs.Spec.Tls))
+			details += fmt.Sprintf("  TLS Routes: %d\n", len(vs.Spec.Tls))
 		}
-		result += "\n"
+		return details + "\n"
+	})
+	if err != nil {
+		return "", err
 	}
-
-	return result, nil
+	return result + items, nil
 }
 
-func (i *Istio) GetDestinationRules(ctx context.Context, namespace string) (string, error) {
-	drList, err := i.istioClient.NetworkingV1alpha3().DestinationRules(namespace).List(ctx, metav1.ListOptions{})
+func (i *Istio) GetDestinationRules(ctx context.Context, namespace string, opts ...GetOption) (string, error) {
+	o := newGetOptions(opts)
+	listOpts, err := o.listOptions("destination rules", crdSelectableFields)
 	if err != nil {
-		return "", fmt.Errorf("failed to list destination rules: %w", err)
+		return "", err
+	}
+	drList, err := i.istioClient.NetworkingV1alpha3().DestinationRules(namespace).List(ctx, listOpts)
+	if err != nil {
+		return "", fmt.Errorf("failed to list destination rules: %w", i.explainAPIError(ctx, err, "list", "destinationrules", namespace))
 	}
 
 	result := fmt.Sprintf("Found %d Destination Rules in namespace '%s':\n", len(drList.Items), namespace)
-	for _, dr := range drList.Items {
-		result += fmt.Sprintf("- %s\n", dr.Name)
+	items, err := renderItems(drList.Items, o, func(dr *networkingv1alpha3.DestinationRule) string {
 		if dr.Spec.Host != "" {
-			result += fmt.Sprintf("  Host: %s\n", dr.Spec.Host)
+			return fmt.Sprintf("  Host: %s\n", dr.Spec.Host)
 		}
+		return ""
+	})
+	if err != nil {
+		return "", err
 	}
-	return result, nil
+	return result + items, nil
 }
 
-func (i *Istio) GetGateways(ctx context.Context, namespace string) (string, error) {
-	gwList, err := i.istioClient.NetworkingV1alpha3().Gateways(namespace).List(ctx, metav1.ListOptions{})
+func (i *Istio) GetGateways(ctx context.Context, namespace string, opts ...GetOption) (string, error) {
+	o := newGetOptions(opts)
+	listOpts, err := o.listOptions("gateways", crdSelectableFields)
+	if err != nil {
+		return "", err
+	}
+	gwList, err := i.istioClient.NetworkingV1alpha3().Gateways(namespace).List(ctx, listOpts)
 	if err != nil {
-		return "", fmt.Errorf("failed to list gateways: %w", err)
+		return "", fmt.Errorf("failed to list gateways: %w", i.explainAPIError(ctx, err, "list", "gateways", namespace))
 	}
 
 	result := fmt.Sprintf("Found %d Gateways in namespace '%s':\n", len(gwList.Items), namespace)
-	for _, gw := range gwList.Items {
-		result += fmt.Sprintf("- %s\n", gw.Name)
+	items, err := renderItems(gwList.Items, o, func(gw *networkingv1alpha3.Gateway) string {
 		if gw.Spec.Selector != nil {
-			result += fmt.Sprintf("  Selector: %v\n", gw.Spec.Selector)
+			return fmt.Sprintf("  Selector: %v\n", gw.Spec.Selector)
 		}
+		return ""
+	})
+	if err != nil {
+		return "", err
 	}
-	return result, nil
+	return result + items, nil
 }
 
-func (i *Istio) GetServiceEntries(ctx context.Context, namespace string) (string, error) {
-	seList, err := i.istioClient.NetworkingV1alpha3().ServiceEntries(namespace).List(ctx, metav1.ListOptions{})
+func (i *Istio) GetServiceEntries(ctx context.Context, namespace string, opts ...GetOption) (string, error) {
+	o := newGetOptions(opts)
+	listOpts, err := o.listOptions("service entries", crdSelectableFields)
 	if err != nil {
-		return "", fmt.Errorf("failed to list service entries: %w", err)
+		return "", err
+	}
+	seList, err := i.istioClient.NetworkingV1alpha3().ServiceEntries(namespace).List(ctx, listOpts)
+	if err != nil {
+		return "", fmt.Errorf("failed to list service entries: %w", i.explainAPIError(ctx, err, "list", "serviceentries", namespace))
 	}
 
 	result := fmt.Sprintf("Found %d Service Entries in namespace '%s':\n", len(seList.Items), namespace)
-	for _, se := range seList.Items {
-		result += fmt.Sprintf("- %s\n", se.Name)
+	items, err := renderItems(seList.Items, o, func(se *networkingv1alpha3.ServiceEntry) string {
+		details := ""
 		if se.Spec.Hosts != nil {
-			result += fmt.Sprintf("  Hosts: %v\n", se.Spec.Hosts)
+			details += fmt.Sprintf("  Hosts: %v\n", se.Spec.Hosts)
 		}
 		if se.Spec.Location.String() != "" {
-			result += fmt.Sprintf("  Location: %s\n", se.Spec.Location.String())
+			details += fmt.Sprintf("  Location: %s\n", se.Spec.Location.String())
 		}
+		return details
+	})
+	if err != nil {
+		return "", err
 	}
-	return result, nil
+	return result + items, nil
 }
 
 // Security resources
-func (i *Istio) GetAuthorizationPolicies(ctx context.Context, namespace string) (string, error) {
-	apList, err := i.istioClient.SecurityV1beta1().AuthorizationPolicies(namespace).List(ctx, metav1.ListOptions{})
+func (i *Istio) GetAuthorizationPolicies(ctx context.Context, namespace string, opts ...GetOption) (string, error) {
+	o := newGetOptions(opts)
+	listOpts, err := o.listOptions("authorization policies", crdSelectableFields)
+	if err != nil {
+		return "", err
+	}
+	apList, err := i.listAuthorizationPolicies(ctx, namespace, listOpts)
 	if err != nil {
-		return "", fmt.Errorf("failed to list authorization policies: %w", err)
+		return "", fmt.Errorf("failed to list authorization policies: %w", i.explainAPIError(ctx, err, "list", "authorizationpolicies", namespace))
 	}
 
-	result := fmt.Sprintf("Found %d Authorization Policies in namespace '%s':\n", len(apList.Items), namespace)
-	for _, ap := range apList.Items {
-		result += fmt.Sprintf("- %s\n", ap.Name)
+	result := fmt.Sprintf("Found %d Authorization Policies in namespace '%s':\n", len(apList), namespace)
+	items, err := renderItems(apList, o, func(ap *securityv1beta1.AuthorizationPolicy) string {
+		details := ""
 		if ap.Spec.Selector != nil && ap.Spec.Selector.MatchLabels != nil {
-			result += fmt.Sprintf("  Selector: %v\n", ap.Spec.Selector.MatchLabels)
+			details += fmt.Sprintf("  Selector: %v\n", ap.Spec.Selector.MatchLabels)
 		}
 		if ap.Spec.Action.String() != "" {
-			result += fmt.Sprintf("  Action: %s\n", ap.Spec.Action.String())
+			details += fmt.Sprintf("  Action: %s\n", ap.Spec.Action.String())
 		}
+		return details
+	})
+	if err != nil {
+		return "", err
 	}
-	return result, nil
+	return result + items, nil
 }
 
-func (i *Istio) GetPeerAuthentications(ctx context.Context, namespace string) (string, error) {
-	paList, err := i.istioClient.SecurityV1beta1().PeerAuthentications(namespace).List(ctx, metav1.ListOptions{})
+func (i *Istio) GetPeerAuthentications(ctx context.Context, namespace string, opts ...GetOption) (string, error) {
+	o := newGetOptions(opts)
+	listOpts, err := o.listOptions("peer authentications", crdSelectableFields)
 	if err != nil {
-		return "", fmt.Errorf("failed to list peer authentications: %w", err)
+		return "", err
+	}
+	paList, err := i.listPeerAuthentications(ctx, namespace, listOpts)
+	if err != nil {
+		return "", fmt.Errorf("failed to list peer authentications: %w", i.explainAPIError(ctx, err, "list", "peerauthentications", namespace))
 	}
 
-	result := fmt.Sprintf("Found %d Peer Authentications in namespace '%s':\n", len(paList.Items), namespace)
-	for _, pa := range paList.Items {
-		result += fmt.Sprintf("- %s\n", pa.Name)
+	result := fmt.Sprintf("Found %d Peer Authentications in namespace '%s':\n", len(paList), namespace)
+	items, err := renderItems(paList, o, func(pa *securityv1beta1.PeerAuthentication) string {
+		details := ""
 		if pa.Spec.Selector != nil && pa.Spec.Selector.MatchLabels != nil {
-			result += fmt.Sprintf("  Selector: %v\n", pa.Spec.Selector.MatchLabels)
+			details += fmt.Sprintf("  Selector: %v\n", pa.Spec.Selector.MatchLabels)
 		}
 		if pa.Spec.Mtls != nil {
-			result += fmt.Sprintf("  mTLS Mode: %s\n", pa.Spec.Mtls.Mode.String())
+			details += fmt.Sprintf("  mTLS Mode: %s\n", pa.Spec.Mtls.Mode.String())
 		}
+		return details
+	})
+	if err != nil {
+		return "", err
 	}
-	return result, nil
+	return result + items, nil
 }
 
 // Configuration resources
-func (i *Istio) GetEnvoyFilters(ctx context.Context, namespace string) (string, error) {
-	efList, err := i.istioClient.NetworkingV1alpha3().EnvoyFilters(namespace).List(ctx, metav1.ListOptions{})
+func (i *Istio) GetEnvoyFilters(ctx context.Context, namespace string, opts ...GetOption) (string, error) {
+	o := newGetOptions(opts)
+	listOpts, err := o.listOptions("envoy filters", crdSelectableFields)
+	if err != nil {
+		return "", err
+	}
+	efList, err := i.istioClient.NetworkingV1alpha3().EnvoyFilters(namespace).List(ctx, listOpts)
 	if err != nil {
-		return "", fmt.Errorf("failed to list envoy filters: %w", err)
+		return "", fmt.Errorf("failed to list envoy filters: %w", i.explainAPIError(ctx, err, "list", "envoyfilters", namespace))
 	}
 
 	result := fmt.Sprintf("Found %d Envoy Filters in namespace '%s':\n", len(efList.Items), namespace)
-	for _, ef := range efList.Items {
-		result += fmt.Sprintf("- %s\n", ef.Name)
+	items, err := renderItems(efList.Items, o, func(ef *networkingv1alpha3.EnvoyFilter) string {
 		if ef.Spec.WorkloadSelector != nil && ef.Spec.WorkloadSelector.Labels != nil {
-			result += fmt.Sprintf("  Workload Selector: %v\n", ef.Spec.WorkloadSelector.Labels)
+			return fmt.Sprintf("  Workload Selector: %v\n", ef.Spec.WorkloadSelector.Labels)
 		}
+		return ""
+	})
+	if err != nil {
+		return "", err
 	}
-	return result, nil
+	return result + items, nil
 }
 
-func (i *Istio) GetTelemetries(ctx context.Context, namespace string) (string, error) {
-	telList, err := i.istioClient.TelemetryV1alpha1().Telemetries(namespace).List(ctx, metav1.ListOptions{})
+func (i *Istio) GetTelemetries(ctx context.Context, namespace string, opts ...GetOption) (string, error) {
+	o := newGetOptions(opts)
+	listOpts, err := o.listOptions("telemetries", crdSelectableFields)
 	if err != nil {
-		return "", fmt.Errorf("failed to list telemetries: %w", err)
+		return "", err
+	}
+	telList, err := i.istioClient.TelemetryV1alpha1().Telemetries(namespace).List(ctx, listOpts)
+	if err != nil {
+		return "", fmt.Errorf("failed to list telemetries: %w", i.explainAPIError(ctx, err, "list", "telemetries", namespace))
 	}
 
 	result := fmt.Sprintf("Found %d Telemetry configurations in namespace '%s':\n", len(telList.Items), namespace)
-	for _, tel := range telList.Items {
-		result += fmt.Sprintf("- %s\n", tel.Name)
+	items, err := renderItems(telList.Items, o, func(tel *telemetryv1alpha1.Telemetry) string {
 		if tel.Spec.Selector != nil && tel.Spec.Selector.MatchLabels != nil {
-			result += fmt.Sprintf("  Selector: %v\n", tel.Spec.Selector.MatchLabels)
+			return fmt.Sprintf("  Selector: %v\n", tel.Spec.Selector.MatchLabels)
 		}
+		return ""
+	})
+	if err != nil {
+		return "", err
 	}
-	return result, nil
+	return result + items, nil
 }
 
 func (i *Istio) GetIstioConfigSummary(ctx context.Context, namespace string) (string, error) {
@@ -278,18 +382,18 @@ func (i *Istio) GetIstioConfigSummary(ctx context.Context, namespace string) (st
 		result += fmt.Sprintf("Service Entries: %d\n", len(seList.Items))
 	}
 
-	apList, err := i.istioClient.SecurityV1beta1().AuthorizationPolicies(namespace).List(ctx, metav1.ListOptions{})
+	apList, err := i.listAuthorizationPolicies(ctx, namespace, metav1.ListOptions{})
 	if err != nil {
 		klog.Warningf("Failed to list authorization policies: %v", err)
 	} else {
-		result += fmt.Sprintf("Authorization Policies: %d\n", len(apList.Items))
+		result += fmt.Sprintf("Authorization Policies: %d\n", len(apList))
 	}
 
-	paList, err := i.istioClient.SecurityV1beta1().PeerAuthentications(namespace).List(ctx, metav1.ListOptions{})
+	paList, err := i.listPeerAuthentications(ctx, namespace, metav1.ListOptions{})
 	if err != nil {
 		klog.Warningf("Failed to list peer authentications: %v", err)
 	} else {
-		result += fmt.Sprintf("Peer Authentications: %d\n", len(paList.Items))
+		result += fmt.Sprintf("Peer Authentications: %d\n", len(paList))
 	}
 
 	efList, err := i.istioClient.NetworkingV1alpha3().EnvoyFilters(namespace).List(ctx, metav1.ListOptions{})
@@ -316,7 +420,7 @@ func (i *Istio) CheckExternalDependencyAvailability(ctx context.Context, service
 	// Check 1: Service Entry existence
 	seList, err := i.istioClient.NetworkingV1alpha3().ServiceEntries(namespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
-		return "", fmt.Errorf("failed to list service entries: %w", err)
+		return "", fmt.Errorf("failed to list service entries: %w", i.explainAPIError(ctx, err, "list", "serviceentries", namespace))
 	}
 
 	serviceEntryFound := false
@@ -360,7 +464,7 @@ func (i *Istio) CheckExternalDependencyAvailability(ctx context.Context, service
 	// Check 2: Virtual Service routing
 	vsList, err := i.istioClient.NetworkingV1alpha3().VirtualServices(namespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
-		return "", fmt.Errorf("failed to list virtual services: %w", err)
+		return "", fmt.Errorf("failed to list virtual services: %w", i.explainAPIError(ctx, err, "list", "virtualservices", namespace))
 	}
 
 	virtualServiceFound := false
@@ -385,7 +489,7 @@ func (i *Istio) CheckExternalDependencyAvailability(ctx context.Context, service
 	// Check 3: Destination Rules
 	drList, err := i.istioClient.NetworkingV1alpha3().DestinationRules(namespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
-		return "", fmt.Errorf("failed to list destination rules: %w", err)
+		return "", fmt.Errorf("failed to list destination rules: %w", i.explainAPIError(ctx, err, "list", "destinationrules", namespace))
 	}
 
 	destinationRuleFound := false
@@ -403,14 +507,14 @@ func (i *Istio) CheckExternalDependencyAvailability(ctx context.Context, service
 	}
 
 	// Check 4: Authorization Policies
-	apList, err := i.istioClient.SecurityV1beta1().AuthorizationPolicies(namespace).List(ctx, metav1.ListOptions{})
+	apList, err := i.listAuthorizationPolicies(ctx, namespace, metav1.ListOptions{})
 	if err != nil {
-		return "", fmt.Errorf("failed to list authorization policies: %w", err)
+		return "", fmt.Errorf("failed to list authorization policies: %w", i.explainAPIError(ctx, err, "list", "authorizationpolicies", namespace))
 	}
 
 	authorizationPolicyFound := false
 	var authorizationPolicyDetails string
-	for _, ap := range apList.Items {
+	for _, ap := range apList {
 		// Check if the policy allows access to the external host
 		if ap.Spec.Action.String() == "ALLOW" {
 			// This is a simplified check - in practice, you'd need to analyze the rules more carefully
@@ -447,20 +551,55 @@ func (i *Istio) CheckExternalDependencyAvailability(ctx context.Context, service
 }
 
 // GetServices retrieves all Kubernetes services in a namespace
-func (i *Istio) GetServices(ctx context.Context, namespace string) (string, error) {
-	services, err := i.kubeClient.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
+func (i *Istio) GetServices(ctx context.Context, namespace string, opts ...GetOption) (string, error) {
+	o := newGetOptions(opts)
+	listOpts, err := o.listOptions("services", serviceSelectableFields)
+	if err != nil {
+		return "", err
+	}
+	services, err := i.kubeClient.CoreV1().Services(namespace).List(ctx, listOpts)
 	if err != nil {
-		return "", fmt.Errorf("failed to list services: %w", err)
+		return "", fmt.Errorf("failed to list services: %w", explainForbidden(err, "list", "services", namespace))
 	}
+	sort.Slice(services.Items, func(a, b int) bool {
+		return services.Items[a].Name < services.Items[b].Name
+	})
 
 	result := fmt.Sprintf("Services in namespace '%s':\n\n", namespace)
-	result += fmt.Sprintf("Found %d services:\n\n", len(services.Items))
+	if o.istioOnly {
+		pods, err := i.kubeClient.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return "", fmt.Errorf("failed to list pods: %w", explainForbidden(err, "list", "pods", namespace))
+		}
+		var meshed []v1.Service
+		for idx := range services.Items {
+			if slices.ContainsFunc(servicePods(&services.Items[idx], pods.Items), hasIstioSidecar) {
+				meshed = append(meshed, services.Items[idx])
+			}
+		}
+		result += fmt.Sprintf("Found %d services backed by pods with an Istio sidecar (%d services in total):\n\n", len(meshed), len(services.Items))
+		services.Items = meshed
+	} else {
+		result += fmt.Sprintf("Found %d services:\n\n", len(services.Items))
+	}
 
 	if len(services.Items) == 0 {
 		result += "No services found in this namespace.\n"
 		return result, nil
 	}
 
+	if o.verbosity != VerbosityNormal {
+		items := make([]*v1.Service, 0, len(services.Items))
+		for idx := range services.Items {
+			items = append(items, &services.Items[idx])
+		}
+		listed, err := renderItems(items, o, nil)
+		if err != nil {
+			return "", err
+		}
+		return result + listed, nil
+	}
+
 	// Group services by type for better organization
 	var clusterIPServices []string
 	var nodePortServices []string
@@ -469,11 +608,12 @@ func (i *Istio) GetServices(ctx context.Context, namespace string) (string, erro
 
 	for _, service := range services.Items {
 		serviceLine := fmt.Sprintf("%-30s", service.Name)
+		ports := servicePortLines(service)
 
 		// Add service type and cluster IP info
 		switch service.Spec.Type {
 		case "NodePort":
-			nodePortServices = append(nodePortServices, fmt.Sprintf("%s (NodePort: %s)", serviceLine, service.Spec.ClusterIP))
+			nodePortServices = append(nodePortServices, fmt.Sprintf("%s (NodePort: %s)%s", serviceLine, service.Spec.ClusterIP, ports))
 		case "LoadBalancer":
 			externalIP := "<pending>"
 			if len(service.Status.LoadBalancer.Ingress) > 0 {
@@ -483,12 +623,12 @@ func (i *Istio) GetServices(ctx context.Context, namespace string) (string, erro
 					externalIP = service.Status.LoadBalancer.Ingress[0].Hostname
 				}
 			}
-			loadBalancerServices = append(loadBalancerServices, fmt.Sprintf("%s (LoadBalancer: %s)", serviceLine, externalIP))
+			loadBalancerServices = append(loadBalancerServices, fmt.Sprintf("%s (LoadBalancer: %s)%s", serviceLine, externalIP, ports))
 		default:
 			if service.Spec.ClusterIP == "None" {
-				headlessServices = append(headlessServices, fmt.Sprintf("%s (Headless)", serviceLine))
+				headlessServices = append(headlessServices, fmt.Sprintf("%s (Headless)%s", serviceLine, ports))
 			} else {
-				clusterIPServices = append(clusterIPServices, fmt.Sprintf("%s (ClusterIP: %s)", serviceLine, service.Spec.ClusterIP))
+				clusterIPServices = append(clusterIPServices, fmt.Sprintf("%s (ClusterIP: %s)%s", serviceLine, service.Spec.ClusterIP, ports))
 			}
 		}
 	}
@@ -532,14 +672,113 @@ func (i *Istio) GetServices(ctx context.Context, namespace string) (string, erro
 	return result, nil
 }
 
-// GetPodsByService finds pods backing a specific Kubernetes service
-func (i *Istio) GetPodsByService(ctx context.Context, namespace, serviceName string) (string, error) {
+// GetPodsByService finds pods backing a specific Kubernetes service. The service may be given by its short name or
+// qualified with its namespace, e.g. 'reviews.bookinfo' or 'reviews.bookinfo.svc.cluster.local'.
+func (i *Istio) GetPodsByService(ctx context.Context, namespace, serviceName string, opts ...GetOption) (string, error) {
+	serviceName, namespace = resolveServiceName(serviceName, namespace)
 	// Get the service to find its selector
 	service, err := i.kubeClient.CoreV1().Services(namespace).Get(ctx, serviceName, metav1.GetOptions{})
 	if err != nil {
-		return "", fmt.Errorf("failed to get service %s: %w", serviceName, err)
+		return "", fmt.Errorf("failed to get service %s: %w", serviceName, explainForbidden(err, "get", "services", namespace))
+	}
+
+	var pods []v1.Pod
+	if service.Spec.Selector != nil {
+		// Find pods matching the service selector
+		podList, err := i.kubeClient.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+			LabelSelector: labels.SelectorFromSet(service.Spec.Selector).String(),
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to list pods for service %s: %w", serviceName, explainForbidden(err, "list", "pods", namespace))
+		}
+		pods = podList.Items
 	}
 
+	return i.describeServicePods(ctx, namespace, service, pods, i.namespaceLabels(ctx, namespace), newGetOptions(opts)), nil
+}
+
+// GetPodsByServices finds pods backing several services of a namespace, grouped by service.
+// Services and pods are listed once for the whole namespace and matched locally; services qualified with
+// another namespace are looked up separately.
+func (i *Istio) GetPodsByServices(ctx context.Context, namespace string, serviceNames []string, opts ...GetOption) (string, error) {
+	services, err := i.kubeClient.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list services: %w", explainForbidden(err, "list", "services", namespace))
+	}
+	pods, err := i.kubeClient.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list pods: %w", explainForbidden(err, "list", "pods", namespace))
+	}
+
+	servicesByName := make(map[string]*v1.Service, len(services.Items))
+	for idx := range services.Items {
+		servicesByName[services.Items[idx].Name] = &services.Items[idx]
+	}
+
+	namespaceLabels := i.namespaceLabels(ctx, namespace)
+	o := newGetOptions(opts)
+	var groups []string
+	for _, serviceName := range serviceNames {
+		name, ns := resolveServiceName(serviceName, namespace)
+		if ns != namespace {
+			// Services qualified with another namespace are looked up on their own
+			group, err := i.GetPodsByService(ctx, ns, name, opts...)
+			if err != nil {
+				group = fmt.Sprintf("[ERROR] %v\n", err)
+			}
+			groups = append(groups, group)
+			continue
+		}
+		service, ok := servicesByName[name]
+		if !ok {
+			groups = append(groups, fmt.Sprintf("[ERROR] Service '%s' not found in namespace '%s'\n", serviceName, namespace))
+			continue
+		}
+		groups = append(groups, i.describeServicePods(ctx, namespace, service, servicePods(service, pods.Items), namespaceLabels, o))
+	}
+	return strings.Join(groups, "\n---\n\n"), nil
+}
+
+// servicePortLines formats the ports of a service, one per line, indented to nest under the service in GetServices
+func servicePortLines(service v1.Service) string {
+	lines := ""
+	for _, port := range service.Spec.Ports {
+		targetPort := port.TargetPort.String()
+		if port.TargetPort.IntValue() == 0 && port.TargetPort.StrVal == "" {
+			// An unset targetPort defaults to the service port
+			targetPort = fmt.Sprint(port.Port)
+		}
+		line := fmt.Sprintf("%d -> %s/%s", port.Port, targetPort, port.Protocol)
+		if port.Name != "" {
+			line = port.Name + ": " + line
+		}
+		if port.NodePort != 0 {
+			line += fmt.Sprintf(" (node port %d)", port.NodePort)
+		}
+		lines += "\n     - " + line
+	}
+	return lines
+}
+
+// servicePods returns the pods selected by a service; services without selector select no pods
+func servicePods(service *v1.Service, pods []v1.Pod) []v1.Pod {
+	if service.Spec.Selector == nil {
+		return nil
+	}
+	var matching []v1.Pod
+	selector := labels.SelectorFromSet(service.Spec.Selector)
+	for _, pod := range pods {
+		if selector.Matches(labels.Set(pod.Labels)) {
+			matching = append(matching, pod)
+		}
+	}
+	return matching
+}
+
+// describeServicePods formats the pods backing a service, or its manually configured endpoints when it has no selector.
+// namespaceLabels are the labels of the namespace, used to detect ambient enrollment.
+func (i *Istio) describeServicePods(ctx context.Context, namespace string, service *v1.Service, pods []v1.Pod, namespaceLabels map[string]string, o getOptions) string {
+	serviceName := service.Name
 	result := fmt.Sprintf("Pods backing service '%s' in namespace '%s':\n\n", serviceName, namespace)
 
 	// Handle headless services or services without selectors
@@ -563,29 +802,16 @@ func (i *Istio) GetPodsByService(ctx context.Context, namespace, serviceName str
 				}
 			}
 		}
-		return result, nil
+		return result
 	}
 
-	// Convert selector to label selector string
-	var selectorParts []string
-	for key, value := range service.Spec.Selector {
-		selectorParts = append(selectorParts, fmt.Sprintf("%s=%s", key, value))
-	}
-	labelSelector := strings.Join(selectorParts, ",")
-
-	// Find pods matching the service selector
-	pods, err := i.kubeClient.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
-		LabelSelector: labelSelector,
-	})
-	if err != nil {
-		return "", fmt.Errorf("failed to list pods for service %s: %w", serviceName, err)
-	}
+	labelSelector := labels.SelectorFromSet(service.Spec.Selector).String()
 
 	// Separate running and non-running pods
 	var runningPods []v1.Pod
 	var nonRunningPods []v1.Pod
 
-	for _, pod := range pods.Items {
+	for _, pod := range pods {
 		if pod.Status.Phase == "Running" {
 			runningPods = append(runningPods, pod)
 		} else {
@@ -595,20 +821,14 @@ func (i *Istio) GetPodsByService(ctx context.Context, namespace, serviceName str
 
 	result += fmt.Sprintf(" Service selector: %s\n", labelSelector)
 	result += fmt.Sprintf(" Total pods found: %d (%d running, %d not running)\n\n",
-		len(pods.Items), len(runningPods), len(nonRunningPods))
+		len(pods), len(runningPods), len(nonRunningPods))
 
 	// Show running pods (most important)
 	if len(runningPods) > 0 {
 		result += fmt.Sprintf(" Running pods (%d) - Ready for proxy commands:\n", len(runningPods))
 		for _, pod := range runningPods {
-			// Check if it has Istio sidecar
-			hasIstio := false
-			for _, container := range pod.Spec.Containers {
-				if container.Name == "istio-proxy" {
-					hasIstio = true
-					break
-				}
-			}
+			// Check if it has an Istio sidecar or is enrolled in ambient mode
+			mode := podMeshMode(pod, namespaceLabels)
 
 			readyIcon := "❌"
 			if isPodReady(pod) {
@@ -616,7 +836,7 @@ func (i *Istio) GetPodsByService(ctx context.Context, namespace, serviceName str
 			}
 
 			istioIcon := "🔗"
-			if hasIstio {
+			if mode != meshModeNone {
 				istioIcon = "🕸️"
 			}
 
@@ -632,11 +852,17 @@ func (i *Istio) GetPodsByService(ctx context.Context, namespace, serviceName str
 			}
 			result += fmt.Sprintf("      Containers: %s\n", strings.Join(appContainers, ", "))
 
-			if hasIstio {
+			switch mode {
+			case meshModeSidecar:
 				result += fmt.Sprintf("      🕸️  Istio mesh: ENABLED\n")
-			} else {
+			case meshModeAmbient:
+				result += fmt.Sprintf("      🕸️  Istio mesh: AMBIENT (no sidecar; traffic handled by ztunnel)\n")
+			default:
 				result += fmt.Sprintf("      ⚠️  Istio mesh: NOT ENABLED\n")
 			}
+			if o.initContainers {
+				result += describeInitContainers(pod, "      ")
+			}
 			result += "\n"
 		}
 	}
@@ -645,7 +871,14 @@ func (i *Istio) GetPodsByService(ctx context.Context, namespace, serviceName str
 	if len(nonRunningPods) > 0 {
 		result += fmt.Sprintf("⏳ Non-running pods (%d):\n", len(nonRunningPods))
 		for _, pod := range nonRunningPods {
-			result += fmt.Sprintf("   ❌ %s (Status: %s)\n", pod.Name, pod.Status.Phase)
+			status := string(pod.Status.Phase)
+			if reason := podInitFailure(pod); reason != "" {
+				status = "Init:" + reason
+			}
+			result += fmt.Sprintf("   ❌ %s (Status: %s)\n", pod.Name, status)
+			if o.initContainers {
+				result += describeInitContainers(pod, "      ")
+			}
 		}
 		result += "\n"
 	}
@@ -656,7 +889,7 @@ func (i *Istio) GetPodsByService(ctx context.Context, namespace, serviceName str
 		result += "   - The deployment is scaled to 0 replicas\n"
 		result += "   - Pods are failing to start\n"
 		result += "   - Label selector mismatch between service and pods\n\n"
-		return result, nil
+		return result
 	}
 
 	// Add helpful next steps
@@ -669,7 +902,7 @@ func (i *Istio) GetPodsByService(ctx context.Context, namespace, serviceName str
 		result += fmt.Sprintf("   get-proxy-routes --namespace %s --pod %s\n", namespace, examplePod)
 	}
 
-	return result, nil
+	return result
 }
 
 // Helper function to check if pod is ready (already exists but ensuring it's here)
@@ -682,67 +915,88 @@ func isPodReady(pod v1.Pod) bool {
 	return false
 }
 
-// DiscoverNamespacesWithSidecars finds namespaces that have pods with Istio sidecars
-// and returns them sorted by the number of sidecars (most injected first)
-func (i *Istio) DiscoverNamespacesWithSidecars(ctx context.Context) (string, error) {
-	namespacesWithSidecars := make(map[string]int)
+// namespaceCount is the number of sidecar and ambient mesh workloads of a namespace
+type namespaceCount struct {
+	namespace string
+	sidecars  int
+	ambient   int
+}
+
+// countMeshWorkloadsByNamespace counts the running pods enrolled in the mesh per namespace, sorted by the number
+// of mesh workloads (most enrolled first) and then by namespace name
+func (i *Istio) countMeshWorkloadsByNamespace(ctx context.Context) ([]namespaceCount, error) {
+	counts := make(map[string]*namespaceCount)
 
 	// Get running pods only (server-side filtering)
 	pods, err := i.kubeClient.CoreV1().Pods("").List(ctx, metav1.ListOptions{
 		FieldSelector: "status.phase=Running",
 	})
 	if err != nil {
-		return "", fmt.Errorf("failed to list running pods for Istio sidecar discovery: %w", err)
+		return nil, fmt.Errorf("failed to list running pods for Istio sidecar discovery: %w", explainForbidden(err, "list", "pods", ""))
 	}
+	namespaceLabels := i.allNamespaceLabels(ctx)
 
-	// Count sidecars per namespace
+	// Count sidecar and ambient workloads per namespace
 	for _, pod := range pods.Items {
 		// Skip pods that are not running or have no containers
 		if pod.Status.Phase != "Running" || len(pod.Spec.Containers) == 0 {
 			continue
 		}
 
-		// Check if pod has istio-proxy sidecar
-		for _, container := range pod.Spec.Containers {
-			if container.Name == "istio-proxy" {
-				namespacesWithSidecars[pod.Namespace]++
-				break
-			}
+		mode := podMeshMode(pod, namespaceLabels[pod.Namespace])
+		if mode == meshModeNone {
+			continue
+		}
+		count, ok := counts[pod.Namespace]
+		if !ok {
+			count = &namespaceCount{namespace: pod.Namespace}
+			counts[pod.Namespace] = count
+		}
+		if mode == meshModeSidecar {
+			count.sidecars++
+		} else {
+			count.ambient++
 		}
-	}
-
-	if len(namespacesWithSidecars) == 0 {
-		return "No namespaces with Istio sidecars found", nil
-	}
-
-	// Create a slice of namespace counts for sorting
-	type namespaceCount struct {
-		namespace string
-		count     int
 	}
 
 	var namespaceCounts []namespaceCount
-	for ns, count := range namespacesWithSidecars {
-		namespaceCounts = append(namespaceCounts, namespaceCount{namespace: ns, count: count})
+	for _, count := range counts {
+		namespaceCounts = append(namespaceCounts, *count)
 	}
 
-	// Sort by count (descending) and then by namespace name (ascending)
+	// Sort by mesh workload count (descending) and then by namespace name (ascending)
 	sort.Slice(namespaceCounts, func(i, j int) bool {
-		if namespaceCounts[i].count != namespaceCounts[j].count {
-			return namespaceCounts[i].count > namespaceCounts[j].count
+		totalI := namespaceCounts[i].sidecars + namespaceCounts[i].ambient
+		totalJ := namespaceCounts[j].sidecars + namespaceCounts[j].ambient
+		if totalI != totalJ {
+			return totalI > totalJ
 		}
 		return namespaceCounts[i].namespace < namespaceCounts[j].namespace
 	})
+	return namespaceCounts, nil
+}
+
+// DiscoverNamespacesWithSidecars finds namespaces that have pods enrolled in the mesh, either with Istio sidecars
+// or through ambient mode, and returns them sorted by the number of mesh workloads (most enrolled first)
+func (i *Istio) DiscoverNamespacesWithSidecars(ctx context.Context) (string, error) {
+	namespaceCounts, err := i.countMeshWorkloadsByNamespace(ctx)
+	if err != nil {
+		return "", err
+	}
+	if len(namespaceCounts) == 0 {
+		return "No namespaces with Istio sidecars or ambient-enrolled workloads found", nil
+	}
 
 	// Build result string
-	result := fmt.Sprintf("Found %d namespaces with Istio sidecars:\n\n", len(namespaceCounts))
-	result += "Rank | Namespace | Sidecar Count | Recommendation\n"
-	result += "-----|-----------|---------------|----------------\n"
+	result := fmt.Sprintf("Found %d namespaces with Istio workloads:\n\n", len(namespaceCounts))
+	result += "Rank | Namespace | Sidecar Count | Ambient Count | Recommendation\n"
+	result += "-----|-----------|---------------|---------------|----------------\n"
 
+	ambientWorkloads := 0
 	for rank, nc := range namespaceCounts {
 		var recommendation string
 		if rank == 0 {
-			recommendation = "BEST - Most Istio-injected workloads"
+			recommendation = "BEST - Most Istio-enrolled workloads"
 		} else if rank < 3 { // 3 is arbitrary, adjust as needed
 			recommendation = "Good - High Istio adoption"
 		} else if rank < 5 {
@@ -751,14 +1005,42 @@ func (i *Istio) DiscoverNamespacesWithSidecars(ctx context.Context) (string, err
 			recommendation = "Low - Minimal Istio usage"
 		}
 
-		result += fmt.Sprintf("%4d | %-9s | %13d | %s\n", rank+1, nc.namespace, nc.count, recommendation)
+		result += fmt.Sprintf("%4d | %-9s | %13d | %13d | %s\n", rank+1, nc.namespace, nc.sidecars, nc.ambient, recommendation)
+		ambientWorkloads += nc.ambient
 	}
 
-	result += "\n💡 **Recommendation**: Start with the top-ranked namespace for Istio operations as it likely contains the most Istio configuration and traffic."
+	if ambientWorkloads > 0 {
+		result += fmt.Sprintf("\n%d workloads are enrolled in ambient mode: they have no sidecar, so proxy-config tools do not apply to them; their traffic is handled by ztunnel and waypoint proxies.\n", ambientWorkloads)
+	}
+	result += "\nRecommendation: Start with the top-ranked namespace for Istio operations as it likely contains the most Istio configuration and traffic.\n"
 
 	return result, nil
 }
 
+// DiscoverNamespacesWithSidecarsJSON returns the namespaces found by DiscoverNamespacesWithSidecars as a JSON array of
+// {namespace, sidecarCount, ambientCount, rank}, in the same order
+func (i *Istio) DiscoverNamespacesWithSidecarsJSON(ctx context.Context) (string, error) {
+	namespaceCounts, err := i.countMeshWorkloadsByNamespace(ctx)
+	if err != nil {
+		return "", err
+	}
+	type rankedNamespace struct {
+		Namespace    string `json:"namespace"`
+		SidecarCount int    `json:"sidecarCount"`
+		AmbientCount int    `json:"ambientCount"`
+		Rank         int    `json:"rank"`
+	}
+	ranked := make([]rankedNamespace, 0, len(namespaceCounts))
+	for rank, nc := range namespaceCounts {
+		ranked = append(ranked, rankedNamespace{Namespace: nc.namespace, SidecarCount: nc.sidecars, AmbientCount: nc.ambient, Rank: rank + 1})
+	}
+	data, err := json.MarshalIndent(ranked, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode namespaces: %w", err)
+	}
+	return string(data), nil
+}
+
 func (i *Istio) WatchKubeConfig(onKubeConfigChange func() error) {
 	if i.clientCmdConfig == nil {
 		klog.V(1).Info("No client config available for kubeconfig watching")
@@ -363,3 +363,30 @@ func extractNamespaceFromPath(path string) string {
 	}
 	return "default"
 }
+
+// TestBuildConfigClientOptions tests that the rate limit and user agent are applied to the client configuration
+func TestBuildConfigClientOptions(t *testing.T) {
+	kubeconfigPath := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(kubeconfigPath, []byte(createTestKubeconfigForVS("https://127.0.0.1:6443")), 0644); err != nil {
+		t.Fatalf("Failed to write kubeconfig: %v", err)
+	}
+
+	config, _, err := buildConfig(kubeconfigPath, WithRateLimit(50, 100))
+	if err != nil {
+		t.Fatalf("buildConfig failed: %v", err)
+	}
+	if config.QPS != 50 || config.Burst != 100 {
+		t.Errorf("Expected QPS 50 and burst 100, got %v and %d", config.QPS, config.Burst)
+	}
+	if !strings.HasPrefix(config.UserAgent, "istio-mcp-server/") {
+		t.Errorf("Expected the istio-mcp-server user agent, got %q", config.UserAgent)
+	}
+
+	config, _, err = buildConfig(kubeconfigPath)
+	if err != nil {
+		t.Fatalf("buildConfig failed: %v", err)
+	}
+	if config.QPS != 0 || config.Burst != 0 {
+		t.Errorf("Expected the client-go default rate limit without options, got %v and %d", config.QPS, config.Burst)
+	}
+}
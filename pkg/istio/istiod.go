@@ -0,0 +1,328 @@
+package istio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// istiodLabelSelector selects the istiod control plane pods
+	istiodLabelSelector = "app=istiod"
+	// istiodMonitoringPort serves istiod's debug endpoints and metrics
+	istiodMonitoringPort = "15014"
+	// istiodContainer is the name of the istiod container in the istiod pods
+	istiodContainer = "discovery"
+	// istiodLogTailLines is how many recent log lines of each istiod replica are searched
+	istiodLogTailLines = 5000
+)
+
+// istiodPods returns the running istiod replicas
+func (i *Istio) istiodPods(ctx context.Context) ([]v1.Pod, error) {
+	pods, err := i.kubeClient.CoreV1().Pods(istioSystemNamespace).List(ctx, metav1.ListOptions{LabelSelector: istiodLabelSelector})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list istiod pods: %w", explainForbidden(err, "list", "pods", istioSystemNamespace))
+	}
+	var running []v1.Pod
+	for _, pod := range pods.Items {
+		if pod.Status.Phase == v1.PodRunning {
+			running = append(running, pod)
+		}
+	}
+	return running, nil
+}
+
+// istiodDebug reads a path of istiod's monitoring port through the Kubernetes API server pod proxy
+func (i *Istio) istiodDebug(ctx context.Context, pod, path string) ([]byte, error) {
+	data, err := i.kubeClient.CoreV1().Pods(istioSystemNamespace).ProxyGet("http", pod, istiodMonitoringPort, path, nil).DoRaw(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s from istiod pod %s: %w", path, pod, explainForbidden(err, "get", "pods/proxy", istioSystemNamespace))
+	}
+	return data, nil
+}
+
+// parseMetrics sums the samples of a Prometheus text exposition by metric name, and additionally by
+// the value of groupLabel for metrics that carry it (e.g. pilot_xds_pushes by 'type')
+func parseMetrics(data, groupLabel string) (totals map[string]float64, grouped map[string]map[string]float64) {
+	totals = make(map[string]float64)
+	grouped = make(map[string]map[string]float64)
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		value, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			continue
+		}
+		name, labelSet, _ := strings.Cut(fields[0], "{")
+		totals[name] += value
+		for _, label := range strings.Split(strings.TrimSuffix(labelSet, "}"), ",") {
+			key, labelValue, found := strings.Cut(label, "=")
+			if found && key == groupLabel {
+				if grouped[name] == nil {
+					grouped[name] = make(map[string]float64)
+				}
+				grouped[name][strings.Trim(labelValue, `"`)] += value
+			}
+		}
+	}
+	return totals, grouped
+}
+
+// GetPushStats reports xDS push statistics of every istiod replica: connected proxies, pushes by type,
+// push errors, stale acknowledgements from lagging proxies, and the issues recorded in istiod's push status
+func (i *Istio) GetPushStats(ctx context.Context) (string, error) {
+	pods, err := i.istiodPods(ctx)
+	if err != nil {
+		return "", err
+	}
+	if len(pods) == 0 {
+		return fmt.Sprintf("No running istiod pods found in namespace '%s'\n", istioSystemNamespace), nil
+	}
+
+	result := fmt.Sprintf("xDS push statistics of %d istiod replicas:\n", len(pods))
+	warnings := 0
+	for _, pod := range pods {
+		result += fmt.Sprintf("\nistiod '%s' (%s):\n", pod.Name, pod.Status.PodIP)
+
+		metrics, err := i.istiodDebug(ctx, pod.Name, "/metrics")
+		if err != nil {
+			result += fmt.Sprintf("  [ERROR] %v\n", err)
+			warnings++
+			continue
+		}
+		totals, byType := parseMetrics(string(metrics), "type")
+
+		result += fmt.Sprintf("  Connected proxies: %.0f\n", totals["pilot_xds"])
+		types := make([]string, 0, len(byType["pilot_xds_pushes"]))
+		for pushType := range byType["pilot_xds_pushes"] {
+			types = append(types, pushType)
+		}
+		sort.Strings(types)
+		var pushes []string
+		for _, pushType := range types {
+			pushes = append(pushes, fmt.Sprintf("%s=%.0f", pushType, byType["pilot_xds_pushes"][pushType]))
+		}
+		result += fmt.Sprintf("  Pushes: %.0f (%s)\n", totals["pilot_xds_pushes"], strings.Join(pushes, ", "))
+
+		pushErrors := map[string]float64{
+			"rejected by proxies": totals["pilot_total_xds_rejects"],
+			"internal errors":     totals["pilot_total_xds_internal_errors"],
+			"send timeouts":       totals["pilot_xds_write_timeout"],
+			"push context errors": totals["pilot_xds_push_context_errors"],
+		}
+		names := make([]string, 0, len(pushErrors))
+		for name := range pushErrors {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		var failed []string
+		for _, name := range names {
+			if pushErrors[name] > 0 {
+				failed = append(failed, fmt.Sprintf("%s=%.0f", name, pushErrors[name]))
+			}
+		}
+		if len(failed) > 0 {
+			result += fmt.Sprintf("  [WARNING] Push errors: %s\n", strings.Join(failed, ", "))
+			warnings++
+		} else {
+			result += "  [OK] No push errors\n"
+		}
+		if expired := totals["pilot_xds_expired_nonce"]; expired > 0 {
+			result += fmt.Sprintf("  [WARNING] Lagging proxies: %.0f acknowledgements of outdated pushes (expired nonces)\n", expired)
+			warnings++
+		}
+
+		status, err := i.istiodDebug(ctx, pod.Name, "/debug/push_status")
+		if err != nil {
+			result += fmt.Sprintf("  [ERROR] %v\n", err)
+			warnings++
+			continue
+		}
+		// ProxyStatus maps a metric (e.g. pilot_eds_no_instances) to the affected keys (clusters, listeners or proxies)
+		var pushStatus struct {
+			ProxyStatus map[string]map[string]json.RawMessage `json:"ProxyStatus"`
+		}
+		if err := json.Unmarshal(status, &pushStatus); err != nil {
+			result += fmt.Sprintf("  [ERROR] failed to parse the push status: %v\n", err)
+			warnings++
+			continue
+		}
+		metricNames := make([]string, 0, len(pushStatus.ProxyStatus))
+		for metric := range pushStatus.ProxyStatus {
+			metricNames = append(metricNames, metric)
+		}
+		sort.Strings(metricNames)
+		if len(metricNames) > 0 {
+			result += "  Push status issues:\n"
+			for _, metric := range metricNames {
+				keys := make([]string, 0, len(pushStatus.ProxyStatus[metric]))
+				for key := range pushStatus.ProxyStatus[metric] {
+					keys = append(keys, key)
+				}
+				sort.Strings(keys)
+				result += fmt.Sprintf("  - %s: %d (%s)\n", metric, len(keys), strings.Join(keys, ", "))
+			}
+		}
+	}
+
+	if warnings == 0 {
+		result += "\n[OK] All istiod replicas push configuration without errors\n"
+	} else {
+		result += fmt.Sprintf("\n[RESULT] %d warnings across %d istiod replicas\n", warnings, len(pods))
+	}
+	return result, nil
+}
+
+// istiodConnections is the subset of istiod's /debug/connections listing the connected xDS clients
+type istiodConnections struct {
+	Clients []struct {
+		// ConnectionID is '<pod>.<namespace>-<counter>'
+		ConnectionID string `json:"connectionId"`
+	} `json:"clients"`
+}
+
+// connectionNamespace returns the namespace of the proxy holding an xDS connection, from its connection ID
+func connectionNamespace(connectionID string) string {
+	proxyID := connectionID
+	if idx := strings.LastIndex(connectionID, "-"); idx >= 0 {
+		if _, err := strconv.Atoi(connectionID[idx+1:]); err == nil {
+			proxyID = connectionID[:idx]
+		}
+	}
+	if idx := strings.LastIndex(proxyID, "."); idx >= 0 {
+		return proxyID[idx+1:]
+	}
+	return "unknown"
+}
+
+// GetIstiodConnectionDistribution reports how many proxies each istiod replica serves, by namespace, from the
+// replicas' /debug/connections, and flags replicas serving far more than their share
+func (i *Istio) GetIstiodConnectionDistribution(ctx context.Context) (string, error) {
+	pods, err := i.istiodPods(ctx)
+	if err != nil {
+		return "", err
+	}
+	if len(pods) == 0 {
+		return fmt.Sprintf("No running istiod pods found in namespace '%s'\n", istioSystemNamespace), nil
+	}
+
+	counts := make(map[string]int, len(pods))
+	byNamespace := make(map[string]map[string]int, len(pods))
+	var unreadable []string
+	total := 0
+	for _, pod := range pods {
+		data, err := i.istiodDebug(ctx, pod.Name, "/debug/connections")
+		if err != nil {
+			unreadable = append(unreadable, pod.Name)
+			continue
+		}
+		var connections istiodConnections
+		if err := json.Unmarshal(data, &connections); err != nil {
+			return "", fmt.Errorf("failed to parse connections of istiod pod %s: %w", pod.Name, err)
+		}
+		byNamespace[pod.Name] = make(map[string]int)
+		for _, client := range connections.Clients {
+			byNamespace[pod.Name][connectionNamespace(client.ConnectionID)]++
+		}
+		counts[pod.Name] = len(connections.Clients)
+		total += len(connections.Clients)
+	}
+
+	result := fmt.Sprintf("Proxy connections across %d istiod replicas (%d proxies):\n\n", len(pods), total)
+	readable := len(pods) - len(unreadable)
+	imbalanced := 0
+	for _, pod := range pods {
+		if _, ok := byNamespace[pod.Name]; !ok {
+			result += fmt.Sprintf("- %s: [ERROR] could not read /debug/connections\n", pod.Name)
+			continue
+		}
+		share := 0.0
+		if total > 0 {
+			share = float64(counts[pod.Name]) / float64(total) * 100
+		}
+		namespaces := make([]string, 0, len(byNamespace[pod.Name]))
+		for ns := range byNamespace[pod.Name] {
+			namespaces = append(namespaces, ns)
+		}
+		sort.Strings(namespaces)
+		var breakdown []string
+		for _, ns := range namespaces {
+			breakdown = append(breakdown, fmt.Sprintf("%s=%d", ns, byNamespace[pod.Name][ns]))
+		}
+		result += fmt.Sprintf("- %s: %d proxies (%.0f%%)", pod.Name, counts[pod.Name], share)
+		if len(breakdown) > 0 {
+			result += fmt.Sprintf(" [%s]", strings.Join(breakdown, ", "))
+		}
+		// A replica serving more than 1.5 times the even share holds most of the xDS memory
+		if readable > 1 && float64(counts[pod.Name]) > 1.5*float64(total)/float64(readable) {
+			result += " [WARNING] above its share"
+			imbalanced++
+		}
+		result += "\n"
+	}
+
+	switch {
+	case len(unreadable) > 0:
+		result += fmt.Sprintf("\n[WARNING] Could not read the connections of %s\n", strings.Join(unreadable, ", "))
+	case imbalanced > 0:
+		result += "\n[RESULT] Proxies are unevenly distributed; they only move to another replica when istiod closes their connection after PILOT_KEEPALIVE_MAX_SERVER_CONNECTION_AGE (30m by default), so this is common shortly after istiod scales up\n"
+	default:
+		result += "\n[OK] Proxies are evenly distributed across the istiod replicas\n"
+	}
+	return result, nil
+}
+
+// GetIstiodLogsForProxy searches the recent logs of every istiod replica for lines mentioning a proxy,
+// e.g. 'productpage-v1-6b746f74dc-9stvs.default', which also matches its xDS connection IDs
+func (i *Istio) GetIstiodLogsForProxy(ctx context.Context, proxyID string) (string, error) {
+	pods, err := i.istiodPods(ctx)
+	if err != nil {
+		return "", err
+	}
+	if len(pods) == 0 {
+		return fmt.Sprintf("No running istiod pods found in namespace '%s'\n", istioSystemNamespace), nil
+	}
+
+	result := fmt.Sprintf("istiod logs mentioning proxy '%s' (last %d lines of %d replicas):\n", proxyID, istiodLogTailLines, len(pods))
+	total := 0
+	for _, pod := range pods {
+		tailLines := int64(istiodLogTailLines)
+		logs, err := i.kubeClient.CoreV1().Pods(istioSystemNamespace).GetLogs(pod.Name, &v1.PodLogOptions{
+			Container: istiodContainer,
+			TailLines: &tailLines,
+		}).DoRaw(ctx)
+		if err != nil {
+			result += fmt.Sprintf("\n[ERROR] failed to read logs of istiod pod %s: %v\n", pod.Name, explainForbidden(err, "get", "pods/log", istioSystemNamespace))
+			continue
+		}
+
+		var matching []string
+		for _, line := range strings.Split(string(logs), "\n") {
+			if strings.Contains(line, proxyID) {
+				matching = append(matching, line)
+			}
+		}
+		total += len(matching)
+		result += fmt.Sprintf("\n%s: %d matching lines\n", pod.Name, len(matching))
+		for _, line := range matching {
+			result += line + "\n"
+		}
+	}
+
+	if total == 0 {
+		result += "\nNo log lines mention this proxy; it may not be connected, or its events are older than the searched lines\n"
+	}
+	return result, nil
+}
@@ -0,0 +1,161 @@
+package istio
+
+import (
+	"context"
+	"testing"
+)
+
+// TestGetPushStats tests reading push statistics from the debug endpoints of each istiod replica
+func TestGetPushStats(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{
+		"/api/v1/namespaces/istio-system/pods": `{
+			"apiVersion": "v1",
+			"kind": "PodList",
+			"items": [
+				{"metadata": {"name": "istiod-7c9f-a", "namespace": "istio-system", "labels": {"app": "istiod"}}, "status": {"phase": "Running", "podIP": "10.0.0.5"}},
+				{"metadata": {"name": "istiod-7c9f-b", "namespace": "istio-system", "labels": {"app": "istiod"}}, "status": {"phase": "Running", "podIP": "10.0.0.6"}}
+			]
+		}`,
+		"/api/v1/namespaces/istio-system/pods/http:istiod-7c9f-a:15014/proxy/metrics": `# HELP pilot_xds Number of endpoints connected to this pilot using XDS.
+# TYPE pilot_xds gauge
+pilot_xds{version="1.25.1"} 12
+pilot_xds_pushes{type="cds"} 40
+pilot_xds_pushes{type="eds"} 310
+pilot_xds_pushes{type="lds"} 40
+pilot_total_xds_rejects{type="lds"} 2
+pilot_xds_expired_nonce{type="eds"} 5
+`,
+		"/api/v1/namespaces/istio-system/pods/http:istiod-7c9f-a:15014/proxy/debug/push_status": `{
+			"ProxyStatus": {
+				"pilot_eds_no_instances": {"outbound|80||legacy.shop.svc.cluster.local": {}}
+			}
+		}`,
+		"/api/v1/namespaces/istio-system/pods/http:istiod-7c9f-b:15014/proxy/metrics": `pilot_xds{version="1.25.1"} 8
+pilot_xds_pushes{type="cds"} 16
+`,
+		"/api/v1/namespaces/istio-system/pods/http:istiod-7c9f-b:15014/proxy/debug/push_status": `{}`,
+	})
+	defer mockServer.Close()
+
+	istio := newTestIstio(t, mockServer.URL)
+
+	result, err := istio.GetPushStats(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to get push stats: %v", err)
+	}
+	assertContains(t, result,
+		"xDS push statistics of 2 istiod replicas",
+		"istiod 'istiod-7c9f-a' (10.0.0.5):\n  Connected proxies: 12\n  Pushes: 390 (cds=40, eds=310, lds=40)",
+		"[WARNING] Push errors: rejected by proxies=2",
+		"[WARNING] Lagging proxies: 5 acknowledgements of outdated pushes",
+		"- pilot_eds_no_instances: 1 (outbound|80||legacy.shop.svc.cluster.local)",
+		"istiod 'istiod-7c9f-b' (10.0.0.6):\n  Connected proxies: 8\n  Pushes: 16 (cds=16)\n  [OK] No push errors",
+		"[RESULT] 2 warnings across 2 istiod replicas",
+	)
+}
+
+// TestGetPushStatsInvalidPushStatus tests that a push status that cannot be parsed is reported for its replica
+// without failing the other replicas
+func TestGetPushStatsInvalidPushStatus(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{
+		"/api/v1/namespaces/istio-system/pods": `{
+			"apiVersion": "v1",
+			"kind": "PodList",
+			"items": [
+				{"metadata": {"name": "istiod-7c9f-a", "namespace": "istio-system", "labels": {"app": "istiod"}}, "status": {"phase": "Running", "podIP": "10.0.0.5"}},
+				{"metadata": {"name": "istiod-7c9f-b", "namespace": "istio-system", "labels": {"app": "istiod"}}, "status": {"phase": "Running", "podIP": "10.0.0.6"}}
+			]
+		}`,
+		"/api/v1/namespaces/istio-system/pods/http:istiod-7c9f-a:15014/proxy/metrics":           `pilot_xds{version="1.25.1"} 12`,
+		"/api/v1/namespaces/istio-system/pods/http:istiod-7c9f-a:15014/proxy/debug/push_status": `{"ProxyStatus": [`,
+		"/api/v1/namespaces/istio-system/pods/http:istiod-7c9f-b:15014/proxy/metrics":           `pilot_xds{version="1.25.1"} 8`,
+		"/api/v1/namespaces/istio-system/pods/http:istiod-7c9f-b:15014/proxy/debug/push_status": `{}`,
+	})
+	defer mockServer.Close()
+
+	istio := newTestIstio(t, mockServer.URL)
+
+	result, err := istio.GetPushStats(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to get push stats: %v", err)
+	}
+	assertContains(t, result,
+		"istiod 'istiod-7c9f-a' (10.0.0.5):\n  Connected proxies: 12",
+		"  [ERROR] failed to parse the push status:",
+		"istiod 'istiod-7c9f-b' (10.0.0.6):\n  Connected proxies: 8",
+		"[RESULT] 1 warnings across 2 istiod replicas",
+	)
+}
+
+// TestGetIstiodLogsForProxy tests that only log lines mentioning the proxy are returned, from every istiod replica
+func TestGetIstiodLogsForProxy(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{
+		"/api/v1/namespaces/istio-system/pods": `{
+			"apiVersion": "v1",
+			"kind": "PodList",
+			"items": [
+				{"metadata": {"name": "istiod-7c9f-a", "namespace": "istio-system"}, "status": {"phase": "Running"}},
+				{"metadata": {"name": "istiod-7c9f-b", "namespace": "istio-system"}, "status": {"phase": "Running"}}
+			]
+		}`,
+		"/api/v1/namespaces/istio-system/pods/istiod-7c9f-a/log": `2025-06-01T10:00:00Z info ads ADS: new connection for node:reviews-v1-5d8f.default-12
+2025-06-01T10:00:01Z info ads CDS: PUSH for node:productpage-v1-6b74.default resources:24 size:22.1kB
+2025-06-01T10:00:02Z warn ads ADS:LDS: ACK ERROR productpage-v1-6b74.default-7 Internal:Error adding/updating listener(s) 0.0.0.0_9080
+2025-06-01T10:00:03Z info ads EDS: PUSH for node:ratings-v1-8c4a.default resources:12 size:3.2kB
+`,
+		"/api/v1/namespaces/istio-system/pods/istiod-7c9f-b/log": `2025-06-01T10:00:00Z info ads ADS: new connection for node:details-v1-7f6c.default-3
+`,
+	})
+	defer mockServer.Close()
+
+	istio := newTestIstio(t, mockServer.URL)
+
+	result, err := istio.GetIstiodLogsForProxy(context.Background(), "productpage-v1-6b74.default")
+	if err != nil {
+		t.Fatalf("Failed to get istiod logs: %v", err)
+	}
+	assertContains(t, result,
+		"istiod-7c9f-a: 2 matching lines",
+		"CDS: PUSH for node:productpage-v1-6b74.default",
+		"ACK ERROR productpage-v1-6b74.default-7",
+		"istiod-7c9f-b: 0 matching lines",
+	)
+	assertNotContains(t, result, "reviews-v1", "ratings-v1", "details-v1")
+}
+
+// TestGetIstiodConnectionDistribution tests counting of the proxies connected to each istiod replica
+func TestGetIstiodConnectionDistribution(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{
+		"/api/v1/namespaces/istio-system/pods": `{
+			"apiVersion": "v1",
+			"kind": "PodList",
+			"items": [
+				{"metadata": {"name": "istiod-7c9f-a", "namespace": "istio-system", "labels": {"app": "istiod"}}, "status": {"phase": "Running"}},
+				{"metadata": {"name": "istiod-7c9f-b", "namespace": "istio-system", "labels": {"app": "istiod"}}, "status": {"phase": "Running"}}
+			]
+		}`,
+		"/api/v1/namespaces/istio-system/pods/http:istiod-7c9f-a:15014/proxy/debug/connections": `{
+			"totalClients": 4,
+			"clients": [
+				{"connectionId": "productpage-v1-6b74.bookinfo-12", "connectedAt": "2025-06-01T10:00:00Z", "address": "10.0.1.4:40512"},
+				{"connectionId": "reviews-v1-5d8f.bookinfo-3", "connectedAt": "2025-06-01T10:00:00Z", "address": "10.0.1.5:40512"},
+				{"connectionId": "reviews-v2-7a1c.bookinfo-4", "connectedAt": "2025-06-01T10:00:00Z", "address": "10.0.1.6:40512"},
+				{"connectionId": "istio-ingressgateway-84c9.istio-system-1", "connectedAt": "2025-06-01T10:00:00Z", "address": "10.0.1.7:40512"}
+			]
+		}`,
+		"/api/v1/namespaces/istio-system/pods/http:istiod-7c9f-b:15014/proxy/debug/connections": `{"totalClients": 0, "clients": []}`,
+	})
+	defer mockServer.Close()
+	istio := newTestIstio(t, mockServer.URL)
+
+	result, err := istio.GetIstiodConnectionDistribution(context.Background())
+	if err != nil {
+		t.Fatalf("GetIstiodConnectionDistribution failed: %v", err)
+	}
+	assertContains(t, result,
+		"Proxy connections across 2 istiod replicas (4 proxies)",
+		"- istiod-7c9f-a: 4 proxies (100%) [bookinfo=3, istio-system=1] [WARNING] above its share",
+		"- istiod-7c9f-b: 0 proxies (0%)\n",
+		"[RESULT] Proxies are unevenly distributed",
+	)
+}
@@ -0,0 +1,150 @@
+package istio
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// segmentKind tells how a JSON path segment selects a value
+type segmentKind int
+
+const (
+	keySegment   segmentKind = iota // object key
+	indexSegment                    // array index
+	nameSegment                     // array element by its "name" field
+)
+
+// pathSegment is a single step of a JSON path
+type pathSegment struct {
+	kind  segmentKind
+	key   string
+	index int
+	name  string
+}
+
+// parseJSONPath splits a dot/bracket path like "configs.dynamic_listeners[0].active_state"
+// or `dynamic_listeners["0.0.0.0_8080"]` into segments
+func parseJSONPath(path string) ([]pathSegment, error) {
+	var segments []pathSegment
+	rest := strings.TrimPrefix(strings.TrimSpace(path), ".")
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+		case '[':
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("invalid path '%s': unclosed '['", path)
+			}
+			selector := rest[1:end]
+			rest = rest[end+1:]
+			if unquoted, err := strconv.Unquote(selector); err == nil {
+				segments = append(segments, pathSegment{name: unquoted, kind: nameSegment})
+			} else if index, err := strconv.Atoi(selector); err == nil {
+				segments = append(segments, pathSegment{index: index, kind: indexSegment})
+			} else if selector != "" {
+				segments = append(segments, pathSegment{name: selector, kind: nameSegment})
+			} else {
+				return nil, fmt.Errorf("invalid path '%s': empty '[]'", path)
+			}
+		default:
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			segments = append(segments, pathSegment{key: rest[:end], kind: keySegment})
+			rest = rest[end:]
+		}
+	}
+	return segments, nil
+}
+
+// extractJSONPath evaluates a path over decoded JSON. Besides plain keys and indices it supports:
+//   - selecting an array element by its "name" field, or the "name" of an object nested one level down
+//     (Envoy wraps clusters as {"cluster": {"name": ...}}), e.g. dynamic_listeners[virtualInbound]
+//   - applying a key to an array, which picks the first element containing that key; this lets
+//     "configs.dynamic_listeners" reach into Envoy's list of typed config dumps
+func extractJSONPath(data interface{}, path string) (interface{}, error) {
+	segments, err := parseJSONPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	current := data
+	traversed := ""
+	for _, segment := range segments {
+		switch segment.kind {
+		case keySegment:
+			traversed = strings.TrimPrefix(traversed+"."+segment.key, ".")
+			value, ok := lookupKey(current, segment.key)
+			if !ok {
+				return nil, fmt.Errorf("path '%s' not found", traversed)
+			}
+			current = value
+		case indexSegment:
+			traversed += fmt.Sprintf("[%d]", segment.index)
+			array, ok := current.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("path '%s' is not an array", traversed)
+			}
+			if segment.index < 0 || segment.index >= len(array) {
+				return nil, fmt.Errorf("path '%s' is out of range (length %d)", traversed, len(array))
+			}
+			current = array[segment.index]
+		case nameSegment:
+			traversed += fmt.Sprintf("[%s]", segment.name)
+			array, ok := current.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("path '%s' is not an array", traversed)
+			}
+			found := false
+			for _, element := range array {
+				if hasName(element, segment.name) {
+					current = element
+					found = true
+					break
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("path '%s' not found: no element named '%s'", traversed, segment.name)
+			}
+		}
+	}
+	return current, nil
+}
+
+// lookupKey reads a key from an object, or from the first element of an array that has it
+func lookupKey(data interface{}, key string) (interface{}, bool) {
+	switch value := data.(type) {
+	case map[string]interface{}:
+		v, ok := value[key]
+		return v, ok
+	case []interface{}:
+		for _, element := range value {
+			if object, ok := element.(map[string]interface{}); ok {
+				if v, ok := object[key]; ok {
+					return v, true
+				}
+			}
+		}
+	}
+	return nil, false
+}
+
+// hasName reports whether an array element is named name, either directly or through one of its child objects
+func hasName(element interface{}, name string) bool {
+	object, ok := element.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	if object["name"] == name {
+		return true
+	}
+	for _, child := range object {
+		if childObject, ok := child.(map[string]interface{}); ok && childObject["name"] == name {
+			return true
+		}
+	}
+	return false
+}
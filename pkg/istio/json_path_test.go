@@ -0,0 +1,176 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// sampleConfigDump is a trimmed Envoy config dump as returned by 'istioctl proxy-config all -o json'
+const sampleConfigDump = `{
+	"configs": [
+		{
+			"@type": "type.googleapis.com/envoy.admin.v3.ClustersConfigDump",
+			"dynamic_active_clusters": [
+				{"version_info": "2025-01-01T00:00:00Z/1", "cluster": {"name": "outbound|9080||reviews.default.svc.cluster.local", "type": "EDS"}},
+				{"version_info": "2025-01-01T00:00:00Z/1", "cluster": {"name": "outbound|9080||ratings.default.svc.cluster.local", "type": "EDS"}}
+			]
+		},
+		{
+			"@type": "type.googleapis.com/envoy.admin.v3.ListenersConfigDump",
+			"dynamic_listeners": [
+				{"name": "virtualInbound", "active_state": {"listener": {"address": {"socket_address": {"address": "0.0.0.0", "port_value": 15006}}}}},
+				{"name": "0.0.0.0_9080", "active_state": {"listener": {"address": {"socket_address": {"address": "0.0.0.0", "port_value": 9080}}}}}
+			]
+		}
+	]
+}`
+
+// TestGetConfigDumpPath tests extracting a subtree of the Envoy config dump
+func TestGetConfigDumpPath(t *testing.T) {
+	client := NewProxyConfigClient("")
+	stubIstioctl(client, sampleConfigDump)
+	ctx := context.Background()
+
+	t.Run("nested path through typed configs", func(t *testing.T) {
+		result, err := client.GetConfigDumpPath(ctx, "default", "productpage-v1", "configs.dynamic_listeners[\"0.0.0.0_9080\"].active_state.listener.address.socket_address.port_value")
+		if err != nil {
+			t.Fatalf("Failed to extract path: %v", err)
+		}
+		if result != "9080" {
+			t.Errorf("Expected 9080, got: %s", result)
+		}
+	})
+
+	t.Run("select by nested name and index", func(t *testing.T) {
+		result, err := client.GetConfigDumpPath(ctx, "default", "productpage-v1", "configs.dynamic_active_clusters[outbound|9080||ratings.default.svc.cluster.local].cluster")
+		if err != nil {
+			t.Fatalf("Failed to extract path: %v", err)
+		}
+		assertContains(t, result, `"name": "outbound|9080||ratings.default.svc.cluster.local"`)
+		assertNotContains(t, result, "reviews")
+
+		result, err = client.GetConfigDumpPath(ctx, "default", "productpage-v1", "configs[1].dynamic_listeners[0].name")
+		if err != nil {
+			t.Fatalf("Failed to extract path: %v", err)
+		}
+		if result != `"virtualInbound"` {
+			t.Errorf("Expected virtualInbound, got: %s", result)
+		}
+	})
+
+	t.Run("missing path", func(t *testing.T) {
+		_, err := client.GetConfigDumpPath(ctx, "default", "productpage-v1", "configs.dynamic_listeners[missing]")
+		if err == nil {
+			t.Fatal("Expected error for missing path")
+		}
+		assertContains(t, err.Error(), "no element named 'missing'")
+
+		_, err = client.GetConfigDumpPath(ctx, "default", "productpage-v1", "configs[5]")
+		if err == nil {
+			t.Fatal("Expected error for out of range index")
+		}
+		assertContains(t, err.Error(), "out of range")
+	})
+}
+
+// TestGetConfigDumpRaw tests that the raw config dump is returned exactly as printed by istioctl
+func TestGetConfigDumpRaw(t *testing.T) {
+	stdout := "{\n    \"configs\":[ {\"@type\": \"x\"} ]\n}\n\n"
+
+	t.Run("stubbed", func(t *testing.T) {
+		client := NewProxyConfigClient("")
+		stubIstioctl(client, stdout)
+		result, err := client.GetConfigDumpRaw(context.Background(), "default", "productpage-v1")
+		if err != nil {
+			t.Fatalf("GetConfigDumpRaw failed: %v", err)
+		}
+		if result != stdout {
+			t.Errorf("Expected output to equal istioctl stdout byte-for-byte, got %q", result)
+		}
+	})
+
+	t.Run("bypasses the cache", func(t *testing.T) {
+		client := NewProxyConfigClient("")
+		calls := countIstioctl(client, map[string]string{"proxy-config": stdout})
+		_, _ = client.GetConfigDump(context.Background(), "default", "productpage-v1")
+		for range 2 {
+			if _, err := client.GetConfigDumpRaw(context.Background(), "default", "productpage-v1"); err != nil {
+				t.Fatalf("GetConfigDumpRaw failed: %v", err)
+			}
+		}
+		if *calls != 3 {
+			t.Errorf("Expected istioctl to run on every raw call, got %d calls", *calls)
+		}
+	})
+
+	t.Run("stderr is not mixed in", func(t *testing.T) {
+		dir := t.TempDir()
+		script := "#!/bin/sh\nprintf '%s' '" + stdout + "'\necho 'Warning: istioctl is out of date' >&2\n"
+		if err := os.WriteFile(filepath.Join(dir, "istioctl"), []byte(script), 0755); err != nil {
+			t.Fatalf("Failed to write fake istioctl: %v", err)
+		}
+		t.Setenv("PATH", dir)
+
+		client := NewProxyConfigClient("")
+		result, err := client.GetConfigDumpRaw(context.Background(), "default", "productpage-v1")
+		if err != nil {
+			t.Fatalf("GetConfigDumpRaw failed: %v", err)
+		}
+		if result != stdout {
+			t.Errorf("Expected output to equal istioctl stdout byte-for-byte, got %q", result)
+		}
+	})
+}
+
+// TestGetConfigDumpLimited tests that an oversized config dump is summarized unless requested in full
+func TestGetConfigDumpLimited(t *testing.T) {
+	var clusters []string
+	for idx := 0; len(strings.Join(clusters, ",")) <= ConfigDumpSummaryThreshold; idx++ {
+		clusters = append(clusters, fmt.Sprintf(`{"cluster": {"name": "outbound|80||service-%d.default.svc.cluster.local", "padding": "%s"}}`, idx, strings.Repeat("x", 200)))
+	}
+	dump := `{"configs": [
+		{"@type": "type.googleapis.com/envoy.admin.v3.BootstrapConfigDump", "bootstrap": {"node": {"id": "sidecar~10.0.0.1~productpage-v1.default~default.svc.cluster.local"}}},
+		{"@type": "type.googleapis.com/envoy.admin.v3.ClustersConfigDump", "dynamic_active_clusters": [` + strings.Join(clusters, ",") + `]}
+	]}`
+
+	client := NewProxyConfigClient("")
+	stubIstioctl(client, dump)
+
+	result, err := client.GetConfigDumpLimited(context.Background(), "default", "productpage-v1")
+	if err != nil {
+		t.Fatalf("GetConfigDumpLimited failed: %v", err)
+	}
+	assertContains(t, result,
+		"showing a summary",
+		"Set full=true",
+		"BootstrapConfigDump:\n  node: sidecar~10.0.0.1~productpage-v1.default~default.svc.cluster.local",
+		fmt.Sprintf("ClustersConfigDump:\n  dynamic_active_clusters: %d\n", len(clusters)),
+		"outbound|80||service-0.default.svc.cluster.local",
+		fmt.Sprintf("... and %d more", len(clusters)-configDumpSummaryNames),
+	)
+	if len(result) > len(dump)/10 {
+		t.Errorf("Expected the summary to be much smaller than the dump, got %d bytes", len(result))
+	}
+
+	full, err := client.GetConfigDump(context.Background(), "default", "productpage-v1")
+	if err != nil {
+		t.Fatalf("GetConfigDump failed: %v", err)
+	}
+	if full != dump {
+		t.Error("Expected the full dump to be returned unchanged")
+	}
+
+	stubIstioctl(client, sampleConfigDump)
+	client.SetCacheTTL(0)
+	small, err := client.GetConfigDumpLimited(context.Background(), "default", "productpage-v1")
+	if err != nil {
+		t.Fatalf("GetConfigDumpLimited failed: %v", err)
+	}
+	if small != sampleConfigDump {
+		t.Error("Expected a small dump to be returned unchanged")
+	}
+}
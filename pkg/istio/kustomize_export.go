@@ -0,0 +1,148 @@
+package istio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// kustomizationFile is the name of the file kustomize reads a base from
+const kustomizationFile = "kustomization.yaml"
+
+// kustomization is the kustomization.yaml of an exported base
+type kustomization struct {
+	APIVersion string   `json:"apiVersion"`
+	Kind       string   `json:"kind"`
+	Namespace  string   `json:"namespace"`
+	Resources  []string `json:"resources"`
+}
+
+// SetExportRoot sets the directory exports may write under; exporting is disabled when empty
+func (i *Istio) SetExportRoot(root string) {
+	i.exportRoot = root
+}
+
+// exportDir resolves the directory an export writes to, given relative to the export root or as an absolute path,
+// and rejects any directory outside the root, including one reached through a symbolic link
+func (i *Istio) exportDir(dir string) (string, error) {
+	if i.exportRoot == "" {
+		return "", fmt.Errorf("exporting is disabled: start the server with --export-root to allow writing under a directory")
+	}
+	root, err := filepath.Abs(i.exportRoot)
+	if err == nil {
+		root, err = filepath.EvalSymlinks(root)
+	}
+	if err != nil {
+		return "", fmt.Errorf("invalid export root %s: %w", i.exportRoot, err)
+	}
+
+	target := dir
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(root, target)
+	}
+	// Resolve the symbolic links of the part of the path that already exists
+	existing, missing := filepath.Clean(target), ""
+	for {
+		resolved, err := filepath.EvalSymlinks(existing)
+		if err == nil {
+			target = filepath.Join(resolved, missing)
+			break
+		}
+		if !errors.Is(err, os.ErrNotExist) || filepath.Dir(existing) == existing {
+			return "", fmt.Errorf("failed to resolve directory %s: %w", dir, err)
+		}
+		missing = filepath.Join(filepath.Base(existing), missing)
+		existing = filepath.Dir(existing)
+	}
+
+	rel, err := filepath.Rel(root, target)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("directory %s is outside the export root %s", dir, i.exportRoot)
+	}
+	return target, nil
+}
+
+// ExportAsKustomize writes the Istio resources of a namespace to a directory as a kustomize base: one YAML file
+// per resource, named <kind>-<name>.yaml and stripped of status and server-populated metadata so it can be
+// re-applied (WithClean(false) writes the raw objects), plus a kustomization.yaml listing them. The directory must
+// be under the export root. Existing files are never overwritten: the export fails before writing anything when
+// one of the files already exists.
+func (i *Istio) ExportAsKustomize(ctx context.Context, namespace, dir string, opts ...GetOption) (string, error) {
+	o := newGetOptions(opts)
+	dir, err := i.exportDir(dir)
+	if err != nil {
+		return "", err
+	}
+	files := make(map[string]string)
+	var names []string
+	for _, kind := range SupportedResourceKinds() {
+		rk := resourceKinds[strings.ToLower(kind)]
+		resources, err := i.listResourcesByName(ctx, rk, namespace)
+		if err != nil {
+			return "", err
+		}
+		for _, obj := range resources {
+			// Typed clients don't populate TypeMeta, which the exported resources need to be applied
+			obj.GetObjectKind().SetGroupVersionKind(i.resourceGVK(rk))
+			content, err := resourceYAML(obj, o.clean)
+			if err != nil {
+				return "", err
+			}
+			name := fmt.Sprintf("%s-%s.yaml", strings.ToLower(kind), obj.GetName())
+			files[name] = content
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return fmt.Sprintf("No Istio resources found in namespace '%s'; nothing was written\n", namespace), nil
+	}
+	sort.Strings(names)
+
+	base, err := yaml.Marshal(kustomization{
+		APIVersion: "kustomize.config.k8s.io/v1beta1",
+		Kind:       "Kustomization",
+		Namespace:  namespace,
+		Resources:  names,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to format %s: %w", kustomizationFile, err)
+	}
+	files[kustomizationFile] = string(base)
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+	for name := range files {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return "", fmt.Errorf("%s already exists in %s; export to an empty directory", name, dir)
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("failed to check %s: %w", name, err)
+		}
+	}
+	for _, name := range append(names, kustomizationFile) {
+		file, err := os.OpenFile(filepath.Join(dir, name), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err != nil {
+			return "", fmt.Errorf("failed to create %s: %w", name, err)
+		}
+		_, err = file.WriteString(files[name])
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+
+	result := fmt.Sprintf("Exported %d Istio resources of namespace '%s' to %s as a kustomize base:\n\n", len(names), namespace, dir)
+	for _, name := range names {
+		result += fmt.Sprintf("- %s\n", name)
+	}
+	result += fmt.Sprintf("- %s\n\nBuild it with: kubectl kustomize %s\n", kustomizationFile, dir)
+	return result, nil
+}
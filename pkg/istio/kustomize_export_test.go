@@ -0,0 +1,109 @@
+package istio
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/yaml"
+)
+
+// TestExportAsKustomize tests that the kustomization.yaml of an export lists every written resource
+func TestExportAsKustomize(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{
+		"/apis/networking.istio.io/v1alpha3/namespaces/bookinfo/virtualservices": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "VirtualServiceList",
+			"items": [{"metadata": {"name": "reviews", "namespace": "bookinfo", "resourceVersion": "100", "uid": "abc"}, "spec": {"hosts": ["reviews"], "http": [{"route": [{"destination": {"host": "reviews"}}]}]}}]
+		}`,
+		"/apis/networking.istio.io/v1alpha3/namespaces/bookinfo/destinationrules": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "DestinationRuleList",
+			"items": [
+				{"metadata": {"name": "reviews", "namespace": "bookinfo"}, "spec": {"host": "reviews"}},
+				{"metadata": {"name": "ratings", "namespace": "bookinfo"}, "spec": {"host": "ratings"}}
+			]
+		}`,
+		"/apis/security.istio.io/v1beta1/namespaces/bookinfo/peerauthentications": `{
+			"apiVersion": "security.istio.io/v1beta1",
+			"kind": "PeerAuthenticationList",
+			"items": [{"metadata": {"name": "default", "namespace": "bookinfo"}, "spec": {"mtls": {"mode": "STRICT"}}}]
+		}`,
+	})
+	defer mockServer.Close()
+
+	istio := newTestIstio(t, mockServer.URL)
+	if _, err := istio.ExportAsKustomize(context.Background(), "bookinfo", "base"); err == nil || !strings.Contains(err.Error(), "exporting is disabled") {
+		t.Fatalf("Expected exporting without an export root to fail, got %v", err)
+	}
+	root := t.TempDir()
+	istio.SetExportRoot(root)
+	dir := filepath.Join(root, "base")
+
+	result, err := istio.ExportAsKustomize(context.Background(), "bookinfo", "base")
+	if err != nil {
+		t.Fatalf("ExportAsKustomize failed: %v", err)
+	}
+	assertContains(t, result, "Exported 4 Istio resources of namespace 'bookinfo'", "- kustomization.yaml")
+
+	data, err := os.ReadFile(filepath.Join(dir, "kustomization.yaml"))
+	if err != nil {
+		t.Fatalf("Failed to read kustomization.yaml: %v", err)
+	}
+	var base kustomization
+	if err := yaml.Unmarshal(data, &base); err != nil {
+		t.Fatalf("Failed to decode kustomization.yaml: %v", err)
+	}
+	if base.Kind != "Kustomization" || base.Namespace != "bookinfo" {
+		t.Errorf("Unexpected kustomization header: %+v", base)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("Failed to read export directory: %v", err)
+	}
+	var written []string
+	for _, entry := range entries {
+		if entry.Name() != "kustomization.yaml" {
+			written = append(written, entry.Name())
+		}
+	}
+	if strings.Join(base.Resources, ",") != strings.Join(written, ",") {
+		t.Errorf("kustomization.yaml lists %v, written resources are %v", base.Resources, written)
+	}
+
+	resource, err := os.ReadFile(filepath.Join(dir, "virtualservice-reviews.yaml"))
+	if err != nil {
+		t.Fatalf("Failed to read exported resource: %v", err)
+	}
+	assertContains(t, string(resource), "apiVersion: networking.istio.io/v1alpha3", "kind: VirtualService", "namespace: bookinfo")
+	assertNotContains(t, string(resource), "resourceVersion", "uid")
+
+	if _, err := istio.ExportAsKustomize(context.Background(), "bookinfo", dir); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("Expected exporting over existing files to fail, got %v", err)
+	}
+}
+
+// TestExportDirConfinedToRoot tests that exports can't write outside the export root
+func TestExportDirConfinedToRoot(t *testing.T) {
+	istio := &Istio{}
+	root := t.TempDir()
+	outside := t.TempDir()
+	if err := os.Symlink(outside, filepath.Join(root, "link")); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+	istio.SetExportRoot(root)
+
+	for _, dir := range []string{"base", "nested/base", filepath.Join(root, "abs")} {
+		if _, err := istio.exportDir(dir); err != nil {
+			t.Errorf("Expected %s to be accepted, got %v", dir, err)
+		}
+	}
+	for _, dir := range []string{"..", "../escape", "nested/../../escape", outside, "/etc", "link", "link/base"} {
+		if _, err := istio.exportDir(dir); err == nil || !strings.Contains(err.Error(), "outside the export root") {
+			t.Errorf("Expected %s to be rejected, got %v", dir, err)
+		}
+	}
+}
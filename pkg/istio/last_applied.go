@@ -0,0 +1,121 @@
+package istio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// lastAppliedAnnotation holds the configuration last applied with kubectl apply
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// appliedFields holds the parts of a resource compared against its last-applied configuration
+type appliedFields struct {
+	Metadata struct {
+		Labels map[string]interface{} `json:"labels"`
+	} `json:"metadata"`
+	Spec interface{} `json:"spec"`
+}
+
+// compactJSON formats a decoded JSON value on a single line
+func compactJSON(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
+
+// jsonDifferences compares two decoded JSON values and describes every leaf that differs, by path
+func jsonDifferences(path string, applied, live interface{}) []string {
+	appliedMap, appliedIsMap := applied.(map[string]interface{})
+	liveMap, liveIsMap := live.(map[string]interface{})
+	if appliedIsMap && liveIsMap {
+		keys := make(map[string]bool)
+		for key := range appliedMap {
+			keys[key] = true
+		}
+		for key := range liveMap {
+			keys[key] = true
+		}
+		sorted := make([]string, 0, len(keys))
+		for key := range keys {
+			sorted = append(sorted, key)
+		}
+		sort.Strings(sorted)
+		var differences []string
+		for _, key := range sorted {
+			differences = append(differences, jsonDifferences(path+"."+key, appliedMap[key], liveMap[key])...)
+		}
+		return differences
+	}
+	appliedSlice, appliedIsSlice := applied.([]interface{})
+	liveSlice, liveIsSlice := live.([]interface{})
+	if appliedIsSlice && liveIsSlice && len(appliedSlice) == len(liveSlice) {
+		var differences []string
+		for idx := range appliedSlice {
+			differences = append(differences, jsonDifferences(fmt.Sprintf("%s[%d]", path, idx), appliedSlice[idx], liveSlice[idx])...)
+		}
+		return differences
+	}
+
+	switch {
+	case reflect.DeepEqual(applied, live):
+		return nil
+	case applied == nil:
+		return []string{fmt.Sprintf("%s: added on the live object (%s)", path, compactJSON(live))}
+	case live == nil:
+		return []string{fmt.Sprintf("%s: removed from the live object (last applied %s)", path, compactJSON(applied))}
+	}
+	return []string{fmt.Sprintf("%s: last applied %s, live %s", path, compactJSON(applied), compactJSON(live))}
+}
+
+// DiffAgainstLastApplied compares the spec and labels of a live Istio resource with its kubectl last-applied
+// configuration, revealing manual edits that the next apply (e.g. by a GitOps controller) would revert
+func (i *Istio) DiffAgainstLastApplied(ctx context.Context, kind, namespace, name string) (string, error) {
+	obj, err := i.getResource(ctx, kind, namespace, name)
+	if err != nil {
+		return "", err
+	}
+	kind = obj.GetObjectKind().GroupVersionKind().Kind
+
+	result := fmt.Sprintf("Drift of %s '%s' in namespace '%s' from its last-applied configuration:\n\n", kind, name, namespace)
+	lastApplied, ok := obj.GetAnnotations()[lastAppliedAnnotation]
+	if !ok {
+		result += fmt.Sprintf("[WARNING] No %s annotation: the resource was not created with kubectl apply, so there is nothing to compare against\n", lastAppliedAnnotation)
+		return result, nil
+	}
+
+	var applied, live appliedFields
+	if err := json.Unmarshal([]byte(lastApplied), &applied); err != nil {
+		return "", fmt.Errorf("failed to parse %s annotation of %s %s: %w", lastAppliedAnnotation, kind, name, err)
+	}
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode %s %s: %w", kind, name, err)
+	}
+	if err := json.Unmarshal(data, &live); err != nil {
+		return "", fmt.Errorf("failed to decode %s %s: %w", kind, name, err)
+	}
+
+	var labelsApplied, labelsLive interface{}
+	if applied.Metadata.Labels != nil {
+		labelsApplied = applied.Metadata.Labels
+	}
+	if live.Metadata.Labels != nil {
+		labelsLive = live.Metadata.Labels
+	}
+	differences := jsonDifferences("metadata.labels", labelsApplied, labelsLive)
+	differences = append(differences, jsonDifferences("spec", applied.Spec, live.Spec)...)
+	if len(differences) == 0 {
+		result += "[OK] The live object matches its last-applied configuration\n"
+		return result, nil
+	}
+	for _, difference := range differences {
+		result += fmt.Sprintf("- %s\n", difference)
+	}
+	result += fmt.Sprintf("\n[WARNING] %d fields were changed outside of kubectl apply; the next apply reverts them\n", len(differences))
+	return result, nil
+}
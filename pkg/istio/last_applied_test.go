@@ -0,0 +1,77 @@
+package istio
+
+import (
+	"context"
+	"testing"
+)
+
+// TestDiffAgainstLastApplied tests comparison of a live resource with its last-applied-configuration annotation
+func TestDiffAgainstLastApplied(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{
+		"/apis/networking.istio.io/v1alpha3/namespaces/bookinfo/virtualservices/reviews": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "VirtualService",
+			"metadata": {
+				"name": "reviews",
+				"namespace": "bookinfo",
+				"labels": {"app": "reviews", "hotfix": "true"},
+				"annotations": {
+					"kubectl.kubernetes.io/last-applied-configuration": "{\"apiVersion\":\"networking.istio.io/v1alpha3\",\"kind\":\"VirtualService\",\"metadata\":{\"labels\":{\"app\":\"reviews\"},\"name\":\"reviews\",\"namespace\":\"bookinfo\"},\"spec\":{\"hosts\":[\"reviews\"],\"http\":[{\"timeout\":\"5s\",\"route\":[{\"destination\":{\"host\":\"reviews\",\"subset\":\"v1\"}}]}]}}"
+				}
+			},
+			"spec": {"hosts": ["reviews"], "http": [{"timeout": "10s", "retries": {"attempts": 3}, "route": [{"destination": {"host": "reviews", "subset": "v1"}}]}]}
+		}`,
+		"/apis/networking.istio.io/v1alpha3/namespaces/bookinfo/virtualservices/ratings": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "VirtualService",
+			"metadata": {
+				"name": "ratings",
+				"namespace": "bookinfo",
+				"annotations": {
+					"kubectl.kubernetes.io/last-applied-configuration": "{\"apiVersion\":\"networking.istio.io/v1alpha3\",\"kind\":\"VirtualService\",\"metadata\":{\"name\":\"ratings\",\"namespace\":\"bookinfo\"},\"spec\":{\"hosts\":[\"ratings\"]}}"
+				}
+			},
+			"spec": {"hosts": ["ratings"]}
+		}`,
+		"/apis/networking.istio.io/v1alpha3/namespaces/bookinfo/virtualservices/details": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "VirtualService",
+			"metadata": {"name": "details", "namespace": "bookinfo"},
+			"spec": {"hosts": ["details"]}
+		}`,
+	})
+	defer mockServer.Close()
+
+	istio := newTestIstio(t, mockServer.URL)
+	ctx := context.Background()
+
+	t.Run("manual edits are reported", func(t *testing.T) {
+		result, err := istio.DiffAgainstLastApplied(ctx, "VirtualService", "bookinfo", "reviews")
+		if err != nil {
+			t.Fatalf("DiffAgainstLastApplied failed: %v", err)
+		}
+		assertContains(t, result,
+			"- metadata.labels.hotfix: added on the live object (\"true\")",
+			"- spec.http[0].retries: added on the live object ({\"attempts\":3})",
+			"- spec.http[0].timeout: last applied \"5s\", live \"10s\"",
+			"[WARNING] 3 fields were changed outside of kubectl apply",
+		)
+		assertNotContains(t, result, "spec.hosts", "subset")
+	})
+
+	t.Run("unchanged resource matches", func(t *testing.T) {
+		result, err := istio.DiffAgainstLastApplied(ctx, "virtualservices", "bookinfo", "ratings")
+		if err != nil {
+			t.Fatalf("DiffAgainstLastApplied failed: %v", err)
+		}
+		assertContains(t, result, "[OK] The live object matches its last-applied configuration")
+	})
+
+	t.Run("resource without annotation", func(t *testing.T) {
+		result, err := istio.DiffAgainstLastApplied(ctx, "VirtualService", "bookinfo", "details")
+		if err != nil {
+			t.Fatalf("DiffAgainstLastApplied failed: %v", err)
+		}
+		assertContains(t, result, "[WARNING] No kubectl.kubernetes.io/last-applied-configuration annotation")
+	})
+}
@@ -0,0 +1,221 @@
+package istio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// envoyFilterNames are readable names of the listener, network and HTTP filters Istio configures
+var envoyFilterNames = map[string]string{
+	"envoy.filters.listener.tls_inspector":          "TLS inspector",
+	"envoy.filters.listener.http_inspector":         "HTTP inspector",
+	"envoy.filters.listener.original_dst":           "original destination",
+	"envoy.filters.listener.proxy_protocol":         "PROXY protocol",
+	"envoy.filters.network.http_connection_manager": "HTTP connection manager",
+	"envoy.filters.network.tcp_proxy":               "TCP proxy",
+	"envoy.filters.network.rbac":                    "RBAC",
+	"envoy.filters.network.sni_cluster":             "SNI cluster",
+	"istio.metadata_exchange":                       "metadata exchange",
+	"istio.stats":                                   "stats",
+	"istio.alpn":                                    "ALPN override",
+	"envoy.filters.http.router":                     "router",
+	"envoy.filters.http.fault":                      "fault injection",
+	"envoy.filters.http.cors":                       "CORS",
+	"envoy.filters.http.rbac":                       "RBAC",
+	"envoy.filters.http.ext_authz":                  "external authorization",
+	"envoy.filters.http.jwt_authn":                  "JWT authentication",
+	"envoy.filters.http.grpc_stats":                 "gRPC stats",
+}
+
+// envoyFilterName returns the readable name of an Envoy filter, or its name as is for filters not in envoyFilterNames
+func envoyFilterName(name string) string {
+	if readable, ok := envoyFilterNames[name]; ok {
+		return readable
+	}
+	return name
+}
+
+// listenerFilter is a listener or network filter of a listener dump with the typed config fields narrated
+type listenerFilter struct {
+	Name        string `json:"name"`
+	TypedConfig struct {
+		// Set by the TCP proxy
+		Cluster string `json:"cluster"`
+		// Set by the HTTP connection manager
+		Rds struct {
+			RouteConfigName string `json:"routeConfigName"`
+		} `json:"rds"`
+		RouteConfig *struct {
+			Name string `json:"name"`
+		} `json:"routeConfig"`
+		HTTPFilters []struct {
+			Name string `json:"name"`
+		} `json:"httpFilters"`
+	} `json:"typedConfig"`
+}
+
+// filterChain is a filter chain of a listener dump
+type filterChain struct {
+	Name             string `json:"name"`
+	FilterChainMatch struct {
+		DestinationPort      int      `json:"destinationPort"`
+		TransportProtocol    string   `json:"transportProtocol"`
+		ApplicationProtocols []string `json:"applicationProtocols"`
+		ServerNames          []string `json:"serverNames"`
+		PrefixRanges         []struct {
+			AddressPrefix string `json:"addressPrefix"`
+			PrefixLen     int    `json:"prefixLen"`
+		} `json:"prefixRanges"`
+	} `json:"filterChainMatch"`
+	Filters         []listenerFilter `json:"filters"`
+	TransportSocket *struct {
+		Name string `json:"name"`
+	} `json:"transportSocket"`
+}
+
+// listenerDump is the subset of a listener in the JSON output of istioctl proxy-config listener used to narrate it
+type listenerDump struct {
+	Name    string `json:"name"`
+	Address struct {
+		SocketAddress struct {
+			Address   string `json:"address"`
+			PortValue int    `json:"portValue"`
+		} `json:"socketAddress"`
+	} `json:"address"`
+	ListenerFilters    []listenerFilter `json:"listenerFilters"`
+	FilterChains       []filterChain    `json:"filterChains"`
+	DefaultFilterChain *filterChain     `json:"defaultFilterChain"`
+}
+
+// describeFilterChainMatch narrates the conditions a connection must meet to use a filter chain
+func describeFilterChainMatch(chain filterChain) string {
+	match := chain.FilterChainMatch
+	var conditions []string
+	if match.DestinationPort != 0 {
+		conditions = append(conditions, fmt.Sprintf("destination port %d", match.DestinationPort))
+	}
+	if len(match.PrefixRanges) > 0 {
+		var ranges []string
+		for _, prefix := range match.PrefixRanges {
+			ranges = append(ranges, fmt.Sprintf("%s/%d", prefix.AddressPrefix, prefix.PrefixLen))
+		}
+		conditions = append(conditions, "destination IP in "+strings.Join(ranges, ", "))
+	}
+	switch match.TransportProtocol {
+	case "tls":
+		conditions = append(conditions, "TLS connections (detected by the TLS inspector)")
+	case "raw_buffer":
+		conditions = append(conditions, "plaintext connections")
+	}
+	if len(match.ApplicationProtocols) > 0 {
+		conditions = append(conditions, "ALPN "+strings.Join(match.ApplicationProtocols, ", "))
+	}
+	if len(match.ServerNames) > 0 {
+		conditions = append(conditions, "SNI "+strings.Join(match.ServerNames, ", "))
+	}
+	if len(conditions) == 0 {
+		return "any connection"
+	}
+	return strings.Join(conditions, "; ")
+}
+
+// describeNetworkFilter narrates a network filter, including the routes or cluster it forwards to
+func describeNetworkFilter(filter listenerFilter) string {
+	description := envoyFilterName(filter.Name)
+	config := filter.TypedConfig
+	switch {
+	case config.Cluster != "":
+		description += fmt.Sprintf(" (cluster %s)", config.Cluster)
+	case config.Rds.RouteConfigName != "" || config.RouteConfig != nil || len(config.HTTPFilters) > 0:
+		var details []string
+		if config.Rds.RouteConfigName != "" {
+			details = append(details, "routes "+config.Rds.RouteConfigName)
+		} else if config.RouteConfig != nil {
+			details = append(details, "inline routes")
+		}
+		if len(config.HTTPFilters) > 0 {
+			var httpFilters []string
+			for _, httpFilter := range config.HTTPFilters {
+				httpFilters = append(httpFilters, envoyFilterName(httpFilter.Name))
+			}
+			details = append(details, "HTTP filters: "+strings.Join(httpFilters, " → "))
+		}
+		description += fmt.Sprintf(" (%s)", strings.Join(details, "; "))
+	}
+	return description
+}
+
+// describeFilterChain narrates the match conditions, TLS termination and filters of a filter chain
+func describeFilterChain(title string, chain filterChain) string {
+	description := fmt.Sprintf("  %s", title)
+	if chain.Name != "" {
+		description += fmt.Sprintf(" '%s'", chain.Name)
+	}
+	description += fmt.Sprintf(": matches %s\n", describeFilterChainMatch(chain))
+	if chain.TransportSocket != nil && strings.Contains(chain.TransportSocket.Name, "tls") {
+		description += "    Terminates TLS (mTLS from other mesh workloads when ALPN is istio)\n"
+	}
+	var filters []string
+	for _, filter := range chain.Filters {
+		filters = append(filters, describeNetworkFilter(filter))
+	}
+	description += fmt.Sprintf("    Filters: %s\n", strings.Join(filters, " → "))
+	return description
+}
+
+// ExplainListenerFilterChains narrates the listeners of a pod's proxy bound to a port, or the filter chains of the
+// virtual listeners matching the port: the listener filters inspecting connections, and for each filter chain the
+// SNI, ALPN, transport protocol and address conditions selecting it and the filters it applies
+func (p *ProxyConfigClient) ExplainListenerFilterChains(ctx context.Context, namespace, podName string, port int) (string, error) {
+	output, err := p.GetListeners(ctx, namespace, podName)
+	if err != nil {
+		return "", err
+	}
+	var listeners []listenerDump
+	if err := json.Unmarshal([]byte(output), &listeners); err != nil {
+		return "", fmt.Errorf("failed to parse listeners of pod %s: %w", podName, err)
+	}
+
+	result := fmt.Sprintf("Filter chains for port %d of pod '%s' in namespace '%s':\n", port, podName, namespace)
+	found := false
+	for _, listener := range listeners {
+		chains := listener.FilterChains
+		bound := listener.Address.SocketAddress.PortValue == port
+		if !bound {
+			// Virtual listeners (e.g. virtualInbound on 15006) dispatch to chains by the original destination port
+			chains = nil
+			for _, chain := range listener.FilterChains {
+				if chain.FilterChainMatch.DestinationPort == port {
+					chains = append(chains, chain)
+				}
+			}
+			if len(chains) == 0 {
+				continue
+			}
+		}
+		found = true
+
+		socket := listener.Address.SocketAddress
+		result += fmt.Sprintf("\nListener '%s' (%s:%d):\n", listener.Name, socket.Address, socket.PortValue)
+		if len(listener.ListenerFilters) > 0 {
+			var filters []string
+			for _, filter := range listener.ListenerFilters {
+				filters = append(filters, envoyFilterName(filter.Name))
+			}
+			result += fmt.Sprintf("  Listener filters: %s\n", strings.Join(filters, " → "))
+		}
+		for idx, chain := range chains {
+			result += describeFilterChain(fmt.Sprintf("Chain %d", idx+1), chain)
+		}
+		if bound && listener.DefaultFilterChain != nil {
+			result += describeFilterChain("Default chain (no other chain matches)", *listener.DefaultFilterChain)
+		}
+	}
+
+	if !found {
+		result += fmt.Sprintf("\nNo listener or filter chain of the proxy handles port %d\n", port)
+	}
+	return result, nil
+}
@@ -0,0 +1,66 @@
+package istio
+
+import (
+	"context"
+	"testing"
+)
+
+// TestExplainListenerFilterChains tests narrating the filter chains of the inbound listener for a port
+func TestExplainListenerFilterChains(t *testing.T) {
+	client := NewProxyConfigClient("")
+	stubIstioctl(client, `[
+		{
+			"name": "virtualInbound",
+			"address": {"socketAddress": {"address": "0.0.0.0", "portValue": 15006}},
+			"listenerFilters": [
+				{"name": "envoy.filters.listener.original_dst"},
+				{"name": "envoy.filters.listener.tls_inspector"},
+				{"name": "envoy.filters.listener.http_inspector"}
+			],
+			"filterChains": [
+				{
+					"name": "0.0.0.0_9080",
+					"filterChainMatch": {"destinationPort": 9080, "transportProtocol": "tls", "applicationProtocols": ["istio-http/1.0", "istio-http/1.1", "istio-h2"]},
+					"transportSocket": {"name": "envoy.transport_sockets.tls"},
+					"filters": [
+						{"name": "istio.metadata_exchange"},
+						{"name": "envoy.filters.network.http_connection_manager", "typedConfig": {
+							"routeConfig": {"name": "inbound|9080||"},
+							"httpFilters": [{"name": "envoy.filters.http.rbac"}, {"name": "istio.stats"}, {"name": "envoy.filters.http.router"}]
+						}}
+					]
+				},
+				{
+					"name": "0.0.0.0_9080",
+					"filterChainMatch": {"destinationPort": 9080, "transportProtocol": "raw_buffer"},
+					"filters": [{"name": "envoy.filters.network.tcp_proxy", "typedConfig": {"cluster": "inbound|9080||"}}]
+				},
+				{
+					"name": "0.0.0.0_15090",
+					"filterChainMatch": {"destinationPort": 15090}
+				}
+			]
+		},
+		{
+			"name": "10.96.0.10_53",
+			"address": {"socketAddress": {"address": "10.96.0.10", "portValue": 53}},
+			"filterChains": [{"filters": [{"name": "envoy.filters.network.tcp_proxy", "typedConfig": {"cluster": "outbound|53||kube-dns.kube-system.svc.cluster.local"}}]}]
+		}
+	]`)
+
+	result, err := client.ExplainListenerFilterChains(context.Background(), "bookinfo", "reviews-v1-abc", 9080)
+	if err != nil {
+		t.Fatalf("ExplainListenerFilterChains failed: %v", err)
+	}
+	assertContains(t, result,
+		"Filter chains for port 9080 of pod 'reviews-v1-abc' in namespace 'bookinfo'",
+		"Listener 'virtualInbound' (0.0.0.0:15006)",
+		"Listener filters: original destination → TLS inspector → HTTP inspector",
+		"Chain 1 '0.0.0.0_9080': matches destination port 9080; TLS connections (detected by the TLS inspector); ALPN istio-http/1.0, istio-http/1.1, istio-h2",
+		"Terminates TLS",
+		"Filters: metadata exchange → HTTP connection manager (inline routes; HTTP filters: RBAC → stats → router)",
+		"Chain 2 '0.0.0.0_9080': matches destination port 9080; plaintext connections",
+		"Filters: TCP proxy (cluster inbound|9080||)",
+	)
+	assertNotContains(t, result, "15090", "kube-dns")
+}
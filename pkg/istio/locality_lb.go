@@ -0,0 +1,95 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	networkingapi "istio.io/api/networking/v1alpha3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// describeLocalityLbSetting renders the failover and distribution rules of a locality load balancing setting
+func describeLocalityLbSetting(setting *networkingapi.LocalityLoadBalancerSetting) string {
+	if setting.GetEnabled() != nil && !setting.GetEnabled().GetValue() {
+		return "[WARNING] Locality load balancing is disabled (enabled: false); requests are spread across all localities\n"
+	}
+	description := ""
+	for _, distribute := range setting.GetDistribute() {
+		var weights []string
+		for _, locality := range slices.Sorted(maps.Keys(distribute.GetTo())) {
+			weights = append(weights, fmt.Sprintf("%s %d%%", locality, distribute.GetTo()[locality]))
+		}
+		description += fmt.Sprintf("  Distribute from %s: %s\n", distribute.GetFrom(), strings.Join(weights, ", "))
+	}
+	for _, failover := range setting.GetFailover() {
+		description += fmt.Sprintf("  Failover from region %s to region %s\n", failover.GetFrom(), failover.GetTo())
+	}
+	if len(setting.GetFailoverPriority()) > 0 {
+		description += fmt.Sprintf("  Failover priority labels: %s\n", strings.Join(setting.GetFailoverPriority(), ", "))
+	}
+	if description == "" {
+		description = "  No explicit rules; requests prefer endpoints in the same zone, then the same region, then any locality\n"
+	}
+	return description
+}
+
+// GetLocalityLbConfig reports the effective locality load balancing settings for requests from namespace to a
+// host: the failover and distribute rules of the DestinationRule Istio applies, falling back to the mesh config,
+// whether outlier detection activates them, and subset overrides
+func (i *Istio) GetLocalityLbConfig(ctx context.Context, namespace, host string) (string, error) {
+	target, err := i.resolveHost(ctx, host, namespace)
+	if err != nil {
+		return "", err
+	}
+	mesh, err := i.getMeshConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+	drList, err := i.istioClient.NetworkingV1alpha3().DestinationRules("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list destination rules: %w", i.explainAPIError(ctx, err, "list", "destinationrules", ""))
+	}
+	rule := effectiveDestinationRule(drList.Items, target, namespace, mesh)
+
+	result := fmt.Sprintf("Locality load balancing for host '%s' from namespace '%s':\n\n", target, namespace)
+	var setting *networkingapi.LocalityLoadBalancerSetting
+	source, outlierDetection := "", false
+	if rule != nil {
+		policy := rule.Spec.GetTrafficPolicy()
+		if policy.GetLoadBalancer().GetLocalityLbSetting() != nil {
+			setting = policy.GetLoadBalancer().GetLocalityLbSetting()
+			source = fmt.Sprintf("DestinationRule '%s/%s'", rule.Namespace, rule.Name)
+		}
+		outlierDetection = policy.GetOutlierDetection() != nil
+	}
+	if setting == nil && mesh.LocalityLbSetting != nil {
+		setting = mesh.LocalityLbSetting
+		source = fmt.Sprintf("mesh config (ConfigMap %s/%s)", istioSystemNamespace, meshConfigMapName)
+	}
+
+	if setting == nil {
+		result += "No locality load balancing settings apply; Istio's default locality-aware routing is used\n"
+		result += describeLocalityLbSetting(&networkingapi.LocalityLoadBalancerSetting{})
+	} else {
+		result += fmt.Sprintf("Effective settings from %s:\n", source)
+		result += describeLocalityLbSetting(setting)
+	}
+	if !outlierDetection {
+		result += "\n[WARNING] No Destination Rule for this host configures outlierDetection; Istio only applies locality failover when outlier detection can eject unhealthy endpoints, so requests are spread across all localities\n"
+	} else {
+		result += "\n[OK] Outlier detection is configured, so locality failover is active\n"
+	}
+
+	if rule != nil {
+		for _, subset := range rule.Spec.GetSubsets() {
+			if subsetSetting := subset.GetTrafficPolicy().GetLoadBalancer().GetLocalityLbSetting(); subsetSetting != nil {
+				result += fmt.Sprintf("\nSubset '%s' of DestinationRule '%s/%s' overrides the settings:\n", subset.GetName(), rule.Namespace, rule.Name)
+				result += describeLocalityLbSetting(subsetSetting)
+			}
+		}
+	}
+	return result, nil
+}
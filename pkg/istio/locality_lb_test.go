@@ -0,0 +1,71 @@
+package istio
+
+import (
+	"context"
+	"testing"
+)
+
+// TestGetLocalityLbConfig tests resolution of the locality load balancing settings for a host
+func TestGetLocalityLbConfig(t *testing.T) {
+	t.Run("destination rule failover", func(t *testing.T) {
+		mockServer := newMockAPIServer(map[string]string{
+			"/apis/networking.istio.io/v1alpha3/destinationrules": `{
+				"apiVersion": "networking.istio.io/v1alpha3",
+				"kind": "DestinationRuleList",
+				"items": [
+					{"metadata": {"name": "reviews", "namespace": "bookinfo"}, "spec": {
+						"host": "reviews",
+						"trafficPolicy": {
+							"loadBalancer": {"localityLbSetting": {"enabled": true, "failover": [{"from": "us-east1", "to": "us-west1"}]}},
+							"outlierDetection": {"consecutive5xxErrors": 5, "interval": "10s", "baseEjectionTime": "30s"}
+						},
+						"subsets": [{"name": "v2", "labels": {"version": "v2"}, "trafficPolicy": {"loadBalancer": {"localityLbSetting": {
+							"distribute": [{"from": "us-east1/*", "to": {"us-east1/*": 80, "us-west1/*": 20}}]
+						}}}}]
+					}},
+					{"metadata": {"name": "ratings", "namespace": "bookinfo"}, "spec": {"host": "ratings", "trafficPolicy": {"loadBalancer": {"localityLbSetting": {"enabled": false}}}}}
+				]
+			}`,
+		})
+		defer mockServer.Close()
+		istio := newTestIstio(t, mockServer.URL)
+
+		result, err := istio.GetLocalityLbConfig(context.Background(), "bookinfo", "reviews")
+		if err != nil {
+			t.Fatalf("GetLocalityLbConfig failed: %v", err)
+		}
+		assertContains(t, result,
+			"Locality load balancing for host 'reviews.bookinfo.svc.cluster.local' from namespace 'bookinfo'",
+			"Effective settings from DestinationRule 'bookinfo/reviews'",
+			"Failover from region us-east1 to region us-west1",
+			"[OK] Outlier detection is configured",
+			"Subset 'v2' of DestinationRule 'bookinfo/reviews' overrides the settings",
+			"Distribute from us-east1/*: us-east1/* 80%, us-west1/* 20%",
+		)
+		assertNotContains(t, result, "disabled", "[WARNING]")
+	})
+
+	t.Run("mesh config without outlier detection", func(t *testing.T) {
+		mockServer := newMockAPIServer(map[string]string{
+			"/api/v1/namespaces/istio-system/configmaps/istio": `{
+				"apiVersion": "v1",
+				"kind": "ConfigMap",
+				"metadata": {"name": "istio", "namespace": "istio-system"},
+				"data": {"mesh": "localityLbSetting:\n  enabled: true\n  failoverPriority:\n  - topology.kubernetes.io/region\n  - topology.kubernetes.io/zone\n"}
+			}`,
+			"/apis/networking.istio.io/v1alpha3/destinationrules": `{"apiVersion": "networking.istio.io/v1alpha3", "kind": "DestinationRuleList", "items": []}`,
+		})
+		defer mockServer.Close()
+		istio := newTestIstio(t, mockServer.URL)
+
+		result, err := istio.GetLocalityLbConfig(context.Background(), "bookinfo", "details")
+		if err != nil {
+			t.Fatalf("GetLocalityLbConfig failed: %v", err)
+		}
+		assertContains(t, result,
+			"Effective settings from mesh config (ConfigMap istio-system/istio)",
+			"Failover priority labels: topology.kubernetes.io/region, topology.kubernetes.io/zone",
+			"[WARNING] No Destination Rule for this host configures outlierDetection",
+		)
+	})
+}
@@ -0,0 +1,82 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+
+	networkingapi "istio.io/api/networking/v1alpha3"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// istioSystemNamespace is the namespace istiod and the mesh config are installed into by default
+	istioSystemNamespace = "istio-system"
+	// meshConfigMapName is the ConfigMap holding the mesh-wide configuration under the "mesh" key
+	meshConfigMapName = "istio"
+)
+
+// meshConfig holds the subset of Istio's MeshConfig used by the tools
+type meshConfig struct {
+	RootNamespace         string   `json:"rootNamespace,omitempty"`
+	TrustDomain           string   `json:"trustDomain,omitempty"`
+	TrustDomainAliases    []string `json:"trustDomainAliases,omitempty"`
+	OutboundTrafficPolicy struct {
+		Mode string `json:"mode,omitempty"`
+	} `json:"outboundTrafficPolicy,omitempty"`
+	// Default exportTo of the resources that don't set one; unset means exported to all namespaces
+	DefaultServiceExportTo         []string `json:"defaultServiceExportTo,omitempty"`
+	DefaultVirtualServiceExportTo  []string `json:"defaultVirtualServiceExportTo,omitempty"`
+	DefaultDestinationRuleExportTo []string `json:"defaultDestinationRuleExportTo,omitempty"`
+	// Providers that Telemetry resources reference by name; the built-in providers are always defined
+	ExtensionProviders []struct {
+		Name string `json:"name"`
+		// External authorizers that CUSTOM AuthorizationPolicies delegate to
+		EnvoyExtAuthzHTTP *extAuthzService `json:"envoyExtAuthzHttp,omitempty"`
+		EnvoyExtAuthzGrpc *extAuthzService `json:"envoyExtAuthzGrpc,omitempty"`
+	} `json:"extensionProviders,omitempty"`
+	// Mesh-wide locality load balancing, overridden by the loadBalancer of DestinationRules
+	LocalityLbSetting *networkingapi.LocalityLoadBalancerSetting `json:"localityLbSetting,omitempty"`
+	// Retry policy of the HTTP routes that set no retries, replacing Istio's built-in default
+	DefaultHTTPRetryPolicy *networkingapi.HTTPRetry `json:"defaultHttpRetryPolicy,omitempty"`
+}
+
+// extAuthzService is the service of an external authorization extension provider
+type extAuthzService struct {
+	Service string `json:"service"`
+	Port    int    `json:"port"`
+}
+
+// defaultMeshConfig returns the values Istio uses when they are not set in the mesh config
+func defaultMeshConfig() *meshConfig {
+	config := &meshConfig{RootNamespace: istioSystemNamespace, TrustDomain: defaultTrustDomain}
+	config.OutboundTrafficPolicy.Mode = "ALLOW_ANY"
+	return config
+}
+
+// getMeshConfig reads the mesh config from the 'istio' ConfigMap in istio-system, falling back
+// to Istio's defaults for unset fields or when the ConfigMap doesn't exist
+func (i *Istio) getMeshConfig(ctx context.Context) (*meshConfig, error) {
+	config := defaultMeshConfig()
+	cm, err := i.kubeClient.CoreV1().ConfigMaps(istioSystemNamespace).Get(ctx, meshConfigMapName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return config, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mesh config: %w", explainForbidden(err, "get", "configmaps", istioSystemNamespace))
+	}
+	if err := yaml.Unmarshal([]byte(cm.Data["mesh"]), config); err != nil {
+		return nil, fmt.Errorf("failed to parse mesh config: %w", err)
+	}
+	if config.RootNamespace == "" {
+		config.RootNamespace = istioSystemNamespace
+	}
+	if config.TrustDomain == "" {
+		config.TrustDomain = defaultTrustDomain
+	}
+	if config.OutboundTrafficPolicy.Mode == "" {
+		config.OutboundTrafficPolicy.Mode = "ALLOW_ANY"
+	}
+	return config, nil
+}
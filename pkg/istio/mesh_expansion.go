@@ -0,0 +1,105 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// autoRegistrationGroupAnnotation is set by istiod on WorkloadEntries it auto-registers for a WorkloadGroup
+const autoRegistrationGroupAnnotation = "istio.io/autoRegistrationGroup"
+
+// CheckMeshExpansionReadiness verifies that a WorkloadGroup is ready for onboarding VMs: the group exists,
+// its ServiceAccount exists, and a ServiceEntry selects its workloads or workloads have been auto-registered
+func (i *Istio) CheckMeshExpansionReadiness(ctx context.Context, namespace, workloadGroup string) (string, error) {
+	result := fmt.Sprintf("Mesh expansion readiness for WorkloadGroup '%s' in namespace '%s':\n\n", workloadGroup, namespace)
+	failures := 0
+
+	check := func(ok bool, okMessage, failMessage string) {
+		if ok {
+			result += "[OK] " + okMessage + "\n"
+		} else {
+			result += "[FAIL] " + failMessage + "\n"
+			failures++
+		}
+	}
+
+	// Check 1: WorkloadGroup
+	group, err := i.istioClient.NetworkingV1alpha3().WorkloadGroups(namespace).Get(ctx, workloadGroup, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		check(false, "", fmt.Sprintf("WorkloadGroup: '%s' not found", workloadGroup))
+		return result + "\n[RESULT] 1 checks failed\n", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get workload group %s: %w", workloadGroup, explainForbidden(err, "get", "workloadgroups", namespace))
+	}
+	template := group.Spec.GetTemplate()
+	groupLabels := group.Spec.GetMetadata().GetLabels()
+	check(true, fmt.Sprintf("WorkloadGroup: '%s' exists (labels: %v)", workloadGroup, groupLabels), "")
+
+	// Check 2: ServiceAccount
+	serviceAccount := template.GetServiceAccount()
+	if serviceAccount == "" {
+		serviceAccount = "default"
+		result += "[WARNING] WorkloadGroup template has no serviceAccount; VMs will run as 'default'\n"
+	}
+	mesh, err := i.getMeshConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+	_, err = i.kubeClient.CoreV1().ServiceAccounts(namespace).Get(ctx, serviceAccount, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		check(false, "", fmt.Sprintf("ServiceAccount: '%s' not found in namespace '%s'; VMs cannot obtain a workload certificate", serviceAccount, namespace))
+	case err != nil:
+		return "", fmt.Errorf("failed to get service account %s: %w", serviceAccount, explainForbidden(err, "get", "serviceaccounts", namespace))
+	default:
+		check(true, fmt.Sprintf("ServiceAccount: '%s' exists (identity %s)", serviceAccount, spiffeID(mesh.TrustDomain, namespace, serviceAccount)), "")
+	}
+
+	// Check 3: network
+	if network := template.GetNetwork(); network != "" {
+		result += fmt.Sprintf("[OK] Network: '%s'\n", network)
+	} else {
+		result += "[WARNING] Network: not set; VMs are assumed to be on the same network as the cluster pods\n"
+	}
+
+	// Check 4: service discovery, through a ServiceEntry selecting the workloads or auto-registered WorkloadEntries
+	seList, err := i.istioClient.NetworkingV1alpha3().ServiceEntries(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list service entries: %w", explainForbidden(err, "list", "serviceentries", namespace))
+	}
+	var selecting []string
+	for _, se := range seList.Items {
+		selector := se.Spec.GetWorkloadSelector().GetLabels()
+		if len(selector) > 0 && labels.SelectorFromSet(selector).Matches(labels.Set(groupLabels)) {
+			selecting = append(selecting, se.Name)
+		}
+	}
+
+	weList, err := i.istioClient.NetworkingV1alpha3().WorkloadEntries(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list workload entries: %w", explainForbidden(err, "list", "workloadentries", namespace))
+	}
+	var registered []string
+	for _, we := range weList.Items {
+		if we.Annotations[autoRegistrationGroupAnnotation] == workloadGroup {
+			registered = append(registered, fmt.Sprintf("%s (%s)", we.Name, we.Spec.GetAddress()))
+		}
+	}
+
+	check(len(selecting) > 0 || len(registered) > 0,
+		fmt.Sprintf("Service discovery: ServiceEntries selecting the group: [%s], auto-registered WorkloadEntries: [%s]", strings.Join(selecting, ", "), strings.Join(registered, ", ")),
+		"Service discovery: no ServiceEntry selects the group labels and no WorkloadEntries were auto-registered; VMs will not be reachable through a mesh service")
+
+	if failures == 0 {
+		result += "\n[RESULT] All checks passed\n"
+	} else {
+		result += fmt.Sprintf("\n[RESULT] %d checks failed\n", failures)
+	}
+	return result, nil
+}
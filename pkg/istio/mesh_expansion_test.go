@@ -0,0 +1,74 @@
+package istio
+
+import (
+	"context"
+	"testing"
+)
+
+// TestCheckMeshExpansionReadiness tests the WorkloadGroup onboarding checks
+func TestCheckMeshExpansionReadiness(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{
+		"/apis/networking.istio.io/v1alpha3/namespaces/vm/workloadgroups/billing": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "WorkloadGroup",
+			"metadata": {"name": "billing", "namespace": "vm"},
+			"spec": {"metadata": {"labels": {"app": "billing"}}, "template": {"serviceAccount": "billing-vm", "network": "vm-network"}}
+		}`,
+		"/apis/networking.istio.io/v1alpha3/namespaces/vm/workloadgroups/ledger": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "WorkloadGroup",
+			"metadata": {"name": "ledger", "namespace": "vm"},
+			"spec": {"metadata": {"labels": {"app": "ledger"}}, "template": {"serviceAccount": "ledger-vm"}}
+		}`,
+		"/api/v1/namespaces/vm/serviceaccounts/ledger-vm": `{"apiVersion": "v1", "kind": "ServiceAccount", "metadata": {"name": "ledger-vm", "namespace": "vm"}}`,
+		"/apis/networking.istio.io/v1alpha3/namespaces/vm/serviceentries": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "ServiceEntryList",
+			"items": [
+				{
+					"metadata": {"name": "ledger", "namespace": "vm"},
+					"spec": {"hosts": ["ledger.vm.svc.cluster.local"], "workloadSelector": {"labels": {"app": "ledger"}}}
+				}
+			]
+		}`,
+		"/apis/networking.istio.io/v1alpha3/namespaces/vm/workloadentries": `{"apiVersion": "networking.istio.io/v1alpha3", "kind": "WorkloadEntryList", "items": []}`,
+	})
+	defer mockServer.Close()
+
+	istio := newTestIstio(t, mockServer.URL)
+	ctx := context.Background()
+
+	t.Run("missing service account", func(t *testing.T) {
+		result, err := istio.CheckMeshExpansionReadiness(ctx, "vm", "billing")
+		if err != nil {
+			t.Fatalf("Failed to check readiness: %v", err)
+		}
+		assertContains(t, result,
+			"[OK] WorkloadGroup: 'billing' exists",
+			"[FAIL] ServiceAccount: 'billing-vm' not found in namespace 'vm'",
+			"[OK] Network: 'vm-network'",
+			"[FAIL] Service discovery",
+			"[RESULT] 2 checks failed",
+		)
+	})
+
+	t.Run("ready group", func(t *testing.T) {
+		result, err := istio.CheckMeshExpansionReadiness(ctx, "vm", "ledger")
+		if err != nil {
+			t.Fatalf("Failed to check readiness: %v", err)
+		}
+		assertContains(t, result,
+			"[OK] ServiceAccount: 'ledger-vm' exists (identity spiffe://cluster.local/ns/vm/sa/ledger-vm)",
+			"ServiceEntries selecting the group: [ledger]",
+			"[RESULT] All checks passed",
+		)
+	})
+
+	t.Run("missing workload group", func(t *testing.T) {
+		result, err := istio.CheckMeshExpansionReadiness(ctx, "vm", "missing")
+		if err != nil {
+			t.Fatalf("Failed to check readiness: %v", err)
+		}
+		assertContains(t, result, "[FAIL] WorkloadGroup: 'missing' not found")
+	})
+}
@@ -0,0 +1,93 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	networkingapi "istio.io/api/networking/v1alpha3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// describeDestination formats a route destination as host, with its port and subset when set
+func describeDestination(destination *networkingapi.Destination) string {
+	result := destination.GetHost()
+	if port := destination.GetPort().GetNumber(); port != 0 {
+		result += fmt.Sprintf(":%d", port)
+	}
+	if subset := destination.GetSubset(); subset != "" {
+		result += fmt.Sprintf(" (subset %s)", subset)
+	}
+	return result
+}
+
+// mirrorPercentage returns the share of requests mirrored by the mirror field of a route, which is all requests
+// unless mirrorPercentage or the deprecated mirrorPercent is set
+func mirrorPercentage(route *networkingapi.HTTPRoute) float64 {
+	if percentage := route.GetMirrorPercentage(); percentage != nil {
+		return percentage.GetValue()
+	}
+	// mirrorPercent is deprecated but still honored by Istio
+	if percent := route.GetMirrorPercent(); percent != nil {
+		return float64(percent.GetValue())
+	}
+	return 100
+}
+
+// GetMirrorConfig lists the HTTP routes of the VirtualServices in a namespace that mirror (shadow) traffic,
+// with the destinations of the route and the mirror destinations with the share of requests they receive
+func (i *Istio) GetMirrorConfig(ctx context.Context, namespace string) (string, error) {
+	vsList, err := i.istioClient.NetworkingV1alpha3().VirtualServices(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list virtual services: %w", explainForbidden(err, "list", "virtualservices", namespace))
+	}
+
+	result := fmt.Sprintf("Traffic mirroring in Virtual Services of namespace '%s':\n\n", namespace)
+	routes := 0
+	for _, vs := range vsList.Items {
+		for idx, route := range vs.Spec.GetHttp() {
+			type mirror struct {
+				destination *networkingapi.Destination
+				percentage  float64
+			}
+			var mirrors []mirror
+			if route.GetMirror() != nil {
+				mirrors = append(mirrors, mirror{route.GetMirror(), mirrorPercentage(route)})
+			}
+			for _, policy := range route.GetMirrors() {
+				percentage := 100.0
+				if policy.GetPercentage() != nil {
+					percentage = policy.GetPercentage().GetValue()
+				}
+				mirrors = append(mirrors, mirror{policy.GetDestination(), percentage})
+			}
+			if len(mirrors) == 0 {
+				continue
+			}
+			routes++
+
+			var sources []string
+			for _, rd := range route.GetRoute() {
+				source := describeDestination(rd.GetDestination())
+				if len(route.GetRoute()) > 1 {
+					source += fmt.Sprintf(" weight %d", rd.GetWeight())
+				}
+				sources = append(sources, source)
+			}
+			result += fmt.Sprintf("%s route %s (hosts: %s)\n", vs.Name, httpRouteName(route, idx), strings.Join(vs.Spec.GetHosts(), ", "))
+			result += fmt.Sprintf("  Routes to: %s\n", strings.Join(sources, ", "))
+			for _, m := range mirrors {
+				result += fmt.Sprintf("  Mirrors to: %s, %g%% of requests\n", describeDestination(m.destination), m.percentage)
+				if m.percentage >= 100 {
+					result += fmt.Sprintf("  [WARNING] All requests are mirrored: %s receives the full load of the route\n", m.destination.GetHost())
+				}
+			}
+		}
+	}
+
+	if routes == 0 {
+		return result + "[OK] No Virtual Service mirrors traffic\n", nil
+	}
+	result += fmt.Sprintf("\n[RESULT] %d routes mirror traffic; responses of mirrors are discarded, but they receive real requests\n", routes)
+	return result, nil
+}
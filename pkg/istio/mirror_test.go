@@ -0,0 +1,51 @@
+package istio
+
+import (
+	"context"
+	"testing"
+)
+
+// TestGetMirrorConfig tests reporting of the traffic mirrored by VirtualService routes
+func TestGetMirrorConfig(t *testing.T) {
+	server := newMockAPIServer(map[string]string{
+		"/apis/networking.istio.io/v1alpha3/namespaces/bookinfo/virtualservices": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "VirtualServiceList",
+			"items": [
+				{
+					"metadata": {"name": "reviews", "namespace": "bookinfo"},
+					"spec": {"hosts": ["reviews"], "http": [{
+						"route": [{"destination": {"host": "reviews", "subset": "v1"}}],
+						"mirror": {"host": "reviews", "subset": "canary"}
+					}]}
+				},
+				{
+					"metadata": {"name": "ratings", "namespace": "bookinfo"},
+					"spec": {"hosts": ["ratings"], "http": [{
+						"name": "shadow",
+						"route": [{"destination": {"host": "ratings", "port": {"number": 9080}}}],
+						"mirrors": [{"destination": {"host": "ratings-shadow"}, "percentage": {"value": 5}}]
+					}]}
+				},
+				{
+					"metadata": {"name": "details", "namespace": "bookinfo"},
+					"spec": {"hosts": ["details"], "http": [{"route": [{"destination": {"host": "details"}}]}]}
+				}
+			]
+		}`,
+	})
+	defer server.Close()
+	istio := newTestIstio(t, server.URL)
+
+	result, err := istio.GetMirrorConfig(context.Background(), "bookinfo")
+	if err != nil {
+		t.Fatalf("GetMirrorConfig failed: %v", err)
+	}
+	assertContains(t, result,
+		"reviews route #1 (hosts: reviews)\n  Routes to: reviews (subset v1)\n  Mirrors to: reviews (subset canary), 100% of requests",
+		"[WARNING] All requests are mirrored: reviews receives the full load",
+		"ratings route 'shadow' (hosts: ratings)\n  Routes to: ratings:9080\n  Mirrors to: ratings-shadow, 5% of requests\n",
+		"[RESULT] 2 routes mirror traffic",
+	)
+	assertNotContains(t, result, "details")
+}
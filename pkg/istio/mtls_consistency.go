@@ -0,0 +1,176 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	networkingapi "istio.io/api/networking/v1alpha3"
+	securityv1beta1api "istio.io/api/security/v1beta1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// mtlsConflict explains why a DestinationRule client TLS mode does not work against a destination accepting
+// the given mTLS mode, returning "" when they are compatible; fatal reports whether connections fail
+func mtlsConflict(client networkingapi.ClientTLSSettings_TLSmode, server string) (message string, fatal bool) {
+	strict := server == securityv1beta1api.PeerAuthentication_MutualTLS_STRICT.String()
+	permissive := server == securityv1beta1api.PeerAuthentication_MutualTLS_PERMISSIVE.String()
+	disabled := server == securityv1beta1api.PeerAuthentication_MutualTLS_DISABLE.String()
+	switch client {
+	case networkingapi.ClientTLSSettings_DISABLE:
+		if strict {
+			return "clients send plaintext but the destination requires mTLS; connections are reset", true
+		}
+		if permissive {
+			return "clients send plaintext although the destination accepts mTLS; traffic is not encrypted", false
+		}
+	case networkingapi.ClientTLSSettings_ISTIO_MUTUAL:
+		if disabled {
+			return "clients send Istio mTLS but the destination only accepts plaintext; connections fail", true
+		}
+	case networkingapi.ClientTLSSettings_SIMPLE, networkingapi.ClientTLSSettings_MUTUAL:
+		if strict {
+			return "clients originate TLS with their own certificates but the destination requires Istio mTLS; connections fail", true
+		}
+		if permissive {
+			return "clients originate TLS with their own certificates; the destination sidecar passes it through, so the application must terminate TLS", false
+		}
+	}
+	return "", false
+}
+
+// ValidateMtlsConsistency cross-checks the client TLS mode the DestinationRule for a host sets for clients in a
+// namespace against the mTLS mode its destination workloads accept, resolved from the PeerAuthentications, and
+// flags the combinations that break connections, such as tls.mode DISABLE against a STRICT destination. Workloads
+// without a sidecar only accept plaintext. Port-level settings of the DestinationRule are checked as well.
+func (i *Istio) ValidateMtlsConsistency(ctx context.Context, namespace, host string) (string, error) {
+	target, err := i.resolveHost(ctx, host, namespace)
+	if err != nil {
+		return "", err
+	}
+	result := fmt.Sprintf("mTLS consistency for %s (clients in namespace '%s'):\n\n", target, namespace)
+	serviceNamespace := hostNamespace(target)
+	if serviceNamespace == "" {
+		return result + "[SKIP] The host is not a Kubernetes service; no PeerAuthentication applies to it\n", nil
+	}
+	serviceName := strings.SplitN(target, ".", 2)[0]
+	service, err := i.kubeClient.CoreV1().Services(serviceNamespace).Get(ctx, serviceName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return result + fmt.Sprintf("[SKIP] Service %s not found in namespace %s\n", serviceName, serviceNamespace), nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get service %s: %w", serviceName, explainForbidden(err, "get", "services", serviceNamespace))
+	}
+	mesh, err := i.getMeshConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+	pods, err := i.kubeClient.CoreV1().Pods(serviceNamespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list pods: %w", explainForbidden(err, "list", "pods", serviceNamespace))
+	}
+	peerAuthentications, err := i.listPeerAuthentications(ctx, "", metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list peer authentications: %w", i.explainAPIError(ctx, err, "list", "peerauthentications", ""))
+	}
+	drList, err := i.istioClient.NetworkingV1alpha3().DestinationRules("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list destination rules: %w", explainForbidden(err, "list", "destinationrules", ""))
+	}
+
+	// The mTLS modes the destination accepts, one entry per distinct mode and deciding policy
+	type serverMode struct {
+		mode, source string
+		pods         int
+	}
+	var servers []*serverMode
+	if len(service.Spec.Selector) > 0 {
+		selector := labels.SelectorFromSet(service.Spec.Selector)
+		for _, pod := range pods.Items {
+			if pod.Status.Phase != v1.PodRunning || !selector.Matches(labels.Set(pod.Labels)) {
+				continue
+			}
+			mode, source := securityv1beta1api.PeerAuthentication_MutualTLS_DISABLE.String(), "no sidecar"
+			if hasIstioSidecar(pod) {
+				mode, source = effectiveMtlsMode(peerAuthentications, pod, mesh.RootNamespace)
+			}
+			found := false
+			for _, server := range servers {
+				if server.mode == mode && server.source == source {
+					server.pods++
+					found = true
+				}
+			}
+			if !found {
+				servers = append(servers, &serverMode{mode: mode, source: source, pods: 1})
+			}
+		}
+	}
+	if len(servers) == 0 {
+		return result + fmt.Sprintf("[SKIP] Service %s has no running pods\n", serviceName), nil
+	}
+	result += "Destination mTLS:\n"
+	for _, server := range servers {
+		result += fmt.Sprintf("  - %s (%s): %d pods\n", server.mode, server.source, server.pods)
+	}
+
+	// The DestinationRule with the highest precedence for clients in the namespace sets the client TLS mode
+	rule := effectiveDestinationRule(drList.Items, target, namespace, mesh)
+	type clientMode struct {
+		scope string
+		mode  networkingapi.ClientTLSSettings_TLSmode
+	}
+	var clients []clientMode
+	if rule != nil {
+		if tls := rule.Spec.GetTrafficPolicy().GetTls(); tls != nil {
+			clients = append(clients, clientMode{scope: "all ports", mode: tls.GetMode()})
+		}
+		for _, portPolicy := range rule.Spec.GetTrafficPolicy().GetPortLevelSettings() {
+			if tls := portPolicy.GetTls(); tls != nil {
+				clients = append(clients, clientMode{scope: fmt.Sprintf("port %d", portPolicy.GetPort().GetNumber()), mode: tls.GetMode()})
+			}
+		}
+	}
+	result += "\n"
+	switch {
+	case rule == nil:
+		return result + "[OK] No DestinationRule applies to the host; clients use auto mTLS, which follows the destination's mode\n", nil
+	case len(clients) == 0:
+		return result + fmt.Sprintf("[OK] DestinationRule '%s/%s' sets no TLS mode; clients use auto mTLS, which follows the destination's mode\n", rule.Namespace, rule.Name), nil
+	}
+	result += fmt.Sprintf("Client TLS (DestinationRule '%s/%s'):\n", rule.Namespace, rule.Name)
+	for _, client := range clients {
+		result += fmt.Sprintf("  - %s: %s\n", client.scope, client.mode)
+	}
+
+	result += "\n"
+	conflicts, warnings := 0, 0
+	for _, client := range clients {
+		for _, server := range servers {
+			message, fatal := mtlsConflict(client.mode, server.mode)
+			switch {
+			case message == "":
+				continue
+			case fatal:
+				conflicts++
+				result += fmt.Sprintf("[FAIL] %s: %s against %s (%s): %s\n", client.scope, client.mode, server.mode, server.source, message)
+			default:
+				warnings++
+				result += fmt.Sprintf("[WARNING] %s: %s against %s (%s): %s\n", client.scope, client.mode, server.mode, server.source, message)
+			}
+		}
+	}
+
+	switch {
+	case conflicts > 0:
+		result += fmt.Sprintf("\n[RESULT] %d conflicts between the DestinationRule TLS settings and the destination's mTLS mode\n", conflicts)
+	case warnings > 0:
+		result += fmt.Sprintf("\n[RESULT] No conflicts, %d warnings\n", warnings)
+	default:
+		result += "[OK] The DestinationRule TLS settings match the destination's mTLS mode\n"
+	}
+	return result, nil
+}
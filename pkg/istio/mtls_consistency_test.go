@@ -0,0 +1,55 @@
+package istio
+
+import (
+	"context"
+	"testing"
+)
+
+// TestValidateMtlsConsistency tests that the client TLS mode of DestinationRules is compared with the mTLS mode of the destination pods
+func TestValidateMtlsConsistency(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{
+		"/api/v1/namespaces/bookinfo/services/reviews": `{
+			"apiVersion": "v1",
+			"kind": "Service",
+			"metadata": {"name": "reviews", "namespace": "bookinfo"},
+			"spec": {"selector": {"app": "reviews"}, "ports": [{"name": "http", "port": 9080}]}
+		}`,
+		"/api/v1/namespaces/bookinfo/pods": `{
+			"apiVersion": "v1",
+			"kind": "PodList",
+			"items": [
+				{"metadata": {"name": "reviews-v1-abc", "namespace": "bookinfo", "labels": {"app": "reviews"}}, "spec": {"containers": [{"name": "reviews"}, {"name": "istio-proxy"}]}, "status": {"phase": "Running"}},
+				{"metadata": {"name": "reviews-v2-def", "namespace": "bookinfo", "labels": {"app": "reviews"}}, "spec": {"containers": [{"name": "reviews"}, {"name": "istio-proxy"}]}, "status": {"phase": "Running"}},
+				{"metadata": {"name": "ratings-v1-ghi", "namespace": "bookinfo", "labels": {"app": "ratings"}}, "spec": {"containers": [{"name": "ratings"}]}, "status": {"phase": "Running"}}
+			]
+		}`,
+		"/apis/security.istio.io/v1beta1/peerauthentications": `{
+			"apiVersion": "security.istio.io/v1beta1",
+			"kind": "PeerAuthenticationList",
+			"items": [{"metadata": {"name": "default", "namespace": "bookinfo"}, "spec": {"mtls": {"mode": "STRICT"}}}]
+		}`,
+		"/apis/networking.istio.io/v1alpha3/destinationrules": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "DestinationRuleList",
+			"items": [
+				{"metadata": {"name": "reviews", "namespace": "bookinfo"}, "spec": {"host": "reviews", "trafficPolicy": {"tls": {"mode": "DISABLE"}}}},
+				{"metadata": {"name": "ratings", "namespace": "bookinfo"}, "spec": {"host": "ratings", "trafficPolicy": {"tls": {"mode": "ISTIO_MUTUAL"}}}}
+			]
+		}`,
+	})
+	defer mockServer.Close()
+
+	istio := newTestIstio(t, mockServer.URL)
+	result, err := istio.ValidateMtlsConsistency(context.Background(), "bookinfo", "reviews")
+	if err != nil {
+		t.Fatalf("ValidateMtlsConsistency failed: %v", err)
+	}
+	assertContains(t, result,
+		"mTLS consistency for reviews.bookinfo.svc.cluster.local",
+		"- STRICT (bookinfo/default): 2 pods",
+		"Client TLS (DestinationRule 'bookinfo/reviews'):",
+		"[FAIL] all ports: DISABLE against STRICT (bookinfo/default): clients send plaintext but the destination requires mTLS",
+		"[RESULT] 1 conflicts",
+	)
+	assertNotContains(t, result, "ratings", "[OK]")
+}
@@ -0,0 +1,151 @@
+package istio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
+)
+
+// normalizedSpec returns the spec of a resource with references to its own namespace replaced by a
+// placeholder, so that equivalent resources in different namespaces compare equal
+// (e.g. host 'reviews.staging.svc.cluster.local' vs 'reviews.prod.svc.cluster.local').
+func normalizedSpec(obj istioObject) (map[string]interface{}, error) {
+	raw, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	var resource struct {
+		Spec json.RawMessage `json:"spec"`
+	}
+	if err := json.Unmarshal(raw, &resource); err != nil {
+		return nil, err
+	}
+	if len(resource.Spec) == 0 {
+		return map[string]interface{}{}, nil
+	}
+
+	namespace := obj.GetNamespace()
+	spec := strings.NewReplacer(
+		"."+namespace+".", ".<namespace>.",
+		"/ns/"+namespace+"/", "/ns/<namespace>/",
+		`"`+namespace+`/`, `"<namespace>/`,
+		`"`+namespace+`"`, `"<namespace>"`,
+	).Replace(string(resource.Spec))
+
+	var normalized map[string]interface{}
+	if err := json.Unmarshal([]byte(spec), &normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+// specDifferences returns the sorted top-level spec fields whose values differ
+func specDifferences(a, b map[string]interface{}) []string {
+	fields := make(map[string]bool)
+	for field := range a {
+		fields[field] = true
+	}
+	for field := range b {
+		fields[field] = true
+	}
+	var differences []string
+	for field := range fields {
+		if !reflect.DeepEqual(a[field], b[field]) {
+			differences = append(differences, field)
+		}
+	}
+	sort.Strings(differences)
+	return differences
+}
+
+// listResourcesByName lists resources of a kind in a namespace keyed by name.
+// A nil map is returned when the kind's CRD is not installed.
+func (i *Istio) listResourcesByName(ctx context.Context, rk resourceKind, namespace string) (map[string]istioObject, error) {
+	objects, err := rk.list(ctx, i, namespace)
+	if apierrors.IsNotFound(err) {
+		klog.Warningf("Skipping %s: resource type not available in the cluster", rk.plural)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s in namespace %s: %w", rk.plural, namespace, explainForbidden(err, "list", rk.plural, namespace))
+	}
+	byName := make(map[string]istioObject, len(objects))
+	for _, obj := range objects {
+		byName[obj.GetName()] = obj
+	}
+	return byName, nil
+}
+
+// CompareNamespaceConfig reports Istio configuration drift between two namespaces: resources present
+// in only one of them and same-named resources whose specs differ
+func (i *Istio) CompareNamespaceConfig(ctx context.Context, namespaceA, namespaceB string) (string, error) {
+	result := fmt.Sprintf("Comparing Istio configuration between namespaces '%s' and '%s':\n\n", namespaceA, namespaceB)
+	differences := 0
+
+	for _, kind := range SupportedResourceKinds() {
+		rk := resourceKinds[strings.ToLower(kind)]
+		resourcesA, err := i.listResourcesByName(ctx, rk, namespaceA)
+		if err != nil {
+			return "", err
+		}
+		resourcesB, err := i.listResourcesByName(ctx, rk, namespaceB)
+		if err != nil {
+			return "", err
+		}
+
+		names := make(map[string]bool)
+		for name := range resourcesA {
+			names[name] = true
+		}
+		for name := range resourcesB {
+			names[name] = true
+		}
+		sortedNames := make([]string, 0, len(names))
+		for name := range names {
+			sortedNames = append(sortedNames, name)
+		}
+		sort.Strings(sortedNames)
+
+		var lines []string
+		for _, name := range sortedNames {
+			objA, inA := resourcesA[name]
+			objB, inB := resourcesB[name]
+			switch {
+			case !inB:
+				lines = append(lines, fmt.Sprintf("- %s: only in '%s'", name, namespaceA))
+			case !inA:
+				lines = append(lines, fmt.Sprintf("- %s: only in '%s'", name, namespaceB))
+			default:
+				specA, err := normalizedSpec(objA)
+				if err != nil {
+					return "", fmt.Errorf("failed to decode %s %s: %w", kind, name, err)
+				}
+				specB, err := normalizedSpec(objB)
+				if err != nil {
+					return "", fmt.Errorf("failed to decode %s %s: %w", kind, name, err)
+				}
+				if fields := specDifferences(specA, specB); len(fields) > 0 {
+					lines = append(lines, fmt.Sprintf("- %s: spec differs (fields: %s)", name, strings.Join(fields, ", ")))
+				}
+			}
+		}
+
+		if len(lines) > 0 {
+			result += fmt.Sprintf("%s:\n%s\n\n", kind, strings.Join(lines, "\n"))
+			differences += len(lines)
+		}
+	}
+
+	if differences == 0 {
+		result += "[OK] No configuration drift found\n"
+	} else {
+		result += fmt.Sprintf("[RESULT] %d differences found\n", differences)
+	}
+	return result, nil
+}
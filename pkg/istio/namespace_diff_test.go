@@ -0,0 +1,83 @@
+package istio
+
+import (
+	"context"
+	"testing"
+)
+
+// TestCompareNamespaceConfig tests reporting of Istio configuration drift between namespaces
+func TestCompareNamespaceConfig(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{
+		"/apis/networking.istio.io/v1alpha3/namespaces/staging/virtualservices": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "VirtualServiceList",
+			"items": [
+				{
+					"metadata": {"name": "reviews", "namespace": "staging", "resourceVersion": "100"},
+					"spec": {"hosts": ["reviews.staging.svc.cluster.local"], "http": [{"route": [{"destination": {"host": "reviews"}}]}]}
+				}
+			]
+		}`,
+		"/apis/networking.istio.io/v1alpha3/namespaces/prod/virtualservices": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "VirtualServiceList",
+			"items": [
+				{
+					"metadata": {"name": "reviews", "namespace": "prod", "resourceVersion": "200"},
+					"spec": {"hosts": ["reviews.prod.svc.cluster.local"], "http": [{"route": [{"destination": {"host": "reviews"}}]}]}
+				}
+			]
+		}`,
+		"/apis/networking.istio.io/v1alpha3/namespaces/staging/destinationrules": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "DestinationRuleList",
+			"items": [
+				{
+					"metadata": {"name": "reviews", "namespace": "staging"},
+					"spec": {"host": "reviews", "trafficPolicy": {"connectionPool": {"tcp": {"maxConnections": 100}}}}
+				},
+				{
+					"metadata": {"name": "ratings", "namespace": "staging"},
+					"spec": {"host": "ratings"}
+				}
+			]
+		}`,
+		"/apis/networking.istio.io/v1alpha3/namespaces/prod/destinationrules": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "DestinationRuleList",
+			"items": [
+				{
+					"metadata": {"name": "reviews", "namespace": "prod"},
+					"spec": {"host": "reviews", "trafficPolicy": {"connectionPool": {"tcp": {"maxConnections": 10}}}}
+				}
+			]
+		}`,
+	})
+	defer mockServer.Close()
+
+	istio := newTestIstio(t, mockServer.URL)
+	ctx := context.Background()
+
+	t.Run("reports drift", func(t *testing.T) {
+		result, err := istio.CompareNamespaceConfig(ctx, "staging", "prod")
+		if err != nil {
+			t.Fatalf("Failed to compare namespaces: %v", err)
+		}
+		assertContains(t, result,
+			"DestinationRule:",
+			"- ratings: only in 'staging'",
+			"- reviews: spec differs (fields: trafficPolicy)",
+			"[RESULT] 2 differences found",
+		)
+		// Hosts differ only by the namespace, which is normalized away
+		assertNotContains(t, result, "VirtualService:")
+	})
+
+	t.Run("identical namespaces", func(t *testing.T) {
+		result, err := istio.CompareNamespaceConfig(ctx, "prod", "prod")
+		if err != nil {
+			t.Fatalf("Failed to compare namespaces: %v", err)
+		}
+		assertContains(t, result, "[OK] No configuration drift found")
+	})
+}
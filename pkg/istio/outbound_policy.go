@@ -0,0 +1,100 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+
+	networkingv1alpha3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// outboundPolicySource is an outbound traffic policy mode and where it comes from
+type outboundPolicySource struct {
+	mode   string
+	source string
+}
+
+// namespaceDefaultSidecar returns the Sidecar without workload selector in a namespace, which applies to all its workloads
+func namespaceDefaultSidecar(sidecars []*networkingv1alpha3.Sidecar) *networkingv1alpha3.Sidecar {
+	for _, sidecar := range sidecars {
+		if len(sidecar.Spec.GetWorkloadSelector().GetLabels()) == 0 {
+			return sidecar
+		}
+	}
+	return nil
+}
+
+// GetEffectiveOutboundPolicy reports whether the workloads of a namespace may reach any external host
+// (ALLOW_ANY) or only registered services (REGISTRY_ONLY). Sidecars with a workload selector override
+// the namespace Sidecar, which overrides the root namespace Sidecar, which overrides the mesh config.
+func (i *Istio) GetEffectiveOutboundPolicy(ctx context.Context, namespace string) (string, error) {
+	mesh, err := i.getMeshConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+	effective := outboundPolicySource{mode: mesh.OutboundTrafficPolicy.Mode, source: "mesh config"}
+
+	result := fmt.Sprintf("Effective outbound traffic policy for namespace '%s':\n\n", namespace)
+	result += fmt.Sprintf("Mesh default: %s (mesh config)\n", effective.mode)
+
+	if mesh.RootNamespace != namespace {
+		rootSidecars, err := i.istioClient.NetworkingV1alpha3().Sidecars(mesh.RootNamespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return "", fmt.Errorf("failed to list sidecars: %w", explainForbidden(err, "list", "sidecars", mesh.RootNamespace))
+		}
+		if sidecar := namespaceDefaultSidecar(rootSidecars.Items); sidecar != nil && sidecar.Spec.OutboundTrafficPolicy != nil {
+			effective = outboundPolicySource{
+				mode:   sidecar.Spec.OutboundTrafficPolicy.GetMode().String(),
+				source: fmt.Sprintf("Sidecar '%s' in root namespace '%s'", sidecar.Name, mesh.RootNamespace),
+			}
+			result += fmt.Sprintf("Root namespace override: %s (%s)\n", effective.mode, effective.source)
+		}
+	}
+
+	sidecarList, err := i.istioClient.NetworkingV1alpha3().Sidecars(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list sidecars: %w", explainForbidden(err, "list", "sidecars", namespace))
+	}
+	if sidecar := namespaceDefaultSidecar(sidecarList.Items); sidecar != nil && sidecar.Spec.OutboundTrafficPolicy != nil {
+		effective = outboundPolicySource{
+			mode:   sidecar.Spec.OutboundTrafficPolicy.GetMode().String(),
+			source: fmt.Sprintf("Sidecar '%s'", sidecar.Name),
+		}
+		result += fmt.Sprintf("Namespace override: %s (%s)\n", effective.mode, effective.source)
+	}
+	result += fmt.Sprintf("\nNamespace default: %s (from %s)\n", effective.mode, effective.source)
+
+	pods, err := i.kubeClient.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list pods: %w", explainForbidden(err, "list", "pods", namespace))
+	}
+
+	result += "\nWorkloads:\n"
+	workloads := 0
+	for _, pod := range pods.Items {
+		if !hasIstioSidecar(pod) {
+			continue
+		}
+		workloads++
+		policy := effective
+		for _, sidecar := range sidecarList.Items {
+			selector := sidecar.Spec.GetWorkloadSelector().GetLabels()
+			if len(selector) == 0 || !labels.SelectorFromSet(selector).Matches(labels.Set(pod.Labels)) {
+				continue
+			}
+			if sidecar.Spec.OutboundTrafficPolicy != nil {
+				policy = outboundPolicySource{
+					mode:   sidecar.Spec.OutboundTrafficPolicy.GetMode().String(),
+					source: fmt.Sprintf("Sidecar '%s'", sidecar.Name),
+				}
+			}
+			break
+		}
+		result += fmt.Sprintf("- %s: %s (from %s)\n", pod.Name, policy.mode, policy.source)
+	}
+	if workloads == 0 {
+		result += "No workloads with an Istio sidecar found\n"
+	}
+	return result, nil
+}
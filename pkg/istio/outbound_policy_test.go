@@ -0,0 +1,57 @@
+package istio
+
+import (
+	"context"
+	"testing"
+)
+
+// TestGetEffectiveOutboundPolicy tests resolution of the outbound traffic policy from mesh config and Sidecars
+func TestGetEffectiveOutboundPolicy(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{
+		"/api/v1/namespaces/istio-system/configmaps/istio": `{
+			"apiVersion": "v1",
+			"kind": "ConfigMap",
+			"metadata": {"name": "istio", "namespace": "istio-system"},
+			"data": {"mesh": "outboundTrafficPolicy:\n  mode: ALLOW_ANY\n"}
+		}`,
+		"/apis/networking.istio.io/v1alpha3/namespaces/istio-system/sidecars": `{"apiVersion": "networking.istio.io/v1alpha3", "kind": "SidecarList", "items": []}`,
+		"/apis/networking.istio.io/v1alpha3/namespaces/payments/sidecars": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "SidecarList",
+			"items": [
+				{
+					"metadata": {"name": "default", "namespace": "payments"},
+					"spec": {"outboundTrafficPolicy": {"mode": "REGISTRY_ONLY"}}
+				},
+				{
+					"metadata": {"name": "gateway-client", "namespace": "payments"},
+					"spec": {"workloadSelector": {"labels": {"app": "stripe-client"}}, "outboundTrafficPolicy": {"mode": "ALLOW_ANY"}}
+				}
+			]
+		}`,
+		"/api/v1/namespaces/payments/pods": `{
+			"apiVersion": "v1",
+			"kind": "PodList",
+			"items": [
+				{"metadata": {"name": "ledger-7d9f", "labels": {"app": "ledger"}}, "spec": {"containers": [{"name": "app"}, {"name": "istio-proxy"}]}},
+				{"metadata": {"name": "stripe-client-5c8b", "labels": {"app": "stripe-client"}}, "spec": {"containers": [{"name": "app"}, {"name": "istio-proxy"}]}},
+				{"metadata": {"name": "batch-job", "labels": {"app": "batch"}}, "spec": {"containers": [{"name": "app"}]}}
+			]
+		}`,
+	})
+	defer mockServer.Close()
+
+	istio := newTestIstio(t, mockServer.URL)
+
+	result, err := istio.GetEffectiveOutboundPolicy(context.Background(), "payments")
+	if err != nil {
+		t.Fatalf("Failed to get effective outbound policy: %v", err)
+	}
+	assertContains(t, result,
+		"Mesh default: ALLOW_ANY (mesh config)",
+		"Namespace default: REGISTRY_ONLY (from Sidecar 'default')",
+		"- ledger-7d9f: REGISTRY_ONLY (from Sidecar 'default')",
+		"- stripe-client-5c8b: ALLOW_ANY (from Sidecar 'gateway-client')",
+	)
+	assertNotContains(t, result, "batch-job")
+}
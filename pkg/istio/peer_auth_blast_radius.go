@@ -0,0 +1,157 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// podWorkloadName returns the workload name Istio reports in its metrics for a pod: the name of the Deployment
+// of a ReplicaSet pod, the name of any other controller, or the pod name
+func podWorkloadName(pod v1.Pod) string {
+	if len(pod.OwnerReferences) == 0 {
+		return pod.Name
+	}
+	owner := pod.OwnerReferences[0]
+	if hash := pod.Labels["pod-template-hash"]; owner.Kind == "ReplicaSet" && hash != "" {
+		return strings.TrimSuffix(owner.Name, "-"+hash)
+	}
+	return owner.Name
+}
+
+// inboundSecurity is the inbound request rate of a workload split by whether requests used mutual TLS, with the
+// clients sending plaintext
+type inboundSecurity struct {
+	mutualTLS float64
+	plaintext float64
+	clients   []string
+}
+
+// inboundSecurityByWorkload reads the inbound request rates of the workloads of a namespace over the last 5
+// minutes by the connection_security_policy the destination proxies report
+func (i *Istio) inboundSecurityByWorkload(ctx context.Context, namespace string) (map[string]*inboundSecurity, error) {
+	samples, err := i.queryPrometheus(ctx, fmt.Sprintf(`sum by (destination_workload, source_workload, source_workload_namespace, connection_security_policy) (rate(istio_requests_total{reporter="destination",destination_workload_namespace=%q}[%s]))`, namespace, topServicesWindow))
+	if err != nil {
+		return nil, err
+	}
+	security := make(map[string]*inboundSecurity)
+	for _, sample := range samples {
+		if sample.value == 0 {
+			continue
+		}
+		workload := sample.labels["destination_workload"]
+		if security[workload] == nil {
+			security[workload] = &inboundSecurity{}
+		}
+		if sample.labels["connection_security_policy"] == "mutual_tls" {
+			security[workload].mutualTLS += sample.value
+			continue
+		}
+		security[workload].plaintext += sample.value
+		client := sample.labels["source_workload_namespace"] + "/" + sample.labels["source_workload"]
+		if !slices.Contains(security[workload].clients, client) {
+			security[workload].clients = append(security[workload].clients, client)
+		}
+	}
+	return security, nil
+}
+
+// GetPeerAuthBlastRadius reports what a change to a PeerAuthentication affects: the mesh workloads it selects (all
+// workloads of its namespace without a selector, or of the mesh in the root namespace), the mTLS mode each one
+// currently has and the policy deciding it, and, when Prometheus is configured, whether their inbound requests of
+// the last 5 minutes used mutual TLS. Plaintext clients are rejected once the mode becomes STRICT.
+func (i *Istio) GetPeerAuthBlastRadius(ctx context.Context, namespace, name string) (string, error) {
+	policy, err := i.getPeerAuthentication(ctx, namespace, name)
+	if err != nil {
+		return "", fmt.Errorf("failed to get peer authentication %s: %w", name, i.explainAPIError(ctx, err, "get", "peerauthentications", namespace))
+	}
+	mesh, err := i.getMeshConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+	policies, err := i.listPeerAuthentications(ctx, "", metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list peer authentications: %w", explainForbidden(err, "list", "peerauthentications", ""))
+	}
+	pods, err := i.listMeshPods(ctx)
+	if err != nil {
+		return "", err
+	}
+	selector := policy.Spec.GetSelector().GetMatchLabels()
+	selected := selectedWorkloads(pods, policy.Namespace, selector, mesh.RootNamespace)
+
+	mode := peerAuthenticationMode(policy)
+	if mode == "" {
+		mode = "UNSET"
+	}
+	result := fmt.Sprintf("Blast radius of PeerAuthentication '%s/%s':\n\n", policy.Namespace, policy.Name)
+	result += fmt.Sprintf("Policy scope: %s, mode %s\n", policyScope(policy.Namespace, selector, mesh.RootNamespace), mode)
+	if len(selector) > 0 {
+		result += fmt.Sprintf("Selector: %v\n", selector)
+	}
+
+	// Inbound request security comes from Prometheus, per namespace of the selected workloads
+	security := make(map[string]map[string]*inboundSecurity)
+	var metricsErr error
+	if i.prometheusURL != "" {
+		for _, pod := range selected {
+			if _, ok := security[pod.Namespace]; ok || metricsErr != nil {
+				continue
+			}
+			security[pod.Namespace], metricsErr = i.inboundSecurityByWorkload(ctx, pod.Namespace)
+		}
+	}
+
+	result += fmt.Sprintf("\nSelected workloads (%d):\n", len(selected))
+	affected, plaintextWorkloads := 0, 0
+	self := policy.Namespace + "/" + policy.Name
+	for _, pod := range selected {
+		current, source := effectiveMtlsMode(policies, pod, mesh.RootNamespace)
+		result += fmt.Sprintf("- %s (pod %s): mTLS %s", workloadName(pod), pod.Name, current)
+		switch {
+		case source == self:
+			affected++
+			result += " (set by this policy)\n"
+		case peerAuthenticationMode(policy) == "":
+			affected++
+			result += fmt.Sprintf(" (inherited from %s)\n", source)
+		default:
+			result += fmt.Sprintf(" (set by %s, which overrides this policy; changing this policy does not affect the workload)\n", source)
+			continue
+		}
+
+		inbound := security[pod.Namespace][podWorkloadName(pod)]
+		switch {
+		case i.prometheusURL == "" || metricsErr != nil:
+		case inbound == nil:
+			result += fmt.Sprintf("    No inbound requests in the last %s\n", topServicesWindow)
+		case inbound.plaintext > 0:
+			plaintextWorkloads++
+			result += fmt.Sprintf("    Inbound requests (last %s): %.2f/s mutual TLS, %.2f/s plaintext\n", topServicesWindow, inbound.mutualTLS, inbound.plaintext)
+			result += fmt.Sprintf("    [WARNING] Plaintext clients, rejected in STRICT mode: %s\n", strings.Join(inbound.clients, ", "))
+		default:
+			result += fmt.Sprintf("    Inbound requests (last %s): %.2f/s, all mutual TLS\n", topServicesWindow, inbound.mutualTLS)
+		}
+	}
+
+	switch {
+	case i.prometheusURL == "":
+		result += "\nPrometheus is not configured: start the server with --prometheus-url to report whether current inbound connections use mutual TLS\n"
+	case metricsErr != nil:
+		result += fmt.Sprintf("\n[WARNING] Could not read inbound request security from Prometheus: %v\n", metricsErr)
+	}
+	if len(selected) == 0 {
+		result += "\n[RESULT] The policy selects no running mesh workload\n"
+	} else {
+		result += fmt.Sprintf("\n[RESULT] %d of %d selected workloads are affected by a change to the policy", affected, len(selected))
+		if plaintextWorkloads > 0 {
+			result += fmt.Sprintf("; %d receive plaintext requests that STRICT mode would reject", plaintextWorkloads)
+		}
+		result += "\n"
+	}
+	return result, nil
+}
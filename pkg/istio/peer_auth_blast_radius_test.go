@@ -0,0 +1,79 @@
+package istio
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestGetPeerAuthBlastRadius tests reporting of the workloads and inbound traffic affected by a PeerAuthentication
+func TestGetPeerAuthBlastRadius(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{
+		"/apis/security.istio.io/v1beta1/namespaces/bookinfo/peerauthentications/reviews": `{
+			"apiVersion": "security.istio.io/v1beta1",
+			"kind": "PeerAuthentication",
+			"metadata": {"name": "reviews", "namespace": "bookinfo"},
+			"spec": {"selector": {"matchLabels": {"app": "reviews"}}, "mtls": {"mode": "PERMISSIVE"}}
+		}`,
+		"/apis/security.istio.io/v1beta1/peerauthentications": `{
+			"apiVersion": "security.istio.io/v1beta1",
+			"kind": "PeerAuthenticationList",
+			"items": [
+				{"metadata": {"name": "default", "namespace": "bookinfo"}, "spec": {"mtls": {"mode": "STRICT"}}},
+				{"metadata": {"name": "reviews", "namespace": "bookinfo"}, "spec": {"selector": {"matchLabels": {"app": "reviews"}}, "mtls": {"mode": "PERMISSIVE"}}}
+			]
+		}`,
+		"/api/v1/pods": `{
+			"apiVersion": "v1",
+			"kind": "PodList",
+			"items": [
+				{"metadata": {"name": "reviews-v1-7d4f9-abc", "namespace": "bookinfo", "labels": {"app": "reviews", "pod-template-hash": "7d4f9"},
+					"ownerReferences": [{"apiVersion": "apps/v1", "kind": "ReplicaSet", "name": "reviews-v1-7d4f9", "uid": "1"}]},
+					"spec": {"containers": [{"name": "reviews"}, {"name": "istio-proxy"}]}, "status": {"phase": "Running"}},
+				{"metadata": {"name": "ratings-v1-def", "namespace": "bookinfo", "labels": {"app": "ratings"}}, "spec": {"containers": [{"name": "ratings"}, {"name": "istio-proxy"}]}, "status": {"phase": "Running"}},
+				{"metadata": {"name": "reviews-ghi", "namespace": "default", "labels": {"app": "reviews"}}, "spec": {"containers": [{"name": "reviews"}, {"name": "istio-proxy"}]}, "status": {"phase": "Running"}}
+			]
+		}`,
+	})
+	defer mockServer.Close()
+
+	t.Run("selected workloads", func(t *testing.T) {
+		istio := newTestIstio(t, mockServer.URL)
+		result, err := istio.GetPeerAuthBlastRadius(context.Background(), "bookinfo", "reviews")
+		if err != nil {
+			t.Fatalf("GetPeerAuthBlastRadius failed: %v", err)
+		}
+		assertContains(t, result,
+			"Policy scope: workload, mode PERMISSIVE",
+			"Selected workloads (1):",
+			"- bookinfo/reviews (pod reviews-v1-7d4f9-abc): mTLS PERMISSIVE (set by this policy)",
+			"Prometheus is not configured",
+			"[RESULT] 1 of 1 selected workloads are affected by a change to the policy",
+		)
+		assertNotContains(t, result, "ratings", "default/reviews")
+	})
+
+	t.Run("inbound mTLS", func(t *testing.T) {
+		prometheus := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"status": "success", "data": {"resultType": "vector", "result": [
+				{"metric": {"destination_workload": "reviews-v1", "source_workload": "productpage-v1", "source_workload_namespace": "bookinfo", "connection_security_policy": "mutual_tls"}, "value": [1700000000, "4"]},
+				{"metric": {"destination_workload": "reviews-v1", "source_workload": "unknown", "source_workload_namespace": "unknown", "connection_security_policy": "none"}, "value": [1700000000, "0.5"]}
+			]}}`))
+		}))
+		defer prometheus.Close()
+
+		istio := newTestIstio(t, mockServer.URL)
+		istio.SetPrometheusURL(prometheus.URL)
+		result, err := istio.GetPeerAuthBlastRadius(context.Background(), "bookinfo", "reviews")
+		if err != nil {
+			t.Fatalf("GetPeerAuthBlastRadius failed: %v", err)
+		}
+		assertContains(t, result,
+			"Inbound requests (last 5m): 4.00/s mutual TLS, 0.50/s plaintext",
+			"[WARNING] Plaintext clients, rejected in STRICT mode: unknown/unknown",
+			"1 receive plaintext requests that STRICT mode would reject",
+		)
+	})
+}
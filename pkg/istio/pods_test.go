@@ -0,0 +1,156 @@
+package istio
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+// TestGetPodsByServices tests finding pods for several services with a single pod List
+func TestGetPodsByServices(t *testing.T) {
+	var podLists atomic.Int32
+	handler := mockAPIHandler(map[string]string{
+		"/api/v1/namespaces/bookinfo/services": `{
+			"apiVersion": "v1",
+			"kind": "ServiceList",
+			"items": [
+				{"metadata": {"name": "reviews", "namespace": "bookinfo"}, "spec": {"selector": {"app": "reviews"}}},
+				{"metadata": {"name": "ratings", "namespace": "bookinfo"}, "spec": {"selector": {"app": "ratings"}}}
+			]
+		}`,
+		"/api/v1/namespaces/bookinfo/pods": `{
+			"apiVersion": "v1",
+			"kind": "PodList",
+			"items": [
+				{
+					"metadata": {"name": "reviews-v1-abc", "labels": {"app": "reviews", "version": "v1"}},
+					"spec": {"containers": [{"name": "reviews"}, {"name": "istio-proxy"}]},
+					"status": {"phase": "Running", "podIP": "10.0.0.1"}
+				},
+				{
+					"metadata": {"name": "ratings-v1-def", "labels": {"app": "ratings", "version": "v1"}},
+					"spec": {"containers": [{"name": "ratings"}]},
+					"status": {"phase": "Running", "podIP": "10.0.0.2"}
+				},
+				{
+					"metadata": {"name": "details-v1-ghi", "labels": {"app": "details"}},
+					"spec": {"containers": [{"name": "details"}]},
+					"status": {"phase": "Running", "podIP": "10.0.0.3"}
+				}
+			]
+		}`,
+	})
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/namespaces/bookinfo/pods" {
+			podLists.Add(1)
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	defer mockServer.Close()
+
+	istio := newTestIstio(t, mockServer.URL)
+
+	result, err := istio.GetPodsByServices(context.Background(), "bookinfo", []string{"reviews", "ratings", "missing"})
+	if err != nil {
+		t.Fatalf("Failed to get pods by services: %v", err)
+	}
+	assertContains(t, result,
+		"Pods backing service 'reviews' in namespace 'bookinfo'",
+		"reviews-v1-abc",
+		"Pods backing service 'ratings' in namespace 'bookinfo'",
+		"ratings-v1-def",
+		"[ERROR] Service 'missing' not found in namespace 'bookinfo'",
+	)
+	assertNotContains(t, result, "details-v1-ghi")
+	if count := podLists.Load(); count != 1 {
+		t.Errorf("Expected pods to be listed once, got %d requests", count)
+	}
+}
+
+// TestGetPodsByServiceInitContainers tests that a failed istio-init container is reported
+func TestGetPodsByServiceInitContainers(t *testing.T) {
+	server := newMockAPIServer(map[string]string{
+		"/api/v1/namespaces/bookinfo/services/reviews": `{
+			"apiVersion": "v1",
+			"kind": "Service",
+			"metadata": {"name": "reviews", "namespace": "bookinfo"},
+			"spec": {"selector": {"app": "reviews"}}
+		}`,
+		"/api/v1/namespaces/bookinfo/pods": `{
+			"apiVersion": "v1",
+			"kind": "PodList",
+			"items": [{
+				"metadata": {"name": "reviews-v1-abc", "labels": {"app": "reviews"}},
+				"spec": {"nodeName": "node-1", "initContainers": [{"name": "istio-init"}], "containers": [{"name": "reviews"}, {"name": "istio-proxy"}]},
+				"status": {
+					"phase": "Pending",
+					"initContainerStatuses": [{
+						"name": "istio-init",
+						"restartCount": 4,
+						"state": {"waiting": {"reason": "CrashLoopBackOff"}},
+						"lastState": {"terminated": {"exitCode": 1, "reason": "Error", "message": "iptables-restore: permission denied"}}
+					}]
+				}
+			}]
+		}`,
+	})
+	defer server.Close()
+	istio := newTestIstio(t, server.URL)
+
+	result, err := istio.GetPodsByService(context.Background(), "bookinfo", "reviews")
+	if err != nil {
+		t.Fatalf("GetPodsByService failed: %v", err)
+	}
+	assertContains(t, result, "reviews-v1-abc (Status: Init:CrashLoopBackOff)")
+	assertNotContains(t, result, "Init containers:")
+
+	result, err = istio.GetPodsByService(context.Background(), "bookinfo", "reviews", WithInitContainers(true))
+	if err != nil {
+		t.Fatalf("GetPodsByService failed: %v", err)
+	}
+	assertContains(t, result,
+		"- istio-init: Waiting: CrashLoopBackOff (restarts: 4)",
+		"Last failure: iptables-restore: permission denied",
+		"[ERROR] istio-init failed, so the pod cannot start",
+		"node 'node-1'",
+	)
+}
+
+// TestGetPodsByServiceInitContainersStarting tests that an istio-init container that is still being created is not
+// reported as failed
+func TestGetPodsByServiceInitContainersStarting(t *testing.T) {
+	server := newMockAPIServer(map[string]string{
+		"/api/v1/namespaces/bookinfo/services/reviews": `{
+			"apiVersion": "v1",
+			"kind": "Service",
+			"metadata": {"name": "reviews", "namespace": "bookinfo"},
+			"spec": {"selector": {"app": "reviews"}}
+		}`,
+		"/api/v1/namespaces/bookinfo/pods": `{
+			"apiVersion": "v1",
+			"kind": "PodList",
+			"items": [{
+				"metadata": {"name": "reviews-v1-abc", "labels": {"app": "reviews"}},
+				"spec": {"nodeName": "node-1", "initContainers": [{"name": "istio-init"}], "containers": [{"name": "reviews"}, {"name": "istio-proxy"}]},
+				"status": {
+					"phase": "Pending",
+					"initContainerStatuses": [{"name": "istio-init", "state": {"waiting": {"reason": "ContainerCreating"}}}]
+				}
+			}]
+		}`,
+	})
+	defer server.Close()
+	istio := newTestIstio(t, server.URL)
+
+	result, err := istio.GetPodsByService(context.Background(), "bookinfo", "reviews", WithInitContainers(true))
+	if err != nil {
+		t.Fatalf("GetPodsByService failed: %v", err)
+	}
+	assertContains(t, result,
+		"reviews-v1-abc (Status: Pending)",
+		"- istio-init: Waiting: ContainerCreating (restarts: 0)",
+	)
+	assertNotContains(t, result, "Init:ContainerCreating", "[ERROR]")
+}
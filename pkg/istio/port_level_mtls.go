@@ -0,0 +1,130 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// declaredContainerPorts returns the container ports the application containers of the pods declare, ignoring the
+// istio-proxy sidecar, whose ports are never targeted by port-level mTLS
+func declaredContainerPorts(pods []v1.Pod) map[uint32]bool {
+	ports := make(map[uint32]bool)
+	for _, pod := range pods {
+		for _, container := range pod.Spec.Containers {
+			if container.Name == "istio-proxy" {
+				continue
+			}
+			for _, port := range container.Ports {
+				ports[uint32(port.ContainerPort)] = true
+			}
+		}
+	}
+	return ports
+}
+
+// servicePortTargets explains, for the ports of the given services that differ from the container port they forward
+// to, which container port should be used instead
+func servicePortTargets(services []v1.Service) map[uint32]string {
+	targets := make(map[uint32]string)
+	for _, svc := range services {
+		for _, port := range svc.Spec.Ports {
+			if target := port.TargetPort.String(); target != "0" && target != fmt.Sprint(port.Port) {
+				targets[uint32(port.Port)] = fmt.Sprintf("it is a port of service '%s', which targets container port %s", svc.Name, target)
+			}
+		}
+	}
+	return targets
+}
+
+// ValidatePortLevelMtls cross-references the portLevelMtls entries of the PeerAuthentications in a namespace with
+// the container ports the selected workloads declare, flagging entries for ports no workload exposes, which Istio
+// silently ignores. Port-level settings key on container ports, not service ports.
+func (i *Istio) ValidatePortLevelMtls(ctx context.Context, namespace string) (string, error) {
+	policies, err := i.listPeerAuthentications(ctx, namespace, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list peer authentications: %w", explainForbidden(err, "list", "peerauthentications", namespace))
+	}
+	pods, err := i.kubeClient.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list pods: %w", explainForbidden(err, "list", "pods", namespace))
+	}
+	services, err := i.kubeClient.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list services: %w", explainForbidden(err, "list", "services", namespace))
+	}
+	serviceTargets := servicePortTargets(services.Items)
+
+	result := fmt.Sprintf("Port-level mTLS of the Peer Authentications in namespace '%s':\n\n", namespace)
+	checked, flagged, unverified := 0, 0, 0
+	for _, pa := range policies {
+		portLevel := pa.Spec.GetPortLevelMtls()
+		if len(portLevel) == 0 {
+			continue
+		}
+		ports := make([]uint32, 0, len(portLevel))
+		for port := range portLevel {
+			ports = append(ports, port)
+		}
+		sort.Slice(ports, func(a, b int) bool { return ports[a] < ports[b] })
+
+		selector := pa.Spec.GetSelector().GetMatchLabels()
+		if len(selector) == 0 {
+			checked += len(ports)
+			flagged += len(ports)
+			result += fmt.Sprintf("PeerAuthentication '%s':\n  [WARNING] portLevelMtls is ignored without a selector; Istio only applies it to workload-specific policies\n\n", pa.Name)
+			continue
+		}
+
+		matcher := labels.SelectorFromSet(selector)
+		var selected []v1.Pod
+		for _, pod := range pods.Items {
+			if matcher.Matches(labels.Set(pod.Labels)) {
+				selected = append(selected, pod)
+			}
+		}
+		declared := declaredContainerPorts(selected)
+		var lines []string
+		switch {
+		case len(selected) == 0:
+			unverified += len(ports)
+			lines = append(lines, fmt.Sprintf("  [WARNING] No pod matches selector %s; the port-level entries cannot be verified", matcher))
+		case len(declared) == 0:
+			unverified += len(ports)
+			lines = append(lines, fmt.Sprintf("  [WARNING] The %d selected pods declare no container ports; the port-level entries cannot be verified", len(selected)))
+		default:
+			for _, port := range ports {
+				checked++
+				mode := portLevel[port].GetMode()
+				if declared[port] {
+					lines = append(lines, fmt.Sprintf("  [OK] Port %d (%s): declared by the selected workload", port, mode))
+					continue
+				}
+				flagged++
+				message := fmt.Sprintf("  [WARNING] Port %d (%s): no container of the selected pods exposes this port, so the entry does nothing", port, mode)
+				if target, ok := serviceTargets[port]; ok {
+					message += "; " + target
+				}
+				lines = append(lines, message)
+			}
+		}
+		result += fmt.Sprintf("PeerAuthentication '%s' (selector %s):\n%s\n\n", pa.Name, matcher, strings.Join(lines, "\n"))
+	}
+
+	switch {
+	case checked == 0 && unverified == 0:
+		result += "No Peer Authentication sets port-level mTLS\n"
+	case checked == 0:
+		result += fmt.Sprintf("[RESULT] None of the %d port-level entries could be verified\n", unverified)
+	case flagged == 0:
+		result += fmt.Sprintf("[RESULT] All %d checked port-level entries match declared container ports\n", checked)
+	default:
+		result += fmt.Sprintf("[RESULT] %d of %d port-level entries reference ports the workloads don't expose or are ignored\n", flagged, checked)
+	}
+	return result, nil
+}
@@ -0,0 +1,64 @@
+package istio
+
+import (
+	"context"
+	"testing"
+)
+
+// TestValidatePortLevelMtls tests that port-level mTLS entries for ports the workload doesn't expose are flagged
+func TestValidatePortLevelMtls(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{
+		"/apis/security.istio.io/v1beta1/namespaces/bookinfo/peerauthentications": `{
+			"apiVersion": "security.istio.io/v1beta1",
+			"kind": "PeerAuthenticationList",
+			"items": [
+				{
+					"metadata": {"name": "reviews", "namespace": "bookinfo"},
+					"spec": {
+						"selector": {"matchLabels": {"app": "reviews"}},
+						"mtls": {"mode": "STRICT"},
+						"portLevelMtls": {"9080": {"mode": "PERMISSIVE"}, "80": {"mode": "DISABLE"}}
+					}
+				},
+				{
+					"metadata": {"name": "default", "namespace": "bookinfo"},
+					"spec": {"mtls": {"mode": "STRICT"}}
+				}
+			]
+		}`,
+		"/api/v1/namespaces/bookinfo/pods": `{
+			"apiVersion": "v1",
+			"kind": "PodList",
+			"items": [{
+				"metadata": {"name": "reviews-v1-abc", "labels": {"app": "reviews"}},
+				"spec": {"containers": [
+					{"name": "reviews", "ports": [{"containerPort": 9080}]},
+					{"name": "istio-proxy", "ports": [{"containerPort": 15090}]}
+				]}
+			}]
+		}`,
+		"/api/v1/namespaces/bookinfo/services": `{
+			"apiVersion": "v1",
+			"kind": "ServiceList",
+			"items": [{
+				"metadata": {"name": "reviews", "namespace": "bookinfo"},
+				"spec": {"selector": {"app": "reviews"}, "ports": [{"name": "http", "port": 80, "targetPort": 9080}]}
+			}]
+		}`,
+	})
+	defer mockServer.Close()
+	istio := newTestIstio(t, mockServer.URL)
+
+	result, err := istio.ValidatePortLevelMtls(context.Background(), "bookinfo")
+	if err != nil {
+		t.Fatalf("Failed to validate port-level mTLS: %v", err)
+	}
+	assertContains(t, result,
+		"PeerAuthentication 'reviews' (selector app=reviews)",
+		"[OK] Port 9080 (PERMISSIVE): declared by the selected workload",
+		"[WARNING] Port 80 (DISABLE): no container of the selected pods exposes this port",
+		"it is a port of service 'reviews', which targets container port 9080",
+		"[RESULT] 1 of 2 port-level entries",
+	)
+	assertNotContains(t, result, "PeerAuthentication 'default'")
+}
@@ -0,0 +1,149 @@
+package istio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// topServicesWindow is the time window request rates are computed over
+	topServicesWindow = "5m"
+	// topServicesErrorRateWarning is the share of 5xx responses above which a service is flagged
+	topServicesErrorRateWarning = 0.05
+	// prometheusTimeout bounds each Prometheus query
+	prometheusTimeout = 15 * time.Second
+)
+
+// prometheusSample is a single series of a Prometheus instant vector
+type prometheusSample struct {
+	labels map[string]string
+	value  float64
+}
+
+// SetPrometheusURL sets the base URL of the Prometheus server scraping Istio metrics (e.g.
+// 'http://prometheus.istio-system:9090'); metrics-backed tools report that Prometheus isn't configured when empty
+func (i *Istio) SetPrometheusURL(prometheusURL string) {
+	i.prometheusURL = strings.TrimSuffix(prometheusURL, "/")
+}
+
+// queryPrometheus runs an instant PromQL query and returns the resulting vector
+func (i *Istio) queryPrometheus(ctx context.Context, query string) ([]prometheusSample, error) {
+	ctx, cancel := context.WithTimeout(ctx, prometheusTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, i.prometheusURL+"/api/v1/query?query="+url.QueryEscape(query), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query Prometheus at %s: %w", i.prometheusURL, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Prometheus response: %w", err)
+	}
+
+	var response struct {
+		Status string `json:"status"`
+		Error  string `json:"error"`
+		Data   struct {
+			ResultType string `json:"resultType"`
+			Result     []struct {
+				Metric map[string]string `json:"metric"`
+				Value  []interface{}     `json:"value"`
+			} `json:"result"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("unexpected Prometheus response (HTTP %d): %w", resp.StatusCode, err)
+	}
+	if response.Status != "success" {
+		return nil, fmt.Errorf("prometheus query failed: %s", response.Error)
+	}
+	if response.Data.ResultType != "vector" {
+		return nil, fmt.Errorf("unexpected Prometheus result type '%s', expected 'vector'", response.Data.ResultType)
+	}
+
+	samples := make([]prometheusSample, 0, len(response.Data.Result))
+	for _, series := range response.Data.Result {
+		if len(series.Value) != 2 {
+			continue
+		}
+		raw, _ := series.Value[1].(string)
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			continue
+		}
+		samples = append(samples, prometheusSample{labels: series.Metric, value: value})
+	}
+	return samples, nil
+}
+
+// GetTopServices ranks the services of a namespace by request rate over the last 5 minutes, from the
+// istio_requests_total metric reported by the destination proxies, with the share of 5xx responses of each
+func (i *Istio) GetTopServices(ctx context.Context, namespace string) (string, error) {
+	if i.prometheusURL == "" {
+		return "Prometheus is not configured: start the server with --prometheus-url (e.g. http://prometheus.istio-system:9090) to rank services by request volume and error rate\n", nil
+	}
+
+	selector := fmt.Sprintf(`reporter="destination",destination_service_namespace=%q`, namespace)
+	requests, err := i.queryPrometheus(ctx, fmt.Sprintf(`sum by (destination_service) (rate(istio_requests_total{%s}[%s]))`, selector, topServicesWindow))
+	if err != nil {
+		return "", err
+	}
+	errorSamples, err := i.queryPrometheus(ctx, fmt.Sprintf(`sum by (destination_service) (rate(istio_requests_total{%s,response_code=~"5.."}[%s]))`, selector, topServicesWindow))
+	if err != nil {
+		return "", err
+	}
+	errorRates := make(map[string]float64, len(errorSamples))
+	for _, sample := range errorSamples {
+		errorRates[sample.labels["destination_service"]] = sample.value
+	}
+
+	type serviceTraffic struct {
+		service   string
+		rate      float64
+		errorRate float64
+	}
+	var services []serviceTraffic
+	for _, sample := range requests {
+		if sample.value == 0 {
+			continue
+		}
+		service := sample.labels["destination_service"]
+		services = append(services, serviceTraffic{service: service, rate: sample.value, errorRate: errorRates[service] / sample.value})
+	}
+	if len(services) == 0 {
+		return fmt.Sprintf("No requests to services in namespace '%s' were recorded in the last %s\n", namespace, topServicesWindow), nil
+	}
+	sort.Slice(services, func(a, b int) bool {
+		if services[a].rate != services[b].rate {
+			return services[a].rate > services[b].rate
+		}
+		return services[a].service < services[b].service
+	})
+
+	result := fmt.Sprintf("Top services in namespace '%s' by request rate over the last %s:\n\n", namespace, topServicesWindow)
+	result += "Rank | Service | Requests/s | 5xx Rate\n"
+	result += "-----|---------|------------|---------\n"
+	var failing []string
+	for rank, st := range services {
+		result += fmt.Sprintf("%4d | %s | %.2f | %.1f%%\n", rank+1, st.service, st.rate, st.errorRate*100)
+		if st.errorRate > topServicesErrorRateWarning {
+			failing = append(failing, fmt.Sprintf("%s (%.1f%%)", st.service, st.errorRate*100))
+		}
+	}
+	if len(failing) > 0 {
+		result += fmt.Sprintf("\n[WARNING] Services with more than %.0f%% 5xx responses: %s\n", topServicesErrorRateWarning*100, strings.Join(failing, ", "))
+	}
+	return result, nil
+}
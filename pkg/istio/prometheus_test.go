@@ -0,0 +1,71 @@
+package istio
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestGetTopServices tests ranking of services by request rate from Prometheus
+func TestGetTopServices(t *testing.T) {
+	t.Run("not configured", func(t *testing.T) {
+		result, err := (&Istio{}).GetTopServices(context.Background(), "bookinfo")
+		if err != nil {
+			t.Fatalf("GetTopServices failed: %v", err)
+		}
+		assertContains(t, result, "Prometheus is not configured", "--prometheus-url")
+	})
+
+	t.Run("ranking", func(t *testing.T) {
+		var queries []string
+		prometheus := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			query := r.URL.Query().Get("query")
+			queries = append(queries, query)
+			w.Header().Set("Content-Type", "application/json")
+			if strings.Contains(query, `response_code=~"5.."`) {
+				w.Write([]byte(`{"status": "success", "data": {"resultType": "vector", "result": [
+					{"metric": {"destination_service": "ratings.bookinfo.svc.cluster.local"}, "value": [1700000000, "2"]}
+				]}}`))
+				return
+			}
+			w.Write([]byte(`{"status": "success", "data": {"resultType": "vector", "result": [
+				{"metric": {"destination_service": "ratings.bookinfo.svc.cluster.local"}, "value": [1700000000, "10"]},
+				{"metric": {"destination_service": "productpage.bookinfo.svc.cluster.local"}, "value": [1700000000, "50.5"]},
+				{"metric": {"destination_service": "details.bookinfo.svc.cluster.local"}, "value": [1700000000, "0"]}
+			]}}`))
+		}))
+		defer prometheus.Close()
+
+		istio := &Istio{}
+		istio.SetPrometheusURL(prometheus.URL + "/")
+		result, err := istio.GetTopServices(context.Background(), "bookinfo")
+		if err != nil {
+			t.Fatalf("GetTopServices failed: %v", err)
+		}
+		assertContains(t, result,
+			"   1 | productpage.bookinfo.svc.cluster.local | 50.50 | 0.0%",
+			"   2 | ratings.bookinfo.svc.cluster.local | 10.00 | 20.0%",
+			"[WARNING] Services with more than 5% 5xx responses: ratings.bookinfo.svc.cluster.local (20.0%)",
+		)
+		assertNotContains(t, result, "details")
+		if len(queries) != 2 || !strings.Contains(queries[0], `destination_service_namespace="bookinfo"`) {
+			t.Errorf("Expected two queries scoped to the namespace, got %v", queries)
+		}
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		prometheus := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"status": "error", "errorType": "bad_data", "error": "parse error"}`))
+		}))
+		defer prometheus.Close()
+
+		istio := &Istio{}
+		istio.SetPrometheusURL(prometheus.URL)
+		if _, err := istio.GetTopServices(context.Background(), "bookinfo"); err == nil || !strings.Contains(err.Error(), "parse error") {
+			t.Errorf("Expected the Prometheus error to be returned, got %v", err)
+		}
+	})
+}
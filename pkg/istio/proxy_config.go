@@ -1,7 +1,9 @@
 package istio
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"os/exec"
@@ -13,63 +15,164 @@ import (
 type ProxyConfigClient struct {
 	kubeconfig string
 	timeout    time.Duration
+	// execCommand runs istioctl with the given arguments and returns its combined output
+	execCommand func(ctx context.Context, args ...string) ([]byte, error)
+	cache       *proxyConfigCache
+	// analyzeCache holds istioctl analyze results by namespace
+	analyzeCache *proxyConfigCache
+	// snapshots holds the proxy config snapshots taken by SnapshotProxyConfig
+	snapshots *proxySnapshotStore
 }
 
 // NewProxyConfigClient creates a new proxy configuration client
 func NewProxyConfigClient(kubeconfig string) *ProxyConfigClient {
 	return &ProxyConfigClient{
-		kubeconfig: kubeconfig,
-		timeout:    30 * time.Second,
+		kubeconfig:   kubeconfig,
+		timeout:      30 * time.Second,
+		execCommand:  runIstioctl,
+		cache:        newProxyConfigCache(DefaultProxyConfigCacheTTL),
+		analyzeCache: newProxyConfigCache(DefaultAnalyzeCacheTTL),
+		snapshots:    newProxySnapshotStore(),
 	}
 }
 
+// SetCacheTTL sets how long proxy configuration of a pod is reused before istioctl is run again.
+// A zero TTL disables caching.
+func (p *ProxyConfigClient) SetCacheTTL(ttl time.Duration) {
+	p.cache = newProxyConfigCache(ttl)
+}
+
 // GetClusters retrieves cluster configuration from a pod's Envoy proxy
 func (p *ProxyConfigClient) GetClusters(ctx context.Context, namespace, podName string) (string, error) {
-	return p.execIstioctl(ctx, "proxy-config", "cluster", fmt.Sprintf("%s.%s", podName, namespace), "-o", "json")
+	return p.execProxyConfig(ctx, namespace, podName, "proxy-config", "cluster", fmt.Sprintf("%s.%s", podName, namespace), "-o", "json")
 }
 
 // GetListeners retrieves listener configuration from a pod's Envoy proxy
 func (p *ProxyConfigClient) GetListeners(ctx context.Context, namespace, podName string) (string, error) {
-	return p.execIstioctl(ctx, "proxy-config", "listener", fmt.Sprintf("%s.%s", podName, namespace), "-o", "json")
+	return p.execProxyConfig(ctx, namespace, podName, "proxy-config", "listener", fmt.Sprintf("%s.%s", podName, namespace), "-o", "json")
 }
 
 // GetRoutes retrieves route configuration from a pod's Envoy proxy
 func (p *ProxyConfigClient) GetRoutes(ctx context.Context, namespace, podName string) (string, error) {
-	return p.execIstioctl(ctx, "proxy-config", "route", fmt.Sprintf("%s.%s", podName, namespace), "-o", "json")
+	return p.execProxyConfig(ctx, namespace, podName, "proxy-config", "route", fmt.Sprintf("%s.%s", podName, namespace), "-o", "json")
 }
 
 // GetEndpoints retrieves endpoint configuration from a pod's Envoy proxy
 func (p *ProxyConfigClient) GetEndpoints(ctx context.Context, namespace, podName string) (string, error) {
-	return p.execIstioctl(ctx, "proxy-config", "endpoint", fmt.Sprintf("%s.%s", podName, namespace), "-o", "json")
+	return p.GetEndpointsFiltered(ctx, namespace, podName, "")
+}
+
+// GetEndpointsFiltered retrieves endpoint configuration from a pod's Envoy proxy,
+// restricted to a single upstream cluster when cluster is not empty
+func (p *ProxyConfigClient) GetEndpointsFiltered(ctx context.Context, namespace, podName, cluster string) (string, error) {
+	args := []string{"proxy-config", "endpoint", fmt.Sprintf("%s.%s", podName, namespace)}
+	if cluster != "" {
+		args = append(args, "--cluster", cluster)
+	}
+	args = append(args, "-o", "json")
+	return p.execProxyConfig(ctx, namespace, podName, args...)
 }
 
 // GetBootstrap retrieves bootstrap configuration from a pod's Envoy proxy
 func (p *ProxyConfigClient) GetBootstrap(ctx context.Context, namespace, podName string) (string, error) {
-	return p.execIstioctl(ctx, "proxy-config", "bootstrap", fmt.Sprintf("%s.%s", podName, namespace), "-o", "json")
+	return p.execProxyConfig(ctx, namespace, podName, "proxy-config", "bootstrap", fmt.Sprintf("%s.%s", podName, namespace), "-o", "json")
 }
 
 // GetSecret retrieves secret configuration from a pod's Envoy proxy
 func (p *ProxyConfigClient) GetSecret(ctx context.Context, namespace, podName string) (string, error) {
-	return p.execIstioctl(ctx, "proxy-config", "secret", fmt.Sprintf("%s.%s", podName, namespace), "-o", "json")
+	return p.execProxyConfig(ctx, namespace, podName, "proxy-config", "secret", fmt.Sprintf("%s.%s", podName, namespace), "-o", "json")
 }
 
 // GetConfigDump retrieves full configuration dump from a pod's Envoy proxy
 func (p *ProxyConfigClient) GetConfigDump(ctx context.Context, namespace, podName string) (string, error) {
+	return p.execProxyConfig(ctx, namespace, podName, "proxy-config", "all", fmt.Sprintf("%s.%s", podName, namespace), "-o", "json")
+}
+
+// GetConfigDumpRaw retrieves the full configuration dump from a pod's Envoy proxy exactly as printed by istioctl,
+// for callers that parse it themselves; unlike the other getters it always runs istioctl, bypassing the cache, and
+// is guaranteed to never be reformatted or summarized
+func (p *ProxyConfigClient) GetConfigDumpRaw(ctx context.Context, namespace, podName string) (string, error) {
 	return p.execIstioctl(ctx, "proxy-config", "all", fmt.Sprintf("%s.%s", podName, namespace), "-o", "json")
 }
 
+// GetConfigDumpPath retrieves the configuration dump from a pod's Envoy proxy and returns only the
+// subtree at the given dot/bracket path (e.g. "configs.dynamic_listeners[virtualInbound]")
+func (p *ProxyConfigClient) GetConfigDumpPath(ctx context.Context, namespace, podName, path string) (string, error) {
+	dump, err := p.GetConfigDump(ctx, namespace, podName)
+	if err != nil {
+		return "", err
+	}
+	var data interface{}
+	if err := json.Unmarshal([]byte(dump), &data); err != nil {
+		return "", fmt.Errorf("failed to parse config dump: %w", err)
+	}
+	value, err := extractJSONPath(data, path)
+	if err != nil {
+		return "", err
+	}
+	extracted, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to format config dump path '%s': %w", path, err)
+	}
+	return string(extracted), nil
+}
+
 // GetProxyStatus retrieves proxy status information for all pods
 func (p *ProxyConfigClient) GetProxyStatus(ctx context.Context) (string, error) {
-	return p.execIstioctl(ctx, "proxy-status")
+	return p.execProxyStatus(ctx, "proxy-status")
 }
 
 // GetProxyStatusForPod retrieves proxy status for a specific pod
 func (p *ProxyConfigClient) GetProxyStatusForPod(ctx context.Context, namespace, podName string) (string, error) {
-	return p.execIstioctl(ctx, "proxy-status", fmt.Sprintf("%s.%s", podName, namespace))
+	return p.execProxyStatus(ctx, "proxy-status", fmt.Sprintf("%s.%s", podName, namespace))
+}
+
+// GetProxyStatusJSON retrieves proxy status as JSON, for all pods or a specific pod when podName is not empty, and
+// invalidates cached configuration of proxies that received a new push
+func (p *ProxyConfigClient) GetProxyStatusJSON(ctx context.Context, namespace, podName string) (string, error) {
+	args := []string{"proxy-status"}
+	if podName != "" {
+		args = append(args, fmt.Sprintf("%s.%s", podName, namespace))
+	}
+	args = append(args, "-o", "json")
+	output, err := p.execIstioctl(ctx, args...)
+	if err != nil {
+		return "", err
+	}
+	// Only the per-proxy sync rows carry nonces; other shapes leave the cache to its TTL
+	var statuses []proxySyncStatus
+	if json.Unmarshal([]byte(output), &statuses) == nil {
+		p.cache.observeProxyStatusJSON(statuses)
+	}
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, []byte(output), "", "  "); err != nil {
+		return "", fmt.Errorf("istioctl proxy-status returned invalid JSON: %w", err)
+	}
+	return indented.String(), nil
+}
+
+// GetIstioctlVersion retrieves the version of the local istioctl client
+func (p *ProxyConfigClient) GetIstioctlVersion(ctx context.Context) (string, error) {
+	output, err := p.execIstioctl(ctx, "version", "--remote=false", "--short")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(output), nil
 }
 
 // execIstioctl executes istioctl commands with proper error handling and timeout
 func (p *ProxyConfigClient) execIstioctl(ctx context.Context, args ...string) (string, error) {
+	output, err := p.runWithTimeout(ctx, args...)
+	if err != nil {
+		return "", fmt.Errorf("istioctl command failed: %w, output: %s", err, string(output))
+	}
+
+	return string(output), nil
+}
+
+// runWithTimeout runs istioctl against the configured kubeconfig within the client timeout,
+// returning its output even when it exits with an error
+func (p *ProxyConfigClient) runWithTimeout(ctx context.Context, args ...string) ([]byte, error) {
 	// Create context with timeout
 	ctxWithTimeout, cancel := context.WithTimeout(ctx, p.timeout)
 	defer cancel()
@@ -82,13 +185,44 @@ func (p *ProxyConfigClient) execIstioctl(ctx context.Context, args ...string) (s
 	cmdArgs = append(cmdArgs, args...)
 
 	// Execute istioctl command
-	cmd := exec.CommandContext(ctxWithTimeout, "istioctl", cmdArgs...)
-	output, err := cmd.CombinedOutput()
+	return p.execCommand(ctxWithTimeout, cmdArgs...)
+}
+
+// execProxyConfig executes an istioctl command reading a pod's proxy configuration, reusing a recent output for the same command
+func (p *ProxyConfigClient) execProxyConfig(ctx context.Context, namespace, podName string, args ...string) (string, error) {
+	key := proxyConfigCacheKey{namespace: namespace, pod: podName, command: strings.Join(args, " ")}
+	if output, ok := p.cache.get(key); ok {
+		return output, nil
+	}
+	output, err := p.execIstioctl(ctx, args...)
 	if err != nil {
-		return "", fmt.Errorf("istioctl command failed: %w, output: %s", err, string(output))
+		return "", err
 	}
+	p.cache.put(key, output)
+	return output, nil
+}
 
-	return string(output), nil
+// execProxyStatus executes istioctl proxy-status and invalidates cached configuration of proxies that received a new push
+func (p *ProxyConfigClient) execProxyStatus(ctx context.Context, args ...string) (string, error) {
+	output, err := p.execIstioctl(ctx, args...)
+	if err != nil {
+		return "", err
+	}
+	p.cache.observeProxyStatus(output)
+	return output, nil
+}
+
+// runIstioctl runs the istioctl binary found in PATH. On success only stdout is returned, so warnings
+// printed to stderr never corrupt JSON output; on failure stderr is appended to explain the error.
+func runIstioctl(ctx context.Context, args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "istioctl", args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return append(stdout.Bytes(), stderr.Bytes()...), err
+	}
+	return stdout.Bytes(), nil
 }
 
 // EnvoyAdminClient handles direct access to Envoy's admin API
@@ -0,0 +1,138 @@
+package istio
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultProxyConfigCacheTTL is how long proxy configuration is reused before istioctl is run again
+const DefaultProxyConfigCacheTTL = 10 * time.Second
+
+// proxyStatusAge matches the time since the last push shown next to each xDS sync state, e.g. "SYNCED (2m14s)"
+var proxyStatusAge = regexp.MustCompile(`\s*\(([0-9hms.]+)\)`)
+
+// proxyConfigCacheKey identifies a cached istioctl proxy-config output
+type proxyConfigCacheKey struct {
+	namespace string
+	pod       string
+	command   string
+}
+
+// proxyConfigCacheEntry is a cached istioctl output and when it was fetched
+type proxyConfigCacheEntry struct {
+	output    string
+	fetchedAt time.Time
+}
+
+// proxyConfigCache is a short-TTL cache of proxy-config outputs. Entries of a pod are dropped
+// as soon as proxy-status shows that istiod pushed new configuration to it.
+type proxyConfigCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	now     func() time.Time
+	entries map[proxyConfigCacheKey]proxyConfigCacheEntry
+	// status holds the last seen proxy-status row of each proxy ("pod.namespace"), without push ages
+	status map[string]string
+	// nonces holds the last seen xDS nonces of each proxy from proxy-status -o json
+	nonces map[string]string
+}
+
+// newProxyConfigCache creates a cache keeping entries for ttl; a zero ttl disables caching
+func newProxyConfigCache(ttl time.Duration) *proxyConfigCache {
+	return &proxyConfigCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[proxyConfigCacheKey]proxyConfigCacheEntry),
+		status:  make(map[string]string),
+		nonces:  make(map[string]string),
+	}
+}
+
+// get returns the cached output for key if it hasn't expired
+func (c *proxyConfigCache) get(key proxyConfigCacheKey) (string, bool) {
+	if c.ttl <= 0 {
+		return "", false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok || c.now().Sub(entry.fetchedAt) >= c.ttl {
+		delete(c.entries, key)
+		return "", false
+	}
+	return entry.output, true
+}
+
+// put stores the output for key
+func (c *proxyConfigCache) put(key proxyConfigCacheKey, output string) {
+	if c.ttl <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = proxyConfigCacheEntry{output: output, fetchedAt: c.now()}
+}
+
+// observeProxyStatus invalidates the entries of every proxy whose proxy-status row shows a configuration
+// change: either the sync states changed, or a push happened after the entry was fetched
+func (c *proxyConfigCache) observeProxyStatus(output string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || fields[0] == "NAME" {
+			continue
+		}
+		proxy := fields[0]
+
+		var lastPush time.Time
+		for _, match := range proxyStatusAge.FindAllStringSubmatch(line, -1) {
+			if age, err := time.ParseDuration(match[1]); err == nil && (lastPush.IsZero() || now.Add(-age).After(lastPush)) {
+				lastPush = now.Add(-age)
+			}
+		}
+		status := strings.Join(strings.Fields(proxyStatusAge.ReplaceAllString(line, "")), " ")
+		changed := c.status[proxy] != "" && c.status[proxy] != status
+		c.status[proxy] = status
+
+		for key, entry := range c.entries {
+			if key.pod+"."+key.namespace != proxy {
+				continue
+			}
+			if changed || (!lastPush.IsZero() && lastPush.After(entry.fetchedAt)) {
+				delete(c.entries, key)
+			}
+		}
+	}
+}
+
+// observeProxyStatusJSON invalidates the entries of every proxy whose xDS nonces in proxy-status -o json show a
+// configuration change: either the nonces changed, or one of them carries a push newer than the entry
+func (c *proxyConfigCache) observeProxyStatusJSON(statuses []proxySyncStatus) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, status := range statuses {
+		sent := []string{status.ClusterSent, status.ListenerSent, status.RouteSent, status.EndpointSent}
+		var lastPush time.Time
+		for _, nonce := range sent {
+			if pushed, _, ok := parsePushVersion(nonceVersion(nonce)); ok && pushed.After(lastPush) {
+				lastPush = pushed
+			}
+		}
+		nonces := strings.Join(append(sent, status.ClusterAcked, status.ListenerAcked, status.RouteAcked, status.EndpointAcked), " ")
+		changed := c.nonces[status.Proxy] != "" && c.nonces[status.Proxy] != nonces
+		c.nonces[status.Proxy] = nonces
+
+		for key, entry := range c.entries {
+			if key.pod+"."+key.namespace != status.Proxy {
+				continue
+			}
+			if changed || (!lastPush.IsZero() && lastPush.After(entry.fetchedAt)) {
+				delete(c.entries, key)
+			}
+		}
+	}
+}
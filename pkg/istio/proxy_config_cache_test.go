@@ -0,0 +1,142 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// countIstioctl replaces the istioctl execution of the client with a stub returning outputs by command,
+// and returns a pointer to the number of invocations
+func countIstioctl(client *ProxyConfigClient, outputs map[string]string) *int {
+	calls := 0
+	client.execCommand = func(ctx context.Context, args ...string) ([]byte, error) {
+		calls++
+		return []byte(outputs[args[0]]), nil
+	}
+	return &calls
+}
+
+// TestProxyConfigCache tests that proxy configuration is reused within the TTL
+func TestProxyConfigCache(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("second call within TTL doesn't exec istioctl", func(t *testing.T) {
+		client := NewProxyConfigClient("")
+		calls := countIstioctl(client, map[string]string{"proxy-config": `{"clusters": []}`})
+
+		for range 2 {
+			if _, err := client.GetClusters(ctx, "default", "productpage-v1"); err != nil {
+				t.Fatalf("Failed to get clusters: %v", err)
+			}
+		}
+		if *calls != 1 {
+			t.Errorf("Expected istioctl to run once, got %d", *calls)
+		}
+
+		if _, err := client.GetListeners(ctx, "default", "productpage-v1"); err != nil {
+			t.Fatalf("Failed to get listeners: %v", err)
+		}
+		if *calls != 2 {
+			t.Errorf("Expected a different subcommand to run istioctl, got %d calls", *calls)
+		}
+	})
+
+	t.Run("expired entries are refetched", func(t *testing.T) {
+		client := NewProxyConfigClient("")
+		now := time.Now()
+		client.cache.now = func() time.Time { return now }
+		calls := countIstioctl(client, map[string]string{"proxy-config": `{}`})
+
+		_, _ = client.GetRoutes(ctx, "default", "productpage-v1")
+		now = now.Add(DefaultProxyConfigCacheTTL)
+		_, _ = client.GetRoutes(ctx, "default", "productpage-v1")
+		if *calls != 2 {
+			t.Errorf("Expected istioctl to run again after the TTL, got %d calls", *calls)
+		}
+	})
+
+	t.Run("disabled with zero TTL", func(t *testing.T) {
+		client := NewProxyConfigClient("")
+		client.SetCacheTTL(0)
+		calls := countIstioctl(client, map[string]string{"proxy-config": `{}`})
+
+		_, _ = client.GetBootstrap(ctx, "default", "productpage-v1")
+		_, _ = client.GetBootstrap(ctx, "default", "productpage-v1")
+		if *calls != 2 {
+			t.Errorf("Expected istioctl to run on every call, got %d", *calls)
+		}
+	})
+
+	t.Run("invalidated by a new push in proxy-status", func(t *testing.T) {
+		client := NewProxyConfigClient("")
+		now := time.Now()
+		client.cache.now = func() time.Time { return now }
+		outputs := map[string]string{
+			"proxy-config": `{}`,
+			"proxy-status": "NAME                       CLUSTER     CDS              LDS              EDS              RDS              ECDS        ISTIOD              VERSION\n" +
+				"productpage-v1.default     Kubernetes  SYNCED (30s)     SYNCED (30s)     SYNCED (30s)     SYNCED (30s)     IGNORED     istiod-6cf8d-x7q    1.25.1\n" +
+				"reviews-v1.default         Kubernetes  SYNCED (2s)      SYNCED (2s)      SYNCED (2s)      SYNCED (2s)      IGNORED     istiod-6cf8d-x7q    1.25.1\n",
+		}
+		calls := countIstioctl(client, outputs)
+
+		_, _ = client.GetClusters(ctx, "default", "productpage-v1")
+		_, _ = client.GetClusters(ctx, "default", "reviews-v1")
+		now = now.Add(5 * time.Second)
+		if _, err := client.GetProxyStatus(ctx); err != nil {
+			t.Fatalf("Failed to get proxy status: %v", err)
+		}
+		*calls = 0
+
+		// productpage-v1 was last pushed before its config was fetched, reviews-v1 after
+		_, _ = client.GetClusters(ctx, "default", "productpage-v1")
+		_, _ = client.GetClusters(ctx, "default", "reviews-v1")
+		if *calls != 1 {
+			t.Errorf("Expected only the pushed proxy to be refetched, got %d calls", *calls)
+		}
+	})
+
+	t.Run("invalidated by a new push in proxy-status -o json", func(t *testing.T) {
+		client := NewProxyConfigClient("")
+		now := time.Now().Truncate(time.Second)
+		client.cache.now = func() time.Time { return now }
+		nonce := func(pushed time.Time, counter int) string {
+			return fmt.Sprintf("%s/%d6f1c2a9e-1d3b-4c5f-9a7e-2b8d0e4f6a1c", pushed.UTC().Format(time.RFC3339), counter)
+		}
+		old, recent := nonce(now.Add(-30*time.Second), 41), nonce(now.Add(3*time.Second), 42)
+		status := func(productpageAcked string) string {
+			return `[{"proxy": "productpage-v1.default", "cluster_sent": "` + old + `", "cluster_acked": "` + productpageAcked + `"},
+				{"proxy": "reviews-v1.default", "cluster_sent": "` + recent + `", "cluster_acked": "` + recent + `"}]`
+		}
+		outputs := map[string]string{"proxy-config": `{}`, "proxy-status": status(old)}
+		calls := countIstioctl(client, outputs)
+
+		_, _ = client.GetClusters(ctx, "default", "productpage-v1")
+		_, _ = client.GetClusters(ctx, "default", "reviews-v1")
+		now = now.Add(5 * time.Second)
+		if _, err := client.GetProxyStatusJSON(ctx, "", ""); err != nil {
+			t.Fatalf("Failed to get proxy status: %v", err)
+		}
+		*calls = 0
+
+		// reviews-v1 received a push after its config was fetched
+		_, _ = client.GetClusters(ctx, "default", "productpage-v1")
+		_, _ = client.GetClusters(ctx, "default", "reviews-v1")
+		if *calls != 1 {
+			t.Errorf("Expected only the pushed proxy to be refetched, got %d calls", *calls)
+		}
+
+		// productpage-v1 acknowledged a different nonce since the last proxy-status
+		outputs["proxy-status"] = status("")
+		if _, err := client.GetProxyStatusJSON(ctx, "", ""); err != nil {
+			t.Fatalf("Failed to get proxy status: %v", err)
+		}
+		*calls = 0
+		_, _ = client.GetClusters(ctx, "default", "productpage-v1")
+		_, _ = client.GetClusters(ctx, "default", "reviews-v1")
+		if *calls != 1 {
+			t.Errorf("Expected only the proxy with changed nonces to be refetched, got %d calls", *calls)
+		}
+	})
+}
@@ -2,10 +2,22 @@ package istio
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 )
 
+// stubIstioctl replaces the istioctl execution of the client with a stub returning the given output,
+// and returns a pointer to the arguments of the last invocation
+func stubIstioctl(client *ProxyConfigClient, output string) *[]string {
+	var captured []string
+	client.execCommand = func(ctx context.Context, args ...string) ([]byte, error) {
+		captured = args
+		return []byte(output), nil
+	}
+	return &captured
+}
+
 // TestProxyConfigClient tests proxy configuration client creation and properties
 func TestProxyConfigClient(t *testing.T) {
 	// Create a proxy config client
@@ -53,3 +65,56 @@ func TestProxyConfigClientIntegration(t *testing.T) {
 		t.Logf("GetProxyStatus failed as expected: %v", err)
 	}
 }
+
+// TestGetEndpointsFiltered tests that the cluster filter is forwarded to istioctl only when provided
+func TestGetEndpointsFiltered(t *testing.T) {
+	client := NewProxyConfigClient("")
+	args := stubIstioctl(client, `[]`)
+	ctx := context.Background()
+
+	t.Run("unfiltered by default", func(t *testing.T) {
+		if _, err := client.GetEndpoints(ctx, "default", "productpage-v1-abc"); err != nil {
+			t.Fatalf("Failed to get endpoints: %v", err)
+		}
+		expected := "proxy-config endpoint productpage-v1-abc.default -o json"
+		if got := strings.Join(*args, " "); got != expected {
+			t.Fatalf("Expected args '%s', got '%s'", expected, got)
+		}
+	})
+
+	t.Run("appends cluster filter when provided", func(t *testing.T) {
+		cluster := "outbound|9080||reviews.default.svc.cluster.local"
+		if _, err := client.GetEndpointsFiltered(ctx, "default", "productpage-v1-abc", cluster); err != nil {
+			t.Fatalf("Failed to get endpoints: %v", err)
+		}
+		expected := "proxy-config endpoint productpage-v1-abc.default --cluster " + cluster + " -o json"
+		if got := strings.Join(*args, " "); got != expected {
+			t.Fatalf("Expected args '%s', got '%s'", expected, got)
+		}
+	})
+}
+
+// TestGetProxyStatusJSON tests that JSON output is requested from istioctl and validated
+func TestGetProxyStatusJSON(t *testing.T) {
+	client := NewProxyConfigClient("")
+	ctx := context.Background()
+
+	t.Run("forwards -o json", func(t *testing.T) {
+		args := stubIstioctl(client, `[{"proxy":"productpage-v1.default","cluster_type_status":"SYNCED"}]`)
+		result, err := client.GetProxyStatusJSON(ctx, "default", "productpage-v1")
+		if err != nil {
+			t.Fatalf("Failed to get proxy status: %v", err)
+		}
+		if got := strings.Join(*args, " "); got != "proxy-status productpage-v1.default -o json" {
+			t.Errorf("Unexpected istioctl arguments: %s", got)
+		}
+		assertContains(t, result, `"proxy": "productpage-v1.default"`)
+	})
+
+	t.Run("rejects invalid JSON", func(t *testing.T) {
+		stubIstioctl(client, "NAME  CLUSTER  CDS\n")
+		if _, err := client.GetProxyStatusJSON(ctx, "", ""); err == nil {
+			t.Fatal("Expected error for invalid JSON output")
+		}
+	})
+}
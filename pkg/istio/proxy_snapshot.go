@@ -0,0 +1,210 @@
+package istio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxProxySnapshots is the number of proxy config snapshots kept in memory; the oldest is dropped beyond it
+const maxProxySnapshots = 20
+
+// proxySnapshot is the clusters, listeners and routes of a pod's proxy at a point in time, keyed by config dump
+// list and resource name
+type proxySnapshot struct {
+	id        string
+	namespace string
+	pod       string
+	takenAt   time.Time
+	resources map[string]map[string]interface{}
+}
+
+// proxySnapshotStore keeps the most recent proxy config snapshots in memory
+type proxySnapshotStore struct {
+	mu        sync.Mutex
+	now       func() time.Time
+	next      int
+	snapshots []*proxySnapshot
+}
+
+// newProxySnapshotStore creates an empty snapshot store
+func newProxySnapshotStore() *proxySnapshotStore {
+	return &proxySnapshotStore{now: time.Now}
+}
+
+// add stores a snapshot under a new ID, dropping the oldest snapshot when the store is full
+func (s *proxySnapshotStore) add(namespace, pod string, resources map[string]map[string]interface{}) *proxySnapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.next++
+	snapshot := &proxySnapshot{
+		id:        fmt.Sprintf("snapshot-%d", s.next),
+		namespace: namespace,
+		pod:       pod,
+		takenAt:   s.now(),
+		resources: resources,
+	}
+	s.snapshots = append(s.snapshots, snapshot)
+	if len(s.snapshots) > maxProxySnapshots {
+		s.snapshots = s.snapshots[len(s.snapshots)-maxProxySnapshots:]
+	}
+	return snapshot
+}
+
+// get returns the snapshot with the given ID
+func (s *proxySnapshotStore) get(id string) (*proxySnapshot, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, snapshot := range s.snapshots {
+		if snapshot.id == id {
+			return snapshot, true
+		}
+	}
+	return nil, false
+}
+
+// stripVersionFields removes the version and update time Envoy records for each resource of a config dump list,
+// which change on every push even when the resource itself is unchanged
+func stripVersionFields(item interface{}) interface{} {
+	fields, ok := item.(map[string]interface{})
+	if !ok {
+		return item
+	}
+	delete(fields, "version_info")
+	delete(fields, "last_updated")
+	for _, value := range fields {
+		if nested, ok := value.(map[string]interface{}); ok {
+			delete(nested, "version_info")
+			delete(nested, "last_updated")
+		}
+	}
+	return fields
+}
+
+// configDumpResources returns the clusters, listeners and routes of an Envoy config dump, keyed by list and name
+func configDumpResources(dump string) (map[string]map[string]interface{}, error) {
+	// istioctl may print warnings before the JSON document
+	if start := strings.Index(dump, "{"); start > 0 {
+		dump = dump[start:]
+	}
+	var data struct {
+		Configs []map[string]interface{} `json:"configs"`
+	}
+	if err := json.Unmarshal([]byte(dump), &data); err != nil {
+		return nil, err
+	}
+	resources := make(map[string]map[string]interface{})
+	for _, section := range comparedConfigSections {
+		resources[section.key] = make(map[string]interface{})
+	}
+	for _, config := range data.Configs {
+		for _, section := range comparedConfigSections {
+			items, _ := config[section.key].([]interface{})
+			for _, item := range items {
+				if name := configDumpItemName(item); name != "" {
+					resources[section.key][name] = stripVersionFields(item)
+				}
+			}
+		}
+	}
+	return resources, nil
+}
+
+// fetchProxyResources reads the current clusters, listeners and routes of a pod's proxy, bypassing the proxy
+// config cache so that a snapshot or diff always reflects what the proxy has now
+func (p *ProxyConfigClient) fetchProxyResources(ctx context.Context, namespace, podName string) (map[string]map[string]interface{}, error) {
+	dump, err := p.execIstioctl(ctx, "proxy-config", "all", fmt.Sprintf("%s.%s", podName, namespace), "-o", "json")
+	if err != nil {
+		return nil, err
+	}
+	resources, err := configDumpResources(dump)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse proxy config of pod %s: %w", podName, err)
+	}
+	return resources, nil
+}
+
+// SnapshotProxyConfig records the current clusters, listeners and routes of a pod's proxy in memory and returns
+// the ID to pass to DiffProxySnapshots. Only the most recent snapshots are kept.
+func (p *ProxyConfigClient) SnapshotProxyConfig(ctx context.Context, namespace, podName string) (string, error) {
+	resources, err := p.fetchProxyResources(ctx, namespace, podName)
+	if err != nil {
+		return "", err
+	}
+	snapshot := p.snapshots.add(namespace, podName, resources)
+
+	result := fmt.Sprintf("Snapshot '%s' of the proxy of pod '%s' in namespace '%s' taken at %s:\n", snapshot.id, podName, namespace, snapshot.takenAt.UTC().Format(time.RFC3339))
+	for _, section := range comparedConfigSections {
+		result += fmt.Sprintf("  %s: %d\n", section.name, len(resources[section.key]))
+	}
+	result += fmt.Sprintf("\nPass snapshot '%s' to diff-proxy-snapshots after a change to confirm it reached the proxy; the last %d snapshots are kept\n", snapshot.id, maxProxySnapshots)
+	return result, nil
+}
+
+// DiffProxySnapshots compares the current clusters, listeners and routes of a pod's proxy with a snapshot taken by
+// SnapshotProxyConfig and reports the resources added, removed and changed since, confirming whether a
+// configuration change propagated to the proxy
+func (p *ProxyConfigClient) DiffProxySnapshots(ctx context.Context, namespace, podName, snapshotID string) (string, error) {
+	snapshot, ok := p.snapshots.get(snapshotID)
+	if !ok {
+		return "", fmt.Errorf("snapshot '%s' not found; it may have been dropped, only the last %d snapshots are kept", snapshotID, maxProxySnapshots)
+	}
+	if snapshot.namespace != namespace || snapshot.pod != podName {
+		return "", fmt.Errorf("snapshot '%s' was taken of pod '%s' in namespace '%s', not pod '%s' in namespace '%s'", snapshotID, snapshot.pod, snapshot.namespace, podName, namespace)
+	}
+	current, err := p.fetchProxyResources(ctx, namespace, podName)
+	if err != nil {
+		return "", err
+	}
+
+	result := fmt.Sprintf("Changes to the proxy of pod '%s' in namespace '%s' since snapshot '%s' (taken %s ago):\n\n", podName, namespace, snapshotID, p.snapshots.now().Sub(snapshot.takenAt).Round(time.Second))
+	changes := 0
+	for _, section := range comparedConfigSections {
+		before, after := snapshot.resources[section.key], current[section.key]
+		added := missingNames(nameSet(after), nameSet(before))
+		removed := missingNames(nameSet(before), nameSet(after))
+		var changed []string
+		for name := range after {
+			if _, ok := before[name]; ok && compactJSON(before[name]) != compactJSON(after[name]) {
+				changed = append(changed, name)
+			}
+		}
+		sort.Strings(changed)
+
+		if len(added) == 0 && len(removed) == 0 && len(changed) == 0 {
+			result += fmt.Sprintf("%s: unchanged (%d)\n", section.name, len(after))
+			continue
+		}
+		changes += len(added) + len(removed) + len(changed)
+		result += fmt.Sprintf("%s: %d added, %d removed, %d changed\n", section.name, len(added), len(removed), len(changed))
+		for _, name := range added {
+			result += fmt.Sprintf("  + %s\n", name)
+		}
+		for _, name := range removed {
+			result += fmt.Sprintf("  - %s\n", name)
+		}
+		for _, name := range changed {
+			result += fmt.Sprintf("  ~ %s\n", name)
+		}
+	}
+
+	if changes == 0 {
+		result += "\n[RESULT] The proxy configuration is unchanged since the snapshot; a change made after it has not reached the proxy\n"
+	} else {
+		result += fmt.Sprintf("\n[RESULT] %d resources changed since the snapshot\n", changes)
+	}
+	return result, nil
+}
+
+// nameSet returns the names of a set of config dump resources
+func nameSet(resources map[string]interface{}) map[string]bool {
+	names := make(map[string]bool, len(resources))
+	for name := range resources {
+		names[name] = true
+	}
+	return names
+}
@@ -0,0 +1,56 @@
+package istio
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// TestSnapshotAndDiffProxyConfig tests that a proxy config snapshot is diffed against the current configuration
+func TestSnapshotAndDiffProxyConfig(t *testing.T) {
+	before := `{"configs": [
+		{"@type": "type.googleapis.com/envoy.admin.v3.ClustersConfigDump", "dynamic_active_clusters": [
+			{"version_info": "v1", "cluster": {"name": "outbound|9080||reviews.default.svc.cluster.local", "connect_timeout": "10s"}},
+			{"version_info": "v1", "cluster": {"name": "outbound|9080||ratings.default.svc.cluster.local"}}
+		]},
+		{"@type": "type.googleapis.com/envoy.admin.v3.ListenersConfigDump", "dynamic_listeners": [
+			{"name": "virtualInbound", "active_state": {"version_info": "v1", "listener": {"name": "virtualInbound"}}}
+		]}
+	]}`
+	after := `{"configs": [
+		{"@type": "type.googleapis.com/envoy.admin.v3.ClustersConfigDump", "dynamic_active_clusters": [
+			{"version_info": "v2", "cluster": {"name": "outbound|9080||reviews.default.svc.cluster.local", "connect_timeout": "5s"}},
+			{"version_info": "v2", "cluster": {"name": "outbound|9080|v2|reviews.default.svc.cluster.local"}}
+		]},
+		{"@type": "type.googleapis.com/envoy.admin.v3.ListenersConfigDump", "dynamic_listeners": [
+			{"name": "virtualInbound", "active_state": {"version_info": "v2", "listener": {"name": "virtualInbound"}}}
+		]}
+	]}`
+
+	client := NewProxyConfigClient("")
+	stubIstioctl(client, before)
+	result, err := client.SnapshotProxyConfig(context.Background(), "default", "productpage-v1-abc")
+	if err != nil {
+		t.Fatalf("SnapshotProxyConfig failed: %v", err)
+	}
+	assertContains(t, result, "Snapshot 'snapshot-1'", "Clusters: 2", "Listeners: 1")
+
+	stubIstioctl(client, after)
+	result, err = client.DiffProxySnapshots(context.Background(), "default", "productpage-v1-abc", "snapshot-1")
+	if err != nil {
+		t.Fatalf("DiffProxySnapshots failed: %v", err)
+	}
+	assertContains(t, result,
+		"Clusters: 1 added, 1 removed, 1 changed",
+		"  + outbound|9080|v2|reviews.default.svc.cluster.local",
+		"  - outbound|9080||ratings.default.svc.cluster.local",
+		"  ~ outbound|9080||reviews.default.svc.cluster.local",
+		// Only the version differs, which changes on every push
+		"Listeners: unchanged (1)",
+		"[RESULT] 3 resources changed since the snapshot",
+	)
+
+	if _, err := client.DiffProxySnapshots(context.Background(), "default", "productpage-v1-abc", "snapshot-9"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected an error for an unknown snapshot, got %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+package istio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// comparedConfigSections are the config dump lists compared between istiod and a proxy, with their display names
+var comparedConfigSections = []struct {
+	key  string
+	name string
+}{
+	{"dynamic_active_clusters", "Clusters"},
+	{"dynamic_listeners", "Listeners"},
+	{"dynamic_route_configs", "Routes"},
+}
+
+// configDumpResourceNames returns the names of the resources of each list of an Envoy config dump, keyed by list
+func configDumpResourceNames(dump string) (map[string]map[string]bool, error) {
+	// istioctl may print warnings before the JSON document
+	if start := strings.Index(dump, "{"); start > 0 {
+		dump = dump[start:]
+	}
+	var data struct {
+		Configs []map[string]interface{} `json:"configs"`
+	}
+	if err := json.Unmarshal([]byte(dump), &data); err != nil {
+		return nil, err
+	}
+	names := make(map[string]map[string]bool)
+	for _, config := range data.Configs {
+		for key, value := range config {
+			items, ok := value.([]interface{})
+			if !ok {
+				continue
+			}
+			if names[key] == nil {
+				names[key] = make(map[string]bool)
+			}
+			for _, item := range items {
+				if name := configDumpItemName(item); name != "" {
+					names[key][name] = true
+				}
+			}
+		}
+	}
+	return names, nil
+}
+
+// missingNames returns the sorted names in a that are not in b
+func missingNames(a, b map[string]bool) []string {
+	var missing []string
+	for name := range a {
+		if !b[name] {
+			missing = append(missing, name)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// GetIstiodConfigDump retrieves the configuration istiod generates for a pod's proxy from istiod's debug endpoint
+func (p *ProxyConfigClient) GetIstiodConfigDump(ctx context.Context, namespace, podName string) (string, error) {
+	return p.execIstioctl(ctx, "experimental", "internal-debug", fmt.Sprintf("config_dump?proxyID=%s.%s", podName, namespace))
+}
+
+// CompareProxyVsIstiod compares the clusters, listeners and routes istiod generates for a pod's proxy with the ones
+// the proxy actually has; differences reveal pushes the proxy rejected or hasn't received yet
+func (p *ProxyConfigClient) CompareProxyVsIstiod(ctx context.Context, namespace, podName string) (string, error) {
+	istiodDump, err := p.GetIstiodConfigDump(ctx, namespace, podName)
+	if err != nil {
+		return "", fmt.Errorf("failed to get istiod's config for pod %s: %w", podName, err)
+	}
+	// A cached dump could predate istiod's config and report a push the proxy has already applied as missing
+	proxyDump, err := p.GetConfigDumpRaw(ctx, namespace, podName)
+	if err != nil {
+		return "", fmt.Errorf("failed to get proxy config of pod %s: %w", podName, err)
+	}
+	istiodNames, err := configDumpResourceNames(istiodDump)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse istiod's config for pod %s: %w", podName, err)
+	}
+	proxyNames, err := configDumpResourceNames(proxyDump)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse proxy config of pod %s: %w", podName, err)
+	}
+
+	result := fmt.Sprintf("Comparing the configuration istiod generates for pod '%s' in namespace '%s' with the configuration of its proxy:\n\n", podName, namespace)
+	differences := 0
+	for _, section := range comparedConfigSections {
+		notApplied := missingNames(istiodNames[section.key], proxyNames[section.key])
+		stale := missingNames(proxyNames[section.key], istiodNames[section.key])
+		if len(notApplied) == 0 && len(stale) == 0 {
+			result += fmt.Sprintf("[OK] %s: all %d match\n", section.name, len(istiodNames[section.key]))
+			continue
+		}
+		differences += len(notApplied) + len(stale)
+		result += fmt.Sprintf("[FAIL] %s: %d differ\n", section.name, len(notApplied)+len(stale))
+		if len(notApplied) > 0 {
+			result += fmt.Sprintf("  Only in istiod (not applied by the proxy): %s\n", strings.Join(notApplied, ", "))
+		}
+		if len(stale) > 0 {
+			result += fmt.Sprintf("  Only in the proxy (removed by istiod): %s\n", strings.Join(stale, ", "))
+		}
+	}
+
+	if differences == 0 {
+		result += "\n[RESULT] The proxy has the configuration istiod generated for it\n"
+	} else {
+		result += fmt.Sprintf("\n[RESULT] %d differences: the proxy rejected or hasn't received istiod's latest push; check get-proxy-status and get-istiod-logs-for-proxy for rejected (NACKed) updates\n", differences)
+	}
+	return result, nil
+}
@@ -0,0 +1,62 @@
+package istio
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+// TestCompareProxyVsIstiod tests comparison of the configuration istiod generates with the one a proxy has
+func TestCompareProxyVsIstiod(t *testing.T) {
+	istiodDump := `{"configs": [
+		{"@type": "type.googleapis.com/envoy.admin.v3.ClustersConfigDump", "dynamic_active_clusters": [
+			{"cluster": {"name": "outbound|9080||reviews.bookinfo.svc.cluster.local"}},
+			{"cluster": {"name": "outbound|9080||ratings.bookinfo.svc.cluster.local"}}
+		]},
+		{"@type": "type.googleapis.com/envoy.admin.v3.ListenersConfigDump", "dynamic_listeners": [{"name": "virtualInbound"}]},
+		{"@type": "type.googleapis.com/envoy.admin.v3.RoutesConfigDump", "dynamic_route_configs": [{"route_config": {"name": "9080"}}]}
+	]}`
+	proxyDump := `{"configs": [
+		{"@type": "type.googleapis.com/envoy.admin.v3.ClustersConfigDump", "dynamic_active_clusters": [
+			{"cluster": {"name": "outbound|9080||reviews.bookinfo.svc.cluster.local"}},
+			{"cluster": {"name": "outbound|9080||details.bookinfo.svc.cluster.local"}}
+		]},
+		{"@type": "type.googleapis.com/envoy.admin.v3.ListenersConfigDump", "dynamic_listeners": [{"name": "virtualInbound"}]},
+		{"@type": "type.googleapis.com/envoy.admin.v3.RoutesConfigDump", "dynamic_route_configs": [{"route_config": {"name": "9080"}}]}
+	]}`
+
+	client := NewProxyConfigClient("")
+	client.execCommand = func(ctx context.Context, args ...string) ([]byte, error) {
+		if slices.Contains(args, "internal-debug") {
+			return []byte(istiodDump), nil
+		}
+		return []byte(proxyDump), nil
+	}
+
+	result, err := client.CompareProxyVsIstiod(context.Background(), "bookinfo", "productpage-v1-abc")
+	if err != nil {
+		t.Fatalf("CompareProxyVsIstiod failed: %v", err)
+	}
+	assertContains(t, result,
+		"[FAIL] Clusters: 2 differ",
+		"Only in istiod (not applied by the proxy): outbound|9080||ratings.bookinfo.svc.cluster.local",
+		"Only in the proxy (removed by istiod): outbound|9080||details.bookinfo.svc.cluster.local",
+		"[OK] Listeners: all 1 match",
+		"[OK] Routes: all 1 match",
+		"[RESULT] 2 differences",
+	)
+
+	t.Run("ignores the cached proxy config", func(t *testing.T) {
+		if _, err := client.GetConfigDump(context.Background(), "bookinfo", "productpage-v1-abc"); err != nil {
+			t.Fatalf("GetConfigDump failed: %v", err)
+		}
+		// The proxy applies the push after its config was cached
+		proxyDump = istiodDump
+		result, err := client.CompareProxyVsIstiod(context.Background(), "bookinfo", "productpage-v1-abc")
+		if err != nil {
+			t.Fatalf("CompareProxyVsIstiod failed: %v", err)
+		}
+		assertContains(t, result, "[OK] Clusters: all 2 match")
+		assertNotContains(t, result, "[FAIL]")
+	})
+}
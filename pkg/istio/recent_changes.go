@@ -0,0 +1,75 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// resourceChange is the latest creation or update of an Istio resource
+type resourceChange struct {
+	kind    string
+	name    string
+	time    time.Time
+	action  string
+	manager string
+}
+
+// latestChange returns when a resource was last created or updated, and by which field manager, from its
+// creationTimestamp and managedFields
+func latestChange(kind string, obj istioObject) resourceChange {
+	change := resourceChange{kind: kind, name: obj.GetName(), time: obj.GetCreationTimestamp().Time, action: "created"}
+	for _, entry := range obj.GetManagedFields() {
+		if entry.Time != nil && entry.Time.Time.After(change.time) {
+			change.time = entry.Time.Time
+			change.action = "updated"
+			change.manager = entry.Manager
+		}
+	}
+	return change
+}
+
+// GetRecentlyModifiedResources lists the Istio resources of every supported kind in a namespace that were created
+// or updated within the given window, most recent first
+func (i *Istio) GetRecentlyModifiedResources(ctx context.Context, namespace string, since time.Duration) (string, error) {
+	now := time.Now()
+	cutoff := now.Add(-since)
+
+	var changes []resourceChange
+	for _, kind := range SupportedResourceKinds() {
+		rk := resourceKinds[strings.ToLower(kind)]
+		resources, err := i.listResourcesByName(ctx, rk, namespace)
+		if err != nil {
+			return "", err
+		}
+		for _, obj := range resources {
+			if change := latestChange(kind, obj); change.time.After(cutoff) {
+				changes = append(changes, change)
+			}
+		}
+	}
+	sort.Slice(changes, func(a, b int) bool {
+		if !changes[a].time.Equal(changes[b].time) {
+			return changes[a].time.After(changes[b].time)
+		}
+		return changes[a].kind+"/"+changes[a].name < changes[b].kind+"/"+changes[b].name
+	})
+
+	result := fmt.Sprintf("Istio resources created or updated in namespace '%s' in the last %s:\n\n", namespace, since)
+	if len(changes) == 0 {
+		result += "No Istio resource was created or updated in this window\n"
+		return result, nil
+	}
+	for _, change := range changes {
+		result += fmt.Sprintf("- %s: %s '%s' %s %s ago", change.time.UTC().Format(time.RFC3339), change.kind, change.name,
+			change.action, now.Sub(change.time).Round(time.Second))
+		if change.manager != "" {
+			result += fmt.Sprintf(" by %s", change.manager)
+		}
+		result += "\n"
+	}
+	result += fmt.Sprintf("\n[RESULT] %d resources changed\n", len(changes))
+	return result, nil
+}
@@ -0,0 +1,55 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestGetRecentlyModifiedResources tests listing of the Istio resources modified within a time window
+func TestGetRecentlyModifiedResources(t *testing.T) {
+	ago := func(d time.Duration) string {
+		return time.Now().Add(-d).UTC().Format(time.RFC3339)
+	}
+	mockServer := newMockAPIServer(map[string]string{
+		"/apis/networking.istio.io/v1alpha3/namespaces/bookinfo/virtualservices": fmt.Sprintf(`{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "VirtualServiceList",
+			"items": [
+				{"metadata": {"name": "reviews", "namespace": "bookinfo", "creationTimestamp": %q, "managedFields": [
+					{"manager": "kubectl-client-side-apply", "operation": "Update", "time": %q},
+					{"manager": "kubectl-edit", "operation": "Update", "time": %q}
+				]}},
+				{"metadata": {"name": "ratings", "namespace": "bookinfo", "creationTimestamp": %q}}
+			]
+		}`, ago(72*time.Hour), ago(72*time.Hour), ago(10*time.Minute), ago(48*time.Hour)),
+		"/apis/networking.istio.io/v1alpha3/namespaces/bookinfo/destinationrules": fmt.Sprintf(`{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "DestinationRuleList",
+			"items": [
+				{"metadata": {"name": "details", "namespace": "bookinfo", "creationTimestamp": %q}}
+			]
+		}`, ago(30*time.Minute)),
+	})
+	defer mockServer.Close()
+
+	istio := newTestIstio(t, mockServer.URL)
+
+	result, err := istio.GetRecentlyModifiedResources(context.Background(), "bookinfo", time.Hour)
+	if err != nil {
+		t.Fatalf("GetRecentlyModifiedResources failed: %v", err)
+	}
+	assertContains(t, result,
+		"in the last 1h0m0s",
+		"VirtualService 'reviews' updated 10m",
+		"ago by kubectl-edit",
+		"DestinationRule 'details' created 30m",
+		"[RESULT] 2 resources changed",
+	)
+	assertNotContains(t, result, "ratings")
+	if strings.Index(result, "reviews") > strings.Index(result, "details") {
+		t.Errorf("Expected the most recent change first, got: %s", result)
+	}
+}
@@ -0,0 +1,139 @@
+package istio
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Verbosity controls how much of each resource the Get* summaries render
+type Verbosity int
+
+const (
+	// VerbosityNormal renders the key fields of each resource
+	VerbosityNormal Verbosity = iota
+	// VerbosityCompact renders only resource names and counts, without hints
+	VerbosityCompact
+	// VerbosityDetailed renders the full spec of each resource
+	VerbosityDetailed
+)
+
+// VerbosityNames contains the accepted verbosity names
+var VerbosityNames = []string{"compact", "normal", "detailed"}
+
+// ParseVerbosity returns the verbosity with the given name; an empty name is VerbosityNormal
+func ParseVerbosity(name string) (Verbosity, error) {
+	switch name {
+	case "", "normal":
+		return VerbosityNormal, nil
+	case "compact":
+		return VerbosityCompact, nil
+	case "detailed":
+		return VerbosityDetailed, nil
+	}
+	return VerbosityNormal, fmt.Errorf("invalid verbosity '%s', valid values are: %s", name, strings.Join(VerbosityNames, ", "))
+}
+
+// getOptions holds the rendering options of the Get* summaries
+type getOptions struct {
+	verbosity      Verbosity
+	fieldSelector  string
+	istioOnly      bool
+	initContainers bool
+	managedFields  bool
+	clean          bool
+}
+
+// GetOption configures how a Get* summary is rendered
+type GetOption func(*getOptions)
+
+// WithVerbosity sets how much of each resource is rendered
+func WithVerbosity(verbosity Verbosity) GetOption {
+	return func(o *getOptions) {
+		o.verbosity = verbosity
+	}
+}
+
+// WithIstioOnly restricts service listings to services backed by pods with an Istio sidecar
+func WithIstioOnly(istioOnly bool) GetOption {
+	return func(o *getOptions) {
+		o.istioOnly = istioOnly
+	}
+}
+
+// WithInitContainers adds the status of init containers, such as istio-init, to pod listings
+func WithInitContainers(initContainers bool) GetOption {
+	return func(o *getOptions) {
+		o.initContainers = initContainers
+	}
+}
+
+// WithManagedFields keeps the server-side apply managedFields of a resource, which record the manager owning each field
+func WithManagedFields(managedFields bool) GetOption {
+	return func(o *getOptions) {
+		o.managedFields = managedFields
+	}
+}
+
+// WithClean sets whether a resource rendered as YAML is stripped of status and server-populated metadata
+// (managedFields, resourceVersion, uid, creationTimestamp, generation) so that it can be re-applied; it is by default
+func WithClean(clean bool) GetOption {
+	return func(o *getOptions) {
+		o.clean = clean
+	}
+}
+
+// newGetOptions applies opts over the defaults
+func newGetOptions(opts []GetOption) getOptions {
+	o := getOptions{verbosity: VerbosityNormal, clean: true}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// renderItems lists resources by name and adds, depending on the verbosity, nothing (compact),
+// the lines produced by describe (normal) or the full spec of the resource (detailed)
+func renderItems[T istioObject](items []T, o getOptions, describe func(T) string) (string, error) {
+	result := ""
+	for _, item := range items {
+		result += fmt.Sprintf("- %s\n", item.GetName())
+		switch o.verbosity {
+		case VerbosityCompact:
+		case VerbosityDetailed:
+			spec, err := renderSpec(item)
+			if err != nil {
+				return "", fmt.Errorf("failed to format %s: %w", item.GetName(), err)
+			}
+			result += spec
+		default:
+			result += describe(item)
+		}
+	}
+	return result, nil
+}
+
+// renderSpec renders the spec of a resource as YAML, indented to nest under its list entry
+func renderSpec(obj istioObject) (string, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return "", err
+	}
+	var decoded struct {
+		Spec interface{} `json:"spec"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return "", err
+	}
+	rendered, err := yaml.Marshal(map[string]interface{}{"spec": decoded.Spec})
+	if err != nil {
+		return "", err
+	}
+	result := ""
+	for _, line := range strings.Split(strings.TrimRight(string(rendered), "\n"), "\n") {
+		result += "  " + line + "\n"
+	}
+	return result, nil
+}
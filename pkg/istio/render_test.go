@@ -0,0 +1,75 @@
+package istio
+
+import (
+	"context"
+	"testing"
+)
+
+// TestVerbosity tests the compact, normal and detailed rendering of the Get* summaries
+func TestVerbosity(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{
+		"/apis/networking.istio.io/v1alpha3/namespaces/default/virtualservices": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "VirtualServiceList",
+			"items": [
+				{
+					"metadata": {"name": "reviews", "namespace": "default"},
+					"spec": {"hosts": ["reviews"], "http": [{"route": [{"destination": {"host": "reviews", "subset": "v1"}}]}]}
+				}
+			]
+		}`,
+		"/api/v1/namespaces/default/services": `{
+			"apiVersion": "v1",
+			"kind": "ServiceList",
+			"items": [
+				{
+					"metadata": {"name": "reviews", "namespace": "default"},
+					"spec": {"type": "ClusterIP", "clusterIP": "10.0.0.10", "ports": [{"name": "http", "port": 9080}]}
+				}
+			]
+		}`,
+	})
+	defer mockServer.Close()
+
+	istio := newTestIstio(t, mockServer.URL)
+	ctx := context.Background()
+
+	t.Run("compact omits details and hints", func(t *testing.T) {
+		result, err := istio.GetVirtualServices(ctx, "default", WithVerbosity(VerbosityCompact))
+		if err != nil {
+			t.Fatalf("Failed to get virtual services: %v", err)
+		}
+		assertContains(t, result, "Found 1 Virtual Services in namespace 'default'", "- reviews\n")
+		assertNotContains(t, result, "Hosts:", "HTTP Routes:")
+
+		result, err = istio.GetServices(ctx, "default", WithVerbosity(VerbosityCompact))
+		if err != nil {
+			t.Fatalf("Failed to get services: %v", err)
+		}
+		assertContains(t, result, "Found 1 services", "- reviews\n")
+		assertNotContains(t, result, "Next step", "ClusterIP Services", "10.0.0.10")
+	})
+
+	t.Run("normal keeps key fields", func(t *testing.T) {
+		result, err := istio.GetVirtualServices(ctx, "default")
+		if err != nil {
+			t.Fatalf("Failed to get virtual services: %v", err)
+		}
+		assertContains(t, result, "  Hosts: [reviews]", "  HTTP Routes: 1")
+		assertNotContains(t, result, "subset: v1")
+	})
+
+	t.Run("detailed renders the full spec", func(t *testing.T) {
+		result, err := istio.GetVirtualServices(ctx, "default", WithVerbosity(VerbosityDetailed))
+		if err != nil {
+			t.Fatalf("Failed to get virtual services: %v", err)
+		}
+		assertContains(t, result, "- reviews\n  spec:\n", "subset: v1")
+	})
+
+	t.Run("invalid verbosity", func(t *testing.T) {
+		if _, err := ParseVerbosity("verbose"); err == nil {
+			t.Fatal("Expected error for unknown verbosity")
+		}
+	})
+}
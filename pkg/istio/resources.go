@@ -0,0 +1,250 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/krutsko/istio-mcp-server/pkg/output"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// istioObject is implemented by every typed Istio custom resource
+type istioObject interface {
+	metav1.Object
+	runtime.Object
+}
+
+// resourceKind describes an Istio resource kind that can be fetched by name
+type resourceKind struct {
+	gvk    schema.GroupVersionKind
+	plural string
+	get    func(ctx context.Context, i *Istio, namespace, name string) (istioObject, error)
+	list   func(ctx context.Context, i *Istio, namespace string) ([]istioObject, error)
+}
+
+// resourceKinds lists the supported Istio resource kinds, keyed by lowercase kind
+var resourceKinds = map[string]resourceKind{
+	"virtualservice": {
+		gvk:    schema.GroupVersionKind{Group: "networking.istio.io", Version: "v1alpha3", Kind: "VirtualService"},
+		plural: "virtualservices",
+		get: func(ctx context.Context, i *Istio, namespace, name string) (istioObject, error) {
+			return i.istioClient.NetworkingV1alpha3().VirtualServices(namespace).Get(ctx, name, metav1.GetOptions{})
+		},
+		list: func(ctx context.Context, i *Istio, namespace string) ([]istioObject, error) {
+			list, err := i.istioClient.NetworkingV1alpha3().VirtualServices(namespace).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			return toObjects(list.Items), nil
+		},
+	},
+	"destinationrule": {
+		gvk:    schema.GroupVersionKind{Group: "networking.istio.io", Version: "v1alpha3", Kind: "DestinationRule"},
+		plural: "destinationrules",
+		get: func(ctx context.Context, i *Istio, namespace, name string) (istioObject, error) {
+			return i.istioClient.NetworkingV1alpha3().DestinationRules(namespace).Get(ctx, name, metav1.GetOptions{})
+		},
+		list: func(ctx context.Context, i *Istio, namespace string) ([]istioObject, error) {
+			list, err := i.istioClient.NetworkingV1alpha3().DestinationRules(namespace).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			return toObjects(list.Items), nil
+		},
+	},
+	"gateway": {
+		gvk:    schema.GroupVersionKind{Group: "networking.istio.io", Version: "v1alpha3", Kind: "Gateway"},
+		plural: "gateways",
+		get: func(ctx context.Context, i *Istio, namespace, name string) (istioObject, error) {
+			return i.istioClient.NetworkingV1alpha3().Gateways(namespace).Get(ctx, name, metav1.GetOptions{})
+		},
+		list: func(ctx context.Context, i *Istio, namespace string) ([]istioObject, error) {
+			list, err := i.istioClient.NetworkingV1alpha3().Gateways(namespace).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			return toObjects(list.Items), nil
+		},
+	},
+	"serviceentry": {
+		gvk:    schema.GroupVersionKind{Group: "networking.istio.io", Version: "v1alpha3", Kind: "ServiceEntry"},
+		plural: "serviceentries",
+		get: func(ctx context.Context, i *Istio, namespace, name string) (istioObject, error) {
+			return i.istioClient.NetworkingV1alpha3().ServiceEntries(namespace).Get(ctx, name, metav1.GetOptions{})
+		},
+		list: func(ctx context.Context, i *Istio, namespace string) ([]istioObject, error) {
+			list, err := i.istioClient.NetworkingV1alpha3().ServiceEntries(namespace).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			return toObjects(list.Items), nil
+		},
+	},
+	"envoyfilter": {
+		gvk:    schema.GroupVersionKind{Group: "networking.istio.io", Version: "v1alpha3", Kind: "EnvoyFilter"},
+		plural: "envoyfilters",
+		get: func(ctx context.Context, i *Istio, namespace, name string) (istioObject, error) {
+			return i.istioClient.NetworkingV1alpha3().EnvoyFilters(namespace).Get(ctx, name, metav1.GetOptions{})
+		},
+		list: func(ctx context.Context, i *Istio, namespace string) ([]istioObject, error) {
+			list, err := i.istioClient.NetworkingV1alpha3().EnvoyFilters(namespace).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			return toObjects(list.Items), nil
+		},
+	},
+	"authorizationpolicy": {
+		gvk:    schema.GroupVersionKind{Group: "security.istio.io", Version: "v1beta1", Kind: "AuthorizationPolicy"},
+		plural: "authorizationpolicies",
+		get: func(ctx context.Context, i *Istio, namespace, name string) (istioObject, error) {
+			return i.getAuthorizationPolicy(ctx, namespace, name)
+		},
+		list: func(ctx context.Context, i *Istio, namespace string) ([]istioObject, error) {
+			list, err := i.listAuthorizationPolicies(ctx, namespace, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			return toObjects(list), nil
+		},
+	},
+	"peerauthentication": {
+		gvk:    schema.GroupVersionKind{Group: "security.istio.io", Version: "v1beta1", Kind: "PeerAuthentication"},
+		plural: "peerauthentications",
+		get: func(ctx context.Context, i *Istio, namespace, name string) (istioObject, error) {
+			return i.getPeerAuthentication(ctx, namespace, name)
+		},
+		list: func(ctx context.Context, i *Istio, namespace string) ([]istioObject, error) {
+			list, err := i.listPeerAuthentications(ctx, namespace, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			return toObjects(list), nil
+		},
+	},
+	"telemetry": {
+		gvk:    schema.GroupVersionKind{Group: "telemetry.istio.io", Version: "v1alpha1", Kind: "Telemetry"},
+		plural: "telemetries",
+		get: func(ctx context.Context, i *Istio, namespace, name string) (istioObject, error) {
+			return i.istioClient.TelemetryV1alpha1().Telemetries(namespace).Get(ctx, name, metav1.GetOptions{})
+		},
+		list: func(ctx context.Context, i *Istio, namespace string) ([]istioObject, error) {
+			list, err := i.istioClient.TelemetryV1alpha1().Telemetries(namespace).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			return toObjects(list.Items), nil
+		},
+	},
+}
+
+// toObjects converts a typed list of Istio resources to the common object interface
+func toObjects[T istioObject](items []T) []istioObject {
+	objects := make([]istioObject, 0, len(items))
+	for _, item := range items {
+		objects = append(objects, item)
+	}
+	return objects
+}
+
+// SupportedResourceKinds returns the names of the Istio resource kinds that can be fetched by name
+func SupportedResourceKinds() []string {
+	kinds := make([]string, 0, len(resourceKinds))
+	for _, rk := range resourceKinds {
+		kinds = append(kinds, rk.gvk.Kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// lookupResourceKind resolves a kind given as singular or plural, in any case (e.g. "VirtualService" or "virtualservices")
+func lookupResourceKind(kind string) (resourceKind, error) {
+	kind = strings.ToLower(kind)
+	if rk, ok := resourceKinds[kind]; ok {
+		return rk, nil
+	}
+	for _, rk := range resourceKinds {
+		if rk.plural == kind {
+			return rk, nil
+		}
+	}
+	return resourceKind{}, fmt.Errorf("unsupported resource kind '%s': supported kinds are %s", kind, strings.Join(SupportedResourceKinds(), ", "))
+}
+
+// getResource fetches a single named Istio resource with its apiVersion and kind populated
+func (i *Istio) getResource(ctx context.Context, kind, namespace, name string) (istioObject, error) {
+	rk, err := lookupResourceKind(kind)
+	if err != nil {
+		return nil, err
+	}
+	obj, err := rk.get(ctx, i, namespace, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s %s: %w", rk.gvk.Kind, name, i.explainAPIError(ctx, err, "get", rk.plural, namespace))
+	}
+	// Typed clients don't populate TypeMeta, which is needed for the output to be re-applied
+	obj.GetObjectKind().SetGroupVersionKind(i.resourceGVK(rk))
+	return obj, nil
+}
+
+// GetResource retrieves a single named Istio resource of any supported kind and returns it as YAML. By default
+// the resource is cleaned of status and server-populated metadata, managedFields included unless requested with
+// WithManagedFields; WithClean(false) returns the raw object.
+func (i *Istio) GetResource(ctx context.Context, kind, namespace, name string, opts ...GetOption) (string, error) {
+	o := newGetOptions(opts)
+	obj, err := i.getResource(ctx, kind, namespace, name)
+	if err != nil {
+		return "", err
+	}
+	var rendered interface{} = obj
+	if o.clean {
+		cleaned, err := output.Clean(obj)
+		if err != nil {
+			return "", fmt.Errorf("failed to clean %s %s: %w", obj.GetObjectKind().GroupVersionKind().Kind, name, err)
+		}
+		if managedFields := obj.GetManagedFields(); o.managedFields && len(managedFields) > 0 {
+			if metadata, ok := cleaned["metadata"].(map[string]interface{}); ok {
+				metadata["managedFields"] = managedFields
+			}
+		}
+		rendered = cleaned
+	}
+
+	yaml, err := output.Yaml.PrintObj(rendered)
+	if err != nil {
+		return "", fmt.Errorf("failed to format %s %s: %w", obj.GetObjectKind().GroupVersionKind().Kind, name, err)
+	}
+	return fmt.Sprintf("%s '%s' in namespace '%s':\n\n%s", obj.GetObjectKind().GroupVersionKind().Kind, name, namespace, yaml), nil
+}
+
+// GetResourceForEditing retrieves a single named Istio resource as YAML without status and server-populated
+// metadata, so that it can be modified and re-applied without conflicts. WithClean(false) returns the raw object.
+func (i *Istio) GetResourceForEditing(ctx context.Context, kind, namespace, name string, opts ...GetOption) (string, error) {
+	o := newGetOptions(opts)
+	obj, err := i.getResource(ctx, kind, namespace, name)
+	if err != nil {
+		return "", err
+	}
+	return resourceYAML(obj, o.clean)
+}
+
+// resourceYAML renders a resource, whose apiVersion and kind are populated, as YAML; when clean, status and
+// server-populated metadata are removed
+func resourceYAML(obj istioObject, clean bool) (string, error) {
+	var rendered interface{} = obj
+	if clean {
+		cleaned, err := output.Clean(obj)
+		if err != nil {
+			return "", fmt.Errorf("failed to clean %s %s: %w", obj.GetObjectKind().GroupVersionKind().Kind, obj.GetName(), err)
+		}
+		rendered = cleaned
+	}
+	yaml, err := output.Yaml.PrintObj(rendered)
+	if err != nil {
+		return "", fmt.Errorf("failed to format %s %s: %w", obj.GetObjectKind().GroupVersionKind().Kind, obj.GetName(), err)
+	}
+	return yaml, nil
+}
@@ -0,0 +1,133 @@
+package istio
+
+import (
+	"context"
+	"testing"
+
+	networkingv1alpha3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
+	"sigs.k8s.io/yaml"
+)
+
+// TestGetResource tests fetching a single named Istio resource
+func TestGetResource(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{
+		"/apis/networking.istio.io/v1alpha3/namespaces/bookinfo/virtualservices/reviews": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "VirtualService",
+			"metadata": {
+				"name": "reviews",
+				"namespace": "bookinfo",
+				"managedFields": [{"manager": "kubectl-client-side-apply", "operation": "Update"}]
+			},
+			"spec": {
+				"hosts": ["reviews"],
+				"http": [{"route": [{"destination": {"host": "reviews", "subset": "v2"}, "weight": 100}]}]
+			}
+		}`,
+	})
+	defer mockServer.Close()
+
+	istio := newTestIstio(t, mockServer.URL)
+	ctx := context.Background()
+
+	t.Run("returns full spec", func(t *testing.T) {
+		result, err := istio.GetResource(ctx, "VirtualService", "bookinfo", "reviews")
+		if err != nil {
+			t.Fatalf("Failed to get resource: %v", err)
+		}
+		assertContains(t, result,
+			"VirtualService 'reviews' in namespace 'bookinfo'",
+			"apiVersion: networking.istio.io/v1alpha3",
+			"kind: VirtualService",
+			"subset: v2",
+			"weight: 100",
+		)
+		assertNotContains(t, result, "managedFields")
+	})
+
+	t.Run("includes managed fields when requested", func(t *testing.T) {
+		result, err := istio.GetResource(ctx, "VirtualService", "bookinfo", "reviews", WithManagedFields(true))
+		if err != nil {
+			t.Fatalf("Failed to get resource: %v", err)
+		}
+		assertContains(t, result, "managedFields:", "manager: kubectl-client-side-apply", "operation: Update")
+	})
+
+	t.Run("accepts plural kind", func(t *testing.T) {
+		result, err := istio.GetResource(ctx, "virtualservices", "bookinfo", "reviews")
+		if err != nil {
+			t.Fatalf("Failed to get resource: %v", err)
+		}
+		assertContains(t, result, "name: reviews")
+	})
+
+	t.Run("rejects unsupported kind", func(t *testing.T) {
+		_, err := istio.GetResource(ctx, "Deployment", "bookinfo", "reviews")
+		if err == nil {
+			t.Fatal("Expected error for unsupported kind")
+		}
+		assertContains(t, err.Error(), "unsupported resource kind", "VirtualService")
+	})
+
+	t.Run("reports missing resource", func(t *testing.T) {
+		_, err := istio.GetResource(ctx, "DestinationRule", "bookinfo", "missing")
+		if err == nil {
+			t.Fatal("Expected error for missing resource")
+		}
+		assertContains(t, err.Error(), "failed to get DestinationRule missing")
+	})
+}
+
+// TestGetResourceForEditing tests that the editable YAML has no server fields and decodes back to the same resource
+func TestGetResourceForEditing(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{
+		"/apis/networking.istio.io/v1alpha3/namespaces/bookinfo/destinationrules/reviews": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "DestinationRule",
+			"metadata": {
+				"name": "reviews",
+				"namespace": "bookinfo",
+				"labels": {"team": "reviews"},
+				"uid": "2f1c7e9a-0d5b-4c3e-8a61-7b9d4e2f1a03",
+				"resourceVersion": "48213",
+				"generation": 3,
+				"creationTimestamp": "2025-01-01T00:00:00Z",
+				"managedFields": [{"manager": "kubectl-client-side-apply", "operation": "Update"}]
+			},
+			"spec": {
+				"host": "reviews",
+				"trafficPolicy": {"connectionPool": {"http": {"http1MaxPendingRequests": 10}}},
+				"subsets": [{"name": "v1", "labels": {"version": "v1"}}]
+			},
+			"status": {"observedGeneration": "3"}
+		}`,
+	})
+	defer mockServer.Close()
+
+	istio := newTestIstio(t, mockServer.URL)
+
+	result, err := istio.GetResourceForEditing(context.Background(), "DestinationRule", "bookinfo", "reviews")
+	if err != nil {
+		t.Fatalf("Failed to get resource for editing: %v", err)
+	}
+	assertNotContains(t, result, "uid:", "resourceVersion:", "generation:", "creationTimestamp:", "managedFields:", "status:", "in namespace")
+
+	// Applying the YAML must not carry a resourceVersion (optimistic concurrency conflict) and must keep the spec
+	var applied networkingv1alpha3.DestinationRule
+	if err := yaml.UnmarshalStrict([]byte(result), &applied); err != nil {
+		t.Fatalf("Edited YAML doesn't decode as a DestinationRule: %v\n%s", err, result)
+	}
+	if applied.APIVersion != "networking.istio.io/v1alpha3" || applied.Kind != "DestinationRule" {
+		t.Errorf("Expected type metadata to be kept, got %s %s", applied.APIVersion, applied.Kind)
+	}
+	if applied.Name != "reviews" || applied.Namespace != "bookinfo" || applied.Labels["team"] != "reviews" {
+		t.Errorf("Expected identifying metadata to be kept, got %+v", applied.ObjectMeta)
+	}
+	if applied.ResourceVersion != "" || applied.UID != "" || applied.Generation != 0 {
+		t.Errorf("Expected no server fields, got %+v", applied.ObjectMeta)
+	}
+	if applied.Spec.GetHost() != "reviews" || applied.Spec.GetTrafficPolicy().GetConnectionPool().GetHttp().GetHttp1MaxPendingRequests() != 10 ||
+		len(applied.Spec.GetSubsets()) != 1 || applied.Spec.GetSubsets()[0].GetLabels()["version"] != "v1" {
+		t.Errorf("Expected spec to round-trip, got %v", &applied.Spec)
+	}
+}
@@ -0,0 +1,64 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AnalyzeRetryTimeoutConfig inspects the retries and timeouts of the HTTP routes of the VirtualServices in a
+// namespace and flags combinations that don't work as intended: a per-try timeout longer than the route timeout,
+// retries without a per-try timeout (the first attempt may use the whole route timeout), and retry budgets
+// (per-try timeout × attempts) that can't complete within the route timeout
+func (i *Istio) AnalyzeRetryTimeoutConfig(ctx context.Context, namespace string) (string, error) {
+	vsList, err := i.istioClient.NetworkingV1alpha3().VirtualServices(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list virtual services: %w", explainForbidden(err, "list", "virtualservices", namespace))
+	}
+
+	result := fmt.Sprintf("Retry and timeout analysis for Virtual Services in namespace '%s':\n\n", namespace)
+	issues, checked := 0, 0
+	for _, vs := range vsList.Items {
+		for idx, route := range vs.Spec.GetHttp() {
+			if route.GetTimeout() == nil || route.GetRetries() == nil {
+				continue
+			}
+			checked++
+			timeout := route.GetTimeout().AsDuration()
+			retries := route.GetRetries()
+			attempts := retries.GetAttempts()
+			if timeout == 0 || attempts == 0 {
+				continue
+			}
+			location := fmt.Sprintf("%s route %s", vs.Name, httpRouteName(route, idx))
+			// Envoy makes the initial request plus up to 'attempts' retries
+			tries := time.Duration(attempts + 1)
+			if retries.GetPerTryTimeout() == nil {
+				result += fmt.Sprintf("[WARNING] %s: %d retries without perTryTimeout; a slow first attempt can use the whole %s timeout, leaving no time for retries\n", location, attempts, timeout)
+				issues++
+				continue
+			}
+			perTry := retries.GetPerTryTimeout().AsDuration()
+			switch {
+			case perTry >= timeout:
+				result += fmt.Sprintf("[ERROR] %s: perTryTimeout %s is not shorter than the route timeout %s; a slow attempt is never retried\n", location, perTry, timeout)
+				issues++
+			case perTry*tries > timeout:
+				result += fmt.Sprintf("[WARNING] %s: %d attempts × perTryTimeout %s = %s exceed the route timeout %s; only %d of %d attempts can time out before the request fails\n",
+					location, attempts+1, perTry, perTry*tries, timeout, int(timeout/perTry), attempts+1)
+				issues++
+			}
+		}
+	}
+
+	if checked == 0 {
+		return result + "No HTTP routes set both retries and a timeout\n", nil
+	}
+	if issues == 0 {
+		return result + fmt.Sprintf("[OK] The retries of all %d HTTP routes with a timeout fit within it\n", checked), nil
+	}
+	result += fmt.Sprintf("\n[RESULT] %d retry/timeout issues found in %d HTTP routes with retries and a timeout\n", issues, checked)
+	return result, nil
+}
@@ -0,0 +1,41 @@
+package istio
+
+import (
+	"context"
+	"testing"
+)
+
+// TestAnalyzeRetryTimeoutConfig tests detection of retry and timeout combinations that cannot work
+func TestAnalyzeRetryTimeoutConfig(t *testing.T) {
+	server := newMockAPIServer(map[string]string{
+		"/apis/networking.istio.io/v1alpha3/namespaces/bookinfo/virtualservices": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "VirtualServiceList",
+			"items": [
+				{
+					"metadata": {"name": "reviews", "namespace": "bookinfo"},
+					"spec": {"hosts": ["reviews"], "http": [
+						{"name": "budget", "timeout": "5s", "retries": {"attempts": 3, "perTryTimeout": "2s"}, "route": [{"destination": {"host": "reviews"}}]},
+						{"timeout": "1s", "retries": {"attempts": 2, "perTryTimeout": "3s"}, "route": [{"destination": {"host": "reviews"}}]},
+						{"name": "no-per-try", "timeout": "10s", "retries": {"attempts": 2}, "route": [{"destination": {"host": "reviews"}}]},
+						{"name": "fits", "timeout": "10s", "retries": {"attempts": 2, "perTryTimeout": "3s"}, "route": [{"destination": {"host": "reviews"}}]}
+					]}
+				}
+			]
+		}`,
+	})
+	defer server.Close()
+	istio := newTestIstio(t, server.URL)
+
+	result, err := istio.AnalyzeRetryTimeoutConfig(context.Background(), "bookinfo")
+	if err != nil {
+		t.Fatalf("AnalyzeRetryTimeoutConfig failed: %v", err)
+	}
+	assertContains(t, result,
+		"[WARNING] reviews route 'budget': 4 attempts × perTryTimeout 2s = 8s exceed the route timeout 5s; only 2 of 4 attempts",
+		"[ERROR] reviews route #2: perTryTimeout 3s is not shorter than the route timeout 1s",
+		"[WARNING] reviews route 'no-per-try': 2 retries without perTryTimeout",
+		"[RESULT] 3 retry/timeout issues found in 4 HTTP routes",
+	)
+	assertNotContains(t, result, "'fits'")
+}
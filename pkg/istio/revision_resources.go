@@ -0,0 +1,92 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// revisionLabel selects the control plane revision that injects a namespace or processes a resource
+const revisionLabel = "istio.io/rev"
+
+// objectRevision returns the revision a resource is bound to by its istio.io/rev label, or annotation as a fallback
+func objectRevision(obj metav1.Object) string {
+	if rev := obj.GetLabels()[revisionLabel]; rev != "" {
+		return rev
+	}
+	return obj.GetAnnotations()[revisionLabel]
+}
+
+// GetResourcesByRevision lists the namespaces and Istio resources of every supported kind bound to a control plane
+// revision through the istio.io/rev label or annotation, to see what a revision owns during a canary upgrade
+func (i *Istio) GetResourcesByRevision(ctx context.Context, revision string) (string, error) {
+	namespaces, err := i.kubeClient.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list namespaces: %w", explainForbidden(err, "list", "namespaces", ""))
+	}
+	var owned []string
+	for _, ns := range namespaces.Items {
+		if objectRevision(&ns) == revision {
+			owned = append(owned, ns.Name)
+		}
+	}
+	sort.Strings(owned)
+
+	var resources []string
+	otherRevisions := make(map[string]int)
+	unlabeled := 0
+	for _, kind := range SupportedResourceKinds() {
+		rk := resourceKinds[strings.ToLower(kind)]
+		objects, err := rk.list(ctx, i, "")
+		if apierrors.IsNotFound(err) {
+			klog.Warningf("Skipping %s: resource type not available in the cluster", rk.plural)
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to list %s: %w", rk.plural, explainForbidden(err, "list", rk.plural, ""))
+		}
+		var names []string
+		for _, obj := range objects {
+			switch rev := objectRevision(obj); rev {
+			case revision:
+				names = append(names, obj.GetNamespace()+"/"+obj.GetName())
+			case "":
+				unlabeled++
+			default:
+				otherRevisions[rev]++
+			}
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			resources = append(resources, fmt.Sprintf("- %s %s", kind, name))
+		}
+	}
+
+	result := fmt.Sprintf("Resources bound to revision '%s':\n\n", revision)
+	result += fmt.Sprintf("Namespaces (%d):\n", len(owned))
+	for _, ns := range owned {
+		result += fmt.Sprintf("- %s\n", ns)
+	}
+	result += fmt.Sprintf("\nIstio resources (%d):\n", len(resources))
+	for _, resource := range resources {
+		result += resource + "\n"
+	}
+
+	if len(otherRevisions) > 0 {
+		revisions := make([]string, 0, len(otherRevisions))
+		for rev, count := range otherRevisions {
+			revisions = append(revisions, fmt.Sprintf("%s (%d)", rev, count))
+		}
+		sort.Strings(revisions)
+		result += fmt.Sprintf("\nResources bound to other revisions: %s\n", strings.Join(revisions, ", "))
+	}
+	if unlabeled > 0 {
+		result += fmt.Sprintf("\nNote: %d Istio resources have no istio.io/rev label and are processed by every revision\n", unlabeled)
+	}
+	return result, nil
+}
@@ -0,0 +1,54 @@
+package istio
+
+import (
+	"context"
+	"testing"
+)
+
+// TestGetResourcesByRevision tests listing of the namespaces and resources bound to a control plane revision
+func TestGetResourcesByRevision(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{
+		"/api/v1/namespaces": `{
+			"apiVersion": "v1",
+			"kind": "NamespaceList",
+			"items": [
+				{"metadata": {"name": "bookinfo", "labels": {"istio.io/rev": "1-25-1"}}},
+				{"metadata": {"name": "payments", "labels": {"istio.io/rev": "1-24-3"}}},
+				{"metadata": {"name": "legacy", "labels": {"istio-injection": "enabled"}}}
+			]
+		}`,
+		"/apis/networking.istio.io/v1alpha3/virtualservices": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "VirtualServiceList",
+			"items": [
+				{"metadata": {"name": "reviews", "namespace": "bookinfo", "labels": {"istio.io/rev": "1-25-1"}}, "spec": {"hosts": ["reviews"]}},
+				{"metadata": {"name": "checkout", "namespace": "payments", "labels": {"istio.io/rev": "1-24-3"}}, "spec": {"hosts": ["checkout"]}},
+				{"metadata": {"name": "ratings", "namespace": "bookinfo"}, "spec": {"hosts": ["ratings"]}}
+			]
+		}`,
+		"/apis/networking.istio.io/v1alpha3/gateways": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "GatewayList",
+			"items": [
+				{"metadata": {"name": "public", "namespace": "ingress", "annotations": {"istio.io/rev": "1-25-1"}}, "spec": {}}
+			]
+		}`,
+	})
+	defer mockServer.Close()
+
+	istio := newTestIstio(t, mockServer.URL)
+	result, err := istio.GetResourcesByRevision(context.Background(), "1-25-1")
+	if err != nil {
+		t.Fatalf("GetResourcesByRevision failed: %v", err)
+	}
+	assertContains(t, result,
+		"Resources bound to revision '1-25-1'",
+		"Namespaces (1):\n- bookinfo\n",
+		"Istio resources (2):",
+		"- Gateway ingress/public",
+		"- VirtualService bookinfo/reviews",
+		"Resources bound to other revisions: 1-24-3 (1)",
+		"Note: 1 Istio resources have no istio.io/rev label",
+	)
+	assertNotContains(t, result, "payments", "checkout", "legacy", "bookinfo/ratings")
+}
@@ -0,0 +1,78 @@
+package istio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// routeConfigurations is the subset of the JSON output of istioctl proxy-config route listing virtual hosts
+type routeConfigurations []struct {
+	Name         string `json:"name"`
+	VirtualHosts []struct {
+		Name    string   `json:"name"`
+		Domains []string `json:"domains"`
+	} `json:"virtualHosts"`
+}
+
+// ListProxyRoutableHosts lists the virtual hosts in the route configuration of a pod's proxy and the domains each
+// one matches, i.e. the HTTP hosts the workload can reach through the mesh. A Sidecar restricting egress shrinks
+// this list.
+func (p *ProxyConfigClient) ListProxyRoutableHosts(ctx context.Context, namespace, podName string) (string, error) {
+	output, err := p.GetRoutes(ctx, namespace, podName)
+	if err != nil {
+		return "", err
+	}
+	var routes routeConfigurations
+	if err := json.Unmarshal([]byte(output), &routes); err != nil {
+		return "", fmt.Errorf("failed to parse routes of pod %s: %w", podName, err)
+	}
+
+	// domains maps a virtual host to the domains it matches, routes to the route configurations (ports) defining it
+	domains := make(map[string][]string)
+	routeNames := make(map[string][]string)
+	passthrough, blocked := false, false
+	for _, route := range routes {
+		for _, vh := range route.VirtualHosts {
+			switch vh.Name {
+			case "allow_any":
+				passthrough = true
+				continue
+			case "block_all":
+				blocked = true
+				continue
+			}
+			if strings.HasPrefix(vh.Name, "inbound|") {
+				continue
+			}
+			for _, domain := range vh.Domains {
+				if !slices.Contains(domains[vh.Name], domain) {
+					domains[vh.Name] = append(domains[vh.Name], domain)
+				}
+			}
+			if !slices.Contains(routeNames[vh.Name], route.Name) {
+				routeNames[vh.Name] = append(routeNames[vh.Name], route.Name)
+			}
+		}
+	}
+
+	hosts := make([]string, 0, len(domains))
+	for host := range domains {
+		hosts = append(hosts, host)
+	}
+	slices.Sort(hosts)
+
+	result := fmt.Sprintf("Hosts routable by the proxy of pod '%s' in namespace '%s' (%d virtual hosts):\n\n", podName, namespace, len(hosts))
+	for _, host := range hosts {
+		result += fmt.Sprintf("- %s (route %s): %s\n", host, strings.Join(routeNames[host], ", "), strings.Join(domains[host], ", "))
+	}
+	switch {
+	case passthrough:
+		result += "\nRequests to other hosts are passed through to their original destination (outboundTrafficPolicy ALLOW_ANY)\n"
+	case blocked:
+		result += "\nRequests to other hosts are blocked (outboundTrafficPolicy REGISTRY_ONLY)\n"
+	}
+	return result, nil
+}
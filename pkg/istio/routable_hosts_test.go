@@ -0,0 +1,49 @@
+package istio
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+// TestListProxyRoutableHosts tests extracting the virtual hosts and domains from a proxy's route configuration
+func TestListProxyRoutableHosts(t *testing.T) {
+	client := NewProxyConfigClient("")
+	args := stubIstioctl(client, `[
+		{
+			"name": "9080",
+			"virtualHosts": [
+				{"name": "reviews.bookinfo.svc.cluster.local:9080", "domains": ["reviews.bookinfo.svc.cluster.local", "reviews", "reviews.bookinfo.svc", "reviews.bookinfo", "10.96.12.4"], "routes": [{"match": {"prefix": "/"}, "route": {"cluster": "outbound|9080||reviews.bookinfo.svc.cluster.local"}}]},
+				{"name": "ratings.bookinfo.svc.cluster.local:9080", "domains": ["ratings.bookinfo.svc.cluster.local", "ratings"]},
+				{"name": "allow_any", "domains": ["*"]}
+			]
+		},
+		{
+			"name": "80",
+			"virtualHosts": [
+				{"name": "api.example.com:80", "domains": ["api.example.com"]},
+				{"name": "allow_any", "domains": ["*"]}
+			]
+		},
+		{
+			"name": "inbound|9080||",
+			"virtualHosts": [{"name": "inbound|http|9080", "domains": ["*"]}]
+		}
+	]`)
+
+	result, err := client.ListProxyRoutableHosts(context.Background(), "bookinfo", "productpage-v1-abc")
+	if err != nil {
+		t.Fatalf("ListProxyRoutableHosts failed: %v", err)
+	}
+	if !slices.Contains(*args, "route") || !slices.Contains(*args, "productpage-v1-abc.bookinfo") {
+		t.Errorf("Expected the route config of the pod to be read, got args %v", *args)
+	}
+	assertContains(t, result,
+		"Hosts routable by the proxy of pod 'productpage-v1-abc' in namespace 'bookinfo' (3 virtual hosts)",
+		"- api.example.com:80 (route 80): api.example.com\n",
+		"- ratings.bookinfo.svc.cluster.local:9080 (route 9080): ratings.bookinfo.svc.cluster.local, ratings\n",
+		"- reviews.bookinfo.svc.cluster.local:9080 (route 9080): reviews.bookinfo.svc.cluster.local, reviews, reviews.bookinfo.svc, reviews.bookinfo, 10.96.12.4\n",
+		"Requests to other hosts are passed through",
+	)
+	assertNotContains(t, result, "inbound|", "allow_any")
+}
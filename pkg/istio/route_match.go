@@ -0,0 +1,212 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	networkingapi "istio.io/api/networking/v1alpha3"
+	networkingv1alpha3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// simulatedRequest is an HTTP request evaluated against VirtualService match conditions
+type simulatedRequest struct {
+	authority string
+	path      string
+	query     url.Values
+	method    string
+	// headers are keyed by lower-case name
+	headers map[string]string
+}
+
+// stringMatches reports whether a value satisfies an Istio StringMatch; regexes must match the whole value.
+// An empty StringMatch only requires the value to be present.
+func stringMatches(match *networkingapi.StringMatch, value string, ignoreCase bool) bool {
+	if ignoreCase {
+		value = strings.ToLower(value)
+	}
+	switch {
+	case match.GetExact() != "":
+		expected := match.GetExact()
+		if ignoreCase {
+			expected = strings.ToLower(expected)
+		}
+		return expected == value
+	case match.GetPrefix() != "":
+		prefix := match.GetPrefix()
+		if ignoreCase {
+			prefix = strings.ToLower(prefix)
+		}
+		return strings.HasPrefix(value, prefix)
+	case match.GetRegex() != "":
+		matched, err := regexp.MatchString("^(?:"+match.GetRegex()+")$", value)
+		return err == nil && matched
+	}
+	return true
+}
+
+// requestMatches evaluates one HTTPMatchRequest against a request, returning the first condition it fails.
+// Conditions on the source workload, gateway or port are not evaluated.
+func requestMatches(match *networkingapi.HTTPMatchRequest, req simulatedRequest) (bool, string) {
+	if match.GetUri() != nil && !stringMatches(match.GetUri(), req.path, match.GetIgnoreUriCase()) {
+		return false, fmt.Sprintf("uri '%s' does not match '%s'", describeStringMatch(match.GetUri()), req.path)
+	}
+	if match.GetMethod() != nil && !stringMatches(match.GetMethod(), req.method, false) {
+		return false, fmt.Sprintf("method '%s' does not match '%s'", describeStringMatch(match.GetMethod()), req.method)
+	}
+	if match.GetAuthority() != nil && !stringMatches(match.GetAuthority(), req.authority, false) {
+		return false, fmt.Sprintf("authority '%s' does not match '%s'", describeStringMatch(match.GetAuthority()), req.authority)
+	}
+	for _, name := range slices.Sorted(maps.Keys(match.GetHeaders())) {
+		headerMatch := match.GetHeaders()[name]
+		value, ok := req.headers[strings.ToLower(name)]
+		if !ok {
+			return false, fmt.Sprintf("header '%s' is missing", name)
+		}
+		if !stringMatches(headerMatch, value, false) {
+			return false, fmt.Sprintf("header '%s' '%s' does not match '%s'", name, describeStringMatch(headerMatch), value)
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(match.GetWithoutHeaders())) {
+		headerMatch := match.GetWithoutHeaders()[name]
+		if value, ok := req.headers[strings.ToLower(name)]; ok && stringMatches(headerMatch, value, false) {
+			return false, fmt.Sprintf("header '%s' is present but listed in withoutHeaders", name)
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(match.GetQueryParams())) {
+		paramMatch := match.GetQueryParams()[name]
+		if !req.query.Has(name) {
+			return false, fmt.Sprintf("query parameter '%s' is missing", name)
+		}
+		if value := req.query.Get(name); !stringMatches(paramMatch, value, false) {
+			return false, fmt.Sprintf("query parameter '%s' '%s' does not match '%s'", name, describeStringMatch(paramMatch), value)
+		}
+	}
+	return true, ""
+}
+
+// meshVirtualServicesForHost returns the VirtualServices bound to the mesh gateway that define a host and are
+// visible in namespace, those of the namespace itself first; VirtualServices without exportTo follow the mesh default
+func meshVirtualServicesForHost(items []*networkingv1alpha3.VirtualService, namespace, target string, mesh *meshConfig) []*networkingv1alpha3.VirtualService {
+	var virtualServices []*networkingv1alpha3.VirtualService
+	for _, vs := range items {
+		if len(vs.Spec.Gateways) > 0 && !slices.Contains(vs.Spec.Gateways, "mesh") {
+			continue
+		}
+		if !exportedTo(effectiveExportTo(vs.Spec.ExportTo, mesh.DefaultVirtualServiceExportTo), vs.Namespace, namespace) {
+			continue
+		}
+		for _, vsHost := range vs.Spec.Hosts {
+			if hostMatches(qualifiedHost(vsHost, vs.Namespace), target) {
+				virtualServices = append(virtualServices, vs)
+				break
+			}
+		}
+	}
+	sort.SliceStable(virtualServices, func(a, b int) bool {
+		return virtualServices[a].Namespace == namespace && virtualServices[b].Namespace != namespace
+	})
+	return virtualServices
+}
+
+// MatchVirtualServiceRoute statically simulates Istio's router for a request sent from namespace to host: it
+// evaluates the HTTP match conditions of the VirtualService for the host in order and reports the first route that
+// matches the path, method and headers, and where it sends the request
+func (i *Istio) MatchVirtualServiceRoute(ctx context.Context, namespace, host, path, method string, headers map[string]string) (string, error) {
+	if path == "" {
+		path = "/"
+	}
+	if method == "" {
+		method = "GET"
+	}
+	target, err := i.resolveHost(ctx, host, namespace)
+	if err != nil {
+		return "", err
+	}
+	req := simulatedRequest{authority: host, method: strings.ToUpper(method), headers: make(map[string]string, len(headers))}
+	req.path, _, _ = strings.Cut(path, "?")
+	if _, rawQuery, found := strings.Cut(path, "?"); found {
+		query, err := url.ParseQuery(rawQuery)
+		if err != nil {
+			return "", fmt.Errorf("invalid query string in path '%s': %w", path, err)
+		}
+		req.query = query
+	}
+	for name, value := range headers {
+		req.headers[strings.ToLower(name)] = value
+	}
+
+	mesh, err := i.getMeshConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+	vsList, err := i.istioClient.NetworkingV1alpha3().VirtualServices("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list virtual services: %w", explainForbidden(err, "list", "virtualservices", ""))
+	}
+
+	result := fmt.Sprintf("Route match for %s %s%s from namespace '%s':\n\n", req.method, target, path, namespace)
+	virtualServices := meshVirtualServicesForHost(vsList.Items, namespace, target, mesh)
+	if len(virtualServices) == 0 {
+		result += "No VirtualService defines this host; the request goes to the host with default routing\n"
+		return result, nil
+	}
+	vs := virtualServices[0]
+	result += fmt.Sprintf("VirtualService '%s/%s':\n", vs.Namespace, vs.Name)
+	if len(virtualServices) > 1 {
+		result += fmt.Sprintf("[WARNING] %d VirtualServices define this host; only the first is considered\n", len(virtualServices))
+	}
+
+	for idx, route := range vs.Spec.GetHttp() {
+		matched := len(route.GetMatch()) == 0
+		var reasons []string
+		for _, match := range route.GetMatch() {
+			ok, reason := requestMatches(match, req)
+			if ok {
+				matched = true
+				break
+			}
+			reasons = append(reasons, reason)
+		}
+		if !matched {
+			result += fmt.Sprintf("  [SKIP] Route %s: %s\n", httpRouteName(route, idx), strings.Join(reasons, "; "))
+			continue
+		}
+
+		result += fmt.Sprintf("  [MATCH] Route %s\n\n", httpRouteName(route, idx))
+		switch {
+		case route.GetRedirect() != nil:
+			result += fmt.Sprintf("[RESULT] The request is redirected to %s%s\n", route.GetRedirect().GetAuthority(), route.GetRedirect().GetUri())
+		case route.GetDirectResponse() != nil:
+			result += fmt.Sprintf("[RESULT] The request gets a direct response with status %d\n", route.GetDirectResponse().GetStatus())
+		default:
+			if route.GetRewrite().GetUri() != "" {
+				result += fmt.Sprintf("The path is rewritten to '%s'\n", route.GetRewrite().GetUri())
+			}
+			result += "[RESULT] The request is sent to:\n"
+			for _, rd := range route.GetRoute() {
+				line := fmt.Sprintf("  -> %s", qualifiedHost(rd.GetDestination().GetHost(), vs.Namespace))
+				if port := rd.GetDestination().GetPort().GetNumber(); port != 0 {
+					line += fmt.Sprintf(":%d", port)
+				}
+				if subset := rd.GetDestination().GetSubset(); subset != "" {
+					line += fmt.Sprintf(" subset '%s'", subset)
+				}
+				if len(route.GetRoute()) > 1 {
+					line += fmt.Sprintf(" (weight %d)", rd.GetWeight())
+				}
+				result += line + "\n"
+			}
+		}
+		return result, nil
+	}
+
+	result += "\n[RESULT] No route matches; the request gets a 404\n"
+	return result, nil
+}
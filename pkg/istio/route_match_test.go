@@ -0,0 +1,122 @@
+package istio
+
+import (
+	"context"
+	"testing"
+)
+
+// TestMatchVirtualServiceRoute tests that the routes of a VirtualService are evaluated in order and the first match wins
+func TestMatchVirtualServiceRoute(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{
+		"/apis/networking.istio.io/v1alpha3/virtualservices": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "VirtualServiceList",
+			"items": [{
+				"metadata": {"name": "reviews", "namespace": "bookinfo"},
+				"spec": {
+					"hosts": ["reviews"],
+					"http": [
+						{
+							"name": "jason",
+							"match": [{"headers": {"end-user": {"exact": "jason"}}}],
+							"route": [{"destination": {"host": "reviews", "subset": "v2"}}]
+						},
+						{
+							"name": "api-writes",
+							"match": [{"uri": {"prefix": "/api"}, "method": {"exact": "POST"}}],
+							"route": [{"destination": {"host": "reviews", "subset": "v3"}}]
+						},
+						{
+							"name": "api",
+							"match": [{"uri": {"prefix": "/api"}}],
+							"route": [
+								{"destination": {"host": "reviews", "subset": "v1"}, "weight": 90},
+								{"destination": {"host": "reviews", "subset": "v3"}, "weight": 10}
+							]
+						}
+					]
+				}
+			}]
+		}`,
+	})
+	defer mockServer.Close()
+	istio := newTestIstio(t, mockServer.URL)
+	ctx := context.Background()
+
+	t.Run("header match wins over later routes", func(t *testing.T) {
+		result, err := istio.MatchVirtualServiceRoute(ctx, "bookinfo", "reviews", "/api/books", "POST", map[string]string{"End-User": "jason"})
+		if err != nil {
+			t.Fatalf("Failed to match route: %v", err)
+		}
+		assertContains(t, result, "[MATCH] Route 'jason'", "-> reviews.bookinfo.svc.cluster.local subset 'v2'")
+		assertNotContains(t, result, "'api-writes'")
+	})
+
+	t.Run("first route matching path and method wins", func(t *testing.T) {
+		result, err := istio.MatchVirtualServiceRoute(ctx, "bookinfo", "reviews", "/api/books", "post", nil)
+		if err != nil {
+			t.Fatalf("Failed to match route: %v", err)
+		}
+		assertContains(t, result,
+			"[SKIP] Route 'jason': header 'end-user' is missing",
+			"[MATCH] Route 'api-writes'",
+			"subset 'v3'",
+		)
+	})
+
+	t.Run("method mismatch falls through to the next route", func(t *testing.T) {
+		result, err := istio.MatchVirtualServiceRoute(ctx, "bookinfo", "reviews", "/api/books", "GET", nil)
+		if err != nil {
+			t.Fatalf("Failed to match route: %v", err)
+		}
+		assertContains(t, result,
+			"[SKIP] Route 'api-writes': method 'POST' does not match 'GET'",
+			"[MATCH] Route 'api'",
+			"subset 'v1' (weight 90)",
+		)
+	})
+
+	t.Run("no matching route", func(t *testing.T) {
+		result, err := istio.MatchVirtualServiceRoute(ctx, "bookinfo", "reviews", "/health", "GET", nil)
+		if err != nil {
+			t.Fatalf("Failed to match route: %v", err)
+		}
+		assertContains(t, result, "uri 'prefix:/api' does not match '/health'", "[RESULT] No route matches; the request gets a 404")
+	})
+}
+
+// TestMatchVirtualServiceRouteDefaultExportTo tests that a VirtualService without exportTo is only matched where the
+// mesh default exportTo makes it visible
+func TestMatchVirtualServiceRouteDefaultExportTo(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{
+		"/api/v1/namespaces/istio-system/configmaps/istio": `{
+			"apiVersion": "v1",
+			"kind": "ConfigMap",
+			"metadata": {"name": "istio", "namespace": "istio-system"},
+			"data": {"mesh": "defaultVirtualServiceExportTo:\n- .\n"}
+		}`,
+		"/apis/networking.istio.io/v1alpha3/virtualservices": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "VirtualServiceList",
+			"items": [{
+				"metadata": {"name": "reviews", "namespace": "bookinfo"},
+				"spec": {"hosts": ["reviews"], "http": [{"route": [{"destination": {"host": "reviews", "subset": "v2"}}]}]}
+			}]
+		}`,
+	})
+	defer mockServer.Close()
+	istio := newTestIstio(t, mockServer.URL)
+	ctx := context.Background()
+
+	result, err := istio.MatchVirtualServiceRoute(ctx, "bookinfo", "reviews", "/", "GET", nil)
+	if err != nil {
+		t.Fatalf("Failed to match route: %v", err)
+	}
+	assertContains(t, result, "VirtualService 'bookinfo/reviews'", "subset 'v2'")
+
+	result, err = istio.MatchVirtualServiceRoute(ctx, "frontend", "reviews.bookinfo.svc.cluster.local", "/", "GET", nil)
+	if err != nil {
+		t.Fatalf("Failed to match route: %v", err)
+	}
+	assertContains(t, result, "No VirtualService defines this host")
+}
@@ -0,0 +1,76 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// FindServicesWithoutRouting lists the services of a namespace backed by mesh workloads that no VirtualService
+// routes and no DestinationRule configures, in any namespace. Such services rely on Istio's default routing
+// (round robin, no retries overrides, no timeouts), which may be intentional or a gap in configuration.
+func (i *Istio) FindServicesWithoutRouting(ctx context.Context, namespace string) (string, error) {
+	services, err := i.kubeClient.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list services: %w", explainForbidden(err, "list", "services", namespace))
+	}
+	pods, err := i.kubeClient.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list pods: %w", explainForbidden(err, "list", "pods", namespace))
+	}
+	vsList, err := i.istioClient.NetworkingV1alpha3().VirtualServices("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list virtual services: %w", explainForbidden(err, "list", "virtualservices", ""))
+	}
+	drList, err := i.istioClient.NetworkingV1alpha3().DestinationRules("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list destination rules: %w", explainForbidden(err, "list", "destinationrules", ""))
+	}
+
+	// Hosts claimed by VirtualServices and DestinationRules, qualified relative to their namespace; patterns may be wildcards
+	var routedHosts, configuredHosts []string
+	for _, vs := range vsList.Items {
+		for _, host := range vs.Spec.GetHosts() {
+			routedHosts = append(routedHosts, qualifiedHost(host, vs.Namespace))
+		}
+	}
+	for _, dr := range drList.Items {
+		configuredHosts = append(configuredHosts, qualifiedHost(dr.Spec.GetHost(), dr.Namespace))
+	}
+
+	namespaceLabels := i.namespaceLabels(ctx, namespace)
+	meshed, unconfigured := 0, 0
+	var lines string
+	for idx := range services.Items {
+		service := &services.Items[idx]
+		inMesh := slices.ContainsFunc(servicePods(service, pods.Items), func(pod v1.Pod) bool {
+			return podMeshMode(pod, namespaceLabels) != meshModeNone
+		})
+		if !inMesh {
+			continue
+		}
+		meshed++
+
+		host := qualifiedHost(service.Name, service.Namespace)
+		matches := func(pattern string) bool { return hostMatches(pattern, host) }
+		if slices.ContainsFunc(routedHosts, matches) || slices.ContainsFunc(configuredHosts, matches) {
+			continue
+		}
+		unconfigured++
+		lines += fmt.Sprintf("[WARNING] %s (%s): no VirtualService or DestinationRule; default routing and connection settings apply\n", service.Name, host)
+	}
+
+	result := fmt.Sprintf("Services without Istio routing configuration in namespace '%s':\n\n", namespace)
+	if meshed == 0 {
+		return result + "No services backed by mesh workloads found\n", nil
+	}
+	if unconfigured == 0 {
+		return result + fmt.Sprintf("[OK] All %d services backed by mesh workloads have a VirtualService or DestinationRule\n", meshed), nil
+	}
+	result += lines
+	result += fmt.Sprintf("\n[RESULT] %d of %d services backed by mesh workloads use default routing\n", unconfigured, meshed)
+	return result, nil
+}
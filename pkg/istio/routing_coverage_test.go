@@ -0,0 +1,55 @@
+package istio
+
+import (
+	"context"
+	"testing"
+)
+
+// TestFindServicesWithoutRouting tests listing of mesh services that no VirtualService or DestinationRule configures
+func TestFindServicesWithoutRouting(t *testing.T) {
+	server := newMockAPIServer(map[string]string{
+		"/api/v1/namespaces/bookinfo/services": `{
+			"apiVersion": "v1",
+			"kind": "ServiceList",
+			"items": [
+				{"metadata": {"name": "reviews", "namespace": "bookinfo"}, "spec": {"selector": {"app": "reviews"}}},
+				{"metadata": {"name": "ratings", "namespace": "bookinfo"}, "spec": {"selector": {"app": "ratings"}}},
+				{"metadata": {"name": "legacy", "namespace": "bookinfo"}, "spec": {"selector": {"app": "legacy"}}}
+			]
+		}`,
+		"/api/v1/namespaces/bookinfo/pods": `{
+			"apiVersion": "v1",
+			"kind": "PodList",
+			"items": [
+				{"metadata": {"name": "reviews-v1", "labels": {"app": "reviews"}}, "spec": {"containers": [{"name": "reviews"}, {"name": "istio-proxy"}]}},
+				{"metadata": {"name": "ratings-v1", "labels": {"app": "ratings"}}, "spec": {"containers": [{"name": "ratings"}, {"name": "istio-proxy"}]}},
+				{"metadata": {"name": "legacy-v1", "labels": {"app": "legacy"}}, "spec": {"containers": [{"name": "legacy"}]}}
+			]
+		}`,
+		"/apis/networking.istio.io/v1alpha3/virtualservices": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "VirtualServiceList",
+			"items": [{
+				"metadata": {"name": "reviews", "namespace": "bookinfo"},
+				"spec": {"hosts": ["reviews"], "http": [{"route": [{"destination": {"host": "reviews"}}]}]}
+			}]
+		}`,
+		"/apis/networking.istio.io/v1alpha3/destinationrules": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "DestinationRuleList",
+			"items": []
+		}`,
+	})
+	defer server.Close()
+	istio := newTestIstio(t, server.URL)
+
+	result, err := istio.FindServicesWithoutRouting(context.Background(), "bookinfo")
+	if err != nil {
+		t.Fatalf("FindServicesWithoutRouting failed: %v", err)
+	}
+	assertContains(t, result,
+		"[WARNING] ratings (ratings.bookinfo.svc.cluster.local): no VirtualService or DestinationRule",
+		"[RESULT] 1 of 2 services backed by mesh workloads use default routing",
+	)
+	assertNotContains(t, result, "reviews (", "legacy")
+}
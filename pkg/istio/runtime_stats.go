@@ -0,0 +1,100 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// proxyRuntimeStats are the server-wide memory and load figures of an Envoy proxy
+type proxyRuntimeStats struct {
+	// tcmalloc figures, also reported by the /memory admin endpoint
+	memoryAllocated int
+	memoryHeapSize  int
+	memoryPhysical  int
+	// Open downstream connections and in-flight requests
+	totalConnections   int
+	downstreamCxActive int
+	downstreamRqActive int
+	upstreamRqActive   int
+	concurrency        int
+	uptimeSeconds      int
+}
+
+// parseProxyRuntimeStats reads the server memory gauges and the active connection and request gauges of the
+// listeners, HTTP connection managers and clusters from Envoy's /stats output
+func parseProxyRuntimeStats(output string) *proxyRuntimeStats {
+	stats := &proxyRuntimeStats{}
+	for _, line := range strings.Split(output, "\n") {
+		name, value, found := strings.Cut(strings.TrimSpace(line), ": ")
+		if !found {
+			continue
+		}
+		count, err := strconv.Atoi(value)
+		if err != nil {
+			continue
+		}
+		switch {
+		case name == "server.memory_allocated":
+			stats.memoryAllocated = count
+		case name == "server.memory_heap_size":
+			stats.memoryHeapSize = count
+		case name == "server.memory_physical_size":
+			stats.memoryPhysical = count
+		case name == "server.total_connections":
+			stats.totalConnections = count
+		case name == "server.concurrency":
+			stats.concurrency = count
+		case name == "server.uptime":
+			stats.uptimeSeconds = count
+		case strings.HasPrefix(name, "listener.") && strings.HasSuffix(name, ".downstream_cx_active") && !strings.Contains(name, ".worker_"):
+			stats.downstreamCxActive += count
+		case strings.HasPrefix(name, "http.") && strings.HasSuffix(name, ".downstream_rq_active"):
+			stats.downstreamRqActive += count
+		case strings.HasPrefix(name, "cluster.") && strings.HasSuffix(name, ".upstream_rq_active"):
+			stats.upstreamRqActive += count
+		}
+	}
+	return stats
+}
+
+// formatBytes renders a byte count in MiB
+func formatBytes(bytes int) string {
+	return fmt.Sprintf("%.1f MiB", float64(bytes)/(1024*1024))
+}
+
+// GetProxyRuntimeStats reports the memory use and the connection and request load of a pod's Envoy proxy from its
+// /stats admin output: the tcmalloc allocated, heap and physical sizes (the figures of the /memory endpoint), the
+// total and active downstream connections, and the active downstream and upstream requests
+func (p *ProxyConfigClient) GetProxyRuntimeStats(ctx context.Context, namespace, podName string) (string, error) {
+	output, err := p.execIstioctl(ctx, "experimental", "envoy-stats", fmt.Sprintf("%s.%s", podName, namespace), "--type", "server")
+	if err != nil {
+		return "", err
+	}
+	stats := parseProxyRuntimeStats(output)
+
+	result := fmt.Sprintf("Runtime stats of the proxy of pod '%s' in namespace '%s':\n\n", podName, namespace)
+	if stats.uptimeSeconds > 0 {
+		result += fmt.Sprintf("Uptime: %ds, worker threads: %d\n\n", stats.uptimeSeconds, stats.concurrency)
+	}
+	result += "Memory:\n"
+	result += fmt.Sprintf("  Allocated: %s (%d bytes)\n", formatBytes(stats.memoryAllocated), stats.memoryAllocated)
+	result += fmt.Sprintf("  Heap size: %s (%d bytes)\n", formatBytes(stats.memoryHeapSize), stats.memoryHeapSize)
+	result += fmt.Sprintf("  Physical: %s (%d bytes)\n", formatBytes(stats.memoryPhysical), stats.memoryPhysical)
+	result += "\nLoad:\n"
+	result += fmt.Sprintf("  Total connections: %d\n", stats.totalConnections)
+	result += fmt.Sprintf("  Active downstream connections: %d\n", stats.downstreamCxActive)
+	result += fmt.Sprintf("  Active downstream requests: %d\n", stats.downstreamRqActive)
+	result += fmt.Sprintf("  Active upstream requests: %d\n", stats.upstreamRqActive)
+
+	// Heap held by tcmalloc but not allocated is not returned to the OS; a large gap points to fragmentation
+	// after a burst of configuration or traffic
+	if stats.memoryAllocated > 0 && stats.memoryHeapSize > 2*stats.memoryAllocated {
+		result += fmt.Sprintf("\n[WARNING] The heap (%s) is more than twice the allocated memory (%s); memory freed after a peak is not returned to the OS\n", formatBytes(stats.memoryHeapSize), formatBytes(stats.memoryAllocated))
+	}
+	if stats.memoryAllocated == 0 && stats.totalConnections == 0 {
+		result += "\n[WARNING] No server memory or connection stats found in the proxy's /stats output\n"
+	}
+	return result, nil
+}
@@ -0,0 +1,43 @@
+package istio
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+// TestGetProxyRuntimeStats tests parsing the memory and connection figures from a proxy's /stats output
+func TestGetProxyRuntimeStats(t *testing.T) {
+	client := NewProxyConfigClient("")
+	args := stubIstioctl(client, `cluster.outbound|9080||reviews.bookinfo.svc.cluster.local.upstream_rq_active: 3
+cluster.outbound|9080||ratings.bookinfo.svc.cluster.local.upstream_rq_active: 1
+http.inbound_0.0.0.0_9080.downstream_rq_active: 2
+listener.0.0.0.0_15006.downstream_cx_active: 5
+listener.0.0.0.0_15006.worker_0.downstream_cx_active: 5
+server.concurrency: 2
+server.memory_allocated: 52428800
+server.memory_heap_size: 134217728
+server.memory_physical_size: 140509184
+server.total_connections: 7
+server.uptime: 3600
+`)
+
+	result, err := client.GetProxyRuntimeStats(context.Background(), "bookinfo", "productpage-v1-abc")
+	if err != nil {
+		t.Fatalf("GetProxyRuntimeStats failed: %v", err)
+	}
+	if !slices.Contains(*args, "productpage-v1-abc.bookinfo") || !slices.Contains(*args, "envoy-stats") {
+		t.Errorf("Expected envoy-stats for productpage-v1-abc.bookinfo, got %v", *args)
+	}
+	assertContains(t, result,
+		"Uptime: 3600s, worker threads: 2",
+		"Allocated: 50.0 MiB (52428800 bytes)",
+		"Heap size: 128.0 MiB (134217728 bytes)",
+		"Physical: 134.0 MiB (140509184 bytes)",
+		"Total connections: 7",
+		"Active downstream connections: 5",
+		"Active downstream requests: 2",
+		"Active upstream requests: 4",
+		"[WARNING] The heap (128.0 MiB) is more than twice the allocated memory (50.0 MiB)",
+	)
+}
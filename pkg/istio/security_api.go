@@ -0,0 +1,130 @@
+package istio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	securityv1 "istio.io/client-go/pkg/apis/security/v1"
+	securityv1beta1 "istio.io/client-go/pkg/apis/security/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+)
+
+// securityGroupVersionV1 is the security API version served by current Istio releases, which will eventually drop v1beta1
+const securityGroupVersionV1 = "security.istio.io/v1"
+
+// useSecurityV1 reports whether the cluster serves the security resources under security.istio.io/v1.
+// The API server is asked once per client; older clusters, and discovery failures, use v1beta1.
+func (i *Istio) useSecurityV1() bool {
+	i.securityAPIOnce.Do(func() {
+		resources, err := i.kubeClient.Discovery().ServerResourcesForGroupVersion(securityGroupVersionV1)
+		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				klog.V(1).Infof("Failed to discover %s, using v1beta1: %v", securityGroupVersionV1, err)
+			}
+			return
+		}
+		for _, resource := range resources.APIResources {
+			if resource.Name == "authorizationpolicies" {
+				i.securityV1 = true
+				return
+			}
+		}
+	})
+	return i.securityV1
+}
+
+// resourceGVK returns the group, version and kind a resource is printed with: security resources carry the version
+// the cluster serves, since they are read from it even though they are held as v1beta1 objects
+func (i *Istio) resourceGVK(rk resourceKind) schema.GroupVersionKind {
+	gvk := rk.gvk
+	if gvk.Group == securityv1.SchemeGroupVersion.Group && i.useSecurityV1() {
+		gvk.Version = securityv1.SchemeGroupVersion.Version
+	}
+	return gvk
+}
+
+// toSecurityV1beta1 converts a security.istio.io/v1 object to its v1beta1 equivalent; both versions share the same spec
+func toSecurityV1beta1[T any](from interface{}) (*T, error) {
+	data, err := json.Marshal(from)
+	if err != nil {
+		return nil, err
+	}
+	to := new(T)
+	if err := json.Unmarshal(data, to); err != nil {
+		return nil, err
+	}
+	return to, nil
+}
+
+// convertSecurityList converts a list of security.istio.io/v1 objects to their v1beta1 equivalents
+func convertSecurityList[T any, F any](items []*F) ([]*T, error) {
+	converted := make([]*T, 0, len(items))
+	for _, item := range items {
+		to, err := toSecurityV1beta1[T](item)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert %T: %w", item, err)
+		}
+		converted = append(converted, to)
+	}
+	return converted, nil
+}
+
+// listAuthorizationPolicies lists AuthorizationPolicies from the security API version served by the cluster
+func (i *Istio) listAuthorizationPolicies(ctx context.Context, namespace string, listOpts metav1.ListOptions) ([]*securityv1beta1.AuthorizationPolicy, error) {
+	if i.useSecurityV1() {
+		list, err := i.istioClient.SecurityV1().AuthorizationPolicies(namespace).List(ctx, listOpts)
+		if err != nil {
+			return nil, err
+		}
+		return convertSecurityList[securityv1beta1.AuthorizationPolicy, securityv1.AuthorizationPolicy](list.Items)
+	}
+	list, err := i.istioClient.SecurityV1beta1().AuthorizationPolicies(namespace).List(ctx, listOpts)
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// getAuthorizationPolicy gets an AuthorizationPolicy from the security API version served by the cluster
+func (i *Istio) getAuthorizationPolicy(ctx context.Context, namespace, name string) (*securityv1beta1.AuthorizationPolicy, error) {
+	if i.useSecurityV1() {
+		policy, err := i.istioClient.SecurityV1().AuthorizationPolicies(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return toSecurityV1beta1[securityv1beta1.AuthorizationPolicy](policy)
+	}
+	return i.istioClient.SecurityV1beta1().AuthorizationPolicies(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+// listPeerAuthentications lists PeerAuthentications from the security API version served by the cluster
+func (i *Istio) listPeerAuthentications(ctx context.Context, namespace string, listOpts metav1.ListOptions) ([]*securityv1beta1.PeerAuthentication, error) {
+	if i.useSecurityV1() {
+		list, err := i.istioClient.SecurityV1().PeerAuthentications(namespace).List(ctx, listOpts)
+		if err != nil {
+			return nil, err
+		}
+		return convertSecurityList[securityv1beta1.PeerAuthentication, securityv1.PeerAuthentication](list.Items)
+	}
+	list, err := i.istioClient.SecurityV1beta1().PeerAuthentications(namespace).List(ctx, listOpts)
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// getPeerAuthentication gets a PeerAuthentication from the security API version served by the cluster
+func (i *Istio) getPeerAuthentication(ctx context.Context, namespace, name string) (*securityv1beta1.PeerAuthentication, error) {
+	if i.useSecurityV1() {
+		policy, err := i.istioClient.SecurityV1().PeerAuthentications(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return toSecurityV1beta1[securityv1beta1.PeerAuthentication](policy)
+	}
+	return i.istioClient.SecurityV1beta1().PeerAuthentications(namespace).Get(ctx, name, metav1.GetOptions{})
+}
@@ -0,0 +1,105 @@
+package istio
+
+import (
+	"context"
+	"testing"
+)
+
+const securityV1Discovery = `{
+	"kind": "APIResourceList",
+	"apiVersion": "v1",
+	"groupVersion": "security.istio.io/v1",
+	"resources": [
+		{"name": "authorizationpolicies", "namespaced": true, "kind": "AuthorizationPolicy", "verbs": ["get", "list"]},
+		{"name": "peerauthentications", "namespaced": true, "kind": "PeerAuthentication", "verbs": ["get", "list"]}
+	]
+}`
+
+// TestSecurityAPIVersionV1 tests that security resources are read from v1 when the API server serves it
+func TestSecurityAPIVersionV1(t *testing.T) {
+	server := newMockAPIServer(map[string]string{
+		"/apis/security.istio.io/v1": securityV1Discovery,
+		"/apis/security.istio.io/v1/namespaces/default/authorizationpolicies": `{
+			"apiVersion": "security.istio.io/v1",
+			"kind": "AuthorizationPolicyList",
+			"items": [{
+				"apiVersion": "security.istio.io/v1",
+				"kind": "AuthorizationPolicy",
+				"metadata": {"name": "allow-frontend", "namespace": "default"},
+				"spec": {"action": "ALLOW", "rules": [{"from": [{"source": {"principals": ["cluster.local/ns/default/sa/frontend"]}}]}]}
+			}]
+		}`,
+		"/apis/security.istio.io/v1/namespaces/default/peerauthentications/strict": `{
+			"apiVersion": "security.istio.io/v1",
+			"kind": "PeerAuthentication",
+			"metadata": {"name": "strict", "namespace": "default"},
+			"spec": {"mtls": {"mode": "STRICT"}}
+		}`,
+	})
+	defer server.Close()
+	istio := newTestIstio(t, server.URL)
+
+	if !istio.useSecurityV1() {
+		t.Fatal("Expected security.istio.io/v1 to be selected when discovery advertises it")
+	}
+
+	result, err := istio.GetAuthorizationPolicies(context.Background(), "default")
+	if err != nil {
+		t.Fatalf("GetAuthorizationPolicies failed: %v", err)
+	}
+	assertContains(t, result, "Found 1 Authorization Policies", "allow-frontend")
+
+	policy, err := istio.getPeerAuthentication(context.Background(), "default", "strict")
+	if err != nil {
+		t.Fatalf("getPeerAuthentication failed: %v", err)
+	}
+	if mode := policy.Spec.GetMtls().GetMode().String(); mode != "STRICT" {
+		t.Errorf("Expected mTLS mode STRICT, got %s", mode)
+	}
+
+	yaml, err := istio.GetResource(context.Background(), "PeerAuthentication", "default", "strict")
+	if err != nil {
+		t.Fatalf("GetResource failed: %v", err)
+	}
+	assertContains(t, yaml, "apiVersion: security.istio.io/v1\n")
+}
+
+// TestSecurityAPIVersionFallsBackToV1beta1 tests that security resources are read from v1beta1 when v1 is not served
+func TestSecurityAPIVersionFallsBackToV1beta1(t *testing.T) {
+	server := newMockAPIServer(map[string]string{
+		"/apis/security.istio.io/v1beta1/namespaces/default/authorizationpolicies/deny-all": `{
+			"apiVersion": "security.istio.io/v1beta1",
+			"kind": "AuthorizationPolicy",
+			"metadata": {"name": "deny-all", "namespace": "default"},
+			"spec": {}
+		}`,
+		"/apis/security.istio.io/v1beta1/namespaces/default/authorizationpolicies": `{
+			"apiVersion": "security.istio.io/v1beta1",
+			"kind": "AuthorizationPolicyList",
+			"items": [{
+				"apiVersion": "security.istio.io/v1beta1",
+				"kind": "AuthorizationPolicy",
+				"metadata": {"name": "deny-all", "namespace": "default"},
+				"spec": {}
+			}]
+		}`,
+	})
+	defer server.Close()
+	istio := newTestIstio(t, server.URL)
+
+	if istio.useSecurityV1() {
+		t.Fatal("Expected security.istio.io/v1beta1 to be selected when v1 is not served")
+	}
+
+	result, err := istio.GetAuthorizationPolicies(context.Background(), "default")
+	if err != nil {
+		t.Fatalf("GetAuthorizationPolicies failed: %v", err)
+	}
+	assertContains(t, result, "Found 1 Authorization Policies", "deny-all")
+
+	yaml, err := istio.GetResourceForEditing(context.Background(), "AuthorizationPolicy", "default", "deny-all")
+	if err != nil {
+		t.Fatalf("GetResourceForEditing failed: %v", err)
+	}
+	assertContains(t, yaml, "apiVersion: security.istio.io/v1beta1\n")
+}
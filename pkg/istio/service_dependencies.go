@@ -0,0 +1,180 @@
+package istio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// proxyScanConcurrency bounds the istioctl processes a scan of the proxies of the mesh runs at once
+const proxyScanConcurrency = 8
+
+// maxScannedProxies caps the proxies whose clusters a scan of the mesh reads, one istioctl run each
+var maxScannedProxies = 100
+
+// outboundClusterHosts returns the ports of every upstream service a proxy has an outbound cluster for, keyed by host,
+// from the JSON output of istioctl proxy-config cluster
+func outboundClusterHosts(clustersJSON string) (map[string][]string, error) {
+	var clusters []struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal([]byte(clustersJSON), &clusters); err != nil {
+		return nil, fmt.Errorf("failed to parse proxy clusters: %w", err)
+	}
+	hosts := make(map[string][]string)
+	for _, cluster := range clusters {
+		// Outbound clusters are named outbound|<port>|<subset>|<host>; subsets of the same port are not repeated
+		parts := strings.Split(cluster.Name, "|")
+		if len(parts) != 4 || parts[0] != "outbound" || parts[2] != "" {
+			continue
+		}
+		hosts[parts[3]] = append(hosts[parts[3]], parts[1])
+	}
+	return hosts, nil
+}
+
+// meshWorkloadPods returns one running pod with an Istio sidecar per workload, identified by namespace and app label
+// (or pod name for pods without one), skipping the given pods
+func meshWorkloadPods(pods []v1.Pod, skip map[string]bool) []v1.Pod {
+	seen := make(map[string]bool)
+	var representatives []v1.Pod
+	for _, pod := range pods {
+		if skip[pod.Namespace+"/"+pod.Name] || pod.Status.Phase != v1.PodRunning || !hasIstioSidecar(pod) {
+			continue
+		}
+		workload := pod.Labels["app"]
+		if workload == "" {
+			workload = pod.Name
+		}
+		if key := pod.Namespace + "/" + workload; !seen[key] {
+			seen[key] = true
+			representatives = append(representatives, pod)
+		}
+	}
+	return representatives
+}
+
+// proxyClusterHosts holds the outbound cluster hosts of a pod's proxy, or the error reading them
+type proxyClusterHosts struct {
+	pod   v1.Pod
+	hosts map[string][]string
+	err   error
+}
+
+// scanProxyClusters reads the outbound cluster hosts of the proxies of pods, running at most proxyScanConcurrency
+// istioctl processes at once, and returns them in the order of pods. Only the first maxScannedProxies pods are
+// read; the number of pods left out is returned so that callers can report partial results.
+func (i *Istio) scanProxyClusters(ctx context.Context, pods []v1.Pod) ([]proxyClusterHosts, int) {
+	skipped := 0
+	if len(pods) > maxScannedProxies {
+		skipped = len(pods) - maxScannedProxies
+		pods = pods[:maxScannedProxies]
+	}
+	scanned := make([]proxyClusterHosts, len(pods))
+	slots := make(chan struct{}, proxyScanConcurrency)
+	var wg sync.WaitGroup
+	for idx, pod := range pods {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			scanned[idx].pod = pod
+			output, err := i.ProxyConfig.GetClusters(ctx, pod.Namespace, pod.Name)
+			if err == nil {
+				scanned[idx].hosts, err = outboundClusterHosts(output)
+			}
+			scanned[idx].err = err
+		}()
+	}
+	wg.Wait()
+	return scanned, skipped
+}
+
+// GetServiceDependencies infers the upstream dependencies of a service from the outbound clusters of one of its
+// proxies, and its downstream callers from the mesh workloads whose proxies have a cluster for the service. In large
+// meshes only the first maxScannedProxies workloads are read and the result says so.
+func (i *Istio) GetServiceDependencies(ctx context.Context, namespace, serviceName string) (string, error) {
+	serviceName, namespace = resolveServiceName(serviceName, namespace)
+	service, err := i.kubeClient.CoreV1().Services(namespace).Get(ctx, serviceName, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get service %s: %w", serviceName, explainForbidden(err, "get", "services", namespace))
+	}
+	pods, err := i.kubeClient.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list pods: %w", explainForbidden(err, "list", "pods", ""))
+	}
+	var namespacePods []v1.Pod
+	for _, pod := range pods.Items {
+		if pod.Namespace == namespace {
+			namespacePods = append(namespacePods, pod)
+		}
+	}
+	backing := servicePods(service, namespacePods)
+	own := make(map[string]bool, len(backing))
+	for _, pod := range backing {
+		own[pod.Namespace+"/"+pod.Name] = true
+	}
+	proxies := meshWorkloadPods(backing, nil)
+	if len(proxies) == 0 {
+		return "", fmt.Errorf("service %s in namespace %s has no running pods with an Istio sidecar", serviceName, namespace)
+	}
+	host := qualifiedHost(serviceName, namespace)
+
+	clusters, err := i.ProxyConfig.GetClusters(ctx, namespace, proxies[0].Name)
+	if err != nil {
+		return "", fmt.Errorf("failed to get clusters of pod %s: %w", proxies[0].Name, err)
+	}
+	upstream, err := outboundClusterHosts(clusters)
+	if err != nil {
+		return "", err
+	}
+	delete(upstream, host)
+	upstreamHosts := make([]string, 0, len(upstream))
+	for upstreamHost := range upstream {
+		upstreamHosts = append(upstreamHosts, upstreamHost)
+	}
+	sort.Strings(upstreamHosts)
+
+	var downstream, unreadable []string
+	scanned, skipped := i.scanProxyClusters(ctx, meshWorkloadPods(pods.Items, own))
+	for _, proxy := range scanned {
+		if proxy.err != nil {
+			unreadable = append(unreadable, proxy.pod.Namespace+"/"+proxy.pod.Name)
+			continue
+		}
+		if _, ok := proxy.hosts[host]; ok {
+			workload := proxy.pod.Labels["app"]
+			if workload == "" {
+				workload = proxy.pod.Name
+			}
+			downstream = append(downstream, fmt.Sprintf("%s (namespace %s, pod %s)", workload, proxy.pod.Namespace, proxy.pod.Name))
+		}
+	}
+
+	result := fmt.Sprintf("Dependencies of service '%s' in namespace '%s' (upstream from the clusters of pod '%s'):\n\n", serviceName, namespace, proxies[0].Name)
+	result += fmt.Sprintf("Upstream (%d services this service's proxy can send requests to):\n", len(upstreamHosts))
+	for _, upstreamHost := range upstreamHosts {
+		ports := upstream[upstreamHost]
+		sort.Strings(ports)
+		result += fmt.Sprintf("- %s (ports %s)\n", upstreamHost, strings.Join(ports, ", "))
+	}
+	result += fmt.Sprintf("\nDownstream (%d workloads whose proxies can send requests to %s):\n", len(downstream), host)
+	for _, caller := range downstream {
+		result += fmt.Sprintf("- %s\n", caller)
+	}
+	if len(unreadable) > 0 {
+		result += fmt.Sprintf("\n[WARNING] Could not read the clusters of %s\n", strings.Join(unreadable, ", "))
+	}
+	if skipped > 0 {
+		result += fmt.Sprintf("\n[WARNING] Partial result: only the proxies of the first %d mesh workloads were read, %d more were skipped\n", maxScannedProxies, skipped)
+	}
+	result += "\nNote: proxies have clusters for every service visible to them, so without Sidecar resources narrowing egress these lists show possible rather than actual dependencies\n"
+	return result, nil
+}
@@ -0,0 +1,96 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// TestGetServiceDependencies tests inference of the upstream and downstream dependencies of a service from proxy clusters
+func TestGetServiceDependencies(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{
+		"/api/v1/namespaces/bookinfo/services/reviews": `{
+			"apiVersion": "v1",
+			"kind": "Service",
+			"metadata": {"name": "reviews", "namespace": "bookinfo"},
+			"spec": {"selector": {"app": "reviews"}}
+		}`,
+		"/api/v1/pods": `{
+			"apiVersion": "v1",
+			"kind": "PodList",
+			"items": [
+				{"metadata": {"name": "reviews-v1-abc", "namespace": "bookinfo", "labels": {"app": "reviews"}}, "spec": {"containers": [{"name": "reviews"}, {"name": "istio-proxy"}]}, "status": {"phase": "Running"}},
+				{"metadata": {"name": "productpage-v1-def", "namespace": "bookinfo", "labels": {"app": "productpage"}}, "spec": {"containers": [{"name": "productpage"}, {"name": "istio-proxy"}]}, "status": {"phase": "Running"}},
+				{"metadata": {"name": "productpage-v1-ghi", "namespace": "bookinfo", "labels": {"app": "productpage"}}, "spec": {"containers": [{"name": "productpage"}, {"name": "istio-proxy"}]}, "status": {"phase": "Running"}},
+				{"metadata": {"name": "ratings-v1-jkl", "namespace": "bookinfo", "labels": {"app": "ratings"}}, "spec": {"containers": [{"name": "ratings"}, {"name": "istio-proxy"}]}, "status": {"phase": "Running"}},
+				{"metadata": {"name": "legacy-mno", "namespace": "legacy", "labels": {"app": "legacy"}}, "spec": {"containers": [{"name": "legacy"}]}, "status": {"phase": "Running"}}
+			]
+		}`,
+	})
+	defer mockServer.Close()
+
+	istio := newTestIstio(t, mockServer.URL)
+	clusters := map[string]string{
+		"reviews-v1-abc.bookinfo": `[
+			{"name": "outbound|9080||ratings.bookinfo.svc.cluster.local"},
+			{"name": "outbound|9080|v1|ratings.bookinfo.svc.cluster.local"},
+			{"name": "outbound|9080||reviews.bookinfo.svc.cluster.local"},
+			{"name": "outbound|443||api.example.com"},
+			{"name": "inbound|9080||"},
+			{"name": "BlackHoleCluster"},
+			{"name": "PassthroughCluster"}
+		]`,
+		"productpage-v1-def.bookinfo": `[
+			{"name": "outbound|9080||reviews.bookinfo.svc.cluster.local"},
+			{"name": "outbound|9080||details.bookinfo.svc.cluster.local"}
+		]`,
+		"ratings-v1-jkl.bookinfo": `[{"name": "outbound|27017||mongodb.bookinfo.svc.cluster.local"}]`,
+	}
+	var mu sync.Mutex
+	var queried []string
+	istio.ProxyConfig.execCommand = func(ctx context.Context, args ...string) ([]byte, error) {
+		// The pod is the argument following "proxy-config cluster"
+		pod := args[slices.Index(args, "cluster")+1]
+		mu.Lock()
+		queried = append(queried, pod)
+		mu.Unlock()
+		if output, ok := clusters[pod]; ok {
+			return []byte(output), nil
+		}
+		return nil, fmt.Errorf("unexpected pod %s", pod)
+	}
+
+	result, err := istio.GetServiceDependencies(context.Background(), "bookinfo", "reviews")
+	if err != nil {
+		t.Fatalf("GetServiceDependencies failed: %v", err)
+	}
+	assertContains(t, result,
+		"upstream from the clusters of pod 'reviews-v1-abc'",
+		"Upstream (2 services",
+		"- api.example.com (ports 443)",
+		"- ratings.bookinfo.svc.cluster.local (ports 9080)",
+		"Downstream (1 workloads whose proxies can send requests to reviews.bookinfo.svc.cluster.local)",
+		"- productpage (namespace bookinfo, pod productpage-v1-def)",
+	)
+	assertNotContains(t, result, "- reviews.bookinfo", "ratings (namespace", "legacy", "[WARNING]")
+	if slices.Contains(queried, "productpage-v1-ghi.bookinfo") {
+		t.Errorf("Expected a single proxy to be queried per workload, queried %v", queried)
+	}
+
+	t.Run("reports a partial result beyond the proxy cap", func(t *testing.T) {
+		defer func(limit int) { maxScannedProxies = limit }(maxScannedProxies)
+		maxScannedProxies = 1
+		istio.ProxyConfig.SetCacheTTL(0)
+
+		result, err := istio.GetServiceDependencies(context.Background(), "bookinfo", "reviews")
+		if err != nil {
+			t.Fatalf("GetServiceDependencies failed: %v", err)
+		}
+		assertContains(t, result,
+			"- productpage (namespace bookinfo, pod productpage-v1-def)",
+			"[WARNING] Partial result: only the proxies of the first 1 mesh workloads were read, 1 more were skipped",
+		)
+	})
+}
@@ -0,0 +1,82 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	networkingv1alpha3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// serviceEntryPorts renders the ports of a ServiceEntry as a sorted 'number/protocol' list
+func serviceEntryPorts(se *networkingv1alpha3.ServiceEntry) string {
+	ports := make([]string, 0, len(se.Spec.Ports))
+	for _, port := range se.Spec.Ports {
+		ports = append(ports, fmt.Sprintf("%d/%s", port.GetNumber(), port.GetProtocol()))
+	}
+	sort.Strings(ports)
+	return "[" + strings.Join(ports, ", ") + "]"
+}
+
+// FindDuplicateServiceEntries reports hosts declared by more than one ServiceEntry. Istio picks one of the
+// entries nondeterministically, so conflicting resolutions or ports make traffic to the host unpredictable.
+func (i *Istio) FindDuplicateServiceEntries(ctx context.Context, namespace string) (string, error) {
+	seList, err := i.istioClient.NetworkingV1alpha3().ServiceEntries(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list service entries: %w", explainForbidden(err, "list", "serviceentries", namespace))
+	}
+
+	byHost := make(map[string][]*networkingv1alpha3.ServiceEntry)
+	for _, se := range seList.Items {
+		for _, host := range se.Spec.Hosts {
+			byHost[host] = append(byHost[host], se)
+		}
+	}
+	hosts := make([]string, 0, len(byHost))
+	for host, entries := range byHost {
+		if len(entries) > 1 {
+			hosts = append(hosts, host)
+		}
+	}
+	sort.Strings(hosts)
+
+	result := fmt.Sprintf("Duplicate Service Entry hosts in namespace '%s':\n\n", namespace)
+	if len(hosts) == 0 {
+		result += fmt.Sprintf("[OK] Each host is defined by a single Service Entry (%d entries checked)\n", len(seList.Items))
+		return result, nil
+	}
+
+	conflicts := 0
+	for _, host := range hosts {
+		entries := byHost[host]
+		names := make([]string, 0, len(entries))
+		resolutions := make([]string, 0, len(entries))
+		ports := make([]string, 0, len(entries))
+		resolutionConflict, portConflict := false, false
+		for _, se := range entries {
+			names = append(names, se.Name)
+			resolutions = append(resolutions, fmt.Sprintf("%s=%s", se.Name, se.Spec.Resolution))
+			ports = append(ports, fmt.Sprintf("%s=%s", se.Name, serviceEntryPorts(se)))
+			resolutionConflict = resolutionConflict || se.Spec.Resolution != entries[0].Spec.Resolution
+			portConflict = portConflict || serviceEntryPorts(se) != serviceEntryPorts(entries[0])
+		}
+
+		if !resolutionConflict && !portConflict {
+			result += fmt.Sprintf("[WARNING] Host '%s' is defined by %d Service Entries: %s (identical resolution and ports)\n", host, len(entries), strings.Join(names, ", "))
+			continue
+		}
+		conflicts++
+		result += fmt.Sprintf("[ERROR] Host '%s' is defined by %d Service Entries: %s\n", host, len(entries), strings.Join(names, ", "))
+		if resolutionConflict {
+			result += fmt.Sprintf("  Conflicting resolution: %s\n", strings.Join(resolutions, ", "))
+		}
+		if portConflict {
+			result += fmt.Sprintf("  Conflicting ports: %s\n", strings.Join(ports, ", "))
+		}
+	}
+
+	result += fmt.Sprintf("\n[RESULT] %d hosts defined more than once, %d with conflicting definitions; consolidate each host into a single Service Entry\n", len(hosts), conflicts)
+	return result, nil
+}
@@ -0,0 +1,44 @@
+package istio
+
+import (
+	"context"
+	"testing"
+)
+
+// TestFindDuplicateServiceEntries tests detection of hosts declared by several Service Entries
+func TestFindDuplicateServiceEntries(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{
+		"/apis/networking.istio.io/v1alpha3/namespaces/default/serviceentries": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "ServiceEntryList",
+			"items": [
+				{
+					"metadata": {"name": "payments-dns", "namespace": "default"},
+					"spec": {"hosts": ["api.payments.com"], "resolution": "DNS", "ports": [{"number": 443, "name": "https", "protocol": "HTTPS"}]}
+				},
+				{
+					"metadata": {"name": "payments-static", "namespace": "default"},
+					"spec": {"hosts": ["api.payments.com"], "resolution": "STATIC", "ports": [{"number": 443, "name": "https", "protocol": "HTTPS"}]}
+				},
+				{
+					"metadata": {"name": "github", "namespace": "default"},
+					"spec": {"hosts": ["github.com"], "resolution": "DNS", "ports": [{"number": 443, "name": "https", "protocol": "HTTPS"}]}
+				}
+			]
+		}`,
+	})
+	defer mockServer.Close()
+
+	istio := newTestIstio(t, mockServer.URL)
+
+	result, err := istio.FindDuplicateServiceEntries(context.Background(), "default")
+	if err != nil {
+		t.Fatalf("Failed to find duplicate service entries: %v", err)
+	}
+	assertContains(t, result,
+		"[ERROR] Host 'api.payments.com' is defined by 2 Service Entries: payments-dns, payments-static",
+		"Conflicting resolution: payments-dns=DNS, payments-static=STATIC",
+		"[RESULT] 1 hosts defined more than once, 1 with conflicting definitions",
+	)
+	assertNotContains(t, result, "github.com", "Conflicting ports")
+}
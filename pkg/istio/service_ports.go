@@ -0,0 +1,123 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// istioProtocols lists the protocols Istio recognizes from a port name prefix or appProtocol
+var istioProtocols = []string{"grpc-web", "grpc", "http2", "http", "https", "mongo", "mysql", "redis", "tcp", "tls", "udp"}
+
+// protocolFromPortName returns the protocol selected by an Istio port name (e.g. "http-web" -> "http")
+func protocolFromPortName(name string) string {
+	name = strings.ToLower(name)
+	for _, protocol := range istioProtocols {
+		if name == protocol || strings.HasPrefix(name, protocol+"-") {
+			return protocol
+		}
+	}
+	return ""
+}
+
+// protocolFromAppProtocol normalizes a Kubernetes appProtocol value to an Istio protocol
+func protocolFromAppProtocol(appProtocol string) string {
+	switch strings.ToLower(appProtocol) {
+	case "kubernetes.io/h2c":
+		return "http2"
+	case "kubernetes.io/ws":
+		return "http"
+	case "kubernetes.io/wss":
+		return "https"
+	}
+	return protocolFromPortName(appProtocol)
+}
+
+// FindPortConflicts inspects Services for port declarations that break Istio protocol selection:
+// ports declared twice for the same protocol, ports without a protocol hint, and appProtocol values that disagree with the port name
+func (i *Istio) FindPortConflicts(ctx context.Context, namespace string) (string, error) {
+	services, err := i.kubeClient.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list services: %w", explainForbidden(err, "list", "services", namespace))
+	}
+
+	sort.Slice(services.Items, func(a, b int) bool {
+		return services.Items[a].Name < services.Items[b].Name
+	})
+
+	result := fmt.Sprintf("Service Port Analysis for namespace '%s':\n\n", namespace)
+	result += fmt.Sprintf("Analyzed %d services\n\n", len(services.Items))
+
+	issueCount := 0
+	for _, service := range services.Items {
+		issues := servicePortIssues(service)
+		if len(issues) == 0 {
+			continue
+		}
+		issueCount += len(issues)
+		result += fmt.Sprintf("- %s\n", service.Name)
+		for _, issue := range issues {
+			result += fmt.Sprintf("  %s\n", issue)
+		}
+	}
+
+	if issueCount == 0 {
+		result += "[OK] No port conflicts or ambiguous protocol declarations found\n"
+		return result, nil
+	}
+
+	result += fmt.Sprintf("\n[RESULT] Found %d port issues\n", issueCount)
+	result += "   Istio selects the protocol from the port name prefix (e.g. 'http-', 'grpc-', 'tcp-') or the appProtocol field.\n"
+	result += "   Ambiguous or conflicting declarations fall back to protocol sniffing or plain TCP, silently disabling L7 features.\n"
+	return result, nil
+}
+
+// servicePortIssues returns the protocol selection issues found in a single Service
+func servicePortIssues(service v1.Service) []string {
+	var issues []string
+
+	// A port number may be declared once per protocol, as DNS does for 53/TCP and 53/UDP
+	type portKey struct {
+		port     int32
+		protocol v1.Protocol
+	}
+	portKeyOf := func(port v1.ServicePort) portKey {
+		if port.Protocol == "" {
+			return portKey{port.Port, v1.ProtocolTCP}
+		}
+		return portKey{port.Port, port.Protocol}
+	}
+	portsByKey := make(map[portKey][]string)
+	for _, port := range service.Spec.Ports {
+		portsByKey[portKeyOf(port)] = append(portsByKey[portKeyOf(port)], port.Name)
+	}
+
+	for _, port := range service.Spec.Ports {
+		key := portKeyOf(port)
+		if names := portsByKey[key]; len(names) > 1 {
+			issues = append(issues, fmt.Sprintf("[ERROR] Port %d/%s is declared %d times (%s)", port.Port, key.protocol, len(names), strings.Join(names, ", ")))
+			delete(portsByKey, key)
+		}
+
+		nameProtocol := protocolFromPortName(port.Name)
+		appProtocol := ""
+		if port.AppProtocol != nil {
+			appProtocol = protocolFromAppProtocol(*port.AppProtocol)
+		}
+
+		switch {
+		case nameProtocol == "" && appProtocol == "":
+			issues = append(issues, fmt.Sprintf("[WARNING] Port %d (name: '%s') has no protocol hint; Istio will fall back to protocol sniffing", port.Port, port.Name))
+		case port.AppProtocol != nil && appProtocol == "":
+			issues = append(issues, fmt.Sprintf("[WARNING] Port %d (name: '%s') has unrecognized appProtocol '%s'", port.Port, port.Name, *port.AppProtocol))
+		case nameProtocol != "" && appProtocol != "" && nameProtocol != appProtocol:
+			issues = append(issues, fmt.Sprintf("[WARNING] Port %d (name: '%s') selects '%s' by name but appProtocol is '%s'; appProtocol takes precedence", port.Port, port.Name, nameProtocol, *port.AppProtocol))
+		}
+	}
+
+	return issues
+}
@@ -0,0 +1,72 @@
+package istio
+
+import (
+	"context"
+	"testing"
+)
+
+// TestFindPortConflicts tests detection of ambiguous and conflicting service port declarations
+func TestFindPortConflicts(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{
+		"/api/v1/namespaces/production/services": `{
+			"apiVersion": "v1",
+			"kind": "ServiceList",
+			"items": [
+				{
+					"metadata": {"name": "reviews", "namespace": "production"},
+					"spec": {"ports": [
+						{"name": "http-web", "port": 9080, "protocol": "TCP"},
+						{"name": "grpc", "port": 9090, "protocol": "TCP", "appProtocol": "grpc"}
+					]}
+				},
+				{
+					"metadata": {"name": "legacy", "namespace": "production"},
+					"spec": {"ports": [
+						{"name": "web", "port": 8080, "protocol": "TCP"},
+						{"name": "dns", "port": 53, "protocol": "TCP"},
+						{"name": "dns-udp", "port": 53, "protocol": "UDP"},
+						{"name": "http-api", "port": 8443, "protocol": "TCP", "appProtocol": "https"},
+						{"name": "http-admin", "port": 9901},
+						{"name": "http-metrics", "port": 9901, "protocol": "TCP"}
+					]}
+				}
+			]
+		}`,
+		"/api/v1/namespaces/clean/services": `{
+			"apiVersion": "v1",
+			"kind": "ServiceList",
+			"items": [
+				{
+					"metadata": {"name": "ratings", "namespace": "clean"},
+					"spec": {"ports": [{"name": "http", "port": 9080, "protocol": "TCP"}]}
+				}
+			]
+		}`,
+	})
+	defer mockServer.Close()
+
+	istio := newTestIstio(t, mockServer.URL)
+	ctx := context.Background()
+
+	t.Run("flags ambiguous port names", func(t *testing.T) {
+		result, err := istio.FindPortConflicts(ctx, "production")
+		if err != nil {
+			t.Fatalf("Failed to find port conflicts: %v", err)
+		}
+		assertContains(t, result,
+			"- legacy",
+			"[WARNING] Port 8080 (name: 'web') has no protocol hint",
+			"[ERROR] Port 9901/TCP is declared 2 times (http-admin, http-metrics)",
+			"selects 'http' by name but appProtocol is 'https'",
+		)
+		assertNotContains(t, result, "- reviews", "Port 53/")
+	})
+
+	t.Run("reports clean namespace", func(t *testing.T) {
+		result, err := istio.FindPortConflicts(ctx, "clean")
+		if err != nil {
+			t.Fatalf("Failed to find port conflicts: %v", err)
+		}
+		assertContains(t, result, "[OK] No port conflicts")
+	})
+}
@@ -296,6 +296,45 @@ func TestGetServicesContextCancellation(t *testing.T) {
 	})
 }
 
+// TestGetServicesIstioOnly tests filtering services to those backed by pods with an Istio sidecar
+func TestGetServicesIstioOnly(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{
+		"/api/v1/namespaces/shop/services": `{
+			"apiVersion": "v1",
+			"kind": "ServiceList",
+			"items": [
+				{"metadata": {"name": "cart", "namespace": "shop"}, "spec": {"type": "ClusterIP", "clusterIP": "10.0.0.1", "selector": {"app": "cart"}}},
+				{"metadata": {"name": "legacy", "namespace": "shop"}, "spec": {"type": "ClusterIP", "clusterIP": "10.0.0.2", "selector": {"app": "legacy"}}}
+			]
+		}`,
+		"/api/v1/namespaces/shop/pods": `{
+			"apiVersion": "v1",
+			"kind": "PodList",
+			"items": [
+				{"metadata": {"name": "cart-7d9f", "namespace": "shop", "labels": {"app": "cart"}}, "spec": {"containers": [{"name": "cart"}, {"name": "istio-proxy"}]}},
+				{"metadata": {"name": "legacy-5c8b", "namespace": "shop", "labels": {"app": "legacy"}}, "spec": {"containers": [{"name": "legacy"}]}}
+			]
+		}`,
+	})
+	defer mockServer.Close()
+
+	istio := newTestIstio(t, mockServer.URL)
+	ctx := context.Background()
+
+	result, err := istio.GetServices(ctx, "shop", WithIstioOnly(true))
+	if err != nil {
+		t.Fatalf("Failed to get services: %v", err)
+	}
+	assertContains(t, result, "Found 1 services backed by pods with an Istio sidecar (2 services in total)", "cart")
+	assertNotContains(t, result, "legacy")
+
+	result, err = istio.GetServices(ctx, "shop")
+	if err != nil {
+		t.Fatalf("Failed to get services: %v", err)
+	}
+	assertContains(t, result, "Found 2 services", "cart", "legacy")
+}
+
 // Helper functions for creating mock servers
 
 func createMockServicesServer() *httptest.Server {
@@ -475,3 +514,41 @@ func extractNamespaceFromServicesPath(path string) string {
 	}
 	return "default"
 }
+
+// TestGetServicesPortsAndOrder tests that services are listed alphabetically within their group, with their ports
+func TestGetServicesPortsAndOrder(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{
+		"/api/v1/namespaces/shop/services": `{
+			"apiVersion": "v1",
+			"kind": "ServiceList",
+			"items": [
+				{"metadata": {"name": "payments", "namespace": "shop"}, "spec": {"type": "ClusterIP", "clusterIP": "10.0.0.3", "ports": [
+					{"name": "grpc", "port": 9090, "targetPort": "grpc-port", "protocol": "TCP"}
+				]}},
+				{"metadata": {"name": "cart", "namespace": "shop"}, "spec": {"type": "ClusterIP", "clusterIP": "10.0.0.1", "ports": [
+					{"name": "http", "port": 80, "targetPort": 8080, "protocol": "TCP"},
+					{"port": 9000, "protocol": "UDP"}
+				]}},
+				{"metadata": {"name": "frontend", "namespace": "shop"}, "spec": {"type": "NodePort", "clusterIP": "10.0.0.2", "ports": [
+					{"name": "http", "port": 80, "targetPort": 8080, "nodePort": 30080, "protocol": "TCP"}
+				]}}
+			]
+		}`,
+	})
+	defer mockServer.Close()
+
+	istio := newTestIstio(t, mockServer.URL)
+
+	result, err := istio.GetServices(context.Background(), "shop")
+	if err != nil {
+		t.Fatalf("Failed to get services: %v", err)
+	}
+	assertContains(t, result,
+		"(ClusterIP: 10.0.0.1)\n     - http: 80 -> 8080/TCP\n     - 9000 -> 9000/UDP\n",
+		"(ClusterIP: 10.0.0.3)\n     - grpc: 9090 -> grpc-port/TCP\n",
+		"(NodePort: 10.0.0.2)\n     - http: 80 -> 8080/TCP (node port 30080)\n",
+	)
+	if strings.Index(result, "cart") > strings.Index(result, "payments") {
+		t.Errorf("Expected services to be sorted alphabetically, got: %s", result)
+	}
+}
@@ -0,0 +1,165 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	networkingv1alpha3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// sidecarNames returns the names of Sidecars
+func sidecarNames(sidecars []*networkingv1alpha3.Sidecar) []string {
+	names := make([]string, 0, len(sidecars))
+	for _, sidecar := range sidecars {
+		names = append(names, sidecar.Name)
+	}
+	return names
+}
+
+// oldestSidecarFirst orders Sidecars by creation time; when several apply at the same level Istio uses the oldest
+func oldestSidecarFirst(sidecars []*networkingv1alpha3.Sidecar) {
+	sort.SliceStable(sidecars, func(a, b int) bool {
+		return sidecars[a].CreationTimestamp.Before(&sidecars[b].CreationTimestamp)
+	})
+}
+
+// sidecarScope holds the Sidecars that may shape the configuration of a pod's proxy, each list oldest first
+type sidecarScope struct {
+	// selecting are the Sidecars of the pod's namespace whose workload selector matches the pod
+	selecting []*networkingv1alpha3.Sidecar
+	// namespaceWide are the Sidecars of the pod's namespace without a workload selector
+	namespaceWide []*networkingv1alpha3.Sidecar
+	// root is the namespace-wide Sidecar of the mesh root namespace, looked up only when the namespace has none
+	root *networkingv1alpha3.Sidecar
+}
+
+// applied returns the Sidecar Istio applies: the oldest one selecting the pod, otherwise the oldest namespace-wide
+// one, otherwise the one of the mesh root namespace, or nil when none exists
+func (s *sidecarScope) applied() *networkingv1alpha3.Sidecar {
+	switch {
+	case len(s.selecting) > 0:
+		return s.selecting[0]
+	case len(s.namespaceWide) > 0:
+		return s.namespaceWide[0]
+	}
+	return s.root
+}
+
+// workloadSidecarScope lists the Sidecars that may apply to a pod in namespace, or to any workload of the
+// namespace when pod is nil, falling back to the mesh root namespace when the namespace has no Sidecar applying
+func (i *Istio) workloadSidecarScope(ctx context.Context, namespace string, pod *v1.Pod, rootNamespace string) (*sidecarScope, error) {
+	sidecarList, err := i.istioClient.NetworkingV1alpha3().Sidecars(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sidecars: %w", i.explainAPIError(ctx, err, "list", "sidecars", namespace))
+	}
+	scope := &sidecarScope{}
+	for _, sidecar := range sidecarList.Items {
+		selector := sidecar.Spec.GetWorkloadSelector().GetLabels()
+		switch {
+		case len(selector) == 0:
+			scope.namespaceWide = append(scope.namespaceWide, sidecar)
+		case pod != nil && labels.SelectorFromSet(selector).Matches(labels.Set(pod.Labels)):
+			scope.selecting = append(scope.selecting, sidecar)
+		}
+	}
+	oldestSidecarFirst(scope.selecting)
+	oldestSidecarFirst(scope.namespaceWide)
+
+	if len(scope.selecting) == 0 && len(scope.namespaceWide) == 0 && rootNamespace != namespace {
+		rootSidecars, err := i.istioClient.NetworkingV1alpha3().Sidecars(rootNamespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list sidecars: %w", explainForbidden(err, "list", "sidecars", rootNamespace))
+		}
+		oldestSidecarFirst(rootSidecars.Items)
+		scope.root = namespaceDefaultSidecar(rootSidecars.Items)
+	}
+	return scope, nil
+}
+
+// GetAppliedSidecarScope resolves the Sidecar resource shaping the configuration of a pod's proxy: a Sidecar of
+// its namespace whose workload selector matches the pod, otherwise the namespace-wide Sidecar, otherwise the
+// namespace-wide Sidecar of the mesh root namespace. It reports why it applies, which Sidecars it overrides, and
+// its egress listeners with the hosts they import.
+func (i *Istio) GetAppliedSidecarScope(ctx context.Context, namespace, podName string) (string, error) {
+	pod, err := i.kubeClient.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get pod %s: %w", podName, explainForbidden(err, "get", "pods", namespace))
+	}
+	mesh, err := i.getMeshConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+	scope, err := i.workloadSidecarScope(ctx, namespace, pod, mesh.RootNamespace)
+	if err != nil {
+		return "", err
+	}
+	selecting, namespaceWide := scope.selecting, scope.namespaceWide
+
+	result := fmt.Sprintf("Sidecar scope of pod '%s' in namespace '%s':\n\n", podName, namespace)
+	if !hasIstioSidecar(*pod) {
+		result += "[WARNING] The pod has no Istio sidecar; no Sidecar resource shapes its traffic\n\n"
+	}
+
+	applied := scope.applied()
+	switch {
+	case len(selecting) > 0:
+		result += fmt.Sprintf("Applied: Sidecar '%s/%s' (workload selector %v matches the pod)\n", applied.Namespace, applied.Name, applied.Spec.GetWorkloadSelector().GetLabels())
+		if len(selecting) > 1 {
+			result += fmt.Sprintf("[WARNING] %d Sidecars select the pod (%s); Istio applies only the oldest\n", len(selecting), strings.Join(sidecarNames(selecting), ", "))
+		}
+		if len(namespaceWide) > 0 {
+			result += fmt.Sprintf("Overrides the namespace-wide Sidecar '%s'\n", namespaceWide[0].Name)
+		}
+	case len(namespaceWide) > 0:
+		result += fmt.Sprintf("Applied: Sidecar '%s/%s' (namespace-wide, no Sidecar selects the pod)\n", applied.Namespace, applied.Name)
+		if len(namespaceWide) > 1 {
+			result += fmt.Sprintf("[WARNING] %d namespace-wide Sidecars exist (%s); Istio applies only the oldest\n", len(namespaceWide), strings.Join(sidecarNames(namespaceWide), ", "))
+		}
+	case applied != nil:
+		result += fmt.Sprintf("Applied: Sidecar '%s/%s' (mesh-wide default of the root namespace; the namespace has no Sidecar)\n", applied.Namespace, applied.Name)
+	}
+	if applied == nil {
+		result += "No Sidecar applies: the proxy imports every host exported to the namespace and captures all ports\n"
+		return result, nil
+	}
+
+	if policy := applied.Spec.GetOutboundTrafficPolicy(); policy != nil {
+		result += fmt.Sprintf("Outbound traffic policy: %s\n", policy.GetMode())
+	}
+	result += "\nEgress:\n"
+	if len(applied.Spec.GetEgress()) == 0 {
+		result += "  No egress listeners: the proxy imports every host exported to the namespace\n"
+	}
+	for _, egress := range applied.Spec.GetEgress() {
+		listener := "all ports"
+		if port := egress.GetPort(); port != nil {
+			listener = fmt.Sprintf("port %d", port.GetNumber())
+			if port.GetProtocol() != "" {
+				listener += "/" + port.GetProtocol()
+			}
+		}
+		if egress.GetBind() != "" {
+			listener += ", bind " + egress.GetBind()
+		}
+		if egress.GetCaptureMode() != 0 {
+			listener += fmt.Sprintf(", capture mode %s", egress.GetCaptureMode())
+		}
+		result += fmt.Sprintf("  - %s: hosts %s\n", listener, strings.Join(egress.GetHosts(), ", "))
+	}
+	if ingress := applied.Spec.GetIngress(); len(ingress) > 0 {
+		result += "\nIngress:\n"
+		for _, listener := range ingress {
+			endpoint := listener.GetDefaultEndpoint()
+			if endpoint == "" {
+				endpoint = "the workload's port"
+			}
+			result += fmt.Sprintf("  - port %d/%s -> %s\n", listener.GetPort().GetNumber(), listener.GetPort().GetProtocol(), endpoint)
+		}
+	}
+	return result, nil
+}
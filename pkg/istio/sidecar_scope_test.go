@@ -0,0 +1,51 @@
+package istio
+
+import (
+	"context"
+	"testing"
+)
+
+// TestGetAppliedSidecarScope tests resolution of the Sidecar resource applying to a pod
+func TestGetAppliedSidecarScope(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{
+		"/api/v1/namespaces/bookinfo/pods/reviews-v1-abc": `{
+			"apiVersion": "v1",
+			"kind": "Pod",
+			"metadata": {"name": "reviews-v1-abc", "namespace": "bookinfo", "labels": {"app": "reviews", "version": "v1"}},
+			"spec": {"containers": [{"name": "reviews"}, {"name": "istio-proxy"}]},
+			"status": {"phase": "Running"}
+		}`,
+		"/apis/networking.istio.io/v1alpha3/namespaces/bookinfo/sidecars": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "SidecarList",
+			"items": [
+				{"metadata": {"name": "default", "namespace": "bookinfo"}, "spec": {
+					"egress": [{"hosts": ["./*", "istio-system/*"]}]
+				}},
+				{"metadata": {"name": "reviews", "namespace": "bookinfo"}, "spec": {
+					"workloadSelector": {"labels": {"app": "reviews"}},
+					"egress": [{"port": {"number": 9080, "protocol": "HTTP", "name": "http"}, "hosts": ["./ratings.bookinfo.svc.cluster.local"]}],
+					"outboundTrafficPolicy": {"mode": "REGISTRY_ONLY"}
+				}},
+				{"metadata": {"name": "ratings", "namespace": "bookinfo"}, "spec": {
+					"workloadSelector": {"labels": {"app": "ratings"}},
+					"egress": [{"hosts": ["./*"]}]
+				}}
+			]
+		}`,
+	})
+	defer mockServer.Close()
+	istio := newTestIstio(t, mockServer.URL)
+
+	result, err := istio.GetAppliedSidecarScope(context.Background(), "bookinfo", "reviews-v1-abc")
+	if err != nil {
+		t.Fatalf("GetAppliedSidecarScope failed: %v", err)
+	}
+	assertContains(t, result,
+		"Applied: Sidecar 'bookinfo/reviews' (workload selector map[app:reviews] matches the pod)",
+		"Overrides the namespace-wide Sidecar 'default'",
+		"Outbound traffic policy: REGISTRY_ONLY",
+		"  - port 9080/HTTP: hosts ./ratings.bookinfo.svc.cluster.local",
+	)
+	assertNotContains(t, result, "istio-system/*", "Sidecar 'bookinfo/ratings'")
+}
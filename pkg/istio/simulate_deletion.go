@@ -0,0 +1,432 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	networkingapi "istio.io/api/networking/v1alpha3"
+	securityv1beta1api "istio.io/api/security/v1beta1"
+	networkingv1alpha3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
+	securityv1beta1 "istio.io/client-go/pkg/apis/security/v1beta1"
+	telemetryv1alpha1 "istio.io/client-go/pkg/apis/telemetry/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// workloadName names the workload of a pod as 'namespace/app', falling back to the pod name without an app label
+func workloadName(pod v1.Pod) string {
+	if app := pod.Labels["app"]; app != "" {
+		return pod.Namespace + "/" + app
+	}
+	return pod.Namespace + "/" + pod.Name
+}
+
+// selectedWorkloads returns the mesh workloads a namespaced policy with a label selector applies to: the selected
+// workloads of its namespace, all workloads of its namespace without a selector, or, for a policy in the root
+// namespace, the selected workloads or all workloads of the whole mesh
+func selectedWorkloads(pods []v1.Pod, namespace string, selector map[string]string, rootNamespace string) []v1.Pod {
+	var selected []v1.Pod
+	for _, pod := range pods {
+		if namespace != rootNamespace && pod.Namespace != namespace {
+			continue
+		}
+		if len(selector) > 0 && !labels.SelectorFromSet(selector).Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+		selected = append(selected, pod)
+	}
+	return selected
+}
+
+// policyScope describes how broadly a policy with a label selector applies
+func policyScope(namespace string, selector map[string]string, rootNamespace string) string {
+	switch {
+	case len(selector) > 0 && namespace == rootNamespace:
+		return "workload, in all namespaces"
+	case len(selector) > 0:
+		return "workload"
+	case namespace == rootNamespace:
+		return "mesh-wide"
+	default:
+		return "namespace-wide"
+	}
+}
+
+// peerAuthenticationMode returns the mTLS mode a PeerAuthentication sets, or "" when it inherits the mode (UNSET)
+func peerAuthenticationMode(policy *securityv1beta1.PeerAuthentication) string {
+	mode := policy.Spec.GetMtls().GetMode()
+	if mode == securityv1beta1api.PeerAuthentication_MutualTLS_UNSET {
+		return ""
+	}
+	return mode.String()
+}
+
+// effectiveMtlsMode resolves the mTLS mode of a pod from the PeerAuthentications: a workload policy of its namespace
+// overrides the namespace policy, which overrides the mesh-wide policy of the root namespace; UNSET inherits from the
+// next level and the default is PERMISSIVE. It returns the mode and the policy deciding it.
+func effectiveMtlsMode(policies []*securityv1beta1.PeerAuthentication, pod v1.Pod, rootNamespace string) (string, string) {
+	levels := []func(policy *securityv1beta1.PeerAuthentication) bool{
+		func(policy *securityv1beta1.PeerAuthentication) bool {
+			selector := policy.Spec.GetSelector().GetMatchLabels()
+			return policy.Namespace == pod.Namespace && len(selector) > 0 && labels.SelectorFromSet(selector).Matches(labels.Set(pod.Labels))
+		},
+		func(policy *securityv1beta1.PeerAuthentication) bool {
+			return policy.Namespace == pod.Namespace && len(policy.Spec.GetSelector().GetMatchLabels()) == 0
+		},
+		func(policy *securityv1beta1.PeerAuthentication) bool {
+			return policy.Namespace == rootNamespace && len(policy.Spec.GetSelector().GetMatchLabels()) == 0
+		},
+	}
+	for _, applies := range levels {
+		for _, policy := range policies {
+			if mode := peerAuthenticationMode(policy); mode != "" && applies(policy) {
+				return mode, policy.Namespace + "/" + policy.Name
+			}
+		}
+	}
+	return securityv1beta1api.PeerAuthentication_MutualTLS_PERMISSIVE.String(), "mesh default"
+}
+
+// SimulateResourceDeletion previews the effect of deleting an Istio resource without deleting it: the hosts that
+// lose routing rules or traffic policies, the workloads that lose an authentication or authorization policy and
+// whether their mTLS mode would downgrade, and the resources left referring to what is deleted
+func (i *Istio) SimulateResourceDeletion(ctx context.Context, kind, namespace, name string) (string, error) {
+	obj, err := i.getResource(ctx, kind, namespace, name)
+	if err != nil {
+		return "", err
+	}
+	mesh, err := i.getMeshConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	result := fmt.Sprintf("Simulated deletion of %s '%s/%s' (nothing is deleted):\n\n", obj.GetObjectKind().GroupVersionKind().Kind, namespace, name)
+	var effects string
+	switch resource := obj.(type) {
+	case *securityv1beta1.PeerAuthentication:
+		effects, err = i.simulatePeerAuthenticationDeletion(ctx, resource, mesh)
+	case *securityv1beta1.AuthorizationPolicy:
+		effects, err = i.simulateAuthorizationPolicyDeletion(ctx, resource, mesh)
+	case *networkingv1alpha3.VirtualService:
+		effects, err = i.simulateVirtualServiceDeletion(ctx, resource)
+	case *networkingv1alpha3.DestinationRule:
+		effects, err = i.simulateDestinationRuleDeletion(ctx, resource)
+	case *networkingv1alpha3.Gateway:
+		effects, err = i.simulateGatewayDeletion(ctx, resource)
+	case *networkingv1alpha3.ServiceEntry:
+		effects, err = i.simulateServiceEntryDeletion(ctx, resource, mesh)
+	case *networkingv1alpha3.EnvoyFilter:
+		effects, err = i.simulateSelectorPolicyDeletion(ctx, namespace, resource.Spec.GetWorkloadSelector().GetLabels(), mesh,
+			"lose the Envoy configuration patches of the filter")
+	case *telemetryv1alpha1.Telemetry:
+		effects, err = i.simulateSelectorPolicyDeletion(ctx, namespace, resource.Spec.GetSelector().GetMatchLabels(), mesh,
+			"fall back to the telemetry (metrics, tracing and access logging) configured at the namespace or mesh level")
+	default:
+		return "", fmt.Errorf("simulating the deletion of a %s is not supported", kind)
+	}
+	if err != nil {
+		return "", err
+	}
+	return result + effects, nil
+}
+
+// listMeshPods lists the pods of all namespaces, reduced to one running pod with a sidecar per workload
+func (i *Istio) listMeshPods(ctx context.Context) ([]v1.Pod, error) {
+	pods, err := i.kubeClient.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", explainForbidden(err, "list", "pods", ""))
+	}
+	return meshWorkloadPods(pods.Items, nil), nil
+}
+
+// simulatePeerAuthenticationDeletion compares the mTLS mode of every mesh workload with and without the policy
+func (i *Istio) simulatePeerAuthenticationDeletion(ctx context.Context, deleted *securityv1beta1.PeerAuthentication, mesh *meshConfig) (string, error) {
+	policies, err := i.listPeerAuthentications(ctx, "", metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list peer authentications: %w", explainForbidden(err, "list", "peerauthentications", ""))
+	}
+	pods, err := i.listMeshPods(ctx)
+	if err != nil {
+		return "", err
+	}
+	remaining := slices.DeleteFunc(slices.Clone(policies), func(policy *securityv1beta1.PeerAuthentication) bool {
+		return policy.Namespace == deleted.Namespace && policy.Name == deleted.Name
+	})
+
+	mode := peerAuthenticationMode(deleted)
+	if mode == "" {
+		mode = "UNSET"
+	}
+	result := fmt.Sprintf("Policy scope: %s, mode %s\n\n", policyScope(deleted.Namespace, deleted.Spec.GetSelector().GetMatchLabels(), mesh.RootNamespace), mode)
+	changed, downgraded := 0, 0
+	for _, pod := range pods {
+		before, beforeSource := effectiveMtlsMode(policies, pod, mesh.RootNamespace)
+		after, afterSource := effectiveMtlsMode(remaining, pod, mesh.RootNamespace)
+		if before == after {
+			continue
+		}
+		changed++
+		transition := fmt.Sprintf("workload %s: %s (%s) -> %s (%s)", workloadName(pod), before, beforeSource, after, afterSource)
+		if before == securityv1beta1api.PeerAuthentication_MutualTLS_STRICT.String() {
+			downgraded++
+			result += fmt.Sprintf("[WARNING] mTLS downgrade for %s; it would accept plaintext traffic\n", transition)
+		} else {
+			result += fmt.Sprintf("mTLS change for %s\n", transition)
+		}
+	}
+	if len(deleted.Spec.GetPortLevelMtls()) > 0 {
+		var ports []string
+		for port, portMtls := range deleted.Spec.GetPortLevelMtls() {
+			ports = append(ports, fmt.Sprintf("%d (%s)", port, portMtls.GetMode()))
+		}
+		slices.Sort(ports)
+		result += fmt.Sprintf("Port-level mTLS settings for ports %s are removed as well\n", strings.Join(ports, ", "))
+	}
+
+	if changed == 0 {
+		result += "[OK] No workload's mTLS mode changes\n"
+	} else {
+		result += fmt.Sprintf("\n[RESULT] %d workloads change mTLS mode, %d downgrade from STRICT\n", changed, downgraded)
+	}
+	return result, nil
+}
+
+// simulateAuthorizationPolicyDeletion explains how the requests the workloads a policy applies to would be
+// authorized without it
+func (i *Istio) simulateAuthorizationPolicyDeletion(ctx context.Context, deleted *securityv1beta1.AuthorizationPolicy, mesh *meshConfig) (string, error) {
+	action := deleted.Spec.GetAction()
+	if deleted.Spec.GetTargetRef() != nil || len(deleted.Spec.GetTargetRefs()) > 0 {
+		return fmt.Sprintf("[SKIP] The %s policy is attached to gateways or services through targetRefs; the workloads it applies to are not evaluated\n", action), nil
+	}
+	policies, err := i.listAuthorizationPolicies(ctx, "", metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list authorization policies: %w", explainForbidden(err, "list", "authorizationpolicies", ""))
+	}
+	pods, err := i.listMeshPods(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	selector := deleted.Spec.GetSelector().GetMatchLabels()
+	affected := selectedWorkloads(pods, deleted.Namespace, selector, mesh.RootNamespace)
+	result := fmt.Sprintf("Policy scope: %s, action %s, %d rules\n\n", policyScope(deleted.Namespace, selector, mesh.RootNamespace), action, len(deleted.Spec.GetRules()))
+	for _, pod := range affected {
+		workload := workloadName(pod)
+		switch action {
+		case securityv1beta1api.AuthorizationPolicy_ALLOW:
+			others := 0
+			for _, policy := range policies {
+				if policy.Spec.GetAction() != action || (policy.Namespace == deleted.Namespace && policy.Name == deleted.Name) {
+					continue
+				}
+				if policy.Namespace != pod.Namespace && policy.Namespace != mesh.RootNamespace {
+					continue
+				}
+				if _, applies := authzPolicyScope(policy, mesh.RootNamespace, labels.Set(pod.Labels)); applies {
+					others++
+				}
+			}
+			if others == 0 {
+				result += fmt.Sprintf("[WARNING] Workload %s: no ALLOW policy would be left, so all requests not denied by other policies would be allowed\n", workload)
+			} else {
+				result += fmt.Sprintf("Workload %s: requests allowed only by this policy's rules would be rejected; %d other ALLOW policies still apply\n", workload, others)
+			}
+		case securityv1beta1api.AuthorizationPolicy_DENY:
+			result += fmt.Sprintf("[WARNING] Workload %s: requests matching this policy's rules would no longer be denied\n", workload)
+		case securityv1beta1api.AuthorizationPolicy_CUSTOM:
+			result += fmt.Sprintf("[WARNING] Workload %s: requests would no longer be checked by external authorizer '%s'\n", workload, deleted.Spec.GetProvider().GetName())
+		default:
+			result += fmt.Sprintf("Workload %s: requests matching this policy's rules would no longer be audited\n", workload)
+		}
+	}
+
+	if len(affected) == 0 {
+		result += "[OK] The policy applies to no running mesh workload\n"
+	} else {
+		result += fmt.Sprintf("\n[RESULT] %d workloads lose the %s policy\n", len(affected), action)
+	}
+	return result, nil
+}
+
+// simulateVirtualServiceDeletion reports the hosts and gateways losing the routing rules of a VirtualService
+func (i *Istio) simulateVirtualServiceDeletion(ctx context.Context, deleted *networkingv1alpha3.VirtualService) (string, error) {
+	vsList, err := i.istioClient.NetworkingV1alpha3().VirtualServices("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list virtual services: %w", explainForbidden(err, "list", "virtualservices", ""))
+	}
+
+	result := fmt.Sprintf("Routes removed: %d HTTP, %d TLS, %d TCP\n\n", len(deleted.Spec.GetHttp()), len(deleted.Spec.GetTls()), len(deleted.Spec.GetTcp()))
+	gateways := deleted.Spec.GetGateways()
+	if len(gateways) == 0 {
+		gateways = []string{"mesh"}
+	}
+	for _, host := range deleted.Spec.GetHosts() {
+		qualified := qualifiedHost(host, deleted.Namespace)
+		var others []string
+		for _, vs := range vsList.Items {
+			if vs.Namespace == deleted.Namespace && vs.Name == deleted.Name {
+				continue
+			}
+			if slices.ContainsFunc(vs.Spec.GetHosts(), func(vsHost string) bool { return hostsIntersect(qualifiedHost(vsHost, vs.Namespace), qualified) }) {
+				others = append(others, vs.Namespace+"/"+vs.Name)
+			}
+		}
+		for _, gateway := range gateways {
+			gateway = gatewayRef(gateway, deleted.Namespace)
+			switch {
+			case len(others) > 0:
+				result += fmt.Sprintf("Host '%s' via %s: routing falls to %s\n", host, gateway, strings.Join(others, ", "))
+			case gateway == "mesh":
+				result += fmt.Sprintf("[WARNING] Host '%s' in the mesh loses its routing rules; requests are load balanced across all endpoints of the service, without subsets, retries, timeouts or fault injection\n", host)
+			default:
+				result += fmt.Sprintf("[WARNING] Host '%s' is no longer routed by gateway %s; requests to it through the gateway fail with 404\n", host, gateway)
+			}
+		}
+	}
+
+	result += fmt.Sprintf("\n[RESULT] %d hosts lose the routing rules of this VirtualService\n", len(deleted.Spec.GetHosts()))
+	return result, nil
+}
+
+// simulateDestinationRuleDeletion reports the traffic policy lost for the rule's host, the rule taking over and the
+// VirtualService routes left pointing to undefined subsets
+func (i *Istio) simulateDestinationRuleDeletion(ctx context.Context, deleted *networkingv1alpha3.DestinationRule) (string, error) {
+	drList, err := i.istioClient.NetworkingV1alpha3().DestinationRules("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list destination rules: %w", explainForbidden(err, "list", "destinationrules", ""))
+	}
+	vsList, err := i.istioClient.NetworkingV1alpha3().VirtualServices("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list virtual services: %w", explainForbidden(err, "list", "virtualservices", ""))
+	}
+
+	host := qualifiedHost(deleted.Spec.GetHost(), deleted.Namespace)
+	remaining := make(map[string]bool)
+	var others []string
+	for _, dr := range drList.Items {
+		if dr.Namespace == deleted.Namespace && dr.Name == deleted.Name || qualifiedHost(dr.Spec.GetHost(), dr.Namespace) != host {
+			continue
+		}
+		others = append(others, dr.Namespace+"/"+dr.Name)
+		for _, subset := range dr.Spec.GetSubsets() {
+			remaining[subset.GetName()] = true
+		}
+	}
+
+	result := ""
+	switch {
+	case len(others) > 0:
+		result += fmt.Sprintf("Host '%s' falls back to DestinationRule %s\n", host, strings.Join(others, ", "))
+	case deleted.Spec.GetTrafficPolicy() != nil:
+		result += fmt.Sprintf("[WARNING] Host '%s' loses its traffic policy (TLS, load balancing, connection pool and outlier detection revert to defaults)\n", host)
+	default:
+		result += fmt.Sprintf("Host '%s' has no traffic policy from this rule to lose\n", host)
+	}
+	if tls := deleted.Spec.GetTrafficPolicy().GetTls(); tls != nil && tls.GetMode() != networkingapi.ClientTLSSettings_ISTIO_MUTUAL && len(others) == 0 {
+		result += fmt.Sprintf("[WARNING] Client TLS mode %s is dropped; clients use auto mTLS instead\n", tls.GetMode())
+	}
+
+	broken := 0
+	for _, vs := range vsList.Items {
+		for _, destination := range virtualServiceDestinations(&vs.Spec) {
+			subset := destination.GetSubset()
+			if subset == "" || remaining[subset] || qualifiedHost(destination.GetHost(), vs.Namespace) != host {
+				continue
+			}
+			broken++
+			result += fmt.Sprintf("[ERROR] VirtualService %s/%s routes to subset '%s', which would be undefined; requests fail with 503\n", vs.Namespace, vs.Name, subset)
+		}
+	}
+
+	if broken == 0 {
+		result += "\n[RESULT] No route is left pointing to an undefined subset\n"
+	} else {
+		result += fmt.Sprintf("\n[RESULT] %d routes would point to undefined subsets\n", broken)
+	}
+	return result, nil
+}
+
+// simulateGatewayDeletion reports the servers a Gateway removes and the VirtualServices no longer served through it
+func (i *Istio) simulateGatewayDeletion(ctx context.Context, deleted *networkingv1alpha3.Gateway) (string, error) {
+	vsList, err := i.istioClient.NetworkingV1alpha3().VirtualServices("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list virtual services: %w", explainForbidden(err, "list", "virtualservices", ""))
+	}
+
+	result := ""
+	for _, server := range deleted.Spec.GetServers() {
+		port := server.GetPort()
+		result += fmt.Sprintf("[WARNING] Port %d/%s of the gateway workload stops accepting traffic for hosts %s\n", port.GetNumber(), port.GetProtocol(), strings.Join(server.GetHosts(), ", "))
+	}
+	ref := deleted.Namespace + "/" + deleted.Name
+	bound := 0
+	for _, vs := range vsList.Items {
+		if !slices.ContainsFunc(vs.Spec.GetGateways(), func(gateway string) bool { return gatewayRef(gateway, vs.Namespace) == ref }) {
+			continue
+		}
+		bound++
+		result += fmt.Sprintf("VirtualService %s/%s (hosts %s) would no longer be served through the gateway\n", vs.Namespace, vs.Name, strings.Join(vs.Spec.GetHosts(), ", "))
+	}
+
+	result += fmt.Sprintf("\n[RESULT] %d servers removed, %d VirtualServices lose the gateway\n", len(deleted.Spec.GetServers()), bound)
+	return result, nil
+}
+
+// simulateServiceEntryDeletion reports what happens to requests to the hosts of a ServiceEntry once they leave the
+// service registry and the resources left referring to them
+func (i *Istio) simulateServiceEntryDeletion(ctx context.Context, deleted *networkingv1alpha3.ServiceEntry, mesh *meshConfig) (string, error) {
+	vsList, err := i.istioClient.NetworkingV1alpha3().VirtualServices("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list virtual services: %w", explainForbidden(err, "list", "virtualservices", ""))
+	}
+	drList, err := i.istioClient.NetworkingV1alpha3().DestinationRules("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list destination rules: %w", explainForbidden(err, "list", "destinationrules", ""))
+	}
+
+	result := ""
+	for _, host := range deleted.Spec.GetHosts() {
+		if mesh.OutboundTrafficPolicy.Mode == "REGISTRY_ONLY" {
+			result += fmt.Sprintf("[ERROR] Host '%s' leaves the service registry; with outboundTrafficPolicy REGISTRY_ONLY requests to it are blocked\n", host)
+		} else {
+			result += fmt.Sprintf("[WARNING] Host '%s' leaves the service registry; requests to it pass through as unknown traffic, without mesh routing, TLS origination or telemetry\n", host)
+		}
+		for _, vs := range vsList.Items {
+			if slices.ContainsFunc(virtualServiceDestinations(&vs.Spec), func(destination *networkingapi.Destination) bool {
+				return hostMatches(host, destination.GetHost())
+			}) {
+				result += fmt.Sprintf("  VirtualService %s/%s would route to an unknown host\n", vs.Namespace, vs.Name)
+			}
+		}
+		for _, dr := range drList.Items {
+			if hostMatches(host, dr.Spec.GetHost()) {
+				result += fmt.Sprintf("  DestinationRule %s/%s would apply to no service\n", dr.Namespace, dr.Name)
+			}
+		}
+	}
+
+	result += fmt.Sprintf("\n[RESULT] %d hosts leave the service registry\n", len(deleted.Spec.GetHosts()))
+	return result, nil
+}
+
+// simulateSelectorPolicyDeletion reports the workloads a resource selecting workloads by label applies to
+func (i *Istio) simulateSelectorPolicyDeletion(ctx context.Context, namespace string, selector map[string]string, mesh *meshConfig, effect string) (string, error) {
+	pods, err := i.listMeshPods(ctx)
+	if err != nil {
+		return "", err
+	}
+	affected := selectedWorkloads(pods, namespace, selector, mesh.RootNamespace)
+	result := fmt.Sprintf("Scope: %s\n\n", policyScope(namespace, selector, mesh.RootNamespace))
+	for _, pod := range affected {
+		result += fmt.Sprintf("Workload %s would %s\n", workloadName(pod), effect)
+	}
+	if len(affected) == 0 {
+		result += "[OK] The resource applies to no running mesh workload\n"
+	} else {
+		result += fmt.Sprintf("\n[RESULT] %d workloads affected\n", len(affected))
+	}
+	return result, nil
+}
@@ -0,0 +1,85 @@
+package istio
+
+import (
+	"context"
+	"testing"
+)
+
+// TestSimulateResourceDeletion tests predicting the mTLS downgrade caused by deleting a namespace-wide PeerAuthentication
+func TestSimulateResourceDeletion(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{
+		"/apis/security.istio.io/v1beta1/namespaces/bookinfo/peerauthentications/default": `{
+			"apiVersion": "security.istio.io/v1beta1",
+			"kind": "PeerAuthentication",
+			"metadata": {"name": "default", "namespace": "bookinfo"},
+			"spec": {"mtls": {"mode": "STRICT"}}
+		}`,
+		"/apis/security.istio.io/v1beta1/peerauthentications": `{
+			"apiVersion": "security.istio.io/v1beta1",
+			"kind": "PeerAuthenticationList",
+			"items": [
+				{"metadata": {"name": "default", "namespace": "bookinfo"}, "spec": {"mtls": {"mode": "STRICT"}}},
+				{"metadata": {"name": "ratings", "namespace": "bookinfo"}, "spec": {"selector": {"matchLabels": {"app": "ratings"}}, "mtls": {"mode": "STRICT"}}}
+			]
+		}`,
+		"/api/v1/pods": `{
+			"apiVersion": "v1",
+			"kind": "PodList",
+			"items": [
+				{"metadata": {"name": "reviews-v1-abc", "namespace": "bookinfo", "labels": {"app": "reviews"}}, "spec": {"containers": [{"name": "reviews"}, {"name": "istio-proxy"}]}, "status": {"phase": "Running"}},
+				{"metadata": {"name": "ratings-v1-def", "namespace": "bookinfo", "labels": {"app": "ratings"}}, "spec": {"containers": [{"name": "ratings"}, {"name": "istio-proxy"}]}, "status": {"phase": "Running"}},
+				{"metadata": {"name": "httpbin-ghi", "namespace": "default", "labels": {"app": "httpbin"}}, "spec": {"containers": [{"name": "httpbin"}, {"name": "istio-proxy"}]}, "status": {"phase": "Running"}}
+			]
+		}`,
+	})
+	defer mockServer.Close()
+	istio := newTestIstio(t, mockServer.URL)
+
+	result, err := istio.SimulateResourceDeletion(context.Background(), "PeerAuthentication", "bookinfo", "default")
+	if err != nil {
+		t.Fatalf("SimulateResourceDeletion failed: %v", err)
+	}
+	assertContains(t, result,
+		"Simulated deletion of PeerAuthentication 'bookinfo/default' (nothing is deleted)",
+		"Policy scope: namespace-wide, mode STRICT",
+		"[WARNING] mTLS downgrade for workload bookinfo/reviews: STRICT (bookinfo/default) -> PERMISSIVE (mesh default); it would accept plaintext traffic",
+		"[RESULT] 1 workloads change mTLS mode, 1 downgrade from STRICT",
+	)
+	assertNotContains(t, result, "bookinfo/ratings", "default/httpbin")
+}
+
+// TestSimulateResourceDeletionRootNamespaceSelector tests that a selector policy in the mesh root namespace affects
+// the selected workloads of every namespace
+func TestSimulateResourceDeletionRootNamespaceSelector(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{
+		"/apis/networking.istio.io/v1alpha3/namespaces/istio-system/envoyfilters/reviews-lua": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "EnvoyFilter",
+			"metadata": {"name": "reviews-lua", "namespace": "istio-system"},
+			"spec": {"workloadSelector": {"labels": {"app": "reviews"}}}
+		}`,
+		"/api/v1/pods": `{
+			"apiVersion": "v1",
+			"kind": "PodList",
+			"items": [
+				{"metadata": {"name": "reviews-v1-abc", "namespace": "bookinfo", "labels": {"app": "reviews"}}, "spec": {"containers": [{"name": "reviews"}, {"name": "istio-proxy"}]}, "status": {"phase": "Running"}},
+				{"metadata": {"name": "reviews-v2-jkl", "namespace": "staging", "labels": {"app": "reviews"}}, "spec": {"containers": [{"name": "reviews"}, {"name": "istio-proxy"}]}, "status": {"phase": "Running"}},
+				{"metadata": {"name": "ratings-v1-def", "namespace": "bookinfo", "labels": {"app": "ratings"}}, "spec": {"containers": [{"name": "ratings"}, {"name": "istio-proxy"}]}, "status": {"phase": "Running"}}
+			]
+		}`,
+	})
+	defer mockServer.Close()
+	istio := newTestIstio(t, mockServer.URL)
+
+	result, err := istio.SimulateResourceDeletion(context.Background(), "EnvoyFilter", "istio-system", "reviews-lua")
+	if err != nil {
+		t.Fatalf("SimulateResourceDeletion failed: %v", err)
+	}
+	assertContains(t, result,
+		"Scope: workload, in all namespaces",
+		"Workload bookinfo/reviews would lose the Envoy configuration patches of the filter",
+		"Workload staging/reviews would lose the Envoy configuration patches of the filter",
+		"[RESULT] 2 workloads affected",
+	)
+	assertNotContains(t, result, "bookinfo/ratings")
+}
@@ -0,0 +1,116 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	networkingv1alpha3 "istio.io/api/networking/v1alpha3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// subsetReference is a route destination that selects a named subset
+type subsetReference struct {
+	virtualService string
+	host           string
+	subset         string
+}
+
+// virtualServiceDestinations collects the destinations of every HTTP, TCP and TLS route of a VirtualService, including mirrors
+func virtualServiceDestinations(spec *networkingv1alpha3.VirtualService) []*networkingv1alpha3.Destination {
+	var destinations []*networkingv1alpha3.Destination
+	for _, route := range spec.GetHttp() {
+		for _, rd := range route.GetRoute() {
+			destinations = append(destinations, rd.GetDestination())
+		}
+		if route.GetMirror() != nil {
+			destinations = append(destinations, route.GetMirror())
+		}
+		for _, mirror := range route.GetMirrors() {
+			destinations = append(destinations, mirror.GetDestination())
+		}
+	}
+	for _, route := range spec.GetTcp() {
+		for _, rd := range route.GetRoute() {
+			destinations = append(destinations, rd.GetDestination())
+		}
+	}
+	for _, route := range spec.GetTls() {
+		for _, rd := range route.GetRoute() {
+			destinations = append(destinations, rd.GetDestination())
+		}
+	}
+	return destinations
+}
+
+// FindMissingSubsetDefinitions reports VirtualService routes to subsets that no DestinationRule defines.
+// Requests matching such routes fail with 503 (no healthy upstream).
+func (i *Istio) FindMissingSubsetDefinitions(ctx context.Context, namespace string) (string, error) {
+	vsList, err := i.istioClient.NetworkingV1alpha3().VirtualServices(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list virtual services: %w", explainForbidden(err, "list", "virtualservices", namespace))
+	}
+
+	var references []subsetReference
+	namespaces := map[string]bool{namespace: true}
+	for _, vs := range vsList.Items {
+		for _, destination := range virtualServiceDestinations(&vs.Spec) {
+			if destination.GetSubset() == "" {
+				continue
+			}
+			host := qualifiedHost(destination.GetHost(), namespace)
+			references = append(references, subsetReference{virtualService: vs.Name, host: host, subset: destination.GetSubset()})
+			// DestinationRules for a service usually live in the service's own namespace
+			if ns := hostNamespace(host); ns != "" {
+				namespaces[ns] = true
+			}
+		}
+	}
+
+	// subsets maps a fully qualified host to the subsets defined for it
+	subsets := make(map[string]map[string]bool)
+	for drNamespace := range namespaces {
+		drList, err := i.istioClient.NetworkingV1alpha3().DestinationRules(drNamespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return "", fmt.Errorf("failed to list destination rules: %w", explainForbidden(err, "list", "destinationrules", drNamespace))
+		}
+		for _, dr := range drList.Items {
+			host := qualifiedHost(dr.Spec.Host, drNamespace)
+			if subsets[host] == nil {
+				subsets[host] = make(map[string]bool)
+			}
+			for _, subset := range dr.Spec.Subsets {
+				subsets[host][subset.Name] = true
+			}
+		}
+	}
+
+	result := fmt.Sprintf("Subset references in namespace '%s':\n\n", namespace)
+	missing := 0
+	for _, ref := range references {
+		defined, hasRule := subsets[ref.host]
+		if defined[ref.subset] {
+			continue
+		}
+		missing++
+		result += fmt.Sprintf("[ERROR] VirtualService '%s' routes to subset '%s' of host '%s', but no DestinationRule defines it\n", ref.virtualService, ref.subset, ref.host)
+		if !hasRule {
+			result += "  No DestinationRule exists for this host\n"
+		} else if len(defined) > 0 {
+			names := make([]string, 0, len(defined))
+			for name := range defined {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			result += fmt.Sprintf("  Defined subsets: %s\n", strings.Join(names, ", "))
+		}
+	}
+
+	if missing == 0 {
+		result += fmt.Sprintf("[OK] All %d subset references are defined by a DestinationRule\n", len(references))
+	} else {
+		result += fmt.Sprintf("\n[RESULT] %d of %d subset references have no backing subset; requests matching these routes fail with 503\n", missing, len(references))
+	}
+	return result, nil
+}
@@ -0,0 +1,68 @@
+package istio
+
+import (
+	"context"
+	"testing"
+)
+
+// TestFindMissingSubsetDefinitions tests detection of routes to undefined subsets
+func TestFindMissingSubsetDefinitions(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{
+		"/apis/networking.istio.io/v1alpha3/namespaces/bookinfo/virtualservices": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "VirtualServiceList",
+			"items": [
+				{
+					"metadata": {"name": "reviews", "namespace": "bookinfo"},
+					"spec": {
+						"hosts": ["reviews"],
+						"http": [{"route": [
+							{"destination": {"host": "reviews", "subset": "v1"}, "weight": 50},
+							{"destination": {"host": "reviews.bookinfo.svc.cluster.local", "subset": "v3"}, "weight": 50}
+						]}]
+					}
+				},
+				{
+					"metadata": {"name": "ratings", "namespace": "bookinfo"},
+					"spec": {
+						"hosts": ["ratings"],
+						"tcp": [{"route": [{"destination": {"host": "ratings", "subset": "v1"}}]}]
+					}
+				},
+				{
+					"metadata": {"name": "details", "namespace": "bookinfo"},
+					"spec": {"hosts": ["details"], "http": [{"route": [{"destination": {"host": "details"}}]}]}
+				}
+			]
+		}`,
+		"/apis/networking.istio.io/v1alpha3/namespaces/bookinfo/destinationrules": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "DestinationRuleList",
+			"items": [
+				{
+					"metadata": {"name": "reviews", "namespace": "bookinfo"},
+					"spec": {"host": "reviews", "subsets": [
+						{"name": "v1", "labels": {"version": "v1"}},
+						{"name": "v2", "labels": {"version": "v2"}}
+					]}
+				}
+			]
+		}`,
+	})
+	defer mockServer.Close()
+
+	istio := newTestIstio(t, mockServer.URL)
+
+	result, err := istio.FindMissingSubsetDefinitions(context.Background(), "bookinfo")
+	if err != nil {
+		t.Fatalf("Failed to find missing subsets: %v", err)
+	}
+	assertContains(t, result,
+		"[ERROR] VirtualService 'reviews' routes to subset 'v3' of host 'reviews.bookinfo.svc.cluster.local'",
+		"Defined subsets: v1, v2",
+		"[ERROR] VirtualService 'ratings' routes to subset 'v1' of host 'ratings.bookinfo.svc.cluster.local'",
+		"No DestinationRule exists for this host",
+		"[RESULT] 2 of 3 subset references have no backing subset",
+	)
+	assertNotContains(t, result, "subset 'v1' of host 'reviews")
+}
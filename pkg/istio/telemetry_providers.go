@@ -0,0 +1,84 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	telemetryapi "istio.io/api/telemetry/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// builtinTelemetryProviders are the providers Istio defines even when the mesh config lists no extensionProviders
+var builtinTelemetryProviders = []string{"envoy", "prometheus", "stackdriver"}
+
+// telemetryProviderReference is a provider a Telemetry resource selects for tracing, metrics or access logging
+type telemetryProviderReference struct {
+	kind string
+	name string
+}
+
+// telemetryProviderReferences collects the provider names a Telemetry resource references
+func telemetryProviderReferences(spec *telemetryapi.Telemetry) []telemetryProviderReference {
+	var references []telemetryProviderReference
+	for _, tracing := range spec.GetTracing() {
+		for _, provider := range tracing.GetProviders() {
+			references = append(references, telemetryProviderReference{kind: "tracing", name: provider.GetName()})
+		}
+	}
+	for _, metrics := range spec.GetMetrics() {
+		for _, provider := range metrics.GetProviders() {
+			references = append(references, telemetryProviderReference{kind: "metrics", name: provider.GetName()})
+		}
+	}
+	for _, accessLogging := range spec.GetAccessLogging() {
+		for _, provider := range accessLogging.GetProviders() {
+			references = append(references, telemetryProviderReference{kind: "access logging", name: provider.GetName()})
+		}
+	}
+	return references
+}
+
+// ValidateTelemetryProviders checks the tracing, metrics and access logging providers the Telemetry resources of a
+// namespace reference against the extensionProviders of the mesh config. Istio silently ignores a reference to an
+// undefined provider, so the telemetry it configures is never produced.
+func (i *Istio) ValidateTelemetryProviders(ctx context.Context, namespace string) (string, error) {
+	mesh, err := i.getMeshConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+	telList, err := i.istioClient.TelemetryV1alpha1().Telemetries(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list telemetries: %w", i.explainAPIError(ctx, err, "list", "telemetries", namespace))
+	}
+
+	defined := slices.Clone(builtinTelemetryProviders)
+	for _, provider := range mesh.ExtensionProviders {
+		if !slices.Contains(defined, provider.Name) {
+			defined = append(defined, provider.Name)
+		}
+	}
+	slices.Sort(defined)
+
+	result := fmt.Sprintf("Telemetry provider references in namespace '%s':\n\n", namespace)
+	result += fmt.Sprintf("Providers defined in the mesh config: %s\n\n", strings.Join(defined, ", "))
+	references, unknown := 0, 0
+	for _, tel := range telList.Items {
+		for _, ref := range telemetryProviderReferences(&tel.Spec) {
+			references++
+			if slices.Contains(defined, ref.name) {
+				continue
+			}
+			unknown++
+			result += fmt.Sprintf("[ERROR] Telemetry '%s' references %s provider '%s', which is not defined in meshConfig.extensionProviders; this telemetry is silently not produced\n", tel.Name, ref.kind, ref.name)
+		}
+	}
+
+	if unknown == 0 {
+		result += fmt.Sprintf("[OK] All %d provider references of %d Telemetry resources are defined\n", references, len(telList.Items))
+	} else {
+		result += fmt.Sprintf("\n[RESULT] %d of %d provider references are undefined\n", unknown, references)
+	}
+	return result, nil
+}
@@ -0,0 +1,42 @@
+package istio
+
+import (
+	"context"
+	"testing"
+)
+
+// TestValidateTelemetryProviders tests that the providers referenced by Telemetry resources are defined in the mesh config
+func TestValidateTelemetryProviders(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{
+		"/api/v1/namespaces/istio-system/configmaps/istio": `{
+			"apiVersion": "v1",
+			"kind": "ConfigMap",
+			"metadata": {"name": "istio", "namespace": "istio-system"},
+			"data": {"mesh": "extensionProviders:\n- name: otel-tracing\n  opentelemetry:\n    service: opentelemetry-collector.observability.svc.cluster.local\n    port: 4317\n"}
+		}`,
+		"/apis/telemetry.istio.io/v1alpha1/namespaces/bookinfo/telemetries": `{
+			"apiVersion": "telemetry.istio.io/v1alpha1",
+			"kind": "TelemetryList",
+			"items": [
+				{"metadata": {"name": "tracing", "namespace": "bookinfo"}, "spec": {"tracing": [{"providers": [{"name": "otel-tracing"}], "randomSamplingPercentage": 10}]}},
+				{"metadata": {"name": "logging", "namespace": "bookinfo"}, "spec": {
+					"accessLogging": [{"providers": [{"name": "envoy"}, {"name": "otel-logs"}]}],
+					"metrics": [{"providers": [{"name": "prometheus"}]}]
+				}}
+			]
+		}`,
+	})
+	defer mockServer.Close()
+	istio := newTestIstio(t, mockServer.URL)
+
+	result, err := istio.ValidateTelemetryProviders(context.Background(), "bookinfo")
+	if err != nil {
+		t.Fatalf("ValidateTelemetryProviders failed: %v", err)
+	}
+	assertContains(t, result,
+		"Providers defined in the mesh config: envoy, otel-tracing, prometheus, stackdriver",
+		"[ERROR] Telemetry 'logging' references access logging provider 'otel-logs', which is not defined in meshConfig.extensionProviders",
+		"[RESULT] 1 of 4 provider references are undefined",
+	)
+	assertNotContains(t, result, "provider 'otel-tracing'", "provider 'envoy'", "provider 'prometheus'")
+}
@@ -0,0 +1,98 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+
+	networkingv1alpha3 "istio.io/api/networking/v1alpha3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// tlsSetting is a client TLS configuration found in a DestinationRule, together with where it was declared
+type tlsSetting struct {
+	scope string
+	tls   *networkingv1alpha3.ClientTLSSettings
+}
+
+// destinationRuleTLSSettings collects every client TLS setting of a DestinationRule,
+// including port-level settings and subset-level overrides
+func destinationRuleTLSSettings(spec *networkingv1alpha3.DestinationRule) []tlsSetting {
+	var settings []tlsSetting
+	collect := func(scope string, policy *networkingv1alpha3.TrafficPolicy) {
+		if policy == nil {
+			return
+		}
+		if policy.Tls != nil {
+			settings = append(settings, tlsSetting{scope: scope, tls: policy.Tls})
+		}
+		for _, portPolicy := range policy.PortLevelSettings {
+			if portPolicy.Tls != nil && portPolicy.Port != nil {
+				settings = append(settings, tlsSetting{scope: fmt.Sprintf("%s, port %d", scope, portPolicy.Port.Number), tls: portPolicy.Tls})
+			}
+		}
+	}
+	collect("traffic policy", spec.TrafficPolicy)
+	for _, subset := range spec.Subsets {
+		collect(fmt.Sprintf("subset '%s'", subset.Name), subset.TrafficPolicy)
+	}
+	return settings
+}
+
+// AuditTlsOrigination lists DestinationRules that originate TLS (SIMPLE or MUTUAL mode) and reports
+// their SNI and certificate configuration, warning about settings that will cause certificate errors
+func (i *Istio) AuditTlsOrigination(ctx context.Context, namespace string) (string, error) {
+	drList, err := i.istioClient.NetworkingV1alpha3().DestinationRules(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list destination rules: %w", explainForbidden(err, "list", "destinationrules", namespace))
+	}
+
+	result := fmt.Sprintf("TLS Origination Audit for namespace '%s':\n\n", namespace)
+
+	originating := 0
+	warnings := 0
+	for _, dr := range drList.Items {
+		for _, setting := range destinationRuleTLSSettings(&dr.Spec) {
+			tls := setting.tls
+			if tls.Mode != networkingv1alpha3.ClientTLSSettings_SIMPLE && tls.Mode != networkingv1alpha3.ClientTLSSettings_MUTUAL {
+				continue
+			}
+			originating++
+
+			result += fmt.Sprintf("- %s (host: %s, %s)\n", dr.Name, dr.Spec.Host, setting.scope)
+			result += fmt.Sprintf("  Mode: %s\n", tls.Mode.String())
+			if tls.Sni != "" {
+				result += fmt.Sprintf("  SNI: %s\n", tls.Sni)
+			} else {
+				result += "  SNI: <not set> (the upstream host name is used)\n"
+			}
+			if tls.CredentialName != "" {
+				result += fmt.Sprintf("  Credential Name: %s\n", tls.CredentialName)
+			}
+
+			skipVerify := tls.InsecureSkipVerify != nil && tls.InsecureSkipVerify.GetValue()
+			switch {
+			case skipVerify:
+				result += "  [WARNING] insecureSkipVerify is enabled; the server certificate is not verified\n"
+				warnings++
+			case tls.CaCertificates == "" && tls.CredentialName == "":
+				result += "  [WARNING] No caCertificates or credentialName set; the server certificate is verified against the proxy's default CA bundle only\n"
+				warnings++
+			}
+
+			if tls.Mode == networkingv1alpha3.ClientTLSSettings_MUTUAL && tls.CredentialName == "" {
+				if tls.ClientCertificate == "" || tls.PrivateKey == "" {
+					result += "  [WARNING] MUTUAL mode requires clientCertificate and privateKey (or credentialName), but they are missing; connections will fail the TLS handshake\n"
+					warnings++
+				}
+			}
+		}
+	}
+
+	if originating == 0 {
+		result += "No DestinationRules performing TLS origination (SIMPLE or MUTUAL mode) found\n"
+		return result, nil
+	}
+
+	result += fmt.Sprintf("\n[RESULT] %d TLS origination settings found, %d warnings\n", originating, warnings)
+	return result, nil
+}
@@ -0,0 +1,66 @@
+package istio
+
+import (
+	"context"
+	"testing"
+)
+
+// TestAuditTlsOrigination tests reporting of DestinationRules originating TLS
+func TestAuditTlsOrigination(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{
+		"/apis/networking.istio.io/v1alpha3/namespaces/egress/destinationrules": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "DestinationRuleList",
+			"items": [
+				{
+					"metadata": {"name": "payments-mtls", "namespace": "egress"},
+					"spec": {
+						"host": "payments.example.com",
+						"trafficPolicy": {"tls": {"mode": "MUTUAL", "sni": "payments.example.com", "caCertificates": "/etc/certs/ca.pem"}}
+					}
+				},
+				{
+					"metadata": {"name": "api-tls", "namespace": "egress"},
+					"spec": {
+						"host": "api.example.com",
+						"trafficPolicy": {"portLevelSettings": [{"port": {"number": 443}, "tls": {"mode": "SIMPLE", "credentialName": "api-ca"}}]}
+					}
+				},
+				{
+					"metadata": {"name": "internal", "namespace": "egress"},
+					"spec": {"host": "reviews", "trafficPolicy": {"tls": {"mode": "ISTIO_MUTUAL"}}}
+				}
+			]
+		}`,
+		"/apis/networking.istio.io/v1alpha3/namespaces/empty/destinationrules": `{"apiVersion": "networking.istio.io/v1alpha3", "kind": "DestinationRuleList", "items": []}`,
+	})
+	defer mockServer.Close()
+
+	istio := newTestIstio(t, mockServer.URL)
+	ctx := context.Background()
+
+	t.Run("warns about MUTUAL origination without client certs", func(t *testing.T) {
+		result, err := istio.AuditTlsOrigination(ctx, "egress")
+		if err != nil {
+			t.Fatalf("Failed to audit TLS origination: %v", err)
+		}
+		assertContains(t, result,
+			"- payments-mtls (host: payments.example.com, traffic policy)",
+			"Mode: MUTUAL",
+			"SNI: payments.example.com",
+			"MUTUAL mode requires clientCertificate and privateKey",
+			"- api-tls (host: api.example.com, traffic policy, port 443)",
+			"Credential Name: api-ca",
+			"[RESULT] 2 TLS origination settings found, 1 warnings",
+		)
+		assertNotContains(t, result, "internal")
+	})
+
+	t.Run("reports namespace without origination", func(t *testing.T) {
+		result, err := istio.AuditTlsOrigination(ctx, "empty")
+		if err != nil {
+			t.Fatalf("Failed to audit TLS origination: %v", err)
+		}
+		assertContains(t, result, "No DestinationRules performing TLS origination")
+	})
+}
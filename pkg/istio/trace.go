@@ -0,0 +1,293 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	networkingapi "istio.io/api/networking/v1alpha3"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// findWorkloadPod returns a pod of the named workload: a pod with that name, its 'app' label, or named after it
+func findWorkloadPod(pods []v1.Pod, workload string) *v1.Pod {
+	for idx, pod := range pods {
+		if pod.Name == workload || pod.Labels["app"] == workload || strings.HasPrefix(pod.Name, workload+"-") {
+			return &pods[idx]
+		}
+	}
+	return nil
+}
+
+// sidecarEgressAllows reports whether one of the 'namespace/dnsName' egress hosts of a Sidecar imports host
+func sidecarEgressAllows(egressHosts []string, clientNamespace, host string) bool {
+	for _, egressHost := range egressHosts {
+		ns, dnsName, found := strings.Cut(egressHost, "/")
+		if !found {
+			continue
+		}
+		switch ns {
+		case "~":
+			continue
+		case ".":
+			ns = clientNamespace
+		}
+		serviceNamespace := hostNamespace(host)
+		if (ns == "*" || serviceNamespace == "" || ns == serviceNamespace) && hostMatches(dnsName, host) {
+			return true
+		}
+	}
+	return false
+}
+
+// httpRouteName identifies an HTTP route of a VirtualService by its name, or by its 1-based position when unnamed
+func httpRouteName(route *networkingapi.HTTPRoute, idx int) string {
+	if route.GetName() != "" {
+		return fmt.Sprintf("'%s'", route.GetName())
+	}
+	return fmt.Sprintf("#%d", idx+1)
+}
+
+// matchHTTPRoute returns the first HTTP route of a VirtualService matching a request, and the index of the route.
+// It also describes the earlier routes whose path matched but which another condition, such as a header, excluded.
+func matchHTTPRoute(spec *networkingapi.VirtualService, req simulatedRequest) (*networkingapi.HTTPRoute, int, []string) {
+	var skipped []string
+	for idx, route := range spec.GetHttp() {
+		if len(route.GetMatch()) == 0 {
+			return route, idx, skipped
+		}
+		var reasons []string
+		for _, match := range route.GetMatch() {
+			ok, reason := requestMatches(match, req)
+			if ok {
+				return route, idx, skipped
+			}
+			if match.GetUri() == nil || stringMatches(match.GetUri(), req.path, match.GetIgnoreUriCase()) {
+				reasons = append(reasons, reason)
+			}
+		}
+		if len(reasons) > 0 {
+			skipped = append(skipped, fmt.Sprintf("route %s: %s", httpRouteName(route, idx), strings.Join(reasons, "; ")))
+		}
+	}
+	return nil, -1, skipped
+}
+
+// trafficPolicyFields names the settings configured in a traffic policy
+func trafficPolicyFields(policy *networkingapi.TrafficPolicy) []string {
+	var fields []string
+	if policy.GetLoadBalancer() != nil {
+		fields = append(fields, "loadBalancer")
+	}
+	if policy.GetConnectionPool() != nil {
+		fields = append(fields, "connectionPool")
+	}
+	if policy.GetOutlierDetection() != nil {
+		fields = append(fields, "outlierDetection")
+	}
+	if policy.GetTls() != nil {
+		fields = append(fields, fmt.Sprintf("tls (%s)", policy.GetTls().GetMode()))
+	}
+	if len(policy.GetPortLevelSettings()) > 0 {
+		fields = append(fields, "portLevelSettings")
+	}
+	return fields
+}
+
+// TraceRequestPath statically follows a request from a workload to host and path through the mesh configuration:
+// the Sidecar egress scope of the workload, the VirtualService route matching the path, the DestinationRule
+// subset and traffic policy, and the resulting Envoy cluster. Each decision is narrated.
+func (i *Istio) TraceRequestPath(ctx context.Context, namespace, fromWorkload, host, path string) (string, error) {
+	if path == "" {
+		path = "/"
+	}
+	target, err := i.resolveHost(ctx, host, namespace)
+	if err != nil {
+		return "", err
+	}
+	result := fmt.Sprintf("Request trace: '%s' in namespace '%s' -> %s%s\n\n", fromWorkload, namespace, target, path)
+
+	// Step 1: source workload
+	pods, err := i.kubeClient.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list pods: %w", explainForbidden(err, "list", "pods", namespace))
+	}
+	pod := findWorkloadPod(pods.Items, fromWorkload)
+	if pod != nil {
+		result += fmt.Sprintf("1. Source: pod '%s' (labels: %v)\n", pod.Name, pod.Labels)
+		if !hasIstioSidecar(*pod) {
+			result += "   [WARNING] The pod has no Istio sidecar; its traffic bypasses the mesh routing below\n"
+		}
+	} else {
+		result += fmt.Sprintf("1. Source: [WARNING] no pod found for workload '%s'; applying namespace-wide configuration\n", fromWorkload)
+	}
+
+	// Step 2: Sidecar egress scope
+	mesh, err := i.getMeshConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+	scope, err := i.workloadSidecarScope(ctx, namespace, pod, mesh.RootNamespace)
+	if err != nil {
+		return "", err
+	}
+	sidecar := scope.applied()
+	if sidecar == nil || len(sidecar.Spec.GetEgress()) == 0 {
+		result += "2. Sidecar egress: [OK] no Sidecar restricts egress; all mesh hosts are visible\n"
+	} else {
+		var egressHosts []string
+		for _, egress := range sidecar.Spec.GetEgress() {
+			egressHosts = append(egressHosts, egress.GetHosts()...)
+		}
+		if sidecarEgressAllows(egressHosts, namespace, target) {
+			result += fmt.Sprintf("2. Sidecar egress: [OK] Sidecar '%s/%s' imports the host (egress hosts: %v)\n", sidecar.Namespace, sidecar.Name, egressHosts)
+		} else {
+			result += fmt.Sprintf("2. Sidecar egress: [FAIL] Sidecar '%s/%s' does not import the host (egress hosts: %v)\n", sidecar.Namespace, sidecar.Name, egressHosts)
+			result += "   The proxy has no route for this host; the request falls through to the outbound traffic policy\n"
+			return result, nil
+		}
+	}
+
+	// Step 3: VirtualService route
+	vsList, err := i.istioClient.NetworkingV1alpha3().VirtualServices("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list virtual services: %w", explainForbidden(err, "list", "virtualservices", ""))
+	}
+	virtualServices := meshVirtualServicesForHost(vsList.Items, namespace, target, mesh)
+
+	var destinations []*networkingapi.HTTPRouteDestination
+	// routeNamespace is the namespace short destination hosts are resolved in
+	routeNamespace := namespace
+	if len(virtualServices) == 0 {
+		result += "3. VirtualService: none applies; the request goes to the host with default routing\n"
+		destinations = []*networkingapi.HTTPRouteDestination{{Destination: &networkingapi.Destination{Host: target}}}
+	} else {
+		vs := virtualServices[0]
+		routeNamespace = vs.Namespace
+		route, idx, skipped := matchHTTPRoute(&vs.Spec, simulatedRequest{authority: host, path: path, method: "GET"})
+		if route == nil {
+			result += fmt.Sprintf("3. VirtualService: [FAIL] '%s/%s' has no HTTP route matching path '%s'; the request gets a 404\n", vs.Namespace, vs.Name, path)
+			for _, reason := range skipped {
+				result += fmt.Sprintf("   [SKIP] %s (the trace sends a plain GET without headers)\n", reason)
+			}
+			return result, nil
+		}
+		result += fmt.Sprintf("3. VirtualService: '%s/%s' route %s matches path '%s'\n", vs.Namespace, vs.Name, httpRouteName(route, idx), path)
+		for _, reason := range skipped {
+			result += fmt.Sprintf("   [SKIP] %s (the trace sends a plain GET without headers)\n", reason)
+		}
+		if len(virtualServices) > 1 {
+			result += fmt.Sprintf("   [WARNING] %d VirtualServices define this host; only the first is considered\n", len(virtualServices))
+		}
+		if route.GetRedirect() != nil {
+			result += fmt.Sprintf("   The route redirects to %s%s; the request does not reach a cluster\n", route.GetRedirect().GetAuthority(), route.GetRedirect().GetUri())
+			return result, nil
+		}
+		if route.GetDirectResponse() != nil {
+			result += fmt.Sprintf("   The route returns a direct response with status %d; the request does not reach a cluster\n", route.GetDirectResponse().GetStatus())
+			return result, nil
+		}
+		if route.GetRewrite().GetUri() != "" {
+			result += fmt.Sprintf("   The path is rewritten to '%s'\n", route.GetRewrite().GetUri())
+		}
+		destinations = route.GetRoute()
+		for _, rd := range destinations {
+			result += fmt.Sprintf("   -> %s", qualifiedHost(rd.GetDestination().GetHost(), vs.Namespace))
+			if rd.GetDestination().GetSubset() != "" {
+				result += fmt.Sprintf(" subset '%s'", rd.GetDestination().GetSubset())
+			}
+			if len(destinations) > 1 {
+				result += fmt.Sprintf(" (weight %d)", rd.GetWeight())
+			}
+			result += "\n"
+		}
+	}
+
+	// Step 4: DestinationRule subset and policy
+	drList, err := i.istioClient.NetworkingV1alpha3().DestinationRules("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list destination rules: %w", explainForbidden(err, "list", "destinationrules", ""))
+	}
+	var clusters []string
+	result += "4. DestinationRule:\n"
+	for _, rd := range destinations {
+		destination := rd.GetDestination()
+		host := qualifiedHost(destination.GetHost(), routeNamespace)
+		rule := effectiveDestinationRule(drList.Items, host, namespace, mesh)
+
+		subset := destination.GetSubset()
+		switch {
+		case rule == nil && subset != "":
+			result += fmt.Sprintf("   [FAIL] No DestinationRule for '%s' defines subset '%s'; the request fails with 503\n", host, subset)
+			continue
+		case rule == nil:
+			result += fmt.Sprintf("   No DestinationRule for '%s'; default traffic policy applies\n", host)
+		default:
+			result += fmt.Sprintf("   '%s/%s' applies to '%s'", rule.Namespace, rule.Name, host)
+			if fields := trafficPolicyFields(rule.Spec.GetTrafficPolicy()); len(fields) > 0 {
+				result += fmt.Sprintf(" (traffic policy: %s)", strings.Join(fields, ", "))
+			}
+			result += "\n"
+			if subset != "" {
+				var found *networkingapi.Subset
+				for _, s := range rule.Spec.GetSubsets() {
+					if s.GetName() == subset {
+						found = s
+						break
+					}
+				}
+				if found == nil {
+					result += fmt.Sprintf("   [FAIL] Subset '%s' is not defined by '%s/%s'; the request fails with 503\n", subset, rule.Namespace, rule.Name)
+					continue
+				}
+				result += fmt.Sprintf("   Subset '%s' selects pods with labels %v", subset, found.GetLabels())
+				if fields := trafficPolicyFields(found.GetTrafficPolicy()); len(fields) > 0 {
+					result += fmt.Sprintf(" and overrides the traffic policy (%s)", strings.Join(fields, ", "))
+				}
+				result += "\n"
+			}
+		}
+		port, err := i.destinationPort(ctx, host, destination.GetPort().GetNumber())
+		if err != nil {
+			return "", err
+		}
+		clusters = append(clusters, fmt.Sprintf("outbound|%s|%s|%s", port, subset, host))
+	}
+
+	// Step 5: resulting Envoy cluster
+	if len(clusters) == 0 {
+		result += "5. Envoy cluster: none; no destination of the route can be reached\n"
+		return result, nil
+	}
+	result += "5. Envoy cluster:\n"
+	for _, cluster := range clusters {
+		result += fmt.Sprintf("   %s\n", cluster)
+	}
+	return result, nil
+}
+
+// destinationPort returns the port of a route destination as used in Envoy cluster names: the explicit
+// destination port, else the only port of the Kubernetes service, else a placeholder
+func (i *Istio) destinationPort(ctx context.Context, host string, number uint32) (string, error) {
+	if number != 0 {
+		return fmt.Sprintf("%d", number), nil
+	}
+	ns := hostNamespace(host)
+	if ns == "" {
+		return "<port>", nil
+	}
+	name := strings.SplitN(host, ".", 2)[0]
+	service, err := i.kubeClient.CoreV1().Services(ns).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return "<port>", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get service %s: %w", name, explainForbidden(err, "get", "services", ns))
+	}
+	if len(service.Spec.Ports) != 1 {
+		return "<port>", nil
+	}
+	return fmt.Sprintf("%d", service.Spec.Ports[0].Port), nil
+}
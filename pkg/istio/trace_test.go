@@ -0,0 +1,204 @@
+package istio
+
+import (
+	"context"
+	"testing"
+)
+
+// TestTraceRequestPath tests that a trace follows the matching route to its subset and cluster
+func TestTraceRequestPath(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{
+		"/api/v1/namespaces/bookinfo/pods": `{
+			"apiVersion": "v1",
+			"kind": "PodList",
+			"items": [
+				{
+					"metadata": {"name": "productpage-v1-6b746f74dc-9stvs", "namespace": "bookinfo", "labels": {"app": "productpage"}},
+					"spec": {"containers": [{"name": "productpage"}, {"name": "istio-proxy"}]}
+				}
+			]
+		}`,
+		"/apis/networking.istio.io/v1alpha3/namespaces/bookinfo/sidecars": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "SidecarList",
+			"items": [
+				{
+					"metadata": {"name": "default", "namespace": "bookinfo"},
+					"spec": {"egress": [{"hosts": ["./*", "istio-system/*"]}]}
+				}
+			]
+		}`,
+		"/apis/networking.istio.io/v1alpha3/virtualservices": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "VirtualServiceList",
+			"items": [
+				{
+					"metadata": {"name": "reviews", "namespace": "bookinfo"},
+					"spec": {
+						"hosts": ["reviews"],
+						"http": [
+							{"name": "beta", "match": [{"uri": {"prefix": "/beta"}}], "route": [{"destination": {"host": "reviews", "subset": "v2"}}]},
+							{"name": "default", "route": [{"destination": {"host": "reviews", "subset": "v1"}}]}
+						]
+					}
+				}
+			]
+		}`,
+		"/apis/networking.istio.io/v1alpha3/destinationrules": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "DestinationRuleList",
+			"items": [
+				{
+					"metadata": {"name": "reviews", "namespace": "bookinfo"},
+					"spec": {
+						"host": "reviews",
+						"trafficPolicy": {"tls": {"mode": "ISTIO_MUTUAL"}},
+						"subsets": [
+							{"name": "v1", "labels": {"version": "v1"}},
+							{"name": "v2", "labels": {"version": "v2"}, "trafficPolicy": {"loadBalancer": {"simple": "ROUND_ROBIN"}}}
+						]
+					}
+				}
+			]
+		}`,
+		"/api/v1/namespaces/bookinfo/services/reviews": `{
+			"apiVersion": "v1",
+			"kind": "Service",
+			"metadata": {"name": "reviews", "namespace": "bookinfo"},
+			"spec": {"ports": [{"name": "http", "port": 9080}]}
+		}`,
+	})
+	defer mockServer.Close()
+
+	istio := newTestIstio(t, mockServer.URL)
+
+	result, err := istio.TraceRequestPath(context.Background(), "bookinfo", "productpage", "reviews", "/beta/ratings")
+	if err != nil {
+		t.Fatalf("Failed to trace request path: %v", err)
+	}
+	assertContains(t, result,
+		"1. Source: pod 'productpage-v1-6b746f74dc-9stvs'",
+		"2. Sidecar egress: [OK] Sidecar 'bookinfo/default' imports the host",
+		"3. VirtualService: 'bookinfo/reviews' route 'beta' matches path '/beta/ratings'",
+		"-> reviews.bookinfo.svc.cluster.local subset 'v2'",
+		"'bookinfo/reviews' applies to 'reviews.bookinfo.svc.cluster.local' (traffic policy: tls (ISTIO_MUTUAL))",
+		"Subset 'v2' selects pods with labels map[version:v2] and overrides the traffic policy (loadBalancer)",
+		"5. Envoy cluster:\n   outbound|9080|v2|reviews.bookinfo.svc.cluster.local",
+	)
+	assertNotContains(t, result, "subset 'v1'", "[FAIL]")
+}
+
+// TestTraceRequestPathSidecarScope tests that a trace applies the oldest Sidecar selecting the source, and the
+// Sidecar of the mesh root namespace when the source namespace has none
+func TestTraceRequestPathSidecarScope(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{
+		"/api/v1/namespaces/shop/pods": `{
+			"apiVersion": "v1",
+			"kind": "PodList",
+			"items": [{"metadata": {"name": "cart-7d9f8c6b5-x2k4p", "namespace": "shop", "labels": {"app": "cart"}}, "spec": {"containers": [{"name": "cart"}, {"name": "istio-proxy"}]}}]
+		}`,
+		"/apis/networking.istio.io/v1alpha3/namespaces/shop/sidecars": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "SidecarList",
+			"items": [
+				{"metadata": {"name": "cart-new", "namespace": "shop", "creationTimestamp": "2024-06-01T00:00:00Z"}, "spec": {
+					"workloadSelector": {"labels": {"app": "cart"}}, "egress": [{"hosts": ["*/*"]}]
+				}},
+				{"metadata": {"name": "cart-old", "namespace": "shop", "creationTimestamp": "2024-01-01T00:00:00Z"}, "spec": {
+					"workloadSelector": {"labels": {"app": "cart"}}, "egress": [{"hosts": ["./*"]}]
+				}}
+			]
+		}`,
+		"/api/v1/namespaces/store/pods": `{
+			"apiVersion": "v1",
+			"kind": "PodList",
+			"items": [{"metadata": {"name": "cart-5c8d7f9b4-q7m2n", "namespace": "store", "labels": {"app": "cart"}}, "spec": {"containers": [{"name": "cart"}, {"name": "istio-proxy"}]}}]
+		}`,
+		"/apis/networking.istio.io/v1alpha3/namespaces/store/sidecars": `{"apiVersion": "networking.istio.io/v1alpha3", "kind": "SidecarList", "items": []}`,
+		"/apis/networking.istio.io/v1alpha3/namespaces/istio-system/sidecars": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "SidecarList",
+			"items": [
+				{"metadata": {"name": "default", "namespace": "istio-system", "creationTimestamp": "2024-01-01T00:00:00Z"}, "spec": {"egress": [{"hosts": ["./*"]}]}}
+			]
+		}`,
+		"/apis/networking.istio.io/v1alpha3/virtualservices":  `{"apiVersion": "networking.istio.io/v1alpha3", "kind": "VirtualServiceList", "items": []}`,
+		"/apis/networking.istio.io/v1alpha3/destinationrules": `{"apiVersion": "networking.istio.io/v1alpha3", "kind": "DestinationRuleList", "items": []}`,
+		"/api/v1/namespaces/payments/services/ledger": `{
+			"apiVersion": "v1",
+			"kind": "Service",
+			"metadata": {"name": "ledger", "namespace": "payments"},
+			"spec": {"ports": [{"name": "http", "port": 8080}]}
+		}`,
+	})
+	defer mockServer.Close()
+	istio := newTestIstio(t, mockServer.URL)
+
+	t.Run("oldest selecting Sidecar", func(t *testing.T) {
+		result, err := istio.TraceRequestPath(context.Background(), "shop", "cart", "ledger.payments.svc.cluster.local", "/")
+		if err != nil {
+			t.Fatalf("Failed to trace request path: %v", err)
+		}
+		assertContains(t, result, "2. Sidecar egress: [FAIL] Sidecar 'shop/cart-old' does not import the host")
+		assertNotContains(t, result, "cart-new")
+	})
+
+	t.Run("root namespace Sidecar", func(t *testing.T) {
+		result, err := istio.TraceRequestPath(context.Background(), "store", "cart", "ledger.payments.svc.cluster.local", "/")
+		if err != nil {
+			t.Fatalf("Failed to trace request path: %v", err)
+		}
+		assertContains(t, result, "2. Sidecar egress: [FAIL] Sidecar 'istio-system/default' does not import the host")
+	})
+}
+
+// TestTraceRequestPathHeaderRoute tests that a trace skips a route matching on a header the request does not send
+// instead of counting it as a match
+func TestTraceRequestPathHeaderRoute(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{
+		"/api/v1/namespaces/bookinfo/pods": `{
+			"apiVersion": "v1",
+			"kind": "PodList",
+			"items": [{"metadata": {"name": "productpage-v1-6b746f74dc-9stvs", "namespace": "bookinfo", "labels": {"app": "productpage"}}, "spec": {"containers": [{"name": "productpage"}, {"name": "istio-proxy"}]}}]
+		}`,
+		"/apis/networking.istio.io/v1alpha3/namespaces/bookinfo/sidecars":     `{"apiVersion": "networking.istio.io/v1alpha3", "kind": "SidecarList", "items": []}`,
+		"/apis/networking.istio.io/v1alpha3/namespaces/istio-system/sidecars": `{"apiVersion": "networking.istio.io/v1alpha3", "kind": "SidecarList", "items": []}`,
+		"/apis/networking.istio.io/v1alpha3/destinationrules":                 `{"apiVersion": "networking.istio.io/v1alpha3", "kind": "DestinationRuleList", "items": []}`,
+		"/apis/networking.istio.io/v1alpha3/virtualservices": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "VirtualServiceList",
+			"items": [
+				{
+					"metadata": {"name": "reviews", "namespace": "bookinfo"},
+					"spec": {
+						"hosts": ["reviews"],
+						"http": [
+							{"name": "jason", "match": [{"headers": {"end-user": {"exact": "jason"}}}], "route": [{"destination": {"host": "reviews", "subset": "v2"}}]},
+							{"name": "default", "route": [{"destination": {"host": "reviews", "subset": "v1"}}]}
+						]
+					}
+				}
+			]
+		}`,
+		"/api/v1/namespaces/bookinfo/services/reviews": `{
+			"apiVersion": "v1",
+			"kind": "Service",
+			"metadata": {"name": "reviews", "namespace": "bookinfo"},
+			"spec": {"ports": [{"name": "http", "port": 9080}]}
+		}`,
+	})
+	defer mockServer.Close()
+
+	istio := newTestIstio(t, mockServer.URL)
+
+	result, err := istio.TraceRequestPath(context.Background(), "bookinfo", "productpage", "reviews", "/reviews/0")
+	if err != nil {
+		t.Fatalf("Failed to trace request path: %v", err)
+	}
+	assertContains(t, result,
+		"3. VirtualService: 'bookinfo/reviews' route 'default' matches path '/reviews/0'",
+		"[SKIP] route 'jason': header 'end-user' is missing",
+		"-> reviews.bookinfo.svc.cluster.local subset 'v1'",
+	)
+	assertNotContains(t, result, "subset 'v2'")
+}
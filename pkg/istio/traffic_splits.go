@@ -0,0 +1,64 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ValidateTrafficSplits checks that the destination weights of every HTTP route of the VirtualServices in a
+// namespace that splits traffic sum to 100. A route with a single destination takes all traffic and is skipped, as
+// are routes that set no weight. Destinations left without a weight in a split receive no traffic.
+func (i *Istio) ValidateTrafficSplits(ctx context.Context, namespace string) (string, error) {
+	vsList, err := i.istioClient.NetworkingV1alpha3().VirtualServices(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list virtual services: %w", i.explainAPIError(ctx, err, "list", "virtualservices", namespace))
+	}
+
+	result := fmt.Sprintf("Traffic splits of the VirtualServices in namespace '%s':\n\n", namespace)
+	splits, misweighted := 0, 0
+	for _, vs := range vsList.Items {
+		for idx, route := range vs.Spec.GetHttp() {
+			destinations := route.GetRoute()
+			total, weighted := int32(0), 0
+			var parts, unweighted []string
+			for _, rd := range destinations {
+				name := describeDestination(rd.GetDestination())
+				total += rd.GetWeight()
+				if rd.GetWeight() > 0 {
+					weighted++
+				} else {
+					unweighted = append(unweighted, name)
+				}
+				parts = append(parts, fmt.Sprintf("%s=%d", name, rd.GetWeight()))
+			}
+			if len(destinations) < 2 || weighted == 0 {
+				continue
+			}
+			splits++
+
+			routeName := httpRouteName(route, idx)
+			if total != 100 {
+				misweighted++
+				result += fmt.Sprintf("[ERROR] VirtualService '%s' route %s: weights sum to %d, not 100 (%s)\n", vs.Name, routeName, total, strings.Join(parts, ", "))
+			} else {
+				result += fmt.Sprintf("[OK] VirtualService '%s' route %s: %s\n", vs.Name, routeName, strings.Join(parts, ", "))
+			}
+			if len(unweighted) > 0 {
+				result += fmt.Sprintf("  [WARNING] %s set no weight and receive no traffic\n", strings.Join(unweighted, ", "))
+			}
+		}
+	}
+
+	switch {
+	case splits == 0:
+		result += "No HTTP route splits traffic across weighted destinations\n"
+	case misweighted == 0:
+		result += fmt.Sprintf("\n[OK] The weights of all %d traffic splits sum to 100\n", splits)
+	default:
+		result += fmt.Sprintf("\n[RESULT] %d of %d traffic splits have weights not summing to 100\n", misweighted, splits)
+	}
+	return result, nil
+}
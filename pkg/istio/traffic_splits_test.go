@@ -0,0 +1,48 @@
+package istio
+
+import (
+	"context"
+	"testing"
+)
+
+// TestValidateTrafficSplits tests detection of split routes whose weights don't sum to 100
+func TestValidateTrafficSplits(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{
+		"/apis/networking.istio.io/v1alpha3/namespaces/bookinfo/virtualservices": `{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind": "VirtualServiceList",
+			"items": [
+				{"metadata": {"name": "reviews", "namespace": "bookinfo"}, "spec": {
+					"hosts": ["reviews"],
+					"http": [
+						{"name": "canary", "match": [{"headers": {"end-user": {"exact": "jason"}}}], "route": [
+							{"destination": {"host": "reviews", "subset": "v2"}, "weight": 50},
+							{"destination": {"host": "reviews", "subset": "v3"}, "weight": 50}
+						]},
+						{"route": [
+							{"destination": {"host": "reviews", "subset": "v1"}, "weight": 80},
+							{"destination": {"host": "reviews", "subset": "v2"}, "weight": 10}
+						]}
+					]
+				}},
+				{"metadata": {"name": "ratings", "namespace": "bookinfo"}, "spec": {
+					"hosts": ["ratings"],
+					"http": [{"route": [{"destination": {"host": "ratings", "subset": "v1"}}]}]
+				}}
+			]
+		}`,
+	})
+	defer mockServer.Close()
+	istio := newTestIstio(t, mockServer.URL)
+
+	result, err := istio.ValidateTrafficSplits(context.Background(), "bookinfo")
+	if err != nil {
+		t.Fatalf("ValidateTrafficSplits failed: %v", err)
+	}
+	assertContains(t, result,
+		"[OK] VirtualService 'reviews' route 'canary': reviews (subset v2)=50, reviews (subset v3)=50",
+		"[ERROR] VirtualService 'reviews' route #2: weights sum to 90, not 100 (reviews (subset v1)=80, reviews (subset v2)=10)",
+		"[RESULT] 1 of 2 traffic splits have weights not summing to 100",
+	)
+	assertNotContains(t, result, "ratings")
+}
@@ -0,0 +1,129 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	securityv1beta1api "istio.io/api/security/v1beta1"
+	securityv1beta1 "istio.io/client-go/pkg/apis/security/v1beta1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// restrictingAuthzPolicies returns the AuthorizationPolicies applying to a workload that restrict which requests
+// it accepts: CUSTOM and DENY policies, and ALLOW policies unless one of them allows all requests. AUDIT policies
+// and policies attached through targetRefs are not considered.
+func restrictingAuthzPolicies(policies []*securityv1beta1.AuthorizationPolicy, pod v1.Pod, rootNamespace string) []string {
+	var restricting, allow []string
+	allowAll := false
+	for _, policy := range policies {
+		if policy.Spec.GetTargetRef() != nil || len(policy.Spec.GetTargetRefs()) > 0 {
+			continue
+		}
+		if policy.Namespace != pod.Namespace && policy.Namespace != rootNamespace {
+			continue
+		}
+		if _, applies := authzPolicyScope(policy, rootNamespace, labels.Set(pod.Labels)); !applies {
+			continue
+		}
+		name := policy.Namespace + "/" + policy.Name
+		switch policy.Spec.GetAction() {
+		case securityv1beta1api.AuthorizationPolicy_CUSTOM, securityv1beta1api.AuthorizationPolicy_DENY:
+			restricting = append(restricting, name)
+		case securityv1beta1api.AuthorizationPolicy_ALLOW:
+			allow = append(allow, name)
+			allowAll = allowAll || matchesAllRequests(policy)
+		}
+	}
+	if !allowAll {
+		restricting = append(restricting, allow...)
+	}
+	return restricting
+}
+
+// FindUnauthenticatedServices flags the services of a namespace that accept unauthenticated, unauthorized
+// traffic: their workloads accept plaintext (PERMISSIVE or DISABLE mTLS, resolved from the PeerAuthentications)
+// and no AuthorizationPolicy restricts their requests, so any client, in or outside the mesh, can call them.
+// Workloads without a sidecar enforce neither and are flagged as well.
+func (i *Istio) FindUnauthenticatedServices(ctx context.Context, namespace string) (string, error) {
+	mesh, err := i.getMeshConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+	services, err := i.kubeClient.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list services: %w", explainForbidden(err, "list", "services", namespace))
+	}
+	pods, err := i.kubeClient.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list pods: %w", explainForbidden(err, "list", "pods", namespace))
+	}
+	peerAuthentications, err := i.listPeerAuthentications(ctx, "", metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list peer authentications: %w", i.explainAPIError(ctx, err, "list", "peerauthentications", ""))
+	}
+	authzPolicies, err := i.listAuthorizationPolicies(ctx, namespace, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list authorization policies: %w", explainForbidden(err, "list", "authorizationpolicies", namespace))
+	}
+	if mesh.RootNamespace != namespace {
+		rootPolicies, err := i.listAuthorizationPolicies(ctx, mesh.RootNamespace, metav1.ListOptions{})
+		if err != nil {
+			return "", fmt.Errorf("failed to list authorization policies: %w", explainForbidden(err, "list", "authorizationpolicies", mesh.RootNamespace))
+		}
+		authzPolicies = append(authzPolicies, rootPolicies...)
+	}
+
+	result := fmt.Sprintf("Services accepting unauthenticated traffic in namespace '%s':\n\n", namespace)
+	checked, exposed := 0, 0
+	for _, svc := range services.Items {
+		if len(svc.Spec.Selector) == 0 {
+			continue
+		}
+		selector := labels.SelectorFromSet(svc.Spec.Selector)
+		var backends []v1.Pod
+		var sidecar *v1.Pod
+		for _, pod := range pods.Items {
+			if pod.Status.Phase != v1.PodRunning || !selector.Matches(labels.Set(pod.Labels)) {
+				continue
+			}
+			backends = append(backends, pod)
+			if sidecar == nil && hasIstioSidecar(pod) {
+				sidecar = &pod
+			}
+		}
+		if len(backends) == 0 {
+			continue
+		}
+		checked++
+
+		if sidecar == nil {
+			exposed++
+			result += fmt.Sprintf("[WARNING] %s: its pods have no sidecar, so neither mTLS nor authorization policies are enforced\n", svc.Name)
+			continue
+		}
+		mode, source := effectiveMtlsMode(peerAuthentications, *sidecar, mesh.RootNamespace)
+		restricting := restrictingAuthzPolicies(authzPolicies, *sidecar, mesh.RootNamespace)
+		switch {
+		case mode == securityv1beta1api.PeerAuthentication_MutualTLS_STRICT.String():
+			result += fmt.Sprintf("[OK] %s: mTLS STRICT (%s)\n", svc.Name, source)
+		case len(restricting) > 0:
+			result += fmt.Sprintf("[OK] %s: mTLS %s (%s), restricted by %s\n", svc.Name, mode, source, strings.Join(restricting, ", "))
+		default:
+			exposed++
+			result += fmt.Sprintf("[WARNING] %s: mTLS %s (%s) and no authorization policy restricts it; it accepts plaintext requests from any client\n", svc.Name, mode, source)
+		}
+	}
+
+	switch {
+	case checked == 0:
+		result += "No service with running pods found\n"
+	case exposed == 0:
+		result += fmt.Sprintf("\n[OK] All %d services require mTLS or are restricted by authorization policies\n", checked)
+	default:
+		result += fmt.Sprintf("\n[RESULT] %d of %d services accept unauthenticated, unauthorized traffic; set mTLS to STRICT or add an AuthorizationPolicy\n", exposed, checked)
+	}
+	return result, nil
+}
@@ -0,0 +1,61 @@
+package istio
+
+import (
+	"context"
+	"testing"
+)
+
+// TestFindUnauthenticatedServices tests detection of services accepting plaintext traffic that no AuthorizationPolicy restricts
+func TestFindUnauthenticatedServices(t *testing.T) {
+	mockServer := newMockAPIServer(map[string]string{
+		"/api/v1/namespaces/bookinfo/services": `{
+			"apiVersion": "v1",
+			"kind": "ServiceList",
+			"items": [
+				{"metadata": {"name": "reviews", "namespace": "bookinfo"}, "spec": {"selector": {"app": "reviews"}}},
+				{"metadata": {"name": "ratings", "namespace": "bookinfo"}, "spec": {"selector": {"app": "ratings"}}},
+				{"metadata": {"name": "details", "namespace": "bookinfo"}, "spec": {"selector": {"app": "details"}}}
+			]
+		}`,
+		"/api/v1/namespaces/bookinfo/pods": `{
+			"apiVersion": "v1",
+			"kind": "PodList",
+			"items": [
+				{"metadata": {"name": "reviews-v1-abc", "namespace": "bookinfo", "labels": {"app": "reviews"}}, "spec": {"containers": [{"name": "reviews"}, {"name": "istio-proxy"}]}, "status": {"phase": "Running"}},
+				{"metadata": {"name": "ratings-v1-def", "namespace": "bookinfo", "labels": {"app": "ratings"}}, "spec": {"containers": [{"name": "ratings"}, {"name": "istio-proxy"}]}, "status": {"phase": "Running"}},
+				{"metadata": {"name": "details-v1-ghi", "namespace": "bookinfo", "labels": {"app": "details"}}, "spec": {"containers": [{"name": "details"}, {"name": "istio-proxy"}]}, "status": {"phase": "Running"}}
+			]
+		}`,
+		"/apis/security.istio.io/v1beta1/peerauthentications": `{
+			"apiVersion": "security.istio.io/v1beta1",
+			"kind": "PeerAuthenticationList",
+			"items": [
+				{"metadata": {"name": "ratings", "namespace": "bookinfo"}, "spec": {"selector": {"matchLabels": {"app": "ratings"}}, "mtls": {"mode": "STRICT"}}}
+			]
+		}`,
+		"/apis/security.istio.io/v1beta1/namespaces/bookinfo/authorizationpolicies": `{
+			"apiVersion": "security.istio.io/v1beta1",
+			"kind": "AuthorizationPolicyList",
+			"items": [
+				{"metadata": {"name": "details-viewer", "namespace": "bookinfo"}, "spec": {
+					"selector": {"matchLabels": {"app": "details"}},
+					"rules": [{"from": [{"source": {"principals": ["cluster.local/ns/bookinfo/sa/productpage"]}}]}]
+				}}
+			]
+		}`,
+		"/apis/security.istio.io/v1beta1/namespaces/istio-system/authorizationpolicies": `{"apiVersion": "security.istio.io/v1beta1", "kind": "AuthorizationPolicyList", "items": []}`,
+	})
+	defer mockServer.Close()
+	istio := newTestIstio(t, mockServer.URL)
+
+	result, err := istio.FindUnauthenticatedServices(context.Background(), "bookinfo")
+	if err != nil {
+		t.Fatalf("FindUnauthenticatedServices failed: %v", err)
+	}
+	assertContains(t, result,
+		"[WARNING] reviews: mTLS PERMISSIVE (mesh default) and no authorization policy restricts it",
+		"[OK] ratings: mTLS STRICT (bookinfo/ratings)",
+		"[OK] details: mTLS PERMISSIVE (mesh default), restricted by bookinfo/details-viewer",
+		"[RESULT] 1 of 3 services accept unauthenticated, unauthorized traffic",
+	)
+}
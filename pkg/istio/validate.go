@@ -0,0 +1,265 @@
+package istio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	networkingv1alpha3 "istio.io/api/networking/v1alpha3"
+	securityv1beta1 "istio.io/api/security/v1beta1"
+	telemetryv1alpha1 "istio.io/api/telemetry/v1alpha1"
+	"sigs.k8s.io/yaml"
+)
+
+// manifestSchema decodes and checks the spec of a resource kind
+type manifestSchema struct {
+	newSpec func() proto.Message
+	// validate returns field-level errors for required fields missing from a decoded spec
+	validate func(spec proto.Message) []string
+}
+
+// manifestSchemas lists the schemas of the supported resource kinds, keyed by kind
+var manifestSchemas = map[string]manifestSchema{
+	"VirtualService": {
+		newSpec: func() proto.Message { return &networkingv1alpha3.VirtualService{} },
+		validate: func(spec proto.Message) []string {
+			return validateVirtualService(spec.(*networkingv1alpha3.VirtualService))
+		},
+	},
+	"DestinationRule": {
+		newSpec: func() proto.Message { return &networkingv1alpha3.DestinationRule{} },
+		validate: func(spec proto.Message) []string {
+			return validateDestinationRule(spec.(*networkingv1alpha3.DestinationRule))
+		},
+	},
+	"Gateway": {
+		newSpec:  func() proto.Message { return &networkingv1alpha3.Gateway{} },
+		validate: func(spec proto.Message) []string { return validateGateway(spec.(*networkingv1alpha3.Gateway)) },
+	},
+	"ServiceEntry": {
+		newSpec: func() proto.Message { return &networkingv1alpha3.ServiceEntry{} },
+		validate: func(spec proto.Message) []string {
+			return validateServiceEntry(spec.(*networkingv1alpha3.ServiceEntry))
+		},
+	},
+	"EnvoyFilter": {
+		newSpec: func() proto.Message { return &networkingv1alpha3.EnvoyFilter{} },
+	},
+	"AuthorizationPolicy": {
+		newSpec: func() proto.Message { return &securityv1beta1.AuthorizationPolicy{} },
+	},
+	"PeerAuthentication": {
+		newSpec: func() proto.Message { return &securityv1beta1.PeerAuthentication{} },
+	},
+	"Telemetry": {
+		newSpec: func() proto.Message { return &telemetryv1alpha1.Telemetry{} },
+	},
+}
+
+// manifestHeader holds the fields of a manifest document needed to pick its schema
+type manifestHeader struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Metadata   struct {
+		Name string `json:"name"`
+	} `json:"metadata"`
+	Spec json.RawMessage `json:"spec"`
+}
+
+// ValidateManifest validates the Istio resources of a YAML manifest, which may hold several documents, against
+// the Istio API schema without contacting the cluster: unknown fields, invalid enum values and missing required
+// fields are reported per resource
+func (i *Istio) ValidateManifest(ctx context.Context, manifest string) (string, error) {
+	documents := splitYamlDocuments(manifest)
+	if len(documents) == 0 {
+		return "", fmt.Errorf("manifest is empty")
+	}
+
+	result := fmt.Sprintf("Validation of %d resources:\n\n", len(documents))
+	invalid := 0
+	for idx, document := range documents {
+		resource, errs := validateManifestDocument(document)
+		if resource == "" {
+			resource = fmt.Sprintf("Document %d", idx+1)
+		}
+		if len(errs) == 0 {
+			result += fmt.Sprintf("[OK] %s\n", resource)
+			continue
+		}
+		invalid++
+		result += fmt.Sprintf("[ERROR] %s\n", resource)
+		for _, err := range errs {
+			result += fmt.Sprintf("  - %s\n", err)
+		}
+	}
+
+	if invalid == 0 {
+		result += "\n[RESULT] All resources are valid\n"
+	} else {
+		result += fmt.Sprintf("\n[RESULT] %d of %d resources are invalid\n", invalid, len(documents))
+	}
+	return result, nil
+}
+
+// splitYamlDocuments splits a multi-document YAML manifest, dropping empty documents
+func splitYamlDocuments(manifest string) []string {
+	var documents []string
+	for _, document := range strings.Split("\n"+manifest, "\n---") {
+		if strings.TrimSpace(document) != "" {
+			documents = append(documents, document)
+		}
+	}
+	return documents
+}
+
+// validateManifestDocument validates a single YAML document, returning a description of the resource and its errors
+func validateManifestDocument(document string) (string, []string) {
+	data, err := yaml.YAMLToJSON([]byte(document))
+	if err != nil {
+		return "", []string{fmt.Sprintf("invalid YAML: %v", err)}
+	}
+	var header manifestHeader
+	if err := json.Unmarshal(data, &header); err != nil {
+		return "", []string{fmt.Sprintf("invalid resource: %v", err)}
+	}
+	resource := fmt.Sprintf("%s '%s'", header.Kind, header.Metadata.Name)
+
+	rk, err := lookupResourceKind(header.Kind)
+	if err != nil {
+		return resource, []string{err.Error()}
+	}
+	if group, _, _ := strings.Cut(header.APIVersion, "/"); group != rk.gvk.Group {
+		return resource, []string{fmt.Sprintf("apiVersion: '%s' is not an API version of %s, expected %s/<version>", header.APIVersion, rk.gvk.Kind, rk.gvk.Group)}
+	}
+
+	var errs []string
+	if header.Metadata.Name == "" {
+		errs = append(errs, "metadata.name: name is required")
+	}
+	if len(header.Spec) == 0 || string(header.Spec) == "null" {
+		return resource, append(errs, "spec: spec is required")
+	}
+
+	schema := manifestSchemas[rk.gvk.Kind]
+	spec := schema.newSpec()
+	specData, err := yaml.Marshal(header.Spec)
+	if err == nil {
+		specData, err = yaml.YAMLToJSON(specData)
+	}
+	if err == nil {
+		err = protojson.Unmarshal(specData, spec)
+	}
+	if err != nil {
+		// protojson errors start with "proto:" followed by a space or a non-breaking space
+		message := strings.TrimPrefix(err.Error(), "proto:")
+		return resource, append(errs, "spec: "+strings.TrimSpace(strings.TrimPrefix(message, " ")))
+	}
+	if schema.validate != nil {
+		errs = append(errs, schema.validate(spec)...)
+	}
+	return resource, errs
+}
+
+// validateVirtualService checks the required fields of a VirtualService. Like Istio, it treats a VirtualService
+// without hosts as a delegate, which only other VirtualServices route to: it may only hold HTTP routes and must not
+// be bound to gateways.
+func validateVirtualService(vs *networkingv1alpha3.VirtualService) []string {
+	var errs []string
+	if len(vs.GetHosts()) == 0 {
+		if len(vs.GetGateways()) > 0 {
+			errs = append(errs, "spec.gateways: a VirtualService without hosts is a delegate, which must not set gateways; set hosts, or remove gateways from the delegate")
+		}
+		if len(vs.GetTls()) > 0 || len(vs.GetTcp()) > 0 {
+			errs = append(errs, "spec: a VirtualService without hosts is a delegate, which only supports http routes; set hosts to route tls or tcp traffic")
+		}
+	}
+	if len(vs.GetHttp()) == 0 && len(vs.GetTls()) == 0 && len(vs.GetTcp()) == 0 {
+		errs = append(errs, "spec: at least one http, tls or tcp route is required")
+	}
+	for idx, route := range vs.GetHttp() {
+		if len(route.GetRoute()) == 0 && route.GetRedirect() == nil && route.GetDirectResponse() == nil && route.GetDelegate() == nil {
+			errs = append(errs, fmt.Sprintf("spec.http[%d]: one of route, redirect, directResponse or delegate is required", idx))
+		}
+		for rdIdx, rd := range route.GetRoute() {
+			if rd.GetDestination().GetHost() == "" {
+				errs = append(errs, fmt.Sprintf("spec.http[%d].route[%d].destination.host: host is required", idx, rdIdx))
+			}
+		}
+	}
+	for idx, route := range vs.GetTcp() {
+		for rdIdx, rd := range route.GetRoute() {
+			if rd.GetDestination().GetHost() == "" {
+				errs = append(errs, fmt.Sprintf("spec.tcp[%d].route[%d].destination.host: host is required", idx, rdIdx))
+			}
+		}
+	}
+	for idx, route := range vs.GetTls() {
+		if len(route.GetMatch()) == 0 {
+			errs = append(errs, fmt.Sprintf("spec.tls[%d].match: at least one match is required", idx))
+		}
+		for rdIdx, rd := range route.GetRoute() {
+			if rd.GetDestination().GetHost() == "" {
+				errs = append(errs, fmt.Sprintf("spec.tls[%d].route[%d].destination.host: host is required", idx, rdIdx))
+			}
+		}
+	}
+	return errs
+}
+
+// validateDestinationRule checks the required fields of a DestinationRule
+func validateDestinationRule(dr *networkingv1alpha3.DestinationRule) []string {
+	var errs []string
+	if dr.GetHost() == "" {
+		errs = append(errs, "spec.host: host is required")
+	}
+	for idx, subset := range dr.GetSubsets() {
+		if subset.GetName() == "" {
+			errs = append(errs, fmt.Sprintf("spec.subsets[%d].name: name is required", idx))
+		}
+	}
+	return errs
+}
+
+// validateGateway checks the required fields of a Gateway
+func validateGateway(gw *networkingv1alpha3.Gateway) []string {
+	var errs []string
+	if len(gw.GetServers()) == 0 {
+		errs = append(errs, "spec.servers: at least one server is required")
+	}
+	for idx, server := range gw.GetServers() {
+		if server.GetPort() == nil {
+			errs = append(errs, fmt.Sprintf("spec.servers[%d].port: port is required", idx))
+		} else {
+			if server.GetPort().GetNumber() == 0 {
+				errs = append(errs, fmt.Sprintf("spec.servers[%d].port.number: number is required", idx))
+			}
+			if server.GetPort().GetProtocol() == "" {
+				errs = append(errs, fmt.Sprintf("spec.servers[%d].port.protocol: protocol is required", idx))
+			}
+		}
+		if len(server.GetHosts()) == 0 {
+			errs = append(errs, fmt.Sprintf("spec.servers[%d].hosts: at least one host is required", idx))
+		}
+	}
+	return errs
+}
+
+// validateServiceEntry checks the required fields of a ServiceEntry
+func validateServiceEntry(se *networkingv1alpha3.ServiceEntry) []string {
+	var errs []string
+	if len(se.GetHosts()) == 0 {
+		errs = append(errs, "spec.hosts: at least one host is required")
+	}
+	for idx, port := range se.GetPorts() {
+		if port.GetNumber() == 0 {
+			errs = append(errs, fmt.Sprintf("spec.ports[%d].number: number is required", idx))
+		}
+		if port.GetName() == "" {
+			errs = append(errs, fmt.Sprintf("spec.ports[%d].name: name is required", idx))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,95 @@
+package istio
+
+import (
+	"context"
+	"testing"
+)
+
+// TestValidateManifest tests schema validation of Istio manifests
+func TestValidateManifest(t *testing.T) {
+	istio := &Istio{}
+
+	t.Run("delegate without hosts", func(t *testing.T) {
+		result, err := istio.ValidateManifest(context.Background(), `
+apiVersion: networking.istio.io/v1beta1
+kind: VirtualService
+metadata:
+  name: reviews
+spec:
+  http:
+  - route:
+    - destination:
+        host: reviews
+---
+apiVersion: networking.istio.io/v1beta1
+kind: VirtualService
+metadata:
+  name: ratings
+spec:
+  gateways:
+  - istio-system/public
+  tcp:
+  - route:
+    - destination:
+        host: ratings
+`)
+		if err != nil {
+			t.Fatalf("ValidateManifest failed: %v", err)
+		}
+		assertContains(t, result,
+			"[OK] VirtualService 'reviews'",
+			"[ERROR] VirtualService 'ratings'",
+			"spec.gateways: a VirtualService without hosts is a delegate, which must not set gateways",
+			"spec: a VirtualService without hosts is a delegate, which only supports http routes",
+			"[RESULT] 1 of 2 resources are invalid",
+		)
+	})
+
+	t.Run("invalid enum and unknown field", func(t *testing.T) {
+		result, err := istio.ValidateManifest(context.Background(), `
+apiVersion: security.istio.io/v1
+kind: PeerAuthentication
+metadata:
+  name: default
+spec:
+  mtls:
+    mode: STRICTT
+---
+apiVersion: networking.istio.io/v1
+kind: DestinationRule
+metadata:
+  name: reviews
+spec:
+  hostname: reviews
+`)
+		if err != nil {
+			t.Fatalf("ValidateManifest failed: %v", err)
+		}
+		assertContains(t, result,
+			"[ERROR] PeerAuthentication 'default'",
+			"STRICTT",
+			"[ERROR] DestinationRule 'reviews'",
+			"hostname",
+			"[RESULT] 2 of 2 resources are invalid",
+		)
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		result, err := istio.ValidateManifest(context.Background(), `
+apiVersion: networking.istio.io/v1
+kind: DestinationRule
+metadata:
+  name: reviews
+spec:
+  host: reviews
+  subsets:
+  - name: v1
+    labels:
+      version: v1
+`)
+		if err != nil {
+			t.Fatalf("ValidateManifest failed: %v", err)
+		}
+		assertContains(t, result, "[OK] DestinationRule 'reviews'", "[RESULT] All resources are valid")
+	})
+}
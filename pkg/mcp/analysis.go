@@ -0,0 +1,818 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// initAnalysisTools initializes tools that cross-check Istio and Kubernetes resources for misconfigurations
+func (s *Server) initAnalysisTools() []server.ServerTool {
+	return []server.ServerTool{
+		{
+			Tool: mcp.NewTool("get-istio-analyze",
+				mcp.WithDescription("Run istioctl analyze to detect Istio configuration problems such as references to missing hosts, gateways or secrets, conflicting resources, and namespaces without sidecar injection. Results are cached for a short time so repeated calls during iterative debugging are fast; set refresh after changing configuration."),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace to analyze (defaults to 'default')"),
+				),
+				mcp.WithBoolean("all-namespaces",
+					mcp.Description("Analyze all namespaces instead of a single one (defaults to false)"),
+				),
+				mcp.WithString("severity",
+					mcp.Description("Minimum severity of the reported messages (defaults to 'info', i.e. all messages)"),
+					mcp.Enum("info", "warning", "error"),
+				),
+				mcp.WithBoolean("refresh",
+					mcp.Description("Run the analysis again instead of returning a cached result (defaults to false)"),
+				),
+				mcp.WithTitleAnnotation("Istio: Analyze"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.getIstioAnalyze,
+		},
+		{
+			Tool: mcp.NewTool("analyze-service-ports",
+				mcp.WithDescription("Analyze Kubernetes Service port declarations for issues that break Istio protocol detection: ports declared twice for the same protocol, ports without a protocol hint (name prefix like 'http-' or appProtocol), and appProtocol values that disagree with the port name. Use this to catch silent L7 failures where traffic is treated as plain TCP."),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace to analyze (defaults to 'default')"),
+				),
+				mcp.WithTitleAnnotation("Istio: Service Port Analysis"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.analyzeServicePorts,
+		},
+		{
+			Tool: mcp.NewTool("audit-tls-origination",
+				mcp.WithDescription("Audit Istio Destination Rules that originate TLS to upstream services (tls.mode SIMPLE or MUTUAL). Reports the mode, SNI, and certificate settings of each rule, and warns when MUTUAL origination lacks client certificates or when server certificates are not verified. Use this to debug certificate errors when calling external services through the mesh."),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace to audit (defaults to 'default')"),
+				),
+				mcp.WithTitleAnnotation("Istio: TLS Origination Audit"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.auditTlsOrigination,
+		},
+		{
+			Tool: mcp.NewTool("compare-namespaces",
+				mcp.WithDescription("Compare the Istio configuration of two namespaces and report drift: resources present in only one namespace, and same-named resources whose specs differ. References to the namespace itself (e.g. hosts like 'reviews.staging.svc.cluster.local') are normalized before comparing. Use this to check that staging and production are configured alike."),
+				mcp.WithString("namespace-a",
+					mcp.Description("First namespace to compare (e.g. 'staging')"),
+					mcp.Required(),
+				),
+				mcp.WithString("namespace-b",
+					mcp.Description("Second namespace to compare (e.g. 'production')"),
+					mcp.Required(),
+				),
+				mcp.WithTitleAnnotation("Istio: Compare Namespaces"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.compareNamespaces,
+		},
+		{
+			Tool: mcp.NewTool("analyze-missing-subsets",
+				mcp.WithDescription("Find Virtual Service routes that send traffic to a subset no Destination Rule defines. Requests matching such routes fail with HTTP 503 (no healthy upstream), which is one of the most common Istio routing bugs. Reports the subsets that are defined for the host, if any."),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace of the Virtual Services to analyze (defaults to 'default')"),
+				),
+				mcp.WithTitleAnnotation("Istio: Missing Subset Analysis"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.analyzeMissingSubsets,
+		},
+		{
+			Tool: mcp.NewTool("check-mesh-expansion-readiness",
+				mcp.WithDescription("Check that a WorkloadGroup is ready for onboarding virtual machines into the mesh: the WorkloadGroup exists, the ServiceAccount of its template exists, its network is set, and a Service Entry selects its workloads or WorkloadEntries have been auto-registered. Run this before installing the Istio agent on a VM."),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace of the WorkloadGroup (defaults to 'default')"),
+				),
+				mcp.WithString("workload-group",
+					mcp.Description("Name of the WorkloadGroup"),
+					mcp.Required(),
+				),
+				mcp.WithTitleAnnotation("Istio: Mesh Expansion Readiness"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.checkMeshExpansionReadiness,
+		},
+		{
+			Tool: mcp.NewTool("analyze-duplicate-service-entries",
+				mcp.WithDescription("Find hosts declared by more than one Service Entry. Istio picks one of the entries nondeterministically, so duplicates with different resolution or ports cause intermittent failures when calling external services. Reports the conflicting resolution and port definitions of each duplicated host."),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace of the Service Entries to analyze (defaults to 'default')"),
+				),
+				mcp.WithTitleAnnotation("Istio: Duplicate Service Entry Analysis"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.analyzeDuplicateServiceEntries,
+		},
+		{
+			Tool: mcp.NewTool("trace-request-path",
+				mcp.WithDescription("Trace how a request from a workload to a host and path is routed by the mesh configuration, narrating each decision: whether the Sidecar egress scope of the workload imports the host, which Virtual Service route matches the path, which Destination Rule subset and traffic policy apply, and the resulting Envoy cluster. Use this as the first step when a request is routed unexpectedly or fails with 404 or 503."),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace of the source workload (defaults to 'default')"),
+				),
+				mcp.WithString("from-workload",
+					mcp.Description("Source workload: a pod name, the value of its 'app' label, or a deployment name"),
+					mcp.Required(),
+				),
+				mcp.WithString("host",
+					mcp.Description("Destination host, short or fully qualified (e.g. 'reviews' or 'reviews.bookinfo.svc.cluster.local')"),
+					mcp.Required(),
+				),
+				mcp.WithString("path",
+					mcp.Description("Request path (defaults to '/')"),
+				),
+				mcp.WithTitleAnnotation("Istio: Trace Request Path"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.traceRequestPath,
+		},
+		{
+			Tool: mcp.NewTool("find-services-without-routing",
+				mcp.WithDescription("Find the services of a namespace backed by mesh workloads (sidecar or ambient) that no Virtual Service routes and no Destination Rule configures, in any namespace. These services rely on Istio's default routing and connection settings, which may be intentional or a gap, e.g. missing timeouts, retries or circuit breaking."),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace of the services (defaults to 'default')"),
+				),
+				mcp.WithTitleAnnotation("Istio: Services Without Routing"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.findServicesWithoutRouting,
+		},
+		{
+			Tool: mcp.NewTool("validate-manifest",
+				mcp.WithDescription("Validate a YAML manifest of Istio resources against the Istio API schema before applying it, without contacting the cluster. Reports unknown fields (typos), invalid enum values (e.g. a wrong mTLS mode) and missing required fields such as Virtual Service hosts, per resource. Supports multi-document manifests."),
+				mcp.WithString("manifest",
+					mcp.Description("YAML manifest holding one or more Istio resources, separated by '---'"),
+					mcp.Required(),
+				),
+				mcp.WithTitleAnnotation("Istio: Validate Manifest"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.validateManifest,
+		},
+		{
+			Tool: mcp.NewTool("check-gateway-host-match",
+				mcp.WithDescription("Check whether a Gateway permits a Virtual Service host, evaluating the namespace scoping ('./*', 'foo/*') and host patterns of each Gateway server. A Virtual Service bound to a gateway whose servers don't permit its host and namespace is silently ignored, so requests return 404. Use this when a gateway route returns 404 even though the Virtual Service lists the gateway."),
+				mcp.WithString("gateway",
+					mcp.Description("Gateway as referenced by the Virtual Service: 'name' for a Gateway in the Virtual Service namespace, or 'namespace/name' (e.g. 'istio-system/ingressgateway')"),
+					mcp.Required(),
+				),
+				mcp.WithString("host",
+					mcp.Description("Host of the Virtual Service (e.g. 'bookinfo.example.com')"),
+					mcp.Required(),
+				),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace of the Virtual Service (defaults to 'default')"),
+				),
+				mcp.WithTitleAnnotation("Istio: Gateway Host Match"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.checkGatewayHostMatch,
+		},
+		{
+			Tool: mcp.NewTool("analyze-retry-timeout",
+				mcp.WithDescription("Analyze the retries and timeouts of Virtual Service HTTP routes for combinations that don't work as intended: a perTryTimeout not shorter than the route timeout (slow attempts are never retried), retries without perTryTimeout, and retry budgets (attempts × perTryTimeout) exceeding the route timeout. Use this to catch reliability misconfigurations before they show up as unexpected 504s."),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace of the Virtual Services (defaults to 'default')"),
+				),
+				mcp.WithTitleAnnotation("Istio: Retry and Timeout Analysis"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.analyzeRetryTimeout,
+		},
+		{
+			Tool: mcp.NewTool("get-fault-injection-summary",
+				mcp.WithDescription("List the Virtual Service HTTP routes that inject faults (delays or aborts), with the error or delay injected and the share of requests affected. Fault injection left enabled after chaos testing causes mysterious errors and latency; use this to answer whether it is still on."),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace of the Virtual Services (defaults to 'default')"),
+				),
+				mcp.WithTitleAnnotation("Istio: Fault Injection Summary"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.getFaultInjectionSummary,
+		},
+		{
+			Tool: mcp.NewTool("get-mirror-config",
+				mcp.WithDescription("List the Virtual Service HTTP routes that mirror (shadow) traffic, with the destinations of the route and each mirror destination with the share of requests it receives. Mirroring is easy to forget and can overload the mirror target; use this to find unintended shadow traffic."),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace of the Virtual Services (defaults to 'default')"),
+				),
+				mcp.WithTitleAnnotation("Istio: Traffic Mirroring"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.getMirrorConfig,
+		},
+		{
+			Tool: mcp.NewTool("get-cors-config",
+				mcp.WithDescription("List the Virtual Service HTTP routes that set a CORS policy, with the allowed origins, methods and headers, exposed headers, max age and whether credentials are allowed, and flag policies browsers reject. Use this to debug browser requests to services behind the mesh failing with CORS errors."),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace of the Virtual Services (defaults to 'default')"),
+				),
+				mcp.WithTitleAnnotation("Istio: CORS Configuration"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.getCorsConfig,
+		},
+		{
+			Tool: mcp.NewTool("validate-gateway-credentials",
+				mcp.WithDescription("Check that the credentialName of every TLS server of the Gateways in a namespace resolves to a Secret with a certificate and key in the namespace of the gateway workload, where istiod looks it up for SDS. Use this when HTTPS on an ingress gateway fails the TLS handshake."),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace of the Gateways (defaults to 'default')"),
+				),
+				mcp.WithTitleAnnotation("Istio: Validate Gateway Credentials"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.validateGatewayCredentials,
+		},
+		{
+			Tool: mcp.NewTool("get-recently-modified",
+				mcp.WithDescription("List the Istio resources of every supported kind in a namespace that were created or updated within a time window, most recent first, with the field manager that made the change. Use this during incident response to correlate configuration changes with the start of an incident."),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace of the resources (defaults to 'default')"),
+				),
+				mcp.WithString("since",
+					mcp.Description("How far back to look, as a duration (e.g. '30m', '1h', '24h'; defaults to '1h')"),
+				),
+				mcp.WithTitleAnnotation("Istio: Recently Modified Resources"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.getRecentlyModified,
+		},
+		{
+			Tool: mcp.NewTool("validate-port-level-mtls",
+				mcp.WithDescription("Cross-reference the portLevelMtls entries of the Peer Authentications in a namespace with the container ports the selected workloads declare, and flag entries for ports no workload exposes or policies without a selector, which Istio silently ignores. Port-level settings key on container ports, so using a service port that forwards to a different target port is a common mistake."),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace of the Peer Authentications (defaults to 'default')"),
+				),
+				mcp.WithTitleAnnotation("Istio: Validate Port-Level mTLS"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.validatePortLevelMtls,
+		},
+		{
+			Tool: mcp.NewTool("match-route",
+				mcp.WithDescription("Statically simulate Istio's router: evaluate the HTTP match conditions (uri, method, authority, headers, query parameters) of the VirtualService for a host in order and report which route the first match selects and where it sends the request, explaining why earlier routes were skipped. Answers 'where would this request go?' without sending live traffic."),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace the request is sent from; short hosts resolve in it (defaults to 'default')"),
+				),
+				mcp.WithString("host",
+					mcp.Description("Host of the request, e.g. 'reviews', 'reviews.bookinfo' or 'reviews.bookinfo.svc.cluster.local'"),
+					mcp.Required(),
+				),
+				mcp.WithString("path",
+					mcp.Description("Request path, optionally with a query string (defaults to '/')"),
+				),
+				mcp.WithString("method",
+					mcp.Description("HTTP method (defaults to 'GET')"),
+				),
+				mcp.WithObject("headers",
+					mcp.Description("Request headers as an object of name to value, e.g. {\"end-user\": \"jason\"}"),
+				),
+				mcp.WithTitleAnnotation("Istio: Match Route"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.matchRoute,
+		},
+		{
+			Tool: mcp.NewTool("get-effective-timeout",
+				mcp.WithDescription("Resolve the VirtualService route a request to a host and path matches and report the timeout and retries the sidecar applies to it: the route's own settings, or the defaults (no timeout, since Istio disables Envoy's 15s route timeout, and the mesh-wide defaultHttpRetryPolicy or Istio's built-in retry policy)."),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace the request is sent from; short hosts resolve in it (defaults to 'default')"),
+				),
+				mcp.WithString("host",
+					mcp.Description("Host of the request, e.g. 'reviews', 'reviews.bookinfo' or 'reviews.bookinfo.svc.cluster.local'"),
+					mcp.Required(),
+				),
+				mcp.WithString("path",
+					mcp.Description("Request path (defaults to '/')"),
+				),
+				mcp.WithTitleAnnotation("Istio: Get Effective Timeout"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.getEffectiveTimeout,
+		},
+		{
+			Tool: mcp.NewTool("analyze-gateway-host-mismatches",
+				mcp.WithDescription("Check every binding of the Virtual Services in a namespace to a Gateway and report hosts that no server of the Gateway permits, by host pattern or namespace scoping, and references to Gateways that do not exist. The gateway ignores the routes for such hosts and returns 404, a very common ingress mistake."),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace of the Virtual Services (defaults to 'default')"),
+				),
+				mcp.WithTitleAnnotation("Istio: Gateway Host Mismatches"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.analyzeGatewayHostMismatches,
+		},
+		{
+			Tool: mcp.NewTool("check-egress-gateway-routing",
+				mcp.WithDescription("Validate the chain that routes requests to an external host through an egress gateway: the Service Entry declaring the host, the Virtual Service bound to the mesh and the egress Gateway, the Gateway server admitting the host, the mesh route to the egress gateway service and the gateway route on to the host, and the Destination Rule (and subset) for the egress gateway service. Reports each missing piece."),
+				mcp.WithString("host",
+					mcp.Description("External host, e.g. 'edition.cnn.com'"),
+					mcp.Required(),
+				),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace of the calling workloads (defaults to 'default')"),
+				),
+				mcp.WithTitleAnnotation("Istio: Check Egress Gateway Routing"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.checkEgressGatewayRouting,
+		},
+		{
+			Tool: mcp.NewTool("validate-telemetry-providers",
+				mcp.WithDescription("Check the tracing, metrics and access logging providers referenced by the Telemetry resources of a namespace against the extensionProviders defined in the mesh config (plus the built-in envoy, prometheus and stackdriver providers). A reference to an undefined provider is silently ignored, so the telemetry it configures is never produced."),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace of the Telemetry resources (defaults to 'default'); use the mesh root namespace for mesh-wide Telemetry"),
+				),
+				mcp.WithTitleAnnotation("Istio: Validate Telemetry Providers"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.validateTelemetryProviders,
+		},
+		{
+			Tool: mcp.NewTool("analyze-cross-namespace-routing",
+				mcp.WithDescription("Check that the VirtualServices of a namespace routing to hosts governed by DestinationRules of other namespaces can actually see those rules through their exportTo (or the mesh-wide defaultDestinationRuleExportTo). A route to a subset of a DestinationRule not exported to the namespace fails with 503, and a hidden rule's traffic policy silently doesn't apply."),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace of the VirtualServices (defaults to 'default')"),
+				),
+				mcp.WithTitleAnnotation("Istio: Analyze Cross-Namespace Routing"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.analyzeCrossNamespaceRouting,
+		},
+		{
+			Tool: mcp.NewTool("validate-traffic-splits",
+				mcp.WithDescription("Check that the destination weights of every HTTP route of the VirtualServices in a namespace that splits traffic (canary, A/B or blue-green) sum to 100, showing the weights of each split. A misweighted split sends a different share of the traffic to each version than intended, and destinations left without a weight receive no traffic."),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace of the VirtualServices (defaults to 'default')"),
+				),
+				mcp.WithTitleAnnotation("Istio: Validate Traffic Splits"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.validateTrafficSplits,
+		},
+		{
+			Tool: mcp.NewTool("verify-canary-weight",
+				mcp.WithDescription("Verify a canary rollout matches intent: check that the VirtualService for a host sends the intended percentage of requests (e.g. 10 for v2=10%) to a subset on every HTTP route to the host, and that the DestinationRule applying to the host defines the subset. Reports each discrepancy; use it after a progressive delivery step."),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace the requests are sent from; short hosts resolve in it (defaults to 'default')"),
+				),
+				mcp.WithString("host",
+					mcp.Description("Host of the canaried service, e.g. 'reviews' or 'reviews.bookinfo.svc.cluster.local'"),
+					mcp.Required(),
+				),
+				mcp.WithString("subset",
+					mcp.Description("Subset of the canary, e.g. 'v2'"),
+					mcp.Required(),
+				),
+				mcp.WithNumber("weight",
+					mcp.Description("Intended percentage of requests for the subset, from 0 to 100"),
+					mcp.Required(),
+				),
+				mcp.WithTitleAnnotation("Istio: Verify Canary Weight"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.verifyCanaryWeight,
+		},
+		{
+			Tool: mcp.NewTool("validate-delegates",
+				mcp.WithDescription("Resolve the delegate of every HTTP route of the VirtualServices in a namespace to an existing VirtualService and flag missing targets, delegate cycles, nested delegation (Istio supports a single level), delegates that set hosts and delegates not exported to the delegating namespace. The routes of a broken delegate are silently dropped."),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace of the delegating (root) VirtualServices (defaults to 'default')"),
+				),
+				mcp.WithTitleAnnotation("Istio: Validate Delegates"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.validateDelegates,
+		},
+		{
+			Tool: mcp.NewTool("find-deprecated-usage",
+				mcp.WithDescription("Scan the VirtualServices, DestinationRules, Gateways and Sidecars of a namespace for fields and values the Istio API marks as deprecated (e.g. mirrorPercent, corsPolicy.allowOrigin, LEAST_CONN, outlierDetection.consecutiveErrors) and report each one with its recommended replacement. Use before an Istio upgrade."),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace to scan (defaults to 'default')"),
+				),
+				mcp.WithTitleAnnotation("Istio: Find Deprecated Usage"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.findDeprecatedUsage,
+		},
+		{
+			Tool: mcp.NewTool("find-unauthenticated-services",
+				mcp.WithDescription("Flag the services of a namespace that accept unauthenticated, unauthorized traffic: their workloads accept plaintext (PERMISSIVE or DISABLE mTLS, resolved from the PeerAuthentications) and no AuthorizationPolicy restricts their requests, so any client can call them. Services whose pods have no sidecar are flagged as well. Use this in security audits."),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace to audit (defaults to 'default')"),
+				),
+				mcp.WithTitleAnnotation("Istio: Find Unauthenticated Services"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.findUnauthenticatedServices,
+		},
+		{
+			Tool: mcp.NewTool("validate-mtls-consistency",
+				mcp.WithDescription("Cross-check the client TLS mode the DestinationRule for a host sets (including port-level settings) against the mTLS mode the host's workloads accept, resolved from the PeerAuthentications, and flag mismatches that break connections, such as tls.mode DISABLE against a STRICT destination or ISTIO_MUTUAL against workloads without a sidecar."),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace of the clients; short hosts resolve in it (defaults to 'default')"),
+				),
+				mcp.WithString("host",
+					mcp.Description("Destination host, e.g. 'reviews', 'reviews.bookinfo' or 'reviews.bookinfo.svc.cluster.local'"),
+					mcp.Required(),
+				),
+				mcp.WithTitleAnnotation("Istio: Validate mTLS Consistency"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.validateMtlsConsistency,
+		},
+	}
+}
+
+func (s *Server) getIstioAnalyze(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	if all, ok := ctr.GetArguments()["all-namespaces"].(bool); ok && all {
+		namespace = ""
+	}
+	severity := ""
+	if sev := ctr.GetArguments()["severity"]; sev != nil {
+		severity = sev.(string)
+	}
+	refresh, _ := ctr.GetArguments()["refresh"].(bool)
+	content, err := s.client().ProxyConfig.Analyze(ctx, namespace, severity, refresh)
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) analyzeServicePorts(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	content, err := s.client().FindPortConflicts(ctx, namespace)
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) auditTlsOrigination(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	content, err := s.client().AuditTlsOrigination(ctx, namespace)
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) compareNamespaces(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespaceA := ""
+	if ns := ctr.GetArguments()["namespace-a"]; ns != nil {
+		namespaceA = ns.(string)
+	}
+	namespaceB := ""
+	if ns := ctr.GetArguments()["namespace-b"]; ns != nil {
+		namespaceB = ns.(string)
+	}
+	if namespaceA == "" || namespaceB == "" {
+		return NewTextResult("", fmt.Errorf("namespace-a and namespace-b are required")), nil
+	}
+	content, err := s.client().CompareNamespaceConfig(ctx, namespaceA, namespaceB)
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) analyzeMissingSubsets(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	content, err := s.client().FindMissingSubsetDefinitions(ctx, namespace)
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) checkMeshExpansionReadiness(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	workloadGroup := ""
+	if wg := ctr.GetArguments()["workload-group"]; wg != nil {
+		workloadGroup = wg.(string)
+	}
+	if workloadGroup == "" {
+		return NewTextResult("", fmt.Errorf("workload-group is required")), nil
+	}
+	content, err := s.client().CheckMeshExpansionReadiness(ctx, namespace, workloadGroup)
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) analyzeDuplicateServiceEntries(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	content, err := s.client().FindDuplicateServiceEntries(ctx, namespace)
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) traceRequestPath(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	fromWorkload := ""
+	if w := ctr.GetArguments()["from-workload"]; w != nil {
+		fromWorkload = w.(string)
+	}
+	host := ""
+	if h := ctr.GetArguments()["host"]; h != nil {
+		host = h.(string)
+	}
+	if fromWorkload == "" || host == "" {
+		return NewTextResult("", fmt.Errorf("from-workload and host are required")), nil
+	}
+	path := "/"
+	if p := ctr.GetArguments()["path"]; p != nil {
+		path = p.(string)
+	}
+	content, err := s.client().TraceRequestPath(ctx, namespace, fromWorkload, host, path)
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) findServicesWithoutRouting(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	content, err := s.client().FindServicesWithoutRouting(ctx, namespace)
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) validateManifest(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	manifest, _ := ctr.GetArguments()["manifest"].(string)
+	if manifest == "" {
+		return NewTextResult("", fmt.Errorf("manifest is required")), nil
+	}
+	content, err := s.client().ValidateManifest(ctx, manifest)
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) checkGatewayHostMatch(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	gateway, _ := ctr.GetArguments()["gateway"].(string)
+	if gateway == "" {
+		return NewTextResult("", fmt.Errorf("gateway is required")), nil
+	}
+	host, _ := ctr.GetArguments()["host"].(string)
+	if host == "" {
+		return NewTextResult("", fmt.Errorf("host is required")), nil
+	}
+	content, err := s.client().CheckGatewayHostMatch(ctx, gateway, host, namespace)
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) analyzeRetryTimeout(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	content, err := s.client().AnalyzeRetryTimeoutConfig(ctx, namespace)
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) getFaultInjectionSummary(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	content, err := s.client().GetFaultInjectionConfig(ctx, namespace)
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) getMirrorConfig(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	content, err := s.client().GetMirrorConfig(ctx, namespace)
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) getCorsConfig(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	content, err := s.client().GetCorsConfig(ctx, namespace)
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) validateGatewayCredentials(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	content, err := s.client().ValidateGatewayCredentials(ctx, namespace)
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) getRecentlyModified(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	since := time.Hour
+	if v, _ := ctr.GetArguments()["since"].(string); v != "" {
+		parsed, err := time.ParseDuration(v)
+		if err != nil || parsed <= 0 {
+			return NewTextResult("", fmt.Errorf("invalid since '%s': expected a positive duration such as '30m' or '1h'", v)), nil
+		}
+		since = parsed
+	}
+	content, err := s.client().GetRecentlyModifiedResources(ctx, namespace, since)
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) validatePortLevelMtls(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	content, err := s.client().ValidatePortLevelMtls(ctx, namespace)
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) matchRoute(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	host, _ := ctr.GetArguments()["host"].(string)
+	if host == "" {
+		return NewTextResult("", fmt.Errorf("host is required")), nil
+	}
+	path, _ := ctr.GetArguments()["path"].(string)
+	method, _ := ctr.GetArguments()["method"].(string)
+	headers := make(map[string]string)
+	if h, ok := ctr.GetArguments()["headers"].(map[string]interface{}); ok {
+		for name, value := range h {
+			headers[name] = fmt.Sprint(value)
+		}
+	}
+	content, err := s.client().MatchVirtualServiceRoute(ctx, namespace, host, path, method, headers)
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) getEffectiveTimeout(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	host, _ := ctr.GetArguments()["host"].(string)
+	if host == "" {
+		return NewTextResult("", fmt.Errorf("host is required")), nil
+	}
+	path, _ := ctr.GetArguments()["path"].(string)
+	content, err := s.client().GetEffectiveTimeout(ctx, namespace, host, path)
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) analyzeGatewayHostMismatches(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	content, err := s.client().FindGatewayHostMismatches(ctx, namespace)
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) checkEgressGatewayRouting(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	host, _ := ctr.GetArguments()["host"].(string)
+	if host == "" {
+		return NewTextResult("", fmt.Errorf("host is required")), nil
+	}
+	content, err := s.client().CheckEgressGatewayRouting(ctx, namespace, host)
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) validateTelemetryProviders(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	content, err := s.client().ValidateTelemetryProviders(ctx, namespace)
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) analyzeCrossNamespaceRouting(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	content, err := s.client().AnalyzeCrossNamespaceRouting(ctx, namespace)
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) validateTrafficSplits(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	content, err := s.client().ValidateTrafficSplits(ctx, namespace)
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) verifyCanaryWeight(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	host, _ := ctr.GetArguments()["host"].(string)
+	if host == "" {
+		return NewTextResult("", fmt.Errorf("host is required")), nil
+	}
+	subset, _ := ctr.GetArguments()["subset"].(string)
+	if subset == "" {
+		return NewTextResult("", fmt.Errorf("subset is required")), nil
+	}
+	weight, err := ctr.RequireInt("weight")
+	if err != nil {
+		return NewTextResult("", fmt.Errorf("weight is required")), nil
+	}
+	content, err := s.client().VerifyCanaryWeight(ctx, namespace, host, subset, weight)
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) validateDelegates(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	content, err := s.client().ValidateDelegates(ctx, namespace)
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) findDeprecatedUsage(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	content, err := s.client().FindDeprecatedUsage(ctx, namespace)
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) findUnauthenticatedServices(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	content, err := s.client().FindUnauthenticatedServices(ctx, namespace)
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) validateMtlsConsistency(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	host, _ := ctr.GetArguments()["host"].(string)
+	if host == "" {
+		return NewTextResult("", fmt.Errorf("host is required")), nil
+	}
+	content, err := s.client().ValidateMtlsConsistency(ctx, namespace, host)
+	return NewTextResult(content, err), nil
+}
@@ -0,0 +1,140 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// batchToolName is the name of the meta-tool running several tool calls at once
+	batchToolName = "batch"
+	// maxBatchSize bounds the number of tool calls of a single batch
+	maxBatchSize = 10
+)
+
+// batchCall is a single tool call of a batch
+type batchCall struct {
+	tool string
+	args map[string]any
+}
+
+// parseBatchCalls reads the calls argument of a batch, rejecting nested batches and tools that aren't read-only
+func parseBatchCalls(ctr mcp.CallToolRequest, handlers map[string]server.ServerTool) ([]batchCall, error) {
+	raw, ok := ctr.GetArguments()["calls"].([]any)
+	if !ok || len(raw) == 0 {
+		return nil, fmt.Errorf("calls is required: a list of {\"tool\": <name>, \"args\": {...}} objects")
+	}
+	if len(raw) > maxBatchSize {
+		return nil, fmt.Errorf("a batch accepts at most %d calls, got %d", maxBatchSize, len(raw))
+	}
+	calls := make([]batchCall, 0, len(raw))
+	for idx, item := range raw {
+		entry, ok := item.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("call %d must be an object with tool and args", idx)
+		}
+		name, _ := entry["tool"].(string)
+		switch tool, found := handlers[name]; {
+		case name == batchToolName:
+			return nil, fmt.Errorf("call %d: batches cannot be nested", idx)
+		case !found:
+			return nil, fmt.Errorf("call %d: unknown tool '%s'", idx, name)
+		case tool.Tool.Annotations.ReadOnlyHint == nil || !*tool.Tool.Annotations.ReadOnlyHint:
+			return nil, fmt.Errorf("call %d: only read-only tools can be batched, '%s' is not", idx, name)
+		}
+		args, _ := entry["args"].(map[string]any)
+		calls = append(calls, batchCall{tool: name, args: args})
+	}
+	return calls, nil
+}
+
+// resultText joins the text content of a tool result
+func resultText(result *mcp.CallToolResult) string {
+	var texts []string
+	for _, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			texts = append(texts, text.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// initBatchTool initializes the meta-tool running several of the given tools in one call, sequentially or concurrently
+func (s *Server) initBatchTool(tools []server.ServerTool) server.ServerTool {
+	handlers := make(map[string]server.ServerTool, len(tools))
+	for _, tool := range tools {
+		handlers[tool.Tool.Name] = tool
+	}
+	return server.ServerTool{
+		Tool: mcp.NewTool(batchToolName,
+			mcp.WithDescription(fmt.Sprintf("Run up to %d read-only tool calls in one request and return their results keyed by index, e.g. to get the configuration of several namespaces at once. Saves round trips over SSE and HTTP. Batches cannot be nested.", maxBatchSize)),
+			mcp.WithArray("calls",
+				mcp.Description("Tool calls to run, each an object with the tool name and its arguments"),
+				mcp.Required(),
+				mcp.Items(map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"tool": map[string]any{"type": "string", "description": "Name of the tool to call"},
+						"args": map[string]any{"type": "object", "description": "Arguments of the tool call"},
+					},
+					"required": []string{"tool"},
+				}),
+			),
+			mcp.WithBoolean("concurrent",
+				mcp.Description("Run the calls concurrently instead of one after the other (defaults to false)"),
+			),
+			mcp.WithTitleAnnotation("Istio: Batch Tool Calls"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+		),
+		Handler: func(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			calls, err := parseBatchCalls(ctr, handlers)
+			if err != nil {
+				return NewTextResult("", err), nil
+			}
+			concurrent, _ := ctr.GetArguments()["concurrent"].(bool)
+
+			results := make([]string, len(calls))
+			run := func(idx int) {
+				request := mcp.CallToolRequest{}
+				request.Params.Name = calls[idx].tool
+				request.Params.Arguments = calls[idx].args
+				result, err := handlers[calls[idx].tool].Handler(ctx, request)
+				switch {
+				case err != nil:
+					results[idx] = "[ERROR] " + err.Error()
+				case result.IsError:
+					results[idx] = "[ERROR] " + resultText(result)
+				default:
+					results[idx] = resultText(result)
+				}
+			}
+			if concurrent {
+				var wg sync.WaitGroup
+				for idx := range calls {
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						run(idx)
+					}()
+				}
+				wg.Wait()
+			} else {
+				for idx := range calls {
+					run(idx)
+				}
+			}
+
+			sections := make([]string, len(calls))
+			for idx, call := range calls {
+				sections[idx] = fmt.Sprintf("[%d] %s:\n%s", idx, call.tool, strings.TrimRight(results[idx], "\n"))
+			}
+			return NewTextResult(strings.Join(sections, "\n\n---\n\n")+"\n", nil), nil
+		},
+	}
+}
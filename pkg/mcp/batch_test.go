@@ -0,0 +1,85 @@
+package mcp
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// TestBatchTool tests that the batch tool runs several tool calls and rejects invalid batches
+func TestBatchTool(t *testing.T) {
+	mockServer := NewMockServer()
+	defer mockServer.Close()
+	mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch req.URL.Path {
+		case "/apis/networking.istio.io/v1alpha3/namespaces/bookinfo/virtualservices":
+			w.Write([]byte(`{"apiVersion": "networking.istio.io/v1alpha3", "kind": "VirtualServiceList", "items": [{"metadata": {"name": "reviews", "namespace": "bookinfo"}}]}`))
+		case "/apis/networking.istio.io/v1alpha3/namespaces/staging/virtualservices":
+			w.Write([]byte(`{"apiVersion": "networking.istio.io/v1alpha3", "kind": "VirtualServiceList", "items": [{"metadata": {"name": "ratings", "namespace": "staging"}}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"kind": "Status", "apiVersion": "v1", "status": "Failure", "reason": "NotFound", "code": 404}`))
+		}
+	}))
+
+	testCaseWithContext(t, &mcpContext{before: func(c *mcpContext) {
+		c.withKubeConfig(mockServer.config)
+	}}, func(c *mcpContext) {
+		server, err := NewServer(Configuration{Profile: &FullProfile{}, Kubeconfig: c.kubeconfigPath})
+		if err != nil {
+			t.Fatalf("Failed to create server: %v", err)
+		}
+		defer server.Close()
+
+		callBatch := func(t *testing.T, params string) mcp.CallToolResult {
+			t.Helper()
+			response := server.server.HandleMessage(c.ctx, []byte(`{"jsonrpc": "2.0", "id": 1, "method": "tools/call", "params": {"name": "batch", "arguments": `+params+`}}`))
+			result, ok := response.(mcp.JSONRPCResponse)
+			if !ok {
+				t.Fatalf("Expected a JSON-RPC response, got %T: %v", response, response)
+			}
+			return result.Result.(mcp.CallToolResult)
+		}
+
+		for _, concurrent := range []string{"false", "true"} {
+			t.Run("runs every call with concurrent="+concurrent, func(t *testing.T) {
+				result := callBatch(t, `{"concurrent": `+concurrent+`, "calls": [
+					{"tool": "get-virtual-services", "args": {"namespace": "bookinfo"}},
+					{"tool": "get-virtual-services", "args": {"namespace": "staging"}}
+				]}`)
+				if result.IsError {
+					t.Fatalf("Unexpected batch error: %v", result.Content)
+				}
+				text := result.Content[0].(mcp.TextContent).Text
+				for _, expected := range []string{
+					"[0] get-virtual-services:\nFound 1 Virtual Services in namespace 'bookinfo'",
+					"- reviews",
+					"[1] get-virtual-services:\nFound 1 Virtual Services in namespace 'staging'",
+					"- ratings",
+				} {
+					if !strings.Contains(text, expected) {
+						t.Errorf("Expected batch result to contain '%s', got: %s", expected, text)
+					}
+				}
+			})
+		}
+
+		t.Run("rejects nested batches", func(t *testing.T) {
+			result := callBatch(t, `{"calls": [{"tool": "batch", "args": {"calls": []}}]}`)
+			if !result.IsError || !strings.Contains(result.Content[0].(mcp.TextContent).Text, "batches cannot be nested") {
+				t.Errorf("Expected nested batch to be rejected, got: %v", result.Content)
+			}
+		})
+
+		t.Run("rejects oversized batches", func(t *testing.T) {
+			calls := strings.Repeat(`{"tool": "get-virtual-services"},`, maxBatchSize+1)
+			result := callBatch(t, `{"calls": [`+strings.TrimSuffix(calls, ",")+`]}`)
+			if !result.IsError || !strings.Contains(result.Content[0].(mcp.TextContent).Text, "at most 10 calls") {
+				t.Errorf("Expected oversized batch to be rejected, got: %v", result.Content)
+			}
+		})
+	})
+}
@@ -0,0 +1,50 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/krutsko/istio-mcp-server/pkg/istio"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// initExportTools initializes the tools writing files to the server's filesystem. Unlike every other tool they
+// are not read-only, so they are only registered when an export root is configured.
+func (s *Server) initExportTools() []server.ServerTool {
+	return []server.ServerTool{
+		{
+			Tool: mcp.NewTool("export-as-kustomize",
+				mcp.WithDescription("Export the Istio resources of a namespace ("+strings.Join(istio.SupportedResourceKinds(), ", ")+") as a ready-to-use kustomize base: each resource is written as clean YAML to <kind>-<name>.yaml in a directory under the server's export root, together with a kustomization.yaml listing them. Existing files are never overwritten. Use this to move mesh configuration into a GitOps repository. Nothing is changed in the cluster."),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace to export (defaults to 'default')"),
+				),
+				mcp.WithString("dir",
+					mcp.Description("Directory to write the base to, relative to the export root; it is created if needed and must not contain files with the same names"),
+					mcp.Required(),
+				),
+				mcp.WithBoolean("clean",
+					mcp.Description("Strip status and server-populated metadata (managedFields, resourceVersion, uid, creationTimestamp, generation) so the YAML can be re-applied; set to false for the raw object (defaults to true)"),
+				),
+				mcp.WithTitleAnnotation("Istio: Export as Kustomize Base"),
+				mcp.WithReadOnlyHintAnnotation(false),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.exportAsKustomize,
+		},
+	}
+}
+
+func (s *Server) exportAsKustomize(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	dir, _ := ctr.GetArguments()["dir"].(string)
+	if dir == "" {
+		return NewTextResult("", fmt.Errorf("dir is required")), nil
+	}
+	content, err := s.client().ExportAsKustomize(ctx, namespace, dir, cleanOption(ctr))
+	return NewTextResult(content, err), nil
+}
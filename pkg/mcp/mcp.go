@@ -2,34 +2,73 @@ package mcp
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"regexp"
+	"sync"
+	"time"
 
 	"github.com/krutsko/istio-mcp-server/pkg/istio"
 	"github.com/krutsko/istio-mcp-server/pkg/version"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	"k8s.io/klog/v2"
 )
 
+// DefaultToolTimeout is the default upper bound on the duration of a single tool call
+const DefaultToolTimeout = 5 * time.Minute
+
 // Configuration holds the server configuration
 type Configuration struct {
 	Profile    Profile
 	Kubeconfig string
+	// ServerName and ServerVersion are advertised to MCP clients during initialization; they default to
+	// the binary name and version, and can be overridden when the server is embedded in another product
+	ServerName    string
+	ServerVersion string
+	// ProxyConfigCacheTTL is how long proxy configuration of a pod is reused between tool calls (0 disables caching)
+	ProxyConfigCacheTTL time.Duration
+	// AnalyzeCacheTTL is how long istioctl analyze results of a namespace are reused between tool calls (0 disables caching)
+	AnalyzeCacheTTL time.Duration
+	// PrometheusURL is the base URL of the Prometheus server scraping Istio metrics, used by metrics-backed tools
+	PrometheusURL string
+	// ExportRoot is the directory export tools may write under; they are only registered when it is set
+	ExportRoot string
+	// ToolTimeout bounds the duration of a single tool call (0 disables the limit)
+	ToolTimeout time.Duration
+	// KubeQPS and KubeBurst limit the rate of requests to the Kubernetes API server (0 keeps the client-go defaults)
+	KubeQPS   float32
+	KubeBurst int
+	// LogRequests logs the name, redacted arguments, duration and error of every tool call at the klog
+	// verbosity LogRequestsLevel
+	LogRequests      bool
+	LogRequestsLevel int
 }
 
 // Server represents the Istio MCP server
 type Server struct {
 	configuration *Configuration
 	server        *server.MCPServer
-	i             *istio.Istio
+	// mu guards i, which is swapped by the kubeconfig watcher while tool handlers run
+	mu sync.RWMutex
+	i  *istio.Istio
 }
 
 // NewServer creates a new Istio MCP server instance
 func NewServer(configuration Configuration) (*Server, error) {
+	if configuration.ServerName == "" {
+		configuration.ServerName = version.BinaryName
+	}
+	if configuration.ServerVersion == "" {
+		configuration.ServerVersion = version.Version
+	}
 	s := &Server{
 		configuration: &configuration,
 		server: server.NewMCPServer(
-			version.BinaryName,
-			version.Version,
+			configuration.ServerName,
+			configuration.ServerVersion,
 			server.WithResourceCapabilities(true, true),
 			server.WithPromptCapabilities(true),
 			server.WithToolCapabilities(true),
@@ -39,23 +78,161 @@ func NewServer(configuration Configuration) (*Server, error) {
 	if err := s.reloadIstioClient(); err != nil {
 		return nil, err
 	}
-	s.i.WatchKubeConfig(s.reloadIstioClient)
+	s.server.AddPrompts(s.initPrompts()...)
+	s.client().WatchKubeConfig(s.reloadIstioClient)
 	return s, nil
 }
 
 // reloadIstioClient reloads the Istio client and updates the server tools
 func (s *Server) reloadIstioClient() error {
-	i, err := istio.NewIstio(s.configuration.Kubeconfig)
+	var opts []istio.ClientOption
+	if s.configuration.KubeQPS > 0 || s.configuration.KubeBurst > 0 {
+		opts = append(opts, istio.WithRateLimit(s.configuration.KubeQPS, s.configuration.KubeBurst))
+	}
+	i, err := istio.NewIstio(s.configuration.Kubeconfig, opts...)
 	if err != nil {
 		return err
 	}
+	i.ProxyConfig.SetCacheTTL(s.configuration.ProxyConfigCacheTTL)
+	i.ProxyConfig.SetAnalyzeCacheTTL(s.configuration.AnalyzeCacheTTL)
+	i.SetPrometheusURL(s.configuration.PrometheusURL)
+	i.SetExportRoot(s.configuration.ExportRoot)
+	s.mu.Lock()
 	s.i = i
-	// All tools are read-only and non-destructive, so no filtering needed
-	tools := s.configuration.Profile.GetTools(s)
+	s.mu.Unlock()
+	// Every tool is read-only and non-destructive except the export tools, which tools() only registers with an export root
+	tools := s.tools()
+	for idx := range tools {
+		tools[idx].Handler = s.withRequestLogging(tools[idx].Tool.Name, s.withToolTimeout(tools[idx].Tool.Name, tools[idx].Handler))
+	}
 	s.server.SetTools(tools...)
 	return nil
 }
 
+// tools returns the tools of the configured profile, the export tools when an export root is configured, and the
+// batch tool running them
+func (s *Server) tools() []server.ServerTool {
+	tools := s.configuration.Profile.GetTools(s)
+	if s.configuration.ExportRoot != "" {
+		tools = append(tools, s.initExportTools()...)
+	}
+	return append(tools, s.initBatchTool(tools))
+}
+
+// ToolDefinitions returns the definitions of the tools a server with the given profile exposes, without connecting to
+// a cluster. The export tools are included with a description noting that they require an export root.
+func ToolDefinitions(profile Profile) []mcp.Tool {
+	s := &Server{configuration: &Configuration{Profile: profile}}
+	tools := s.tools()
+	definitions := make([]mcp.Tool, 0, len(tools))
+	for _, tool := range tools {
+		definitions = append(definitions, tool.Tool)
+	}
+	for _, tool := range s.initExportTools() {
+		tool.Tool.Description = "[Only available when the server is started with --export-root] " + tool.Tool.Description
+		definitions = append(definitions, tool.Tool)
+	}
+	return definitions
+}
+
+// withToolTimeout bounds the duration of a tool handler by the configured tool timeout. The handler runs in its own
+// goroutine, so that a call that doesn't honor its context still returns to the client when the deadline passes.
+func (s *Server) withToolTimeout(name string, handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	timeout := s.configuration.ToolTimeout
+	if timeout <= 0 {
+		return handler
+	}
+	return func(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		type outcome struct {
+			result *mcp.CallToolResult
+			err    error
+		}
+		done := make(chan outcome, 1)
+		go func() {
+			result, err := handler(ctx, ctr)
+			done <- outcome{result, err}
+		}()
+		select {
+		case o := <-done:
+			// Replace the "context deadline exceeded" error of a call cut short by the deadline with a clearer one
+			if o.result != nil && o.result.IsError && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				break
+			}
+			return o.result, o.err
+		case <-ctx.Done():
+			if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, ctx.Err()
+			}
+		}
+		return NewTextResult("", fmt.Errorf("tool %s timed out after %s", name, timeout)), nil
+	}
+}
+
+// sensitiveArgument matches the names of tool arguments, and of nested keys such as request headers, whose values
+// are never logged
+var sensitiveArgument = regexp.MustCompile(`(?i)authorization|token|password|secret|credential|cookie|api[-_]?key`)
+
+// redactArguments returns a copy of tool arguments with the values of sensitive keys replaced at any depth
+func redactArguments(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		redacted := make(map[string]any, len(v))
+		for key, nested := range v {
+			if sensitiveArgument.MatchString(key) {
+				redacted[key] = "[REDACTED]"
+			} else {
+				redacted[key] = redactArguments(nested)
+			}
+		}
+		return redacted
+	case []any:
+		redacted := make([]any, len(v))
+		for idx, nested := range v {
+			redacted[idx] = redactArguments(nested)
+		}
+		return redacted
+	}
+	return value
+}
+
+// withRequestLogging logs every call of a tool handler when request logging is enabled. Only the tool arguments
+// are logged, redacted; request headers such as Authorization never are.
+func (s *Server) withRequestLogging(name string, handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	if !s.configuration.LogRequests {
+		return handler
+	}
+	level := klog.Level(s.configuration.LogRequestsLevel)
+	return func(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		start := time.Now()
+		result, err := handler(ctx, ctr)
+		arguments, marshalErr := json.Marshal(redactArguments(ctr.GetArguments()))
+		if marshalErr != nil {
+			arguments = []byte("<unprintable>")
+		}
+		keysAndValues := []any{"tool", name, "arguments", string(arguments), "duration", time.Since(start).Round(time.Millisecond)}
+		switch {
+		case err != nil:
+			klog.V(level).InfoS("Tool call failed", append(keysAndValues, "error", err)...)
+		case result != nil && result.IsError && len(result.Content) > 0:
+			text, _ := result.Content[0].(mcp.TextContent)
+			klog.V(level).InfoS("Tool call failed", append(keysAndValues, "error", text.Text)...)
+		default:
+			klog.V(level).InfoS("Tool call", keysAndValues...)
+		}
+		return result, err
+	}
+}
+
+// client returns the current Istio client, which may be replaced at any time by a kubeconfig reload
+func (s *Server) client() *istio.Istio {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.i
+}
+
 // ServeStdio starts the server in STDIO mode
 func (s *Server) ServeStdio() error {
 	return server.ServeStdio(s.server)
@@ -81,8 +258,8 @@ func (s *Server) ServeHTTP() *server.StreamableHTTPServer {
 
 // Close cleans up server resources
 func (s *Server) Close() {
-	if s.i != nil {
-		s.i.Close()
+	if i := s.client(); i != nil {
+		i.Close()
 	}
 }
 
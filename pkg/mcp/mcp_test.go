@@ -1,14 +1,23 @@
 package mcp
 
 import (
+	"bytes"
 	"context"
 	"net/http"
 	"os"
 	"runtime"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/krutsko/istio-mcp-server/pkg/istio"
+	"github.com/krutsko/istio-mcp-server/pkg/version"
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"k8s.io/klog/v2"
+	"k8s.io/klog/v2/textlogger"
 )
 
 // TestNewServer tests server creation with valid configuration
@@ -40,6 +49,42 @@ func TestNewServer(t *testing.T) {
 	})
 }
 
+// TestServerInfo tests that the configured server name and version are advertised to MCP clients
+func TestServerInfo(t *testing.T) {
+	testCase(t, func(c *mcpContext) {
+		initialize := func(t *testing.T, configuration Configuration) mcp.Implementation {
+			t.Helper()
+			configuration.Profile = &FullProfile{}
+			configuration.Kubeconfig = c.kubeconfigPath
+			server, err := NewServer(configuration)
+			if err != nil {
+				t.Fatalf("Failed to create server: %v", err)
+			}
+			defer server.Close()
+			response := server.server.HandleMessage(c.ctx, []byte(`{"jsonrpc": "2.0", "id": 1, "method": "initialize", "params": {"protocolVersion": "2025-03-26", "clientInfo": {"name": "test", "version": "1.0"}}}`))
+			result, ok := response.(mcp.JSONRPCResponse)
+			if !ok {
+				t.Fatalf("Expected a JSON-RPC response, got %T", response)
+			}
+			return result.Result.(mcp.InitializeResult).ServerInfo
+		}
+
+		t.Run("defaults to the binary name and version", func(t *testing.T) {
+			info := initialize(t, Configuration{})
+			if info.Name != version.BinaryName || info.Version != version.Version {
+				t.Errorf("Expected server info %s/%s, got %s/%s", version.BinaryName, version.Version, info.Name, info.Version)
+			}
+		})
+
+		t.Run("uses the configured name and version", func(t *testing.T) {
+			info := initialize(t, Configuration{ServerName: "acme-mesh-assistant", ServerVersion: "2.1.0"})
+			if info.Name != "acme-mesh-assistant" || info.Version != "2.1.0" {
+				t.Errorf("Expected server info acme-mesh-assistant/2.1.0, got %s/%s", info.Name, info.Version)
+			}
+		})
+	})
+}
+
 // TestNewServerWithInvalidKubeconfig tests server creation with invalid kubeconfig
 func TestNewServerWithInvalidKubeconfig(t *testing.T) {
 	testCase(t, func(c *mcpContext) {
@@ -232,3 +277,137 @@ type TestError struct {
 func (e *TestError) Error() string {
 	return e.message
 }
+
+// TestConcurrentReload tests that tool handlers can run while the Istio client is reloaded.
+// Run with -race to detect unsynchronized access to the client.
+func TestConcurrentReload(t *testing.T) {
+	testCase(t, func(c *mcpContext) {
+		if err := c.setupMCPServer(); err != nil {
+			t.Fatalf("Failed to setup MCP server: %v", err)
+		}
+		defer c.server.Close()
+
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				if err := c.server.reloadIstioClient(); err != nil {
+					t.Errorf("Failed to reload Istio client: %v", err)
+					return
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				if _, err := c.callTool("get-virtual-services", map[string]interface{}{}); err != nil {
+					t.Errorf("Failed to call tool: %v", err)
+					return
+				}
+				if c.server.client() == nil {
+					t.Error("Expected Istio client to be set")
+					return
+				}
+			}
+		}()
+		wg.Wait()
+	})
+}
+
+// TestToolTimeout tests that a tool call blocked on a slow API server fails once the tool timeout passes
+func TestToolTimeout(t *testing.T) {
+	release := make(chan struct{})
+	mockServer := NewMockServer()
+	defer mockServer.Close()
+	defer close(release)
+	mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		// Never answer, as if the API server hung
+		select {
+		case <-release:
+		case <-req.Context().Done():
+		}
+	}))
+
+	testCaseWithContext(t, &mcpContext{before: func(c *mcpContext) {
+		c.withKubeConfig(mockServer.config)
+	}}, func(c *mcpContext) {
+		server, err := NewServer(Configuration{
+			Profile:     &FullProfile{},
+			Kubeconfig:  c.kubeconfigPath,
+			ToolTimeout: 100 * time.Millisecond,
+		})
+		if err != nil {
+			t.Fatalf("Failed to create server: %v", err)
+		}
+		defer server.Close()
+
+		start := time.Now()
+		response := server.server.HandleMessage(c.ctx, []byte(`{"jsonrpc": "2.0", "id": 1, "method": "tools/call", "params": {"name": "get-virtual-services", "arguments": {"namespace": "default"}}}`))
+		if elapsed := time.Since(start); elapsed > 5*time.Second {
+			t.Fatalf("Expected the tool call to be cut short by the timeout, took %s", elapsed)
+		}
+		result, ok := response.(mcp.JSONRPCResponse)
+		if !ok {
+			t.Fatalf("Expected a JSON-RPC response, got %T: %v", response, response)
+		}
+		callResult := result.Result.(mcp.CallToolResult)
+		if !callResult.IsError {
+			t.Fatal("Expected the timed out tool call to return an error")
+		}
+		text := callResult.Content[0].(mcp.TextContent).Text
+		if text != "tool get-virtual-services timed out after 100ms" {
+			t.Errorf("Unexpected timeout error: %s", text)
+		}
+	})
+}
+
+// TestRequestLogging tests that tool calls are logged with sensitive argument values and the Authorization header redacted
+func TestRequestLogging(t *testing.T) {
+	var logs bytes.Buffer
+	klog.SetLoggerWithOptions(textlogger.NewLogger(textlogger.NewConfig(textlogger.Output(&logs))))
+	defer klog.ClearLogger()
+
+	testCaseWithContext(t, &mcpContext{}, func(c *mcpContext) {
+		server, err := NewServer(Configuration{
+			Profile:     &FullProfile{},
+			Kubeconfig:  c.kubeconfigPath,
+			LogRequests: true,
+		})
+		if err != nil {
+			t.Fatalf("Failed to create server: %v", err)
+		}
+		defer server.Close()
+
+		ctx := context.WithValue(c.ctx, istio.AuthorizationHeader, "Bearer header-token")
+		server.server.HandleMessage(ctx, []byte(`{"jsonrpc": "2.0", "id": 1, "method": "tools/call", "params": {"name": "match-route", "arguments": {
+			"namespace": "bookinfo", "host": "reviews", "headers": {"end-user": "jason", "Authorization": "Bearer argument-token"}
+		}}}`))
+
+		output := logs.String()
+		for _, expected := range []string{`tool="match-route"`, `\"end-user\":\"jason\"`, `\"Authorization\":\"[REDACTED]\"`, "duration="} {
+			if !strings.Contains(output, expected) {
+				t.Errorf("Expected the request log to contain %s, got: %s", expected, output)
+			}
+		}
+		for _, secret := range []string{"argument-token", "header-token"} {
+			if strings.Contains(output, secret) {
+				t.Errorf("Expected %s to be redacted from the request log, got: %s", secret, output)
+			}
+		}
+	})
+}
+
+// TestExportToolsRequireExportRoot tests that tools writing files are only registered with an export root
+func TestExportToolsRequireExportRoot(t *testing.T) {
+	hasExportTool := func(configuration *Configuration) bool {
+		s := &Server{configuration: configuration}
+		return slices.ContainsFunc(s.tools(), func(tool server.ServerTool) bool { return tool.Tool.Name == "export-as-kustomize" })
+	}
+	if hasExportTool(&Configuration{Profile: &FullProfile{}}) {
+		t.Error("Expected export-as-kustomize to be disabled without an export root")
+	}
+	if !hasExportTool(&Configuration{Profile: &FullProfile{}, ExportRoot: t.TempDir()}) {
+		t.Error("Expected export-as-kustomize to be registered with an export root")
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"slices"
+	"strings"
 
+	"github.com/krutsko/istio-mcp-server/pkg/istio"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
@@ -54,6 +56,7 @@ func (p *FullProfile) GetTools(s *Server) []server.ServerTool {
 		s.initSecurityTools(),
 		s.initConfigurationTools(),
 		s.initProxyConfigTools(),
+		s.initAnalysisTools(),
 	)
 }
 
@@ -66,6 +69,8 @@ func (s *Server) initNetworkingTools() []server.ServerTool {
 				mcp.WithString("namespace",
 					mcp.Description("Namespace to query (defaults to 'default'). Istio services can span multiple namespaces."),
 				),
+				withVerbosity(),
+				withFieldSelector(),
 				mcp.WithTitleAnnotation("Istio: Virtual Services"),
 				mcp.WithReadOnlyHintAnnotation(true),
 				mcp.WithDestructiveHintAnnotation(false),
@@ -78,36 +83,163 @@ func (s *Server) initNetworkingTools() []server.ServerTool {
 				mcp.WithString("namespace",
 					mcp.Description("Namespace to query (defaults to 'default'). Check multiple namespaces for complete Istio configuration."),
 				),
+				withVerbosity(),
+				withFieldSelector(),
 				mcp.WithTitleAnnotation("Istio: Destination Rules"),
 				mcp.WithReadOnlyHintAnnotation(true),
 				mcp.WithDestructiveHintAnnotation(false),
 			),
 			Handler: s.getDestinationRules,
 		},
+		{
+			Tool: mcp.NewTool("get-effective-destination-rule",
+				mcp.WithDescription("Get every Destination Rule that applies to a host across all namespaces, honoring exportTo visibility, and the configuration Istio applies from them. The rule with the highest precedence wins (client namespace, then service namespace, then mesh root namespace); rules for the same host in its namespace are merged, and rules in other namespaces are reported as shadowed. Use this when a policy seems to be ignored or overridden by a rule in another namespace."),
+				mcp.WithString("host",
+					mcp.Description("Host of the service, short or fully qualified (e.g. 'reviews.bookinfo' or 'reviews.bookinfo.svc.cluster.local')"),
+					mcp.Required(),
+				),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace of the calling workloads (optional, defaults to the namespace of the host)"),
+				),
+				mcp.WithTitleAnnotation("Istio: Effective Destination Rule"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.getEffectiveDestinationRule,
+		},
+		{
+			Tool: mcp.NewTool("get-destination-rule-blast-radius",
+				mcp.WithDescription("Get what a change to a Destination Rule affects before editing it: the services its host governs, the Virtual Services routing to its subsets (flagging subsets the rule does not define), and the mesh workloads whose proxies send traffic to those hosts, from their clusters. Only workloads the rule is exported to are listed."),
+				mcp.WithString("name",
+					mcp.Description("Name of the Destination Rule"),
+					mcp.Required(),
+				),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace of the Destination Rule (defaults to 'default')"),
+				),
+				mcp.WithTitleAnnotation("Istio: Destination Rule Blast Radius"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.getDestinationRuleBlastRadius,
+		},
+		{
+			Tool: mcp.NewTool("get-locality-lb-config",
+				mcp.WithDescription("Get the effective locality load balancing settings for a host: the failover regions, failover priority labels and distribute weights from the Destination Rule with the highest precedence, falling back to the mesh config, plus subset overrides. Reports whether outlier detection is configured, without which Istio does not fail over between localities. Use this to explain cross-zone and cross-region traffic."),
+				mcp.WithString("host",
+					mcp.Description("Host of the service, short or fully qualified (e.g. 'reviews', 'reviews.bookinfo' or 'reviews.bookinfo.svc.cluster.local')"),
+					mcp.Required(),
+				),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace of the calling workloads; short hosts resolve in it (defaults to 'default')"),
+				),
+				mcp.WithTitleAnnotation("Istio: Locality Load Balancing"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.getLocalityLbConfig,
+		},
 		{
 			Tool: mcp.NewTool("get-gateways",
 				mcp.WithDescription("Get Istio Gateways from any namespace. Gateways configure load balancers for incoming traffic to the service mesh. Use this to inspect ingress/egress configuration and external access patterns."),
 				mcp.WithString("namespace",
 					mcp.Description("Namespace to query (defaults to 'default'). Gateway configurations may exist in ingress or dedicated namespaces."),
 				),
+				withVerbosity(),
+				withFieldSelector(),
 				mcp.WithTitleAnnotation("Istio: Gateways"),
 				mcp.WithReadOnlyHintAnnotation(true),
 				mcp.WithDestructiveHintAnnotation(false),
 			),
 			Handler: s.getGateways,
 		},
+		{
+			Tool: mcp.NewTool("get-ingress-gateway-address",
+				mcp.WithDescription("Get the external address (IP or hostname) and ports of the Istio ingress gateway, i.e. where to send requests to reach the mesh from outside the cluster. Reports load balancers that are still <pending> and the NodePort fallback to use meanwhile."),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace of the ingress gateway service (defaults to 'istio-system')"),
+				),
+				mcp.WithTitleAnnotation("Istio: Ingress Gateway Address"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.getIngressGatewayAddress,
+		},
+		{
+			Tool: mcp.NewTool("get-gateway-endpoints",
+				mcp.WithDescription("For each Gateway in a namespace, find the Service fronting the gateway workload it selects and report the address (load balancer IP/hostname, external IP or cluster IP) and port a client actually connects to for each server's hosts. Flags Gateways with no Service and server ports the Service doesn't expose. Use this to bridge a Gateway resource to its real network address."),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace of the Gateways (defaults to 'default')"),
+				),
+				mcp.WithTitleAnnotation("Istio: Gateway Endpoints"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.getGatewayEndpoints,
+		},
 		{
 			Tool: mcp.NewTool("get-service-entries",
 				mcp.WithDescription("Get Istio Service Entries from any namespace. Service Entries allow adding external services to the service mesh registry. Use this to inspect external service configurations and mesh expansion settings."),
 				mcp.WithString("namespace",
 					mcp.Description("Namespace to query (defaults to 'default'). External service configurations may be centralized in specific namespaces."),
 				),
+				withVerbosity(),
+				withFieldSelector(),
 				mcp.WithTitleAnnotation("Istio: Service Entries"),
 				mcp.WithReadOnlyHintAnnotation(true),
 				mcp.WithDestructiveHintAnnotation(false),
 			),
 			Handler: s.getServiceEntries,
 		},
+		{
+			Tool: mcp.NewTool("list-external-hosts",
+				mcp.WithDescription("List the external dependencies of the whole mesh: the hosts of ServiceEntries located outside the mesh, across all namespaces, and the hosts VirtualServices route to that are not Kubernetes services. Each host appears once with the ServiceEntries and VirtualServices referencing it, and hosts no ServiceEntry declares are flagged. Use this for egress audits to answer what the mesh calls externally."),
+				mcp.WithTitleAnnotation("Istio: External Hosts"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.listExternalHosts,
+		},
+		{
+			Tool: mcp.NewTool("get-effective-outbound-policy",
+				mcp.WithDescription("Get the effective outbound traffic policy of a namespace and each of its workloads: ALLOW_ANY (any external host is reachable) or REGISTRY_ONLY (only services registered in the mesh, e.g. through Service Entries). Combines the mesh-wide default with Sidecar overrides in the root namespace, the namespace itself, and per-workload Sidecars. Use this to explain why egress to an external host is blocked."),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace to query (defaults to 'default')"),
+				),
+				mcp.WithTitleAnnotation("Istio: Effective Outbound Policy"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.getEffectiveOutboundPolicy,
+		},
+		{
+			Tool: mcp.NewTool("get-applied-sidecar-scope",
+				mcp.WithDescription("Get the Sidecar resource shaping a pod's proxy and its egress: a Sidecar whose workload selector matches the pod overrides the namespace-wide Sidecar, which overrides the root namespace default. Reports why the Sidecar applies, which ones it overrides or conflicts with, and the hosts each egress listener imports. Use this to explain why a proxy doesn't see a service."),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace of the pod (defaults to 'default')"),
+				),
+				mcp.WithString("pod",
+					mcp.Description("Pod name containing the Istio proxy (sidecar)"),
+					mcp.Required(),
+				),
+				mcp.WithTitleAnnotation("Istio: Applied Sidecar Scope"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.getAppliedSidecarScope,
+		},
+		{
+			Tool: mcp.NewTool("get-waypoint-proxies",
+				mcp.WithDescription("Get the waypoint proxies of a namespace in ambient mode and the namespaces and services labeled istio.io/use-waypoint to send their traffic through them. Ambient workloads have no sidecar: L7 routing and authorization policy run on waypoints, so this is the ambient equivalent of finding a workload's sidecar."),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace of the waypoints (defaults to 'default')"),
+				),
+				mcp.WithTitleAnnotation("Istio: Waypoint Proxies"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.getWaypointProxies,
+		},
 	}
 }
 
@@ -120,6 +252,8 @@ func (s *Server) initSecurityTools() []server.ServerTool {
 				mcp.WithString("namespace",
 					mcp.Description("Namespace to query (defaults to 'default'). Security policies may be defined in multiple namespaces for different service boundaries."),
 				),
+				withVerbosity(),
+				withFieldSelector(),
 				mcp.WithTitleAnnotation("Istio: Authorization Policies"),
 				mcp.WithReadOnlyHintAnnotation(true),
 				mcp.WithDestructiveHintAnnotation(false),
@@ -132,12 +266,96 @@ func (s *Server) initSecurityTools() []server.ServerTool {
 				mcp.WithString("namespace",
 					mcp.Description("Namespace to query (defaults to 'default'). Authentication policies may be namespace-specific or inherited from mesh-wide settings."),
 				),
+				withVerbosity(),
+				withFieldSelector(),
 				mcp.WithTitleAnnotation("Istio: Peer Authentications"),
 				mcp.WithReadOnlyHintAnnotation(true),
 				mcp.WithDestructiveHintAnnotation(false),
 			),
 			Handler: s.getPeerAuthentications,
 		},
+		{
+			Tool: mcp.NewTool("get-peer-auth-blast-radius",
+				mcp.WithDescription("Get what a change to a Peer Authentication affects before changing its mTLS mode: the mesh workloads it selects (all workloads of its namespace without a selector, or of the mesh in the root namespace), the mTLS mode each one currently has and the policy deciding it, and, when Prometheus is configured, whether their inbound requests use mutual TLS. Plaintext clients are rejected once the mode becomes STRICT."),
+				mcp.WithString("name",
+					mcp.Description("Name of the Peer Authentication"),
+					mcp.Required(),
+				),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace of the Peer Authentication (defaults to 'default')"),
+				),
+				mcp.WithTitleAnnotation("Istio: Peer Authentication Blast Radius"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.getPeerAuthBlastRadius,
+		},
+		{
+			Tool: mcp.NewTool("get-workload-identity",
+				mcp.WithDescription("Get the SPIFFE identity (e.g. 'spiffe://cluster.local/ns/foo/sa/bar') that a pod presents in Istio mTLS connections, derived from its ServiceAccount. Use this to write or verify AuthorizationPolicy principals for a workload."),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace of the pod (defaults to 'default')"),
+				),
+				mcp.WithString("pod",
+					mcp.Description("Pod name to resolve the identity for"),
+					mcp.Required(),
+				),
+				mcp.WithTitleAnnotation("Istio: Workload Identity"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.getWorkloadIdentity,
+		},
+		{
+			Tool: mcp.NewTool("find-workloads-by-identity",
+				mcp.WithDescription("Find the pods that run with a given SPIFFE identity. Accepts the URI form ('spiffe://cluster.local/ns/foo/sa/bar') or the AuthorizationPolicy principal form ('cluster.local/ns/foo/sa/bar'). Use this to map policy principals back to real workloads."),
+				mcp.WithString("spiffe-id",
+					mcp.Description("SPIFFE identity or AuthorizationPolicy principal to look up"),
+					mcp.Required(),
+				),
+				mcp.WithTitleAnnotation("Istio: Workloads by Identity"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.findWorkloadsByIdentity,
+		},
+		{
+			Tool: mcp.NewTool("get-mesh-identity-config",
+				mcp.WithDescription("Get the mesh's root namespace and SPIFFE trust domain (with any trust domain aliases) from the mesh config. Policies in the root namespace apply mesh-wide, and the trust domain is the first segment of every workload identity and AuthorizationPolicy principal. Use this before writing principals or mesh-wide PeerAuthentications."),
+				mcp.WithTitleAnnotation("Istio: Mesh Identity Config"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.getMeshIdentityConfig,
+		},
+		{
+			Tool: mcp.NewTool("get-effective-authz",
+				mcp.WithDescription("Get all Authorization Policies affecting a workload, mesh-wide (root namespace), namespace-wide and workload-specific, and explain their combined effect: CUSTOM policies delegate to an external authorizer first, any matching DENY rule rejects the request, and when ALLOW policies apply a request must match one of their rules. Use this to understand why a request is rejected with 403 RBAC: access denied."),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace of the workload (defaults to 'default')"),
+				),
+				mcp.WithString("selector",
+					mcp.Description("Labels of the workload, e.g. 'app=reviews' or 'app=reviews,version=v1'"),
+					mcp.Required(),
+				),
+				mcp.WithTitleAnnotation("Istio: Effective Authorization"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.getEffectiveAuthz,
+		},
+		{
+			Tool: mcp.NewTool("get-custom-authz-policies",
+				mcp.WithDescription("List the Authorization Policies with the CUSTOM action in a namespace, the external authorization provider each one delegates to (e.g. OPA or oauth2-proxy), and whether that provider is defined as an envoyExtAuthzHttp or envoyExtAuthzGrpc extension provider in the mesh config. Requests matching a CUSTOM policy with an undefined provider are rejected."),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace of the Authorization Policies (defaults to 'default')"),
+				),
+				mcp.WithTitleAnnotation("Istio: CUSTOM Authorization Policies"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.getCustomAuthzPolicies,
+		},
 	}
 }
 
@@ -146,31 +364,123 @@ func (s *Server) initConfigurationTools() []server.ServerTool {
 	return []server.ServerTool{
 		{
 			Tool: mcp.NewTool("discover-istio-namespaces",
-				mcp.WithDescription("Discover namespaces that have pods with Istio sidecars and rank them by injection density. This tool helps identify the most probable best namespace for Istio operations by analyzing which namespaces have the most Istio-injected workloads. Use this to prioritize which namespaces to investigate first for Istio configuration and traffic analysis."),
+				mcp.WithDescription("Discover namespaces that have pods with Istio sidecars or enrolled in ambient mode (istio.io/dataplane-mode=ambient) and rank them by mesh workload count, reporting sidecar and ambient workloads separately. This tool helps identify the most probable best namespace for Istio operations by analyzing which namespaces have the most Istio-enrolled workloads. Use this to prioritize which namespaces to investigate first for Istio configuration and traffic analysis."),
+				mcp.WithString("format",
+					mcp.Description("Output format: 'text' for a ranked table (default) or 'json' for an array of {namespace, sidecarCount, ambientCount, rank} ordered by rank"),
+					mcp.Enum("text", "json"),
+				),
 				mcp.WithTitleAnnotation("Istio: Namespace Discovery"),
 				mcp.WithReadOnlyHintAnnotation(true),
 				mcp.WithDestructiveHintAnnotation(false),
 			),
 			Handler: s.discoverIstioNamespaces,
 		},
+		{
+			Tool: mcp.NewTool("diagnose-mcp-server",
+				mcp.WithDescription("Run a self-test of the Istio MCP server environment and return a checklist: Kubernetes API reachability and version, istioctl availability and version, Istio CRD installation (networking.istio.io), and whether the target namespace exists. Run this first when other tools fail unexpectedly."),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace to verify access to (defaults to 'default')"),
+				),
+				mcp.WithTitleAnnotation("Istio MCP: Diagnostics"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.diagnoseMcpServer,
+		},
+		{
+			Tool: mcp.NewTool("get-xds-push-stats",
+				mcp.WithDescription("Get xDS push statistics from every istiod replica: connected proxies, pushes by type (cds, lds, rds, eds), push errors such as configuration rejected by proxies or send timeouts, proxies lagging behind (expired nonces), and the issues istiod recorded while computing pushes (e.g. clusters without endpoints or conflicting listeners). Reads istiod's monitoring port (15014) through the Kubernetes API. Use this when configuration changes take long to reach proxies or proxies are STALE."),
+				mcp.WithTitleAnnotation("Istio: xDS Push Statistics"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.getXdsPushStats,
+		},
+		{
+			Tool: mcp.NewTool("get-istiod-connection-distribution",
+				mcp.WithDescription("Report how many proxies each istiod replica serves, broken down by namespace, from the replicas' /debug/connections endpoint, and flag replicas serving well above their share. Uneven distribution concentrates xDS memory and push load on one replica. Reads istiod's monitoring port (15014) through the Kubernetes API."),
+				mcp.WithTitleAnnotation("Istio: istiod Connection Distribution"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.getIstiodConnectionDistribution,
+		},
+		{
+			Tool: mcp.NewTool("get-istiod-logs-for-proxy",
+				mcp.WithDescription("Get the istiod log lines mentioning a proxy, searched across the recent logs of every istiod replica. istiod logs connections, pushes and rejected configuration (NACKs) per proxy, so this explains why a proxy doesn't sync or is STALE."),
+				mcp.WithString("proxy",
+					mcp.Description("Proxy ID as shown by get-proxy-status, i.e. '<pod>.<namespace>' (e.g. 'productpage-v1-6b746f74dc-9stvs.default')"),
+					mcp.Required(),
+				),
+				mcp.WithTitleAnnotation("Istio: istiod Logs for Proxy"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.getIstiodLogsForProxy,
+		},
+		{
+			Tool: mcp.NewTool("get-injection-image",
+				mcp.WithDescription("Report the proxy image new pods of a namespace get from sidecar injection: the revision selected by the namespace's istio-injection or istio.io/rev label, resolved through revision tags, and the image configured in that revision's istio-sidecar-injector ConfigMap. Use this to plan upgrades and to check which namespaces move to a new revision on their next restart."),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace to check (defaults to 'default')"),
+				),
+				mcp.WithTitleAnnotation("Istio: Injection Image"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.getInjectionImage,
+		},
+		{
+			Tool: mcp.NewTool("get-resources-by-revision",
+				mcp.WithDescription("List the namespaces and Istio resources bound to a control plane revision through the istio.io/rev label or annotation, across all namespaces. Use this during a canary upgrade to see what each revision owns and what still has to move before the old revision is removed."),
+				mcp.WithString("revision",
+					mcp.Description("Control plane revision (e.g. '1-25-1' or 'canary')"),
+					mcp.Required(),
+				),
+				mcp.WithTitleAnnotation("Istio: Resources by Revision"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.getResourcesByRevision,
+		},
 		{
 			Tool: mcp.NewTool("get-envoy-filters",
 				mcp.WithDescription("Get Istio Envoy Filters from any namespace. Envoy Filters allow custom configuration of Envoy proxy behavior, including custom filters, listeners, and clusters. Use this to inspect advanced Istio service mesh configurations."),
 				mcp.WithString("namespace",
 					mcp.Description("Namespace to query (defaults to 'default'). Custom Envoy configurations may be applied to specific namespaces or workloads."),
 				),
+				withVerbosity(),
+				withFieldSelector(),
 				mcp.WithTitleAnnotation("Istio: Envoy Filters"),
 				mcp.WithReadOnlyHintAnnotation(true),
 				mcp.WithDestructiveHintAnnotation(false),
 			),
 			Handler: s.getEnvoyFilters,
 		},
+		{
+			Tool: mcp.NewTool("get-envoy-filters-for-workload",
+				mcp.WithDescription("List the EnvoyFilters that patch a pod's proxy: filters of the mesh root namespace and the pod's namespace whose workload selector matches the pod's labels, in the order Istio applies them. Use this to find out which EnvoyFilters actually affect a workload."),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace of the pod (defaults to 'default')"),
+				),
+				mcp.WithString("pod",
+					mcp.Description("Pod name of the workload"),
+					mcp.Required(),
+				),
+				mcp.WithTitleAnnotation("Istio: EnvoyFilters for Workload"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.getEnvoyFiltersForWorkload,
+		},
 		{
 			Tool: mcp.NewTool("get-telemetry",
 				mcp.WithDescription("Get Istio Telemetry configurations from any namespace. Telemetry policies define observability settings including metrics, tracing, and logging for the service mesh. Use this to inspect monitoring and observability configurations."),
 				mcp.WithString("namespace",
 					mcp.Description("Namespace to query (defaults to 'default'). Telemetry policies may be namespace-specific or inherited from mesh-wide settings."),
 				),
+				withVerbosity(),
+				withFieldSelector(),
 				mcp.WithTitleAnnotation("Istio: Telemetry"),
 				mcp.WithReadOnlyHintAnnotation(true),
 				mcp.WithDestructiveHintAnnotation(false),
@@ -189,6 +499,115 @@ func (s *Server) initConfigurationTools() []server.ServerTool {
 			),
 			Handler: s.getIstioConfigSummary,
 		},
+		{
+			Tool: mcp.NewTool("get-istio-resource",
+				mcp.WithDescription("Get a single named Istio resource with its full spec as YAML. Supported kinds: "+strings.Join(istio.SupportedResourceKinds(), ", ")+". Use this instead of listing when you know the resource name and need its complete configuration."),
+				mcp.WithString("kind",
+					mcp.Description("Kind of the resource, singular or plural (e.g. 'VirtualService' or 'destinationrules')"),
+					mcp.Required(),
+				),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace of the resource (defaults to 'default')"),
+				),
+				mcp.WithString("name",
+					mcp.Description("Name of the resource"),
+					mcp.Required(),
+				),
+				mcp.WithBoolean("include-managed-fields",
+					mcp.Description("Include metadata.managedFields, which record the field manager (controller or client) owning each field under server-side apply. Useful to diagnose 'conflict with another field manager' errors (defaults to false)"),
+				),
+				mcp.WithBoolean("clean",
+					mcp.Description("Strip status and server-populated metadata (managedFields, resourceVersion, uid, creationTimestamp, generation) so the YAML can be re-applied; set to false for the raw object (defaults to true)"),
+				),
+				mcp.WithTitleAnnotation("Istio: Get Resource"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.getIstioResource,
+		},
+		{
+			Tool: mcp.NewTool("get-resource-for-editing",
+				mcp.WithDescription("Get a single named Istio resource as clean YAML ready to be modified and re-applied: status and server-populated metadata (resourceVersion, uid, managedFields, creationTimestamp, generation) are removed, and no header is added. Supported kinds: "+strings.Join(istio.SupportedResourceKinds(), ", ")+". Use this instead of get-istio-resource when you intend to change the resource."),
+				mcp.WithString("kind",
+					mcp.Description("Kind of the resource, singular or plural (e.g. 'VirtualService' or 'destinationrules')"),
+					mcp.Required(),
+				),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace of the resource (defaults to 'default')"),
+				),
+				mcp.WithString("name",
+					mcp.Description("Name of the resource"),
+					mcp.Required(),
+				),
+				mcp.WithBoolean("clean",
+					mcp.Description("Strip status and server-populated metadata (managedFields, resourceVersion, uid, creationTimestamp, generation) so the YAML can be re-applied; set to false for the raw object (defaults to true)"),
+				),
+				mcp.WithTitleAnnotation("Istio: Get Resource for Editing"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.getResourceForEditing,
+		},
+		{
+			Tool: mcp.NewTool("diff-against-last-applied",
+				mcp.WithDescription("Compare the spec and labels of a live Istio resource with its kubectl.kubernetes.io/last-applied-configuration annotation and list every field that differs. Supported kinds: "+strings.Join(istio.SupportedResourceKinds(), ", ")+". Use this to reveal manual edits that the next kubectl apply or GitOps sync will revert."),
+				mcp.WithString("kind",
+					mcp.Description("Kind of the resource, singular or plural (e.g. 'VirtualService' or 'destinationrules')"),
+					mcp.Required(),
+				),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace of the resource (defaults to 'default')"),
+				),
+				mcp.WithString("name",
+					mcp.Description("Name of the resource"),
+					mcp.Required(),
+				),
+				mcp.WithTitleAnnotation("Istio: Diff Against Last Applied"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.diffAgainstLastApplied,
+		},
+		{
+			Tool: mcp.NewTool("simulate-deletion",
+				mcp.WithDescription("Preview the impact of deleting an Istio resource without deleting it: which hosts lose routing rules or traffic policies, which VirtualService routes would point to undefined subsets, which workloads lose an authentication or authorization policy and whether their mTLS mode would downgrade from STRICT. Supported kinds: "+strings.Join(istio.SupportedResourceKinds(), ", ")+". Use this before proposing or performing a deletion."),
+				mcp.WithString("kind",
+					mcp.Description("Kind of the resource, singular or plural (e.g. 'PeerAuthentication' or 'virtualservices')"),
+					mcp.Required(),
+				),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace of the resource (defaults to 'default')"),
+				),
+				mcp.WithString("name",
+					mcp.Description("Name of the resource"),
+					mcp.Required(),
+				),
+				mcp.WithTitleAnnotation("Istio: Simulate Deletion"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.simulateDeletion,
+		},
+		{
+			Tool: mcp.NewTool("get-export-scope",
+				mcp.WithDescription("Report the effective exportTo of a VirtualService, DestinationRule or ServiceEntry, including the mesh-wide default when the resource sets none, and list the namespaces that can actually see it. Use this when a resource seems to be ignored by workloads in another namespace."),
+				mcp.WithString("kind",
+					mcp.Description("Kind of the resource: VirtualService, DestinationRule or ServiceEntry (singular or plural)"),
+					mcp.Required(),
+				),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace of the resource (defaults to 'default')"),
+				),
+				mcp.WithString("name",
+					mcp.Description("Name of the resource"),
+					mcp.Required(),
+				),
+				mcp.WithTitleAnnotation("Istio: Export Scope"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.getExportScope,
+		},
 		{
 			Tool: mcp.NewTool("check-external-dependency-availability",
 				mcp.WithDescription("Check if an external dependency (like RDS, S3, etc.) is properly configured and accessible for a specific service. This tool validates that all required Istio resources (Service Entries, Virtual Services, Destination Rules, Authorization Policies) exist and are properly configured to allow the service to access the external dependency."),
@@ -215,6 +634,11 @@ func (s *Server) initConfigurationTools() []server.ServerTool {
 				mcp.WithString("namespace",
 					mcp.Description("Namespace to list services from (defaults to 'default'). Services are the entry points to your applications."),
 				),
+				mcp.WithBoolean("istio-only",
+					mcp.Description("Only list services whose backing pods have the istio-proxy sidecar (defaults to false)"),
+				),
+				withVerbosity(),
+				withFieldSelector(),
 				mcp.WithTitleAnnotation("Kubernetes: Service Discovery"),
 				mcp.WithReadOnlyHintAnnotation(true),
 				mcp.WithDestructiveHintAnnotation(false),
@@ -228,8 +652,13 @@ func (s *Server) initConfigurationTools() []server.ServerTool {
 					mcp.Description("Namespace containing the service (defaults to 'default')"),
 				),
 				mcp.WithString("service",
-					mcp.Description("Service name to find backing pods for (use 'get-services' first to discover available services)"),
-					mcp.Required(),
+					mcp.Description("Service name to find backing pods for (use 'get-services' first to discover available services). Short names resolve in 'namespace'; 'reviews.bookinfo' and 'reviews.bookinfo.svc.cluster.local' select the namespace explicitly. Either 'service' or 'services' is required."),
+				),
+				mcp.WithString("services",
+					mcp.Description("Comma-separated list of service names to find backing pods for at once (e.g. 'productpage,reviews,ratings'). Results are grouped by service."),
+				),
+				mcp.WithBoolean("include-init-containers",
+					mcp.Description("Show the status of each pod's init containers, including istio-init (or istio-validation with the Istio CNI plugin), whose failure keeps the pod from starting (defaults to false)"),
 				),
 				mcp.WithTitleAnnotation("Kubernetes: Service Pod Discovery"),
 				mcp.WithReadOnlyHintAnnotation(true),
@@ -237,6 +666,34 @@ func (s *Server) initConfigurationTools() []server.ServerTool {
 			),
 			Handler: s.getPodsByService,
 		},
+		{
+			Tool: mcp.NewTool("get-top-services",
+				mcp.WithDescription("Rank the services of a namespace by request rate over the last 5 minutes, with the share of 5xx responses of each, from Istio's istio_requests_total metric in Prometheus. Use this to decide which service to investigate first. Requires the server to be started with --prometheus-url."),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace of the services (defaults to 'default')"),
+				),
+				mcp.WithTitleAnnotation("Istio: Top Services"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.getTopServices,
+		},
+		{
+			Tool: mcp.NewTool("get-service-dependencies",
+				mcp.WithDescription("Build a lightweight dependency map of a service without Kiali: upstream services are inferred from the outbound clusters of one of its proxies, downstream callers from the mesh workloads whose proxies have a cluster for the service. Use this to understand what a service talks to and who calls it."),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace of the service (defaults to 'default')"),
+				),
+				mcp.WithString("service",
+					mcp.Description("Name of the Kubernetes service; 'reviews.bookinfo' and 'reviews.bookinfo.svc.cluster.local' select its namespace explicitly"),
+					mcp.Required(),
+				),
+				mcp.WithTitleAnnotation("Istio: Service Dependencies"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.getServiceDependencies,
+		},
 	}
 }
 
@@ -276,31 +733,70 @@ func (s *Server) initProxyConfigTools() []server.ServerTool {
 			Handler: s.getProxyListeners,
 		},
 		{
-			Tool: mcp.NewTool("get-proxy-routes",
-				mcp.WithDescription("Get Envoy route configuration from any Istio proxy pod. Routes define how requests are matched and routed to clusters. Use this for debugging traffic routing and Virtual Service configuration issues."),
+			Tool: mcp.NewTool("explain-listener-filter-chains",
+				mcp.WithDescription("Explain the Envoy filter chains handling a port in an Istio proxy pod. For the listener bound to the port, or the inbound/outbound virtual listener chains matching it, narrates the listener filters (e.g. TLS inspector), the SNI, ALPN, transport protocol and address conditions selecting each filter chain, and the network and HTTP filters it applies (e.g. metadata exchange → HTTP connection manager). Use this to understand why a connection is handled as TLS, plaintext, HTTP or TCP."),
 				mcp.WithString("namespace",
-					mcp.Description("Namespace of the pod (defaults to 'default'). Route configurations reflect Virtual Service rules applied to the pod."),
+					mcp.Description("Namespace of the pod (defaults to 'default')"),
 				),
 				mcp.WithString("pod",
 					mcp.Description("Pod name containing the Istio proxy (sidecar)"),
 					mcp.Required(),
 				),
-				mcp.WithTitleAnnotation("Istio: Proxy Routes"),
+				mcp.WithNumber("port",
+					mcp.Description("Port whose listener and filter chains to explain, e.g. a service port or 15006 for the inbound virtual listener"),
+					mcp.Required(),
+				),
+				mcp.WithTitleAnnotation("Istio: Explain Listener Filter Chains"),
 				mcp.WithReadOnlyHintAnnotation(true),
 				mcp.WithDestructiveHintAnnotation(false),
 			),
-			Handler: s.getProxyRoutes,
+			Handler: s.explainListenerFilterChains,
 		},
 		{
-			Tool: mcp.NewTool("get-proxy-endpoints",
-				mcp.WithDescription("Get Envoy endpoint configuration from any Istio proxy pod. Endpoints represent the actual instances of upstream services. Use this for debugging service discovery and endpoint health issues."),
-				mcp.WithString("namespace",
+			Tool: mcp.NewTool("get-proxy-routes",
+				mcp.WithDescription("Get Envoy route configuration from any Istio proxy pod. Routes define how requests are matched and routed to clusters. Use this for debugging traffic routing and Virtual Service configuration issues."),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace of the pod (defaults to 'default'). Route configurations reflect Virtual Service rules applied to the pod."),
+				),
+				mcp.WithString("pod",
+					mcp.Description("Pod name containing the Istio proxy (sidecar)"),
+					mcp.Required(),
+				),
+				mcp.WithTitleAnnotation("Istio: Proxy Routes"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.getProxyRoutes,
+		},
+		{
+			Tool: mcp.NewTool("list-proxy-routable-hosts",
+				mcp.WithDescription("List the virtual hosts in a proxy's route configuration and the domains each matches, i.e. the HTTP hosts the workload can reach through the mesh, and whether requests to other hosts are passed through or blocked. A quick way to confirm a Sidecar egress restriction took effect: the list should shrink."),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace of the pod (defaults to 'default')"),
+				),
+				mcp.WithString("pod",
+					mcp.Description("Pod name containing the Istio proxy (sidecar)"),
+					mcp.Required(),
+				),
+				mcp.WithTitleAnnotation("Istio: Proxy Routable Hosts"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.listProxyRoutableHosts,
+		},
+		{
+			Tool: mcp.NewTool("get-proxy-endpoints",
+				mcp.WithDescription("Get Envoy endpoint configuration from any Istio proxy pod. Endpoints represent the actual instances of upstream services. Use this for debugging service discovery and endpoint health issues."),
+				mcp.WithString("namespace",
 					mcp.Description("Namespace of the pod (defaults to 'default'). Endpoint configurations show service discovery results."),
 				),
 				mcp.WithString("pod",
 					mcp.Description("Pod name containing the Istio proxy (sidecar)"),
 					mcp.Required(),
 				),
+				mcp.WithString("cluster",
+					mcp.Description("Optional Envoy cluster name to filter endpoints by (e.g. 'outbound|9080||reviews.default.svc.cluster.local'). Use this on proxies with many clusters to isolate one upstream."),
+				),
 				mcp.WithTitleAnnotation("Istio: Proxy Endpoints"),
 				mcp.WithReadOnlyHintAnnotation(true),
 				mcp.WithDestructiveHintAnnotation(false),
@@ -323,9 +819,57 @@ func (s *Server) initProxyConfigTools() []server.ServerTool {
 			),
 			Handler: s.getProxyBootstrap,
 		},
+		{
+			Tool: mcp.NewTool("get-proxy-concurrency",
+				mcp.WithDescription("Report the number of Envoy worker threads (concurrency) of an Istio proxy pod together with the proxy's CPU and memory requests and limits, and warn when concurrency doesn't fit the CPU limit. Use this to tune sidecar performance."),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace of the pod (defaults to 'default')"),
+				),
+				mcp.WithString("pod",
+					mcp.Description("Pod name containing the Istio proxy (sidecar)"),
+					mcp.Required(),
+				),
+				mcp.WithTitleAnnotation("Istio: Proxy Concurrency"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.getProxyConcurrency,
+		},
+		{
+			Tool: mcp.NewTool("get-intercepted-ports",
+				mcp.WithDescription("Report which traffic of a pod is redirected to its Istio sidecar: the inbound container ports captured or bypassed according to the traffic.sidecar.istio.io include/exclude annotations, the outbound IP ranges and ports excluded from capture, and the interception mode from the proxy bootstrap. Use this to explain why some traffic bypasses the mesh (no mTLS, policies or telemetry)."),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace of the pod (defaults to 'default')"),
+				),
+				mcp.WithString("pod",
+					mcp.Description("Pod name containing the Istio proxy (sidecar)"),
+					mcp.Required(),
+				),
+				mcp.WithTitleAnnotation("Istio: Intercepted Ports"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.getInterceptedPorts,
+		},
+		{
+			Tool: mcp.NewTool("get-workload-cert-chain",
+				mcp.WithDescription("Decode the workload certificate chain an Istio proxy presents in mTLS connections: the SPIFFE identity in the SAN, subject, issuer, validity window and chain depth, plus the root CA it trusts. Private key material is never returned. Use this to verify the identity a workload presents or to debug expired or mis-issued certificates."),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace of the pod (defaults to 'default')"),
+				),
+				mcp.WithString("pod",
+					mcp.Description("Pod name containing the Istio proxy (sidecar)"),
+					mcp.Required(),
+				),
+				mcp.WithTitleAnnotation("Istio: Workload Certificate Chain"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.getWorkloadCertChain,
+		},
 		{
 			Tool: mcp.NewTool("get-proxy-config-dump",
-				mcp.WithDescription("Get full Envoy configuration dump from any Istio proxy pod. This provides complete proxy configuration including all listeners, clusters, routes, and endpoints. Use this for comprehensive Istio proxy debugging and troubleshooting."),
+				mcp.WithDescription("Get full Envoy configuration dump from any Istio proxy pod. This provides complete proxy configuration including all listeners, clusters, routes, and endpoints. Large dumps are summarized unless full is set. Use this for comprehensive Istio proxy debugging and troubleshooting."),
 				mcp.WithString("namespace",
 					mcp.Description("Namespace of the pod (defaults to 'default'). Full config dump shows complete proxy state."),
 				),
@@ -333,12 +877,85 @@ func (s *Server) initProxyConfigTools() []server.ServerTool {
 					mcp.Description("Pod name containing the Istio proxy (sidecar)"),
 					mcp.Required(),
 				),
+				mcp.WithString("path",
+					mcp.Description("Optional dot/bracket path to extract only a part of the dump, e.g. 'configs.dynamic_listeners', 'configs.dynamic_listeners[0]' or 'configs.dynamic_active_clusters[outbound|9080||reviews.default.svc.cluster.local]'. Brackets with a name select the array element with that name. Keys applied to an array pick the first element containing the key."),
+				),
+				mcp.WithBoolean("raw",
+					mcp.Description("Return the istioctl JSON output byte-for-byte, freshly fetched without the cache and without any reformatting or summarization, for feeding other tools (defaults to false; cannot be combined with path)"),
+				),
+				mcp.WithBoolean("full",
+					mcp.Description("Return the complete dump even when it is large (defaults to false: dumps larger than 256 KiB are summarized with resource counts and names)"),
+				),
 				mcp.WithTitleAnnotation("Istio: Proxy Config Dump"),
 				mcp.WithReadOnlyHintAnnotation(true),
 				mcp.WithDestructiveHintAnnotation(false),
 			),
 			Handler: s.getProxyConfigDump,
 		},
+		{
+			Tool: mcp.NewTool("get-circuit-breaker-state",
+				mcp.WithDescription("Get the live circuit breaker state of an Istio proxy: which upstream clusters currently have an open circuit breaker (cx_open, rq_open, rq_pending_open, ...) with their configured limits, and which hosts outlier detection has ejected. Reads Envoy's admin /clusters and /stats output, so it shows what is happening now rather than the static Destination Rule configuration. Use this when requests fail fast with 503 UO (upstream overflow) or a subset of endpoints stops receiving traffic."),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace of the pod (defaults to 'default')"),
+				),
+				mcp.WithString("pod",
+					mcp.Description("Pod name containing the Istio proxy (sidecar)"),
+					mcp.Required(),
+				),
+				mcp.WithTitleAnnotation("Istio: Circuit Breaker State"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.getCircuitBreakerState,
+		},
+		{
+			Tool: mcp.NewTool("get-proxy-runtime-stats",
+				mcp.WithDescription("Get the memory use and load of an Istio proxy from Envoy's admin /stats output: tcmalloc allocated, heap and physical memory (the figures of the /memory endpoint), total and active downstream connections, and active downstream and upstream requests. Use this for capacity planning and to spot proxies under memory pressure before they are OOM-killed."),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace of the pod (defaults to 'default')"),
+				),
+				mcp.WithString("pod",
+					mcp.Description("Pod name containing the Istio proxy (sidecar)"),
+					mcp.Required(),
+				),
+				mcp.WithTitleAnnotation("Istio: Proxy Runtime Stats"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.getProxyRuntimeStats,
+		},
+		{
+			Tool: mcp.NewTool("get-ejected-clusters",
+				mcp.WithDescription("List only the upstream clusters of an Istio proxy where outlier detection is currently ejecting endpoints (nonzero outlier_detection.ejections_active or hosts flagged failed_outlier_check), with the ejected host addresses. Reads Envoy's admin /clusters and /stats output. Use this to pinpoint which services are actively failing and losing endpoints."),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace of the pod (defaults to 'default')"),
+				),
+				mcp.WithString("pod",
+					mcp.Description("Pod name containing the Istio proxy (sidecar)"),
+					mcp.Required(),
+				),
+				mcp.WithTitleAnnotation("Istio: Ejected Clusters"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.getEjectedClusters,
+		},
+		{
+			Tool: mcp.NewTool("get-endpoint-health-summary",
+				mcp.WithDescription("Summarize, for each cluster of a pod's proxy, the number of healthy, unhealthy and draining endpoints and their load-balancing weights, flagging clusters where traffic is concentrated on few endpoints. Use this to explain uneven load across the pods of a service."),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace of the pod (defaults to 'default')"),
+				),
+				mcp.WithString("pod",
+					mcp.Description("Pod name containing the Istio proxy (sidecar) sending the traffic"),
+					mcp.Required(),
+				),
+				mcp.WithTitleAnnotation("Istio: Endpoint Health Summary"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.getEndpointHealthSummary,
+		},
 		{
 			Tool: mcp.NewTool("get-proxy-status",
 				mcp.WithDescription("Get proxy status information for all Istio proxies or a specific pod. Shows proxy sync status, configuration version, and connectivity health. Use this to monitor Istio service mesh health and configuration distribution."),
@@ -348,134 +965,783 @@ func (s *Server) initProxyConfigTools() []server.ServerTool {
 				mcp.WithString("pod",
 					mcp.Description("Pod name (optional, if not provided shows all proxies). Use this to check specific proxy sync status."),
 				),
+				mcp.WithString("output",
+					mcp.Description("Output format: 'text' for istioctl's table (default) or 'json' for machine-readable sync state"),
+					mcp.Enum("text", "json"),
+				),
 				mcp.WithTitleAnnotation("Istio: Proxy Status"),
 				mcp.WithReadOnlyHintAnnotation(true),
 				mcp.WithDestructiveHintAnnotation(false),
 			),
 			Handler: s.getProxyStatus,
 		},
+		{
+			Tool: mcp.NewTool("get-config-version-distribution",
+				mcp.WithDescription("Bucket the proxies connected to istiod by the configuration version (push version of the xDS nonce) they last acknowledged, as reported by proxy-status, with the count per version and the proxies behind the latest one. Use this during a rollout to see how far a configuration push has propagated; a long tail on an old version indicates stuck proxies."),
+				mcp.WithTitleAnnotation("Istio: Config Version Distribution"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.getConfigVersionDistribution,
+		},
+		{
+			Tool: mcp.NewTool("compare-proxy-vs-istiod",
+				mcp.WithDescription("Compare the clusters, listeners and routes istiod generates for a pod's proxy with the ones the proxy actually has. Differences reveal configuration pushes the proxy rejected or hasn't received. Use this when a proxy behaves as if recent configuration changes were not applied."),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace of the pod (defaults to 'default')"),
+				),
+				mcp.WithString("pod",
+					mcp.Description("Pod name containing the Istio proxy (sidecar)"),
+					mcp.Required(),
+				),
+				mcp.WithTitleAnnotation("Istio: Compare Proxy vs istiod"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.compareProxyVsIstiod,
+		},
+		{
+			Tool: mcp.NewTool("snapshot-proxy-config",
+				mcp.WithDescription("Record the current clusters, listeners and routes of a pod's proxy in memory and return a snapshot ID. Take a snapshot before applying a configuration change, then pass the ID to diff-proxy-snapshots to confirm the change reached the proxy. Only the most recent snapshots are kept."),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace of the pod (defaults to 'default')"),
+				),
+				mcp.WithString("pod",
+					mcp.Description("Pod name containing the Istio proxy (sidecar)"),
+					mcp.Required(),
+				),
+				mcp.WithTitleAnnotation("Istio: Snapshot Proxy Config"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.snapshotProxyConfig,
+		},
+		{
+			Tool: mcp.NewTool("diff-proxy-snapshots",
+				mcp.WithDescription("Compare the current clusters, listeners and routes of a pod's proxy with a snapshot taken by snapshot-proxy-config and list the resources added, removed and changed since. Resources whose only difference is the push version are unchanged. Use this to confirm a configuration change propagated to the proxy."),
+				mcp.WithString("namespace",
+					mcp.Description("Namespace of the pod (defaults to 'default')"),
+				),
+				mcp.WithString("pod",
+					mcp.Description("Pod name containing the Istio proxy (sidecar)"),
+					mcp.Required(),
+				),
+				mcp.WithString("snapshot",
+					mcp.Description("Snapshot ID returned by snapshot-proxy-config, e.g. 'snapshot-1'"),
+					mcp.Required(),
+				),
+				mcp.WithTitleAnnotation("Istio: Diff Proxy Snapshots"),
+				mcp.WithReadOnlyHintAnnotation(true),
+				mcp.WithDestructiveHintAnnotation(false),
+			),
+			Handler: s.diffProxySnapshots,
+		},
+	}
+}
+
+// withVerbosity adds the verbosity argument shared by the resource listing tools
+func withVerbosity() mcp.ToolOption {
+	return mcp.WithString("verbosity",
+		mcp.Description("How much detail to return: 'compact' lists only names and counts, 'normal' adds the key fields (default), 'detailed' includes the full spec of each resource"),
+		mcp.Enum(istio.VerbosityNames...),
+	)
+}
+
+// withFieldSelector adds the field-selector argument shared by the resource listing tools
+func withFieldSelector() mcp.ToolOption {
+	return mcp.WithString("field-selector",
+		mcp.Description("Kubernetes field selector restricting the listed resources (e.g. 'metadata.name=reviews'). Istio resources can only be selected by metadata.name and metadata.namespace"),
+	)
+}
+
+// fieldSelectorOption converts the field-selector argument of a tool call into a listing option
+func fieldSelectorOption(ctr mcp.CallToolRequest) istio.GetOption {
+	selector, _ := ctr.GetArguments()["field-selector"].(string)
+	return istio.WithFieldSelector(selector)
+}
+
+// cleanOption converts the clean argument of a tool call, which defaults to true, into a rendering option
+func cleanOption(ctr mcp.CallToolRequest) istio.GetOption {
+	clean := true
+	if v, ok := ctr.GetArguments()["clean"].(bool); ok {
+		clean = v
+	}
+	return istio.WithClean(clean)
+}
+
+// verbosityOption converts the verbosity argument of a tool call into a rendering option
+func verbosityOption(ctr mcp.CallToolRequest) (istio.GetOption, error) {
+	name := ""
+	if v := ctr.GetArguments()["verbosity"]; v != nil {
+		name = v.(string)
+	}
+	verbosity, err := istio.ParseVerbosity(name)
+	if err != nil {
+		return nil, err
+	}
+	return istio.WithVerbosity(verbosity), nil
+}
+
+// Handler methods for networking tools
+func (s *Server) getVirtualServices(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	opt, err := verbosityOption(ctr)
+	if err != nil {
+		return NewTextResult("", err), nil
+	}
+	content, err := s.client().GetVirtualServices(ctx, namespace, opt, fieldSelectorOption(ctr))
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) getDestinationRules(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	opt, err := verbosityOption(ctr)
+	if err != nil {
+		return NewTextResult("", err), nil
+	}
+	content, err := s.client().GetDestinationRules(ctx, namespace, opt, fieldSelectorOption(ctr))
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) getEffectiveDestinationRule(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	host := ""
+	if h := ctr.GetArguments()["host"]; h != nil {
+		host = h.(string)
+	}
+	if host == "" {
+		return NewTextResult("", fmt.Errorf("host is required")), nil
+	}
+	namespace := ""
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	content, err := s.client().GetAllDestinationRulesAffecting(ctx, host, namespace)
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) getDestinationRuleBlastRadius(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	name := ""
+	if n := ctr.GetArguments()["name"]; n != nil {
+		name = n.(string)
+	}
+	if name == "" {
+		return NewTextResult("", fmt.Errorf("name is required")), nil
+	}
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	content, err := s.client().GetDestinationRuleBlastRadius(ctx, namespace, name)
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) getLocalityLbConfig(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	host := ""
+	if h := ctr.GetArguments()["host"]; h != nil {
+		host = h.(string)
+	}
+	if host == "" {
+		return NewTextResult("", fmt.Errorf("host is required")), nil
+	}
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	content, err := s.client().GetLocalityLbConfig(ctx, namespace, host)
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) getGateways(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	opt, err := verbosityOption(ctr)
+	if err != nil {
+		return NewTextResult("", err), nil
+	}
+	content, err := s.client().GetGateways(ctx, namespace, opt, fieldSelectorOption(ctr))
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) getIngressGatewayAddress(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := "istio-system"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	content, err := s.client().GetIngressGatewayAddress(ctx, namespace)
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) getGatewayEndpoints(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	content, err := s.client().GetGatewayEndpoints(ctx, namespace)
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) getServiceEntries(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	opt, err := verbosityOption(ctr)
+	if err != nil {
+		return NewTextResult("", err), nil
+	}
+	content, err := s.client().GetServiceEntries(ctx, namespace, opt, fieldSelectorOption(ctr))
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) listExternalHosts(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	content, err := s.client().ListExternalHosts(ctx)
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) getEffectiveOutboundPolicy(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	content, err := s.client().GetEffectiveOutboundPolicy(ctx, namespace)
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) getAppliedSidecarScope(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	podName := ""
+	if pod := ctr.GetArguments()["pod"]; pod != nil {
+		podName = pod.(string)
+	}
+	if podName == "" {
+		return NewTextResult("", fmt.Errorf("pod name is required")), nil
+	}
+	content, err := s.client().GetAppliedSidecarScope(ctx, namespace, podName)
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) getWaypointProxies(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	content, err := s.client().GetWaypointProxies(ctx, namespace)
+	return NewTextResult(content, err), nil
+}
+
+// Handler methods for security tools
+func (s *Server) getAuthorizationPolicies(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	opt, err := verbosityOption(ctr)
+	if err != nil {
+		return NewTextResult("", err), nil
+	}
+	content, err := s.client().GetAuthorizationPolicies(ctx, namespace, opt, fieldSelectorOption(ctr))
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) getPeerAuthentications(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	opt, err := verbosityOption(ctr)
+	if err != nil {
+		return NewTextResult("", err), nil
+	}
+	content, err := s.client().GetPeerAuthentications(ctx, namespace, opt, fieldSelectorOption(ctr))
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) getPeerAuthBlastRadius(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	name := ""
+	if n := ctr.GetArguments()["name"]; n != nil {
+		name = n.(string)
+	}
+	if name == "" {
+		return NewTextResult("", fmt.Errorf("name is required")), nil
+	}
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	content, err := s.client().GetPeerAuthBlastRadius(ctx, namespace, name)
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) getWorkloadIdentity(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	podName := ""
+	if pod := ctr.GetArguments()["pod"]; pod != nil {
+		podName = pod.(string)
+	}
+	if podName == "" {
+		return NewTextResult("", fmt.Errorf("pod name is required")), nil
+	}
+	content, err := s.client().GetWorkloadIdentity(ctx, namespace, podName)
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) findWorkloadsByIdentity(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	spiffeID := ""
+	if id := ctr.GetArguments()["spiffe-id"]; id != nil {
+		spiffeID = id.(string)
+	}
+	if spiffeID == "" {
+		return NewTextResult("", fmt.Errorf("spiffe-id is required")), nil
+	}
+	content, err := s.client().FindWorkloadsByIdentity(ctx, spiffeID)
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) getMeshIdentityConfig(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	content, err := s.client().GetMeshIdentityConfig(ctx)
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) getEffectiveAuthz(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	selector, _ := ctr.GetArguments()["selector"].(string)
+	if selector == "" {
+		return NewTextResult("", fmt.Errorf("selector is required")), nil
+	}
+	content, err := s.client().GetEffectiveAuthzForWorkload(ctx, namespace, selector)
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) getCustomAuthzPolicies(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	content, err := s.client().GetCustomAuthzPolicies(ctx, namespace)
+	return NewTextResult(content, err), nil
+}
+
+// Handler methods for configuration tools
+func (s *Server) getEnvoyFilters(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	opt, err := verbosityOption(ctr)
+	if err != nil {
+		return NewTextResult("", err), nil
+	}
+	content, err := s.client().GetEnvoyFilters(ctx, namespace, opt, fieldSelectorOption(ctr))
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) getEnvoyFiltersForWorkload(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	podName := ""
+	if pod := ctr.GetArguments()["pod"]; pod != nil {
+		podName = pod.(string)
+	}
+	if podName == "" {
+		return NewTextResult("", fmt.Errorf("pod name is required")), nil
+	}
+	content, err := s.client().GetEnvoyFiltersForWorkload(ctx, namespace, podName)
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) getTelemetries(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	opt, err := verbosityOption(ctr)
+	if err != nil {
+		return NewTextResult("", err), nil
+	}
+	content, err := s.client().GetTelemetries(ctx, namespace, opt, fieldSelectorOption(ctr))
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) getIstioConfigSummary(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	content, err := s.client().GetIstioConfigSummary(ctx, namespace)
+	return NewTextResult(content, err), nil
+}
+
+// Handler method for fetching a single named resource
+func (s *Server) getIstioResource(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	kind := ""
+	if k := ctr.GetArguments()["kind"]; k != nil {
+		kind = k.(string)
+	}
+	if kind == "" {
+		return NewTextResult("", fmt.Errorf("kind is required")), nil
+	}
+
+	name := ""
+	if n := ctr.GetArguments()["name"]; n != nil {
+		name = n.(string)
+	}
+	if name == "" {
+		return NewTextResult("", fmt.Errorf("name is required")), nil
+	}
+
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+
+	managedFields, _ := ctr.GetArguments()["include-managed-fields"].(bool)
+	content, err := s.client().GetResource(ctx, kind, namespace, name, istio.WithManagedFields(managedFields), cleanOption(ctr))
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) getResourceForEditing(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	kind := ""
+	if k := ctr.GetArguments()["kind"]; k != nil {
+		kind = k.(string)
+	}
+	name := ""
+	if n := ctr.GetArguments()["name"]; n != nil {
+		name = n.(string)
+	}
+	if kind == "" || name == "" {
+		return NewTextResult("", fmt.Errorf("kind and name are required")), nil
+	}
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
 	}
+	content, err := s.client().GetResourceForEditing(ctx, kind, namespace, name, cleanOption(ctr))
+	return NewTextResult(content, err), nil
 }
 
-// Handler methods for networking tools
-func (s *Server) getVirtualServices(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *Server) diffAgainstLastApplied(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	kind := ""
+	if k := ctr.GetArguments()["kind"]; k != nil {
+		kind = k.(string)
+	}
+	name := ""
+	if n := ctr.GetArguments()["name"]; n != nil {
+		name = n.(string)
+	}
+	if kind == "" || name == "" {
+		return NewTextResult("", fmt.Errorf("kind and name are required")), nil
+	}
 	namespace := "default"
 	if ns := ctr.GetArguments()["namespace"]; ns != nil {
 		namespace = ns.(string)
 	}
-	content, err := s.i.GetVirtualServices(ctx, namespace)
+	content, err := s.client().DiffAgainstLastApplied(ctx, kind, namespace, name)
 	return NewTextResult(content, err), nil
 }
 
-func (s *Server) getDestinationRules(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *Server) simulateDeletion(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	kind := ""
+	if k := ctr.GetArguments()["kind"]; k != nil {
+		kind = k.(string)
+	}
+	name := ""
+	if n := ctr.GetArguments()["name"]; n != nil {
+		name = n.(string)
+	}
+	if kind == "" || name == "" {
+		return NewTextResult("", fmt.Errorf("kind and name are required")), nil
+	}
 	namespace := "default"
 	if ns := ctr.GetArguments()["namespace"]; ns != nil {
 		namespace = ns.(string)
 	}
-	content, err := s.i.GetDestinationRules(ctx, namespace)
+	content, err := s.client().SimulateResourceDeletion(ctx, kind, namespace, name)
 	return NewTextResult(content, err), nil
 }
 
-func (s *Server) getGateways(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *Server) getExportScope(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	kind := ""
+	if k := ctr.GetArguments()["kind"]; k != nil {
+		kind = k.(string)
+	}
+	name := ""
+	if n := ctr.GetArguments()["name"]; n != nil {
+		name = n.(string)
+	}
+	if kind == "" || name == "" {
+		return NewTextResult("", fmt.Errorf("kind and name are required")), nil
+	}
 	namespace := "default"
 	if ns := ctr.GetArguments()["namespace"]; ns != nil {
 		namespace = ns.(string)
 	}
-	content, err := s.i.GetGateways(ctx, namespace)
+	content, err := s.client().GetResourceExportScope(ctx, kind, namespace, name)
 	return NewTextResult(content, err), nil
 }
 
-func (s *Server) getServiceEntries(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+// Handler method for external dependency availability check
+func (s *Server) checkExternalDependencyAvailability(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	serviceName := ""
+	if service := ctr.GetArguments()["service-name"]; service != nil {
+		serviceName = service.(string)
+	}
+	if serviceName == "" {
+		return NewTextResult("", fmt.Errorf("service-name is required")), nil
+	}
+
+	externalHost := ""
+	if host := ctr.GetArguments()["external-host"]; host != nil {
+		externalHost = host.(string)
+	}
+	if externalHost == "" {
+		return NewTextResult("", fmt.Errorf("external-host is required")), nil
+	}
+
 	namespace := "default"
 	if ns := ctr.GetArguments()["namespace"]; ns != nil {
 		namespace = ns.(string)
 	}
-	content, err := s.i.GetServiceEntries(ctx, namespace)
+
+	content, err := s.client().CheckExternalDependencyAvailability(ctx, serviceName, externalHost, namespace)
 	return NewTextResult(content, err), nil
 }
 
-// Handler methods for security tools
-func (s *Server) getAuthorizationPolicies(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+// Handler method for Istio namespace discovery
+func (s *Server) discoverIstioNamespaces(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if format := ctr.GetArguments()["format"]; format != nil && format.(string) == "json" {
+		content, err := s.client().DiscoverNamespacesWithSidecarsJSON(ctx)
+		return NewTextResult(content, err), nil
+	}
+	content, err := s.client().DiscoverNamespacesWithSidecars(ctx)
+	return NewTextResult(content, err), nil
+}
+
+// Handler method for server diagnostics
+func (s *Server) getXdsPushStats(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	content, err := s.client().GetPushStats(ctx)
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) getIstiodConnectionDistribution(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	content, err := s.client().GetIstiodConnectionDistribution(ctx)
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) getIstiodLogsForProxy(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	proxy := ""
+	if p := ctr.GetArguments()["proxy"]; p != nil {
+		proxy = p.(string)
+	}
+	if proxy == "" {
+		return NewTextResult("", fmt.Errorf("proxy is required")), nil
+	}
+	content, err := s.client().GetIstiodLogsForProxy(ctx, proxy)
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) getInjectionImage(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	namespace := "default"
 	if ns := ctr.GetArguments()["namespace"]; ns != nil {
 		namespace = ns.(string)
 	}
-	content, err := s.i.GetAuthorizationPolicies(ctx, namespace)
+	content, err := s.client().GetInjectionImage(ctx, namespace)
 	return NewTextResult(content, err), nil
 }
 
-func (s *Server) getPeerAuthentications(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *Server) getResourcesByRevision(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	revision := ""
+	if r := ctr.GetArguments()["revision"]; r != nil {
+		revision = r.(string)
+	}
+	if revision == "" {
+		return NewTextResult("", fmt.Errorf("revision is required")), nil
+	}
+	content, err := s.client().GetResourcesByRevision(ctx, revision)
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) diagnoseMcpServer(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	namespace := "default"
 	if ns := ctr.GetArguments()["namespace"]; ns != nil {
 		namespace = ns.(string)
 	}
-	content, err := s.i.GetPeerAuthentications(ctx, namespace)
+	content, err := s.client().Diagnose(ctx, namespace)
 	return NewTextResult(content, err), nil
 }
 
-// Handler methods for configuration tools
-func (s *Server) getEnvoyFilters(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+// Handler methods for proxy configuration tools
+func (s *Server) getProxyClusters(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	namespace := "default"
 	if ns := ctr.GetArguments()["namespace"]; ns != nil {
 		namespace = ns.(string)
 	}
-	content, err := s.i.GetEnvoyFilters(ctx, namespace)
+	podName := ""
+	if pod := ctr.GetArguments()["pod"]; pod != nil {
+		podName = pod.(string)
+	}
+	if podName == "" {
+		return NewTextResult("", fmt.Errorf("pod name is required")), nil
+	}
+	content, err := s.client().ProxyConfig.GetClusters(ctx, namespace, podName)
 	return NewTextResult(content, err), nil
 }
 
-func (s *Server) getTelemetries(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *Server) getProxyListeners(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	namespace := "default"
 	if ns := ctr.GetArguments()["namespace"]; ns != nil {
 		namespace = ns.(string)
 	}
-	content, err := s.i.GetTelemetries(ctx, namespace)
+	podName := ""
+	if pod := ctr.GetArguments()["pod"]; pod != nil {
+		podName = pod.(string)
+	}
+	if podName == "" {
+		return NewTextResult("", fmt.Errorf("pod name is required")), nil
+	}
+	content, err := s.client().ProxyConfig.GetListeners(ctx, namespace, podName)
 	return NewTextResult(content, err), nil
 }
 
-func (s *Server) getIstioConfigSummary(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *Server) explainListenerFilterChains(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	namespace := "default"
 	if ns := ctr.GetArguments()["namespace"]; ns != nil {
 		namespace = ns.(string)
 	}
-	content, err := s.i.GetIstioConfigSummary(ctx, namespace)
+	podName := ""
+	if pod := ctr.GetArguments()["pod"]; pod != nil {
+		podName = pod.(string)
+	}
+	if podName == "" {
+		return NewTextResult("", fmt.Errorf("pod name is required")), nil
+	}
+	port := ctr.GetInt("port", 0)
+	if port <= 0 {
+		return NewTextResult("", fmt.Errorf("port is required")), nil
+	}
+	content, err := s.client().ProxyConfig.ExplainListenerFilterChains(ctx, namespace, podName, port)
 	return NewTextResult(content, err), nil
 }
 
-// Handler method for external dependency availability check
-func (s *Server) checkExternalDependencyAvailability(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	serviceName := ""
-	if service := ctr.GetArguments()["service-name"]; service != nil {
-		serviceName = service.(string)
+func (s *Server) getProxyRoutes(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
 	}
-	if serviceName == "" {
-		return NewTextResult("", fmt.Errorf("service-name is required")), nil
+	podName := ""
+	if pod := ctr.GetArguments()["pod"]; pod != nil {
+		podName = pod.(string)
+	}
+	if podName == "" {
+		return NewTextResult("", fmt.Errorf("pod name is required")), nil
 	}
+	content, err := s.client().ProxyConfig.GetRoutes(ctx, namespace, podName)
+	return NewTextResult(content, err), nil
+}
 
-	externalHost := ""
-	if host := ctr.GetArguments()["external-host"]; host != nil {
-		externalHost = host.(string)
+func (s *Server) listProxyRoutableHosts(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
 	}
-	if externalHost == "" {
-		return NewTextResult("", fmt.Errorf("external-host is required")), nil
+	podName := ""
+	if pod := ctr.GetArguments()["pod"]; pod != nil {
+		podName = pod.(string)
+	}
+	if podName == "" {
+		return NewTextResult("", fmt.Errorf("pod name is required")), nil
+	}
+	content, err := s.client().ProxyConfig.ListProxyRoutableHosts(ctx, namespace, podName)
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) getProxyEndpoints(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	podName := ""
+	if pod := ctr.GetArguments()["pod"]; pod != nil {
+		podName = pod.(string)
 	}
+	if podName == "" {
+		return NewTextResult("", fmt.Errorf("pod name is required")), nil
+	}
+	cluster := ""
+	if c := ctr.GetArguments()["cluster"]; c != nil {
+		cluster = c.(string)
+	}
+	content, err := s.client().ProxyConfig.GetEndpointsFiltered(ctx, namespace, podName, cluster)
+	return NewTextResult(content, err), nil
+}
 
+func (s *Server) getProxyBootstrap(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	namespace := "default"
 	if ns := ctr.GetArguments()["namespace"]; ns != nil {
 		namespace = ns.(string)
 	}
+	podName := ""
+	if pod := ctr.GetArguments()["pod"]; pod != nil {
+		podName = pod.(string)
+	}
+	if podName == "" {
+		return NewTextResult("", fmt.Errorf("pod name is required")), nil
+	}
+	content, err := s.client().ProxyConfig.GetBootstrap(ctx, namespace, podName)
+	return NewTextResult(content, err), nil
+}
 
-	content, err := s.i.CheckExternalDependencyAvailability(ctx, serviceName, externalHost, namespace)
+func (s *Server) getProxyConcurrency(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	podName := ""
+	if pod := ctr.GetArguments()["pod"]; pod != nil {
+		podName = pod.(string)
+	}
+	if podName == "" {
+		return NewTextResult("", fmt.Errorf("pod name is required")), nil
+	}
+	content, err := s.client().GetProxyConcurrency(ctx, namespace, podName)
 	return NewTextResult(content, err), nil
 }
 
-// Handler method for Istio namespace discovery
-func (s *Server) discoverIstioNamespaces(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	content, err := s.i.DiscoverNamespacesWithSidecars(ctx)
+func (s *Server) getInterceptedPorts(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	podName := ""
+	if pod := ctr.GetArguments()["pod"]; pod != nil {
+		podName = pod.(string)
+	}
+	if podName == "" {
+		return NewTextResult("", fmt.Errorf("pod name is required")), nil
+	}
+	content, err := s.client().GetInterceptedPorts(ctx, namespace, podName)
 	return NewTextResult(content, err), nil
 }
 
-// Handler methods for proxy configuration tools
-func (s *Server) getProxyClusters(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *Server) getWorkloadCertChain(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	namespace := "default"
 	if ns := ctr.GetArguments()["namespace"]; ns != nil {
 		namespace = ns.(string)
@@ -487,11 +1753,11 @@ func (s *Server) getProxyClusters(ctx context.Context, ctr mcp.CallToolRequest)
 	if podName == "" {
 		return NewTextResult("", fmt.Errorf("pod name is required")), nil
 	}
-	content, err := s.i.ProxyConfig.GetClusters(ctx, namespace, podName)
+	content, err := s.client().ProxyConfig.GetWorkloadCertChain(ctx, namespace, podName)
 	return NewTextResult(content, err), nil
 }
 
-func (s *Server) getProxyListeners(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *Server) getProxyConfigDump(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	namespace := "default"
 	if ns := ctr.GetArguments()["namespace"]; ns != nil {
 		namespace = ns.(string)
@@ -503,11 +1769,27 @@ func (s *Server) getProxyListeners(ctx context.Context, ctr mcp.CallToolRequest)
 	if podName == "" {
 		return NewTextResult("", fmt.Errorf("pod name is required")), nil
 	}
-	content, err := s.i.ProxyConfig.GetListeners(ctx, namespace, podName)
+	path, _ := ctr.GetArguments()["path"].(string)
+	if raw, _ := ctr.GetArguments()["raw"].(bool); raw {
+		if path != "" {
+			return NewTextResult("", fmt.Errorf("raw and path cannot be combined")), nil
+		}
+		content, err := s.client().ProxyConfig.GetConfigDumpRaw(ctx, namespace, podName)
+		return NewTextResult(content, err), nil
+	}
+	if path != "" {
+		content, err := s.client().ProxyConfig.GetConfigDumpPath(ctx, namespace, podName, path)
+		return NewTextResult(content, err), nil
+	}
+	if full, _ := ctr.GetArguments()["full"].(bool); full {
+		content, err := s.client().ProxyConfig.GetConfigDump(ctx, namespace, podName)
+		return NewTextResult(content, err), nil
+	}
+	content, err := s.client().ProxyConfig.GetConfigDumpLimited(ctx, namespace, podName)
 	return NewTextResult(content, err), nil
 }
 
-func (s *Server) getProxyRoutes(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *Server) getCircuitBreakerState(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	namespace := "default"
 	if ns := ctr.GetArguments()["namespace"]; ns != nil {
 		namespace = ns.(string)
@@ -519,11 +1801,11 @@ func (s *Server) getProxyRoutes(ctx context.Context, ctr mcp.CallToolRequest) (*
 	if podName == "" {
 		return NewTextResult("", fmt.Errorf("pod name is required")), nil
 	}
-	content, err := s.i.ProxyConfig.GetRoutes(ctx, namespace, podName)
+	content, err := s.client().ProxyConfig.GetCircuitBreakerState(ctx, namespace, podName)
 	return NewTextResult(content, err), nil
 }
 
-func (s *Server) getProxyEndpoints(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *Server) getProxyRuntimeStats(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	namespace := "default"
 	if ns := ctr.GetArguments()["namespace"]; ns != nil {
 		namespace = ns.(string)
@@ -535,11 +1817,11 @@ func (s *Server) getProxyEndpoints(ctx context.Context, ctr mcp.CallToolRequest)
 	if podName == "" {
 		return NewTextResult("", fmt.Errorf("pod name is required")), nil
 	}
-	content, err := s.i.ProxyConfig.GetEndpoints(ctx, namespace, podName)
+	content, err := s.client().ProxyConfig.GetProxyRuntimeStats(ctx, namespace, podName)
 	return NewTextResult(content, err), nil
 }
 
-func (s *Server) getProxyBootstrap(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *Server) getEjectedClusters(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	namespace := "default"
 	if ns := ctr.GetArguments()["namespace"]; ns != nil {
 		namespace = ns.(string)
@@ -551,11 +1833,11 @@ func (s *Server) getProxyBootstrap(ctx context.Context, ctr mcp.CallToolRequest)
 	if podName == "" {
 		return NewTextResult("", fmt.Errorf("pod name is required")), nil
 	}
-	content, err := s.i.ProxyConfig.GetBootstrap(ctx, namespace, podName)
+	content, err := s.client().ProxyConfig.GetEjectedClusters(ctx, namespace, podName)
 	return NewTextResult(content, err), nil
 }
 
-func (s *Server) getProxyConfigDump(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func (s *Server) getEndpointHealthSummary(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	namespace := "default"
 	if ns := ctr.GetArguments()["namespace"]; ns != nil {
 		namespace = ns.(string)
@@ -567,7 +1849,7 @@ func (s *Server) getProxyConfigDump(ctx context.Context, ctr mcp.CallToolRequest
 	if podName == "" {
 		return NewTextResult("", fmt.Errorf("pod name is required")), nil
 	}
-	content, err := s.i.ProxyConfig.GetConfigDump(ctx, namespace, podName)
+	content, err := s.client().ProxyConfig.GetEndpointHealthSummary(ctx, namespace, podName)
 	return NewTextResult(content, err), nil
 }
 
@@ -584,14 +1866,79 @@ func (s *Server) getProxyStatus(ctx context.Context, ctr mcp.CallToolRequest) (*
 	var content string
 	var err error
 
+	if output := ctr.GetArguments()["output"]; output != nil && output.(string) == "json" {
+		if namespace == "" {
+			podName = ""
+		}
+		content, err = s.client().ProxyConfig.GetProxyStatusJSON(ctx, namespace, podName)
+		return NewTextResult(content, err), nil
+	}
+
 	if podName != "" && namespace != "" {
 		// Get status for specific pod
-		content, err = s.i.ProxyConfig.GetProxyStatusForPod(ctx, namespace, podName)
+		content, err = s.client().ProxyConfig.GetProxyStatusForPod(ctx, namespace, podName)
 	} else {
 		// Get status for all proxies
-		content, err = s.i.ProxyConfig.GetProxyStatus(ctx)
+		content, err = s.client().ProxyConfig.GetProxyStatus(ctx)
+	}
+
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) getConfigVersionDistribution(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	content, err := s.client().GetConfigVersionDistribution(ctx)
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) compareProxyVsIstiod(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	podName := ""
+	if pod := ctr.GetArguments()["pod"]; pod != nil {
+		podName = pod.(string)
+	}
+	if podName == "" {
+		return NewTextResult("", fmt.Errorf("pod name is required")), nil
+	}
+	content, err := s.client().ProxyConfig.CompareProxyVsIstiod(ctx, namespace, podName)
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) snapshotProxyConfig(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
 	}
+	podName := ""
+	if pod := ctr.GetArguments()["pod"]; pod != nil {
+		podName = pod.(string)
+	}
+	if podName == "" {
+		return NewTextResult("", fmt.Errorf("pod name is required")), nil
+	}
+	content, err := s.client().ProxyConfig.SnapshotProxyConfig(ctx, namespace, podName)
+	return NewTextResult(content, err), nil
+}
 
+func (s *Server) diffProxySnapshots(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	podName := ""
+	if pod := ctr.GetArguments()["pod"]; pod != nil {
+		podName = pod.(string)
+	}
+	if podName == "" {
+		return NewTextResult("", fmt.Errorf("pod name is required")), nil
+	}
+	snapshot, _ := ctr.GetArguments()["snapshot"].(string)
+	if snapshot == "" {
+		return NewTextResult("", fmt.Errorf("snapshot is required")), nil
+	}
+	content, err := s.client().ProxyConfig.DiffProxySnapshots(ctx, namespace, podName, snapshot)
 	return NewTextResult(content, err), nil
 }
 
@@ -601,7 +1948,15 @@ func (s *Server) getServices(ctx context.Context, ctr mcp.CallToolRequest) (*mcp
 	if ns := ctr.GetArguments()["namespace"]; ns != nil {
 		namespace = ns.(string)
 	}
-	content, err := s.i.GetServices(ctx, namespace)
+	opt, err := verbosityOption(ctr)
+	if err != nil {
+		return NewTextResult("", err), nil
+	}
+	istioOnly := false
+	if v, ok := ctr.GetArguments()["istio-only"].(bool); ok {
+		istioOnly = v
+	}
+	content, err := s.client().GetServices(ctx, namespace, opt, fieldSelectorOption(ctr), istio.WithIstioOnly(istioOnly))
 	return NewTextResult(content, err), nil
 }
 
@@ -610,6 +1965,18 @@ func (s *Server) getPodsByService(ctx context.Context, ctr mcp.CallToolRequest)
 	if ns := ctr.GetArguments()["namespace"]; ns != nil {
 		namespace = ns.(string)
 	}
+	initContainers, _ := ctr.GetArguments()["include-init-containers"].(bool)
+	opt := istio.WithInitContainers(initContainers)
+	if svcs := ctr.GetArguments()["services"]; svcs != nil && svcs.(string) != "" {
+		var serviceNames []string
+		for _, name := range strings.Split(svcs.(string), ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				serviceNames = append(serviceNames, name)
+			}
+		}
+		content, err := s.client().GetPodsByServices(ctx, namespace, serviceNames, opt)
+		return NewTextResult(content, err), nil
+	}
 	serviceName := ""
 	if svc := ctr.GetArguments()["service"]; svc != nil {
 		serviceName = svc.(string)
@@ -617,7 +1984,29 @@ func (s *Server) getPodsByService(ctx context.Context, ctr mcp.CallToolRequest)
 	if serviceName == "" {
 		return NewTextResult("", fmt.Errorf("service name is required - use 'get-services' first to discover available services")), nil
 	}
-	content, err := s.i.GetPodsByService(ctx, namespace, serviceName)
+	content, err := s.client().GetPodsByService(ctx, namespace, serviceName, opt)
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) getTopServices(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	content, err := s.client().GetTopServices(ctx, namespace)
+	return NewTextResult(content, err), nil
+}
+
+func (s *Server) getServiceDependencies(ctx context.Context, ctr mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := "default"
+	if ns := ctr.GetArguments()["namespace"]; ns != nil {
+		namespace = ns.(string)
+	}
+	service, _ := ctr.GetArguments()["service"].(string)
+	if service == "" {
+		return NewTextResult("", fmt.Errorf("service is required")), nil
+	}
+	content, err := s.client().GetServiceDependencies(ctx, namespace, service)
 	return NewTextResult(content, err), nil
 }
 
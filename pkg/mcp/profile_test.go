@@ -2,6 +2,8 @@ package mcp
 
 import (
 	"context"
+	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/mark3labs/mcp-go/mcp"
@@ -307,3 +309,58 @@ func TestToolParameters(t *testing.T) {
 		})
 	})
 }
+
+// TestYamlToolsClean tests that the YAML-exporting tools strip server-populated fields unless clean is false
+func TestYamlToolsClean(t *testing.T) {
+	mockServer := NewMockServer()
+	defer mockServer.Close()
+	mockServer.Handle(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch req.URL.Path {
+		case "/apis/networking.istio.io/v1alpha3/namespaces/bookinfo/virtualservices/reviews":
+			w.Write([]byte(`{"apiVersion": "networking.istio.io/v1alpha3", "kind": "VirtualService",
+				"metadata": {"name": "reviews", "namespace": "bookinfo", "resourceVersion": "4242", "uid": "6f1c2a9e-1d3b-4c5f-9a7e-2b8d0e4f6a1c", "creationTimestamp": "2024-01-01T00:00:00Z"},
+				"spec": {"hosts": ["reviews"]},
+				"status": {"observedGeneration": 3}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"kind": "Status", "apiVersion": "v1", "status": "Failure", "reason": "NotFound", "code": 404}`))
+		}
+	}))
+
+	testCaseWithContext(t, &mcpContext{before: func(c *mcpContext) {
+		c.withKubeConfig(mockServer.config)
+	}}, func(c *mcpContext) {
+		server, err := NewServer(Configuration{Profile: &FullProfile{}, Kubeconfig: c.kubeconfigPath})
+		if err != nil {
+			t.Fatalf("Failed to create server: %v", err)
+		}
+		defer server.Close()
+
+		serverFields := []string{"resourceVersion: \"4242\"", "uid: 6f1c2a9e-1d3b-4c5f-9a7e-2b8d0e4f6a1c", "creationTimestamp:", "status:"}
+		for _, tool := range []string{"get-istio-resource", "get-resource-for-editing"} {
+			for _, clean := range []string{"", `, "clean": true`, `, "clean": false`} {
+				t.Run(tool+clean, func(t *testing.T) {
+					response := server.server.HandleMessage(c.ctx, []byte(`{"jsonrpc": "2.0", "id": 1, "method": "tools/call", "params": {"name": "`+tool+`", "arguments": {"kind": "VirtualService", "namespace": "bookinfo", "name": "reviews"`+clean+`}}}`))
+					result, ok := response.(mcp.JSONRPCResponse)
+					if !ok {
+						t.Fatalf("Expected a JSON-RPC response, got %T: %v", response, response)
+					}
+					callResult := result.Result.(mcp.CallToolResult)
+					if callResult.IsError {
+						t.Fatalf("Unexpected error: %v", callResult.Content)
+					}
+					text := callResult.Content[0].(mcp.TextContent).Text
+					if !strings.Contains(text, "name: reviews") || !strings.Contains(text, "- reviews") {
+						t.Errorf("Expected the resource in the output, got: %s", text)
+					}
+					for _, field := range serverFields {
+						if present := strings.Contains(text, field); present != (clean == `, "clean": false`) {
+							t.Errorf("Expected '%s' present=%t, got: %s", field, !present, text)
+						}
+					}
+				})
+			}
+		}
+	})
+}
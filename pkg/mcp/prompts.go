@@ -0,0 +1,107 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// workflowPrompt describes a guided troubleshooting workflow that chains the server tools.
+// Template placeholders in the form {argument} are replaced with the prompt arguments,
+// or left as <argument> when the client doesn't provide them.
+type workflowPrompt struct {
+	name        string
+	description string
+	arguments   []mcp.PromptArgument
+	template    string
+}
+
+var workflowPrompts = []workflowPrompt{
+	{
+		name:        "debug-503-errors",
+		description: "Step-by-step workflow to find the cause of HTTP 503 (no healthy upstream / upstream connect error) responses for a service",
+		arguments: []mcp.PromptArgument{
+			{Name: "namespace", Description: "Namespace of the failing service", Required: true},
+			{Name: "service", Description: "Name of the service returning 503 errors", Required: true},
+		},
+		template: `Investigate why requests to service '{service}' in namespace '{namespace}' return HTTP 503 errors. Follow these steps, using the results of each to decide whether to continue:
+
+1. Run 'get-pods-by-service' with namespace '{namespace}' and service '{service}' to check that ready pods with an Istio sidecar back the service.
+2. Run 'analyze-service-ports' for namespace '{namespace}' to rule out port naming issues that break protocol detection.
+3. Run 'get-virtual-services' and 'get-destination-rules' for namespace '{namespace}'. Check that every subset referenced by a route is defined by a Destination Rule, and look for aggressive outlier detection or connection pool limits.
+4. Pick a client pod and run 'get-proxy-status' to confirm its configuration is SYNCED with istiod.
+5. Run 'get-proxy-clusters' and 'get-proxy-endpoints' on that pod and verify the cluster for '{service}.{namespace}.svc.cluster.local' exists and has healthy endpoints.
+6. Run 'get-peer-authentications' and 'audit-tls-origination' for namespace '{namespace}' to detect mTLS mode mismatches between client and server.
+
+Summarize the most likely root cause and the configuration change that would fix it.`,
+	},
+	{
+		name:        "audit-mesh-security",
+		description: "Workflow to review the mTLS and authorization posture of a namespace",
+		arguments: []mcp.PromptArgument{
+			{Name: "namespace", Description: "Namespace to audit", Required: true},
+		},
+		template: `Audit the security posture of namespace '{namespace}' in the Istio service mesh:
+
+1. Run 'get-peer-authentications' for namespace '{namespace}' and for the mesh root namespace (usually 'istio-system'). Determine whether mTLS is STRICT, PERMISSIVE, or DISABLE for each workload.
+2. Run 'get-authorization-policies' for namespace '{namespace}'. Note workloads without any policy, ALLOW policies with empty rules (allow everything), and DENY policies.
+3. For each principal referenced by a policy, use 'find-workloads-by-identity' to confirm it maps to real workloads.
+4. Run 'audit-tls-origination' for namespace '{namespace}' to check TLS to external services.
+
+Report the findings grouped by severity, with a recommended fix for each.`,
+	},
+	{
+		name:        "check-external-access",
+		description: "Workflow to verify that a workload can reach an external host through the mesh",
+		arguments: []mcp.PromptArgument{
+			{Name: "namespace", Description: "Namespace of the calling service", Required: true},
+			{Name: "service", Description: "Name of the calling service", Required: true},
+			{Name: "host", Description: "External hostname to reach (e.g. 'api.example.com')", Required: true},
+		},
+		template: `Verify that service '{service}' in namespace '{namespace}' can reach the external host '{host}':
+
+1. Run 'check-external-dependency-availability' with service-name '{service}', external-host '{host}' and namespace '{namespace}'.
+2. If a Service Entry exists, run 'audit-tls-origination' for namespace '{namespace}' to check TLS settings for '{host}'.
+3. Run 'get-pods-by-service' for '{service}', then 'get-proxy-clusters' on one of its pods and confirm an outbound cluster for '{host}' exists.
+
+Explain whether the host is reachable and list any missing configuration.`,
+	},
+}
+
+// initPrompts initializes the guided workflow prompts
+func (s *Server) initPrompts() []server.ServerPrompt {
+	prompts := make([]server.ServerPrompt, 0, len(workflowPrompts))
+	for _, wp := range workflowPrompts {
+		options := []mcp.PromptOption{mcp.WithPromptDescription(wp.description)}
+		for _, argument := range wp.arguments {
+			argumentOptions := []mcp.ArgumentOption{mcp.ArgumentDescription(argument.Description)}
+			if argument.Required {
+				argumentOptions = append(argumentOptions, mcp.RequiredArgument())
+			}
+			options = append(options, mcp.WithArgument(argument.Name, argumentOptions...))
+		}
+		prompts = append(prompts, server.ServerPrompt{
+			Prompt:  mcp.NewPrompt(wp.name, options...),
+			Handler: wp.handle,
+		})
+	}
+	return prompts
+}
+
+// handle renders the workflow template with the request arguments
+func (wp workflowPrompt) handle(_ context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	replacements := make([]string, 0, len(wp.arguments)*2)
+	for _, argument := range wp.arguments {
+		value := request.Params.Arguments[argument.Name]
+		if value == "" {
+			value = "<" + argument.Name + ">"
+		}
+		replacements = append(replacements, "{"+argument.Name+"}", value)
+	}
+	text := strings.NewReplacer(replacements...).Replace(wp.template)
+	return mcp.NewGetPromptResult(wp.description, []mcp.PromptMessage{
+		mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(text)),
+	}), nil
+}
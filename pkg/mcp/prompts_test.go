@@ -0,0 +1,70 @@
+package mcp
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// TestPrompts tests that the workflow prompts are registered and retrievable through the MCP protocol
+func TestPrompts(t *testing.T) {
+	testCase(t, func(c *mcpContext) {
+		if err := c.setupMCPServer(); err != nil {
+			t.Fatalf("Failed to setup MCP server: %v", err)
+		}
+		defer c.server.Close()
+
+		t.Run("prompts are listed", func(t *testing.T) {
+			response := c.server.server.HandleMessage(c.ctx, json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"prompts/list"}`))
+			rpcResponse, ok := response.(mcp.JSONRPCResponse)
+			if !ok {
+				t.Fatalf("Expected JSON-RPC response, got %#v", response)
+			}
+			result, ok := rpcResponse.Result.(mcp.ListPromptsResult)
+			if !ok {
+				t.Fatalf("Expected ListPromptsResult, got %#v", rpcResponse.Result)
+			}
+			names := make(map[string]bool)
+			for _, prompt := range result.Prompts {
+				names[prompt.Name] = true
+			}
+			for _, expected := range []string{"debug-503-errors", "audit-mesh-security", "check-external-access"} {
+				if !names[expected] {
+					t.Errorf("Expected prompt '%s' to be registered, got %v", expected, names)
+				}
+			}
+		})
+
+		t.Run("prompt is rendered with arguments", func(t *testing.T) {
+			response := c.server.server.HandleMessage(c.ctx, json.RawMessage(`{"jsonrpc":"2.0","id":2,"method":"prompts/get","params":{"name":"debug-503-errors","arguments":{"namespace":"bookinfo","service":"reviews"}}}`))
+			rpcResponse, ok := response.(mcp.JSONRPCResponse)
+			if !ok {
+				t.Fatalf("Expected JSON-RPC response, got %#v", response)
+			}
+			result, ok := rpcResponse.Result.(mcp.GetPromptResult)
+			if !ok {
+				t.Fatalf("Expected GetPromptResult, got %#v", rpcResponse.Result)
+			}
+			if len(result.Messages) != 1 {
+				t.Fatalf("Expected 1 prompt message, got %d", len(result.Messages))
+			}
+			text := result.Messages[0].Content.(mcp.TextContent).Text
+			for _, expected := range []string{"service 'reviews' in namespace 'bookinfo'", "get-pods-by-service", "reviews.bookinfo.svc.cluster.local"} {
+				if !strings.Contains(text, expected) {
+					t.Errorf("Expected prompt to contain '%s', got: %s", expected, text)
+				}
+			}
+		})
+
+		t.Run("missing arguments are left as placeholders", func(t *testing.T) {
+			response := c.server.server.HandleMessage(c.ctx, json.RawMessage(`{"jsonrpc":"2.0","id":3,"method":"prompts/get","params":{"name":"audit-mesh-security"}}`))
+			result := response.(mcp.JSONRPCResponse).Result.(mcp.GetPromptResult)
+			text := result.Messages[0].Content.(mcp.TextContent).Text
+			if !strings.Contains(text, "namespace '<namespace>'") {
+				t.Errorf("Expected placeholder for missing namespace, got: %s", text)
+			}
+		})
+	})
+}
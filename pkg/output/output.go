@@ -110,6 +110,30 @@ func (p *jsonOutput) PrintObj(obj interface{}) (string, error) {
 	return string(ret), nil
 }
 
+// ServerMetadataFields lists the metadata fields populated by the API server.
+// They are rejected or ignored when re-applying a resource, so they are stripped from cleaned output.
+var ServerMetadataFields = []string{"managedFields", "resourceVersion", "uid", "creationTimestamp", "generation"}
+
+// Clean returns a copy of the given object without server-populated metadata and status,
+// so that the serialized output can be re-applied to a cluster (e.g. when exporting for GitOps).
+func Clean(obj interface{}) (map[string]interface{}, error) {
+	raw, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	var cleaned map[string]interface{}
+	if err := json.Unmarshal(raw, &cleaned); err != nil {
+		return nil, fmt.Errorf("object is not a resource: %w", err)
+	}
+	if metadata, ok := cleaned["metadata"].(map[string]interface{}); ok {
+		for _, field := range ServerMetadataFields {
+			delete(metadata, field)
+		}
+	}
+	delete(cleaned, "status")
+	return cleaned, nil
+}
+
 // init initializes the output format names
 func init() {
 	Names = make([]string, 0)
@@ -351,3 +351,75 @@ func TestComplexObjects(t *testing.T) {
 		}
 	})
 }
+
+// TestClean tests stripping of server-populated fields for re-applyable output
+func TestClean(t *testing.T) {
+	obj := map[string]interface{}{
+		"apiVersion": "networking.istio.io/v1alpha3",
+		"kind":       "VirtualService",
+		"metadata": map[string]interface{}{
+			"name":              "reviews",
+			"namespace":         "default",
+			"uid":               "8d3c0a4e-6f0b-4c1e-9a47-2b3f5e1d9c01",
+			"resourceVersion":   "12345",
+			"generation":        2,
+			"creationTimestamp": "2024-01-01T00:00:00Z",
+			"managedFields":     []interface{}{map[string]interface{}{"manager": "kubectl"}},
+		},
+		"spec": map[string]interface{}{
+			"hosts": []interface{}{"reviews"},
+		},
+		"status": map[string]interface{}{
+			"observedGeneration": 1,
+		},
+	}
+	serverFields := []string{"uid:", "resourceVersion:", "generation:", "creationTimestamp:", "managedFields:", "status:"}
+
+	t.Run("cleaned output omits server fields", func(t *testing.T) {
+		cleaned, err := Clean(obj)
+		if err != nil {
+			t.Fatalf("Failed to clean object: %v", err)
+		}
+		result, err := Yaml.PrintObj(cleaned)
+		if err != nil {
+			t.Fatalf("Failed to print cleaned object: %v", err)
+		}
+		for _, field := range serverFields {
+			if strings.Contains(result, field) {
+				t.Fatalf("Expected cleaned YAML not to contain '%s', got: %s", field, result)
+			}
+		}
+		for _, field := range []string{"name: reviews", "namespace: default", "- reviews"} {
+			if !strings.Contains(result, field) {
+				t.Fatalf("Expected cleaned YAML to contain '%s', got: %s", field, result)
+			}
+		}
+	})
+
+	t.Run("raw output retains server fields", func(t *testing.T) {
+		result, err := Yaml.PrintObj(obj)
+		if err != nil {
+			t.Fatalf("Failed to print raw object: %v", err)
+		}
+		for _, field := range serverFields {
+			if !strings.Contains(result, field) {
+				t.Fatalf("Expected raw YAML to contain '%s', got: %s", field, result)
+			}
+		}
+	})
+
+	t.Run("does not modify the original object", func(t *testing.T) {
+		if _, err := Clean(obj); err != nil {
+			t.Fatalf("Failed to clean object: %v", err)
+		}
+		if _, ok := obj["status"]; !ok {
+			t.Fatal("Expected original object to keep its status")
+		}
+	})
+
+	t.Run("rejects non-object values", func(t *testing.T) {
+		if _, err := Clean([]string{"not", "an", "object"}); err == nil {
+			t.Fatal("Expected error when cleaning a non-object value")
+		}
+	})
+}